@@ -24,6 +24,10 @@
 // sample rate. However, decoders in e.g. audio/mp3 package adjust sample rate automatically,
 // and you don't have to care about it as long as you use those decoders.
 //
+// Decoders are provided for WAV (audio/wav), MP3 (audio/mp3), Ogg/Vorbis (audio/vorbis), and Ogg/Opus (audio/opus).
+// For other formats, decode them into the stream format above with a third-party decoder,
+// or convert the files into one of the supported formats in advance.
+//
 // An audio context can generate 'players' (audio.Player objects),
 // and you can play sound by calling Play function of players.
 // When multiple players play, mixing is automatically done.
@@ -66,12 +70,17 @@ type Context struct {
 	initedOnce sync.Once
 
 	sampleRate int
+	bufferSize time.Duration
 	err        error
 	ready      bool
 	readyOnce  sync.Once
 
 	players map[*playerImpl]struct{}
 
+	// clock is a player of an endless silence to count the played samples.
+	clock     *playerImpl
+	clockOnce sync.Once
+
 	m         sync.Mutex
 	semaphore chan struct{}
 }
@@ -89,6 +98,33 @@ var (
 //
 // NewContext panics when an audio context is already created.
 func NewContext(sampleRate int) *Context {
+	return NewContextWithOptions(&NewContextOptions{
+		SampleRate: sampleRate,
+	})
+}
+
+// NewContextOptions represents options for NewContextWithOptions.
+type NewContextOptions struct {
+	// SampleRate specifies the number of samples that should be played during one second.
+	// Usual numbers are 44100 or 48000.
+	SampleRate int
+
+	// BufferSize specifies the buffer size of the audio device.
+	//
+	// If 0 is specified, the default buffer size of the platform is used.
+	// A smaller buffer size reduces the output latency, which is useful for rhythm games for example.
+	// On the other hand, a too small buffer size causes buffer underruns, which sound like glitch noises.
+	// The default buffer sizes are about 20-50[ms] on desktops and browsers, and about 130[ms] on iOS.
+	// Buffer sizes less than 10[ms] are not recommended, and the platform might use a larger size than specified.
+	//
+	// On browsers, the buffer size is rounded to a power of 2 between 256 and 16384 in samples.
+	BufferSize time.Duration
+}
+
+// NewContextWithOptions creates a new audio context with the given options.
+//
+// NewContextWithOptions panics when an audio context is already created.
+func NewContextWithOptions(options *NewContextOptions) *Context {
 	theContextLock.Lock()
 	defer theContextLock.Unlock()
 
@@ -96,9 +132,12 @@ func NewContext(sampleRate int) *Context {
 		panic("audio: context is already created")
 	}
 
+	sampleRate := options.SampleRate
+	bufferSize := deviceBufferSize(runtime.GOOS, sampleRate, options.BufferSize)
 	c := &Context{
 		sampleRate:    sampleRate,
-		playerFactory: newPlayerFactory(sampleRate),
+		bufferSize:    bufferSize,
+		playerFactory: newPlayerFactory(sampleRate, bufferSize),
 		players:       map[*playerImpl]struct{}{},
 		inited:        make(chan struct{}),
 		semaphore:     make(chan struct{}, 1),
@@ -232,6 +271,37 @@ func (c *Context) gcPlayers() error {
 	return nil
 }
 
+func (c *Context) ensureClock() *playerImpl {
+	c.clockOnce.Do(func() {
+		p, err := c.playerFactory.newPlayer(c, silentStream{})
+		if err != nil {
+			c.setError(err)
+			return
+		}
+		p.Play()
+		c.clock = p
+	})
+	return c.clock
+}
+
+// SamplePosition returns the current position of the audio clock in samples.
+//
+// The audio clock starts when SamplePosition or Player.PlayAt is called for the first time,
+// and advances as the audio device consumes samples.
+// The audio clock doesn't advance while the audio is suspended.
+//
+// The audio clock advances by the audio device's buffer, so the value doesn't increase smoothly.
+// Use the value to schedule playing with Player.PlayAt rather than to measure time precisely.
+//
+// SamplePosition is concurrent-safe.
+func (c *Context) SamplePosition() int64 {
+	clock := c.ensureClock()
+	if clock == nil {
+		return 0
+	}
+	return clock.samplePosition()
+}
+
 // IsReady returns a boolean value indicating whether the audio is ready or not.
 //
 // On some browsers, user interaction like click or pressing keys is required to start audio.
@@ -262,6 +332,22 @@ func (c *Context) IsReady() bool {
 	return false
 }
 
+// OutputLatency returns the estimated output latency.
+//
+// OutputLatency is estimated from the buffer size of the audio device.
+// The actual latency can be larger due to the OS's audio mixer and the hardware.
+// If the latency cannot be estimated, OutputLatency returns 0.
+//
+// OutputLatency is useful to compensate the timing of inputs in e.g. rhythm games.
+//
+// OutputLatency is concurrent-safe.
+func (c *Context) OutputLatency() time.Duration {
+	if c.bufferSize != 0 {
+		return c.bufferSize
+	}
+	return defaultDeviceBufferSize(runtime.GOOS, c.sampleRate)
+}
+
 // SampleRate returns the sample rate.
 func (c *Context) SampleRate() int {
 	return c.sampleRate
@@ -369,6 +455,22 @@ func (p *Player) Play() {
 	p.p.Play()
 }
 
+// PlayAt plays the stream when the audio clock reaches samplePosition.
+//
+// samplePosition is a position of the audio clock in samples, which Context.SamplePosition returns.
+// Players started by PlayAt with the same samplePosition start at the same sample.
+// This is useful to synchronize sounds with each other, e.g. PlayAt(context.SamplePosition() + n).
+// If samplePosition is already past, PlayAt starts playing immediately like Play.
+// In rare cases where the audio device consumes samples during PlayAt, the start might be off by the device's buffer.
+//
+// The player is regarded as playing while waiting for samplePosition, and Position returns 0 during the wait.
+// SetPosition or Rewind during the wait cancels the wait.
+//
+// If the player is already playing, PlayAt does nothing.
+func (p *Player) PlayAt(samplePosition int64) {
+	p.p.PlayAt(samplePosition)
+}
+
 // IsPlaying returns boolean indicating whether the player is playing.
 func (p *Player) IsPlaying() bool {
 	return p.p.IsPlaying()
@@ -430,6 +532,79 @@ func (p *Player) SetVolume(volume float64) {
 	p.p.SetVolume(volume)
 }
 
+// Pan returns the current stereo panning of this player [-1-1].
+func (p *Player) Pan() float64 {
+	return p.p.Pan()
+}
+
+// SetPan sets the stereo panning of this player.
+// pan must be in between -1 and 1. SetPan panics otherwise.
+//
+// -1 plays only the left channel, 1 plays only the right channel, and 0 plays the both channels as they are.
+// The panning works as a balance control: the channel on the opposite side is attenuated linearly.
+// To pan a monaural sound, the source should have the same samples in the both channels.
+//
+// Combined with SetVolume, SetPan can be used for basic 2D positional audio.
+//
+// The new panning is applied after the player's buffer is played.
+// If you want to change the panning responsively, use a small buffer size with SetBufferSize.
+func (p *Player) SetPan(pan float64) {
+	p.p.SetPan(pan)
+}
+
+// Effect is an audio effect like a reverb or a filter.
+type Effect interface {
+	// ProcessAudio processes PCM samples in place.
+	//
+	// samples are interleaved samples of channelCount channels, and each value is in [-1, 1].
+	// The result values are clamped to [-1, 1].
+	// The length of samples varies, and is at most the player's buffer size (0.5[s] by default).
+	// sampleRate is the sample rate of the context, and channelCount is always 2 so far.
+	//
+	// ProcessAudio is called from a goroutine for audio, not from the game's goroutine.
+	// ProcessAudio should return as soon as possible, or the audio might be interrupted.
+	ProcessAudio(samples []float32, sampleRate int, channelCount int)
+}
+
+// SetEffect sets an effect applied to the stream of this player.
+// If effect is nil, no effect is applied.
+//
+// The effect is applied after the playback rate and the panning are applied.
+// The effect is applied when the player reads the stream, which is ahead of the actual playing by the player's buffer.
+// If you want to change the effect's parameters responsively, use a small buffer size with SetBufferSize.
+//
+// Effects are applied to each player. There is no effect for the whole audio output.
+func (p *Player) SetEffect(effect Effect) {
+	p.p.SetEffect(effect)
+}
+
+// PlaybackRate returns the current playback rate of this player.
+//
+// The default playback rate is 1.
+func (p *Player) PlaybackRate() float64 {
+	return p.p.PlaybackRate()
+}
+
+// SetPlaybackRate sets the playback rate of this player.
+// rate must be positive and finite. SetPlaybackRate panics otherwise.
+//
+// For example, 2 plays the stream twice as fast, and 0.5 plays the stream half as fast.
+// Like a tape, changing the playback rate changes the pitch too. Preserving the pitch is not supported.
+//
+// The stream is resampled on the fly with linear interpolation.
+// This is cheap enough to use for many players, but the quality is not as good as offline resampling.
+// A high playback rate might cause aliasing noises.
+//
+// Position and SetPosition are based on the position in the source stream.
+// For example, if the playback rate is 2, Position advances by 2 seconds per second.
+//
+// If the source is seekable, the new playback rate is applied immediately.
+// Otherwise, the new playback rate is applied after the player's buffer is played.
+// See also SetBufferSize.
+func (p *Player) SetPlaybackRate(rate float64) {
+	p.p.SetPlaybackRate(rate)
+}
+
 // SetBufferSize adjusts the buffer size of the player.
 // If 0 is specified, the default buffer size is used.
 // A small buffer size is useful if you want to play a real-time PCM for example.
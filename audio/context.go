@@ -16,15 +16,17 @@ package audio
 
 import (
 	"io"
+	"time"
 
 	"github.com/ebitengine/oto/v3"
 )
 
-func newContext(sampleRate int) (context, chan struct{}, error) {
+func newContext(sampleRate int, bufferSize time.Duration) (context, chan struct{}, error) {
 	ctx, ready, err := oto.NewContext(&oto.NewContextOptions{
 		SampleRate:   sampleRate,
 		ChannelCount: channelCount,
 		Format:       oto.FormatSignedInt16LE,
+		BufferSize:   bufferSize,
 	})
 	err = addErrorInfoForContextCreation(err)
 	return &contextProxy{ctx}, ready, err
@@ -47,3 +49,41 @@ type contextProxy struct {
 func (c *contextProxy) NewPlayer(r io.Reader) player {
 	return c.otoContext.NewPlayer(r)
 }
+
+// deviceBufferSize returns the buffer size of the audio device that is actually used for the specified size on goos.
+func deviceBufferSize(goos string, sampleRate int, bufferSize time.Duration) time.Duration {
+	if bufferSize == 0 {
+		return 0
+	}
+	if goos != "js" {
+		return bufferSize
+	}
+
+	// ScriptProcessorNode accepts only a power of 2 between 256 and 16384 as a buffer size in samples.
+	samples := int(int64(bufferSize) * int64(sampleRate) / int64(time.Second))
+	n := 256
+	for n < samples && n < 16384 {
+		n <<= 1
+	}
+	return time.Duration(n) * time.Second / time.Duration(sampleRate)
+}
+
+// defaultDeviceBufferSize returns the default buffer size of the audio device on goos, or 0 if unknown.
+//
+// The values must be synced with the default values in Oto.
+func defaultDeviceBufferSize(goos string, sampleRate int) time.Duration {
+	var samples int
+	switch goos {
+	case "windows":
+		return 50 * time.Millisecond
+	case "darwin":
+		samples = 1024
+	case "ios":
+		samples = 6144
+	case "js", "linux", "freebsd", "openbsd", "netbsd", "dragonfly":
+		samples = 2048
+	default:
+		return 0
+	}
+	return time.Duration(samples) * time.Second / time.Duration(sampleRate)
+}
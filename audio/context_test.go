@@ -0,0 +1,190 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+func samplesToDuration(samples int, sampleRate int) time.Duration {
+	return time.Duration(samples) * time.Second / time.Duration(sampleRate)
+}
+
+func TestDeviceBufferSize(t *testing.T) {
+	testCases := []struct {
+		Name       string
+		GOOS       string
+		SampleRate int
+		BufferSize time.Duration
+		Want       time.Duration
+	}{
+		{
+			Name:       "zero",
+			GOOS:       "linux",
+			SampleRate: 48000,
+			BufferSize: 0,
+			Want:       0,
+		},
+		{
+			Name:       "zero on js",
+			GOOS:       "js",
+			SampleRate: 48000,
+			BufferSize: 0,
+			Want:       0,
+		},
+		{
+			Name:       "as is on linux",
+			GOOS:       "linux",
+			SampleRate: 48000,
+			BufferSize: 10 * time.Millisecond,
+			Want:       10 * time.Millisecond,
+		},
+		{
+			Name:       "as is on windows",
+			GOOS:       "windows",
+			SampleRate: 44100,
+			BufferSize: 3 * time.Millisecond,
+			Want:       3 * time.Millisecond,
+		},
+		{
+			Name:       "minimum on js",
+			GOOS:       "js",
+			SampleRate: 48000,
+			BufferSize: time.Millisecond,
+			Want:       samplesToDuration(256, 48000),
+		},
+		{
+			Name:       "power of 2 on js",
+			GOOS:       "js",
+			SampleRate: 48000,
+			BufferSize: 10 * time.Millisecond,
+			Want:       samplesToDuration(512, 48000),
+		},
+		{
+			Name:       "exact power of 2 on js",
+			GOOS:       "js",
+			SampleRate: 48000,
+			BufferSize: samplesToDuration(1024, 48000) + time.Nanosecond,
+			Want:       samplesToDuration(1024, 48000),
+		},
+		{
+			Name:       "maximum on js",
+			GOOS:       "js",
+			SampleRate: 44100,
+			BufferSize: time.Second,
+			Want:       samplesToDuration(16384, 44100),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			if got := audio.DeviceBufferSizeForTesting(tc.GOOS, tc.SampleRate, tc.BufferSize); got != tc.Want {
+				t.Errorf("got: %v, want: %v", got, tc.Want)
+			}
+		})
+	}
+}
+
+func TestDefaultDeviceBufferSize(t *testing.T) {
+	testCases := []struct {
+		GOOS       string
+		SampleRate int
+		Want       time.Duration
+	}{
+		{
+			GOOS:       "windows",
+			SampleRate: 48000,
+			Want:       50 * time.Millisecond,
+		},
+		{
+			GOOS:       "darwin",
+			SampleRate: 48000,
+			Want:       samplesToDuration(1024, 48000),
+		},
+		{
+			GOOS:       "ios",
+			SampleRate: 48000,
+			Want:       samplesToDuration(6144, 48000),
+		},
+		{
+			GOOS:       "js",
+			SampleRate: 44100,
+			Want:       samplesToDuration(2048, 44100),
+		},
+		{
+			GOOS:       "linux",
+			SampleRate: 48000,
+			Want:       samplesToDuration(2048, 48000),
+		},
+		{
+			GOOS:       "freebsd",
+			SampleRate: 44100,
+			Want:       samplesToDuration(2048, 44100),
+		},
+		{
+			GOOS:       "android",
+			SampleRate: 48000,
+			Want:       0,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.GOOS, func(t *testing.T) {
+			if got := audio.DefaultDeviceBufferSizeForTesting(tc.GOOS, tc.SampleRate); got != tc.Want {
+				t.Errorf("got: %v, want: %v", got, tc.Want)
+			}
+		})
+	}
+}
+
+func TestNewContextWithOptionsDefault(t *testing.T) {
+	c := audio.NewContextWithOptions(&audio.NewContextOptions{
+		SampleRate: 48000,
+	})
+	defer audio.ResetContextForTesting()
+
+	if got, want := c.SampleRate(), 48000; got != want {
+		t.Errorf("SampleRate(): got: %d, want: %d", got, want)
+	}
+	if got, want := c.OutputLatency(), audio.DefaultDeviceBufferSizeForTesting(runtime.GOOS, 48000); got != want {
+		t.Errorf("OutputLatency(): got: %v, want: %v", got, want)
+	}
+}
+
+func TestNewContextWithOptionsBufferSize(t *testing.T) {
+	c := audio.NewContextWithOptions(&audio.NewContextOptions{
+		SampleRate: 48000,
+		BufferSize: 20 * time.Millisecond,
+	})
+	defer audio.ResetContextForTesting()
+
+	if got, want := c.SampleRate(), 48000; got != want {
+		t.Errorf("SampleRate(): got: %d, want: %d", got, want)
+	}
+	if got, want := c.OutputLatency(), audio.DeviceBufferSizeForTesting(runtime.GOOS, 48000, 20*time.Millisecond); got != want {
+		t.Errorf("OutputLatency(): got: %v, want: %v", got, want)
+	}
+}
+
+func TestNewContextOutputLatency(t *testing.T) {
+	c := audio.NewContext(44100)
+	defer audio.ResetContextForTesting()
+
+	if got, want := c.OutputLatency(), audio.DefaultDeviceBufferSizeForTesting(runtime.GOOS, 44100); got != want {
+		t.Errorf("OutputLatency(): got: %v, want: %v", got, want)
+	}
+}
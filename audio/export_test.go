@@ -17,6 +17,7 @@ package audio
 import (
 	"io"
 	"sync"
+	"time"
 )
 
 type (
@@ -62,6 +63,9 @@ func (p *dummyPlayer) Play() {
 	p.m.Lock()
 	p.playing = true
 	p.m.Unlock()
+	if manualReadingForTesting {
+		return
+	}
 	go func() {
 		if _, err := io.ReadAll(p.r); err != nil {
 			panic(err)
@@ -154,3 +158,52 @@ func ResetContextForTesting() {
 func (i *InfiniteLoop) SetNoBlendForTesting(value bool) {
 	i.noBlendForTesting = value
 }
+
+// manualReadingForTesting reports whether dummy players don't read their streams automatically.
+// If manualReadingForTesting is true, tests read the streams with ReadPlayerForTesting and ReadClockForTesting.
+var manualReadingForTesting bool
+
+func SetManualReadingForTesting(manual bool) {
+	manualReadingForTesting = manual
+}
+
+// ReadPlayerForTesting reads the stream of the player as the underlying player does.
+func ReadPlayerForTesting(p *Player, buf []byte) (int, error) {
+	p.p.m.Lock()
+	r := p.p.player.(*dummyPlayer).r
+	p.p.m.Unlock()
+	return io.ReadFull(r, buf)
+}
+
+// ReadClockForTesting advances the audio clock of the context by reading its stream.
+func ReadClockForTesting(c *Context, buf []byte) (int, error) {
+	clock := c.ensureClock()
+	clock.m.Lock()
+	r := clock.player.(*dummyPlayer).r
+	clock.m.Unlock()
+	return io.ReadFull(r, buf)
+}
+
+type ResamplerForTesting struct {
+	r resampler
+}
+
+func (r *ResamplerForTesting) Read(src io.Reader, buf []byte, rate float64, drain bool) (int, int64, error) {
+	return r.r.read(src, buf, rate, drain)
+}
+
+func (r *ResamplerForTesting) IsEmpty() bool {
+	return r.r.isEmpty()
+}
+
+func PanToVolumesForTesting(pan float64) (float64, float64) {
+	return panToVolumes(pan)
+}
+
+func DeviceBufferSizeForTesting(goos string, sampleRate int, bufferSize time.Duration) time.Duration {
+	return deviceBufferSize(goos, sampleRate, bufferSize)
+}
+
+func DefaultDeviceBufferSizeForTesting(goos string, sampleRate int) time.Duration {
+	return defaultDeviceBufferSize(goos, sampleRate)
+}
@@ -92,5 +92,15 @@ func (s *Stereo16) Seek(offset int64, whence int) (int64, error) {
 	if s.eight {
 		offset /= 2
 	}
-	return s.source.Seek(offset, whence)
+	pos, err := s.source.Seek(offset, whence)
+	if err != nil {
+		return 0, err
+	}
+	if s.mono {
+		pos *= 2
+	}
+	if s.eight {
+		pos *= 2
+	}
+	return pos, nil
 }
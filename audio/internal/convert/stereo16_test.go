@@ -0,0 +1,83 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2/audio/internal/convert"
+)
+
+func TestStereo16Seek(t *testing.T) {
+	cases := []struct {
+		Name  string
+		Mono  bool
+		Eight bool
+	}{
+		{
+			Name:  "stereo 8bit",
+			Eight: true,
+		},
+		{
+			Name: "mono 16bit",
+			Mono: true,
+		},
+		{
+			Name:  "mono 8bit",
+			Mono:  true,
+			Eight: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			src := make([]byte, 256)
+			for i := range src {
+				src[i] = byte(i)
+			}
+			s := convert.NewStereo16(bytes.NewReader(src), c.Mono, c.Eight)
+
+			// The position must be in the converted stream, which is 4 bytes per frame.
+			pos, err := s.Seek(16, io.SeekStart)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := pos, int64(16); got != want {
+				t.Errorf("Seek(16, io.SeekStart): got: %d, want: %d", got, want)
+			}
+
+			pos, err = s.Seek(8, io.SeekCurrent)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := pos, int64(24); got != want {
+				t.Errorf("Seek(8, io.SeekCurrent): got: %d, want: %d", got, want)
+			}
+
+			all, err := io.ReadAll(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pos, err = s.Seek(0, io.SeekCurrent)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := pos, int64(24+len(all)); got != want {
+				t.Errorf("Seek(0, io.SeekCurrent) at the end: got: %d, want: %d", got, want)
+			}
+		})
+	}
+}
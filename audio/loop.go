@@ -52,6 +52,12 @@ func NewInfiniteLoop(src io.ReadSeeker, length int64) *InfiniteLoop {
 // NewInfiniteLoopWithIntro creates a new infinite loop stream with an intro part.
 // NewInfiniteLoopWithIntro accepts a source stream src, introLength in bytes and loopLength in bytes.
 //
+// The stream plays the intro part once, and then plays the loop part [introLength, introLength+loopLength) repeatedly.
+// The loop joint is sample-accurate.
+// introLength and loopLength are rounded down to multiples of a sample size (4 bytes for 16bit stereo).
+// If you have loop points in samples, multiply them by 4,
+// e.g. NewInfiniteLoopWithIntro(src, loopStart*4, (loopEnd-loopStart)*4).
+//
 // If the loop's total length is exactly the same as src's length, you might hear noises around the loop joint.
 // This noise can be heard especially when src is decoded from a lossy compression format like Ogg/Vorbis and MP3.
 // In this case, try to add more (about 0.1[s]) data to src after the loop end.
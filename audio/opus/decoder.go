@@ -0,0 +1,269 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/pion/opus"
+)
+
+const (
+	// sampleRate is the sample rate of decoded Opus streams.
+	// Opus streams are always decoded at 48 kHz regardless of the original sample rate.
+	sampleRate = 48000
+
+	// maxPacketSamples is the maximum number of samples per channel in one Opus packet (120 [ms]).
+	maxPacketSamples = sampleRate * 120 / 1000
+
+	// preRollSamples is the number of samples decoded before the seeking target to converge the decoder state (80 [ms]).
+	//
+	// See https://www.rfc-editor.org/rfc/rfc7845#section-4.6.
+	preRollSamples = sampleRate * 80 / 1000
+)
+
+// decoder decodes an Ogg/Opus stream into interleaved float32 samples.
+type decoder struct {
+	src     io.Reader
+	packets packetReader
+	opus    opus.Decoder
+
+	channelCount int
+	preSkip      int64
+	gain         float32
+
+	// pages is the index of the audio pages. pages is nil when src is not an io.Seeker.
+	pages []page
+
+	// pos is the granule position of the next decoded sample.
+	pos int64
+
+	// skip is the granule position before which decoded samples are discarded.
+	skip int64
+
+	// end is the granule position of the end of the stream, or -1 if unknown.
+	end int64
+
+	buf    []float32
+	pcm    []float32
+	pcmPos int
+}
+
+func newDecoder(src io.Reader) (*decoder, error) {
+	d := &decoder{
+		src: src,
+		packets: packetReader{
+			r: src,
+		},
+		end: -1,
+	}
+
+	var start int64
+	seeker, seekable := src.(io.Seeker)
+	if seekable {
+		s, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, err
+		}
+		start = s
+	}
+
+	if err := d.readHeaders(); err != nil {
+		return nil, err
+	}
+	headerPageCount := d.packets.pageCount
+
+	opusDecoder, err := opus.NewDecoderWithOutput(sampleRate, d.channelCount)
+	if err != nil {
+		return nil, fmt.Errorf("opus: %w", err)
+	}
+	d.opus = opusDecoder
+	d.buf = make([]float32, maxPacketSamples*d.channelCount)
+	d.skip = d.preSkip
+
+	if seekable {
+		rs := src.(io.ReadSeeker)
+		if _, err := rs.Seek(start, io.SeekStart); err != nil {
+			return nil, err
+		}
+		pages, end, err := scanPages(rs, d.packets.serial)
+		if err != nil {
+			return nil, err
+		}
+		if len(pages) > headerPageCount {
+			d.pages = pages[headerPageCount:]
+		} else {
+			d.pages = []page{}
+		}
+		d.end = end
+
+		if err := d.SetPosition(0); err != nil {
+			return nil, err
+		}
+	}
+
+	return d, nil
+}
+
+func (d *decoder) readHeaders() error {
+	// See https://www.rfc-editor.org/rfc/rfc7845#section-5.1.
+	head, err := d.packets.nextPacket()
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	if len(head) < 19 || !bytes.HasPrefix(head, []byte("OpusHead")) {
+		return fmt.Errorf("opus: invalid identification header")
+	}
+	if version := head[8]; version>>4 != 0 {
+		return fmt.Errorf("opus: unsupported version: %d", version)
+	}
+	d.channelCount = int(head[9])
+	d.preSkip = int64(binary.LittleEndian.Uint16(head[10:12]))
+	// The output gain is a Q7.8 value in dB.
+	gain := int16(binary.LittleEndian.Uint16(head[16:18]))
+	d.gain = float32(math.Pow(10, float64(gain)/(20*256)))
+	if family := head[18]; family != 0 {
+		return fmt.Errorf("opus: unsupported channel mapping family: %d", family)
+	}
+	if d.channelCount != 1 && d.channelCount != 2 {
+		return fmt.Errorf("opus: number of channels must be 1 or 2 but was %d", d.channelCount)
+	}
+
+	// See https://www.rfc-editor.org/rfc/rfc7845#section-5.2.
+	tags, err := d.packets.nextPacket()
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	if !bytes.HasPrefix(tags, []byte("OpusTags")) {
+		return fmt.Errorf("opus: invalid comment header")
+	}
+	return nil
+}
+
+// Read reads interleaved samples.
+func (d *decoder) Read(buf []float32) (int, error) {
+	for d.pcmPos == len(d.pcm) {
+		if err := d.decodePacket(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(buf, d.pcm[d.pcmPos:])
+	d.pcmPos += n
+	return n, nil
+}
+
+func (d *decoder) decodePacket() error {
+	if d.end >= 0 && d.pos >= d.end {
+		return io.EOF
+	}
+
+	packet, err := d.packets.nextPacket()
+	if err != nil {
+		return err
+	}
+	if d.packets.eos {
+		d.end = d.packets.granule
+	}
+
+	d.pcm = d.pcm[:0]
+	d.pcmPos = 0
+	if len(packet) == 0 {
+		return nil
+	}
+
+	n, err := d.opus.DecodeToFloat32(packet, d.buf)
+	if err != nil {
+		return fmt.Errorf("opus: %w", err)
+	}
+	start := d.pos
+	d.pos += int64(n)
+
+	from := int64(0)
+	if d.skip > start {
+		from = min(d.skip-start, int64(n))
+	}
+	to := int64(n)
+	if d.end >= 0 {
+		to = max(min(d.end-start, to), from)
+	}
+
+	d.pcm = d.buf[from*int64(d.channelCount) : to*int64(d.channelCount)]
+	for i, v := range d.pcm {
+		v *= d.gain
+		// Clamp the value as the decoded value can be slightly out of the range.
+		d.pcm[i] = min(max(v, -1), 1)
+	}
+	return nil
+}
+
+// SetPosition sets the position in samples per channel.
+func (d *decoder) SetPosition(pos int64) error {
+	if d.pages == nil {
+		return fmt.Errorf("opus: the source must be io.Seeker to seek")
+	}
+
+	target := pos + d.preSkip
+	var idx int
+	for i, p := range d.pages {
+		if p.start > target-preRollSamples {
+			break
+		}
+		if p.continued {
+			continue
+		}
+		idx = i
+	}
+
+	var start int64
+	if len(d.pages) > 0 {
+		if _, err := d.src.(io.Seeker).Seek(d.pages[idx].offset, io.SeekStart); err != nil {
+			return err
+		}
+		start = d.pages[idx].start
+	}
+
+	d.packets.reset()
+	if err := d.opus.Init(sampleRate, d.channelCount); err != nil {
+		return fmt.Errorf("opus: %w", err)
+	}
+	d.pos = start
+	d.skip = target
+	d.pcm = d.pcm[:0]
+	d.pcmPos = 0
+	return nil
+}
+
+// Length returns the number of samples per channel, or 0 if unknown.
+func (d *decoder) Length() int64 {
+	if d.pages == nil || d.end < 0 {
+		return 0
+	}
+	return max(d.end-d.preSkip, 0)
+}
+
+// Channels returns the number of channels.
+func (d *decoder) Channels() int {
+	return d.channelCount
+}
@@ -0,0 +1,10 @@
+module github.com/hajimehoshi/ebiten/v2/audio/opus
+
+go 1.24.0
+
+require (
+	github.com/hajimehoshi/ebiten/v2 v2.0.0-00010101000000-000000000000
+	github.com/pion/opus v0.1.0
+)
+
+replace github.com/hajimehoshi/ebiten/v2 => ../..
@@ -0,0 +1,253 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opus
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+const (
+	pageHeaderSize = 27
+
+	pageFlagContinued = 0x01
+	pageFlagEOS       = 0x04
+)
+
+// pageHeader is a header of an Ogg page with its lacing values.
+//
+// See https://www.rfc-editor.org/rfc/rfc3533#section-6.
+type pageHeader struct {
+	header [pageHeaderSize]byte
+	lacing [255]byte
+}
+
+// read reads a page header from r and returns the number of the segments and the size of the page body.
+func (p *pageHeader) read(r io.Reader) (int, int, error) {
+	if _, err := io.ReadFull(r, p.header[:]); err != nil {
+		return 0, 0, err
+	}
+	if string(p.header[:4]) != "OggS" {
+		return 0, 0, fmt.Errorf("opus: invalid Ogg page")
+	}
+	if p.header[4] != 0 {
+		return 0, 0, fmt.Errorf("opus: unsupported Ogg version: %d", p.header[4])
+	}
+	n := int(p.header[26])
+	if _, err := io.ReadFull(r, p.lacing[:n]); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, 0, err
+	}
+	var size int
+	for _, l := range p.lacing[:n] {
+		size += int(l)
+	}
+	return n, size, nil
+}
+
+func (p *pageHeader) flags() byte {
+	return p.header[5]
+}
+
+// granulePosition returns the granule position of the page, or -1 if no packet finishes on the page.
+func (p *pageHeader) granulePosition() int64 {
+	return int64(binary.LittleEndian.Uint64(p.header[6:14]))
+}
+
+func (p *pageHeader) serial() uint32 {
+	return binary.LittleEndian.Uint32(p.header[14:18])
+}
+
+// page is an entry of the page index used for seeking.
+type page struct {
+	// offset is the offset of the page in the source.
+	offset int64
+
+	// start is the granule position where the first packet beginning on the page starts.
+	start int64
+
+	// continued reports whether the page begins with a continued packet.
+	continued bool
+}
+
+// scanPages scans the page headers of the logical stream serial from the current position of r.
+// scanPages returns the page index and the last granule position of the stream.
+func scanPages(r io.ReadSeeker, serial uint32) ([]page, int64, error) {
+	offset, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var pages []page
+	var p pageHeader
+	var granule int64
+	for {
+		n, size, err := p.read(r)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		if _, err := r.Seek(int64(size), io.SeekCurrent); err != nil {
+			return nil, 0, err
+		}
+		if p.serial() == serial {
+			pages = append(pages, page{
+				offset:    offset,
+				start:     granule,
+				continued: p.flags()&pageFlagContinued != 0,
+			})
+			if g := p.granulePosition(); g != -1 {
+				granule = g
+			}
+			if p.flags()&pageFlagEOS != 0 {
+				break
+			}
+		}
+		offset += int64(pageHeaderSize + n + size)
+	}
+	return pages, granule, nil
+}
+
+// packetReader reads packets of the first logical stream from an Ogg stream.
+type packetReader struct {
+	r io.Reader
+
+	serial    uint32
+	hasSerial bool
+
+	page         pageHeader
+	segmentCount int
+	segmentIndex int
+	body         []byte
+	bodyPos      int
+
+	partial    []byte
+	discarding bool
+
+	// granule is the granule position of the current page.
+	granule int64
+
+	// eos reports whether the current page is the last page of the stream.
+	eos bool
+
+	// pageCount is the number of the pages read so far.
+	pageCount int
+}
+
+// reset resets the state so that the next packet is read from a page beginning at the current position of r.
+func (p *packetReader) reset() {
+	p.segmentCount = 0
+	p.segmentIndex = 0
+	p.partial = p.partial[:0]
+	p.discarding = false
+	p.eos = false
+}
+
+func (p *packetReader) readPage() error {
+	for {
+		if p.eos {
+			return io.EOF
+		}
+
+		n, size, err := p.page.read(p.r)
+		if err != nil {
+			return err
+		}
+		if cap(p.body) < size {
+			p.body = make([]byte, size)
+		}
+		p.body = p.body[:size]
+		if _, err := io.ReadFull(p.r, p.body); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+
+		if !p.hasSerial {
+			p.serial = p.page.serial()
+			p.hasSerial = true
+		}
+		// Pages of other logical streams are skipped.
+		if p.page.serial() != p.serial {
+			continue
+		}
+
+		p.pageCount++
+		p.granule = p.page.granulePosition()
+		p.eos = p.page.flags()&pageFlagEOS != 0
+		if p.page.flags()&pageFlagContinued == 0 {
+			p.partial = p.partial[:0]
+			p.discarding = false
+		} else if len(p.partial) == 0 {
+			// The beginning of the packet was not read, e.g. after seeking. Discard the rest of the packet.
+			p.discarding = true
+		}
+		p.segmentCount = n
+		p.segmentIndex = 0
+		p.bodyPos = 0
+		return nil
+	}
+}
+
+// nextPacket returns the next packet.
+//
+// The returned slice is valid until the next call of nextPacket.
+func (p *packetReader) nextPacket() ([]byte, error) {
+	for {
+		if p.segmentIndex == p.segmentCount {
+			if err := p.readPage(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		start := p.bodyPos
+		var complete bool
+		for p.segmentIndex < p.segmentCount {
+			l := int(p.page.lacing[p.segmentIndex])
+			p.segmentIndex++
+			p.bodyPos += l
+			if l < 255 {
+				complete = true
+				break
+			}
+		}
+		data := p.body[start:p.bodyPos]
+
+		if p.discarding {
+			if complete {
+				p.discarding = false
+			}
+			continue
+		}
+		if !complete {
+			p.partial = append(p.partial, data...)
+			continue
+		}
+		if len(p.partial) > 0 {
+			p.partial = append(p.partial, data...)
+			packet := p.partial
+			p.partial = p.partial[:0]
+			return packet, nil
+		}
+		return data, nil
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package opus provides Ogg/Opus decoder.
+//
+// This package is a separate module from Ebitengine, as it depends on a decoder that requires Go 1.24 or later.
+package opus
+
+import (
+	"io"
+
+	"github.com/hajimehoshi/ebiten/v2/audio/internal/convert"
+)
+
+// Stream is a decoded audio stream.
+type Stream struct {
+	decoded io.ReadSeeker
+	size    int64
+}
+
+// Read is implementation of io.Reader's Read.
+func (s *Stream) Read(p []byte) (int, error) {
+	return s.decoded.Read(p)
+}
+
+// Seek is implementation of io.Seeker's Seek.
+//
+// Note that Seek can take long since decoding is a relatively heavy task.
+func (s *Stream) Seek(offset int64, whence int) (int64, error) {
+	return s.decoded.Seek(offset, whence)
+}
+
+// Length returns the size of decoded stream in bytes.
+//
+// If the source is not io.Seeker, Length returns 0.
+func (s *Stream) Length() int64 {
+	return s.size
+}
+
+type decoded struct {
+	posInBytes int64
+	decoder    *decoder
+	decoderr   io.Reader
+}
+
+func (d *decoded) Read(b []byte) (int, error) {
+	if d.decoderr == nil {
+		d.decoderr = convert.NewReaderFromFloat32Reader(d.decoder)
+	}
+	n, err := d.decoderr.Read(b)
+	d.posInBytes += int64(n)
+	return n, err
+}
+
+func (d *decoded) Seek(offset int64, whence int) (int64, error) {
+	next := int64(0)
+	switch whence {
+	case io.SeekStart:
+		next = offset
+	case io.SeekCurrent:
+		next = d.posInBytes + offset
+	case io.SeekEnd:
+		next = d.Length() + offset
+	}
+	// pos should be always a multiple of the frame size.
+	frameSize := int64(d.decoder.Channels()) * 2
+	next = next / frameSize * frameSize
+	if err := d.decoder.SetPosition(next / frameSize); err != nil {
+		return 0, err
+	}
+	d.posInBytes = next
+	d.decoderr = nil
+	return next, nil
+}
+
+func (d *decoded) Length() int64 {
+	return d.decoder.Length() * int64(d.decoder.Channels()) * 2 // 2 means 16bit per sample.
+}
+
+// decode accepts an Ogg/Opus stream and returns a decorded stream.
+func decode(in io.Reader) (*decoded, int, int, error) {
+	d, err := newDecoder(in)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return &decoded{decoder: d}, d.Channels(), sampleRate, nil
+}
+
+// DecodeWithoutResampling decodes Ogg/Opus data to playable stream.
+//
+// DecodeWithoutResampling returns error when decoding fails or IO error happens.
+//
+// Opus streams are always decoded at 48000 [Hz] regardless of the original sample rate.
+//
+// The returned Stream's Seek and Length are available only when src is an io.Seeker.
+//
+// A Stream doesn't close src even if src implements io.Closer.
+// Closing the source is src owner's responsibility.
+func DecodeWithoutResampling(src io.Reader) (*Stream, error) {
+	decoded, channelCount, _, err := decode(src)
+	if err != nil {
+		return nil, err
+	}
+	var s io.ReadSeeker = decoded
+	size := decoded.Length()
+	if channelCount == 1 {
+		s = convert.NewStereo16(s, true, false)
+		size *= 2
+	}
+	stream := &Stream{
+		decoded: s,
+		size:    size,
+	}
+	return stream, nil
+}
+
+// DecodeWithSampleRate decodes Ogg/Opus data to playable stream.
+//
+// DecodeWithSampleRate returns error when decoding fails or IO error happens.
+//
+// DecodeWithSampleRate automatically resamples the stream to fit with sampleRate if necessary.
+//
+// The returned Stream's Seek and Length are available only when src is an io.Seeker.
+//
+// A Stream doesn't close src even if src implements io.Closer.
+// Closing the source is src owner's responsibility.
+func DecodeWithSampleRate(sampleRate int, src io.Reader) (*Stream, error) {
+	decoded, channelCount, origSampleRate, err := decode(src)
+	if err != nil {
+		return nil, err
+	}
+	var s io.ReadSeeker = decoded
+	size := decoded.Length()
+	if channelCount == 1 {
+		s = convert.NewStereo16(s, true, false)
+		size *= 2
+	}
+	if origSampleRate != sampleRate {
+		r := convert.NewResampling(s, size, origSampleRate, sampleRate)
+		s = r
+		size = r.Length()
+	}
+	stream := &Stream{decoded: s, size: size}
+	return stream, nil
+}
@@ -0,0 +1,193 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opus_test
+
+import (
+	"bytes"
+	_ "embed"
+	"io"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2/audio/opus"
+)
+
+var (
+	// test_mono.opus is speech_8.opus from github.com/hraban/opus (MIT License).
+	//
+	//go:embed test_mono.opus
+	test_mono_opus []byte
+)
+
+// testMonoLength is the length of test_mono.opus in samples: the last granule position 518712 minus the pre-skip 312.
+const testMonoLength = 518400
+
+func TestMono(t *testing.T) {
+	s, err := opus.DecodeWithoutResampling(bytes.NewReader(test_mono_opus))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Stream decoded by audio/opus.DecodeWithoutResampling() is always 16bit stereo.
+	if got, want := s.Length(), int64(testMonoLength*2*2); got != want {
+		t.Errorf("s.Length(): got: %d, want: %d", got, want)
+	}
+
+	buf, err := io.ReadAll(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := int64(len(buf)), s.Length(); got != want {
+		t.Errorf("len(buf): got: %d, want: %d", got, want)
+	}
+
+	var silent bool = true
+	for i := 0; i < len(buf); i += 4 {
+		if buf[i] != buf[i+2] || buf[i+1] != buf[i+3] {
+			t.Fatalf("the left and the right channels must be the same at %d", i)
+		}
+		if buf[i] != 0 || buf[i+1] != 0 {
+			silent = false
+		}
+	}
+	if silent {
+		t.Errorf("the decoded stream must not be silent")
+	}
+}
+
+func TestSampleRate(t *testing.T) {
+	s, err := opus.DecodeWithSampleRate(44100, bytes.NewReader(test_mono_opus))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := s.Length(), int64(testMonoLength*44100/48000*2*2); got != want {
+		t.Errorf("s.Length(): got: %d, want: %d", got, want)
+	}
+
+	buf, err := io.ReadAll(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := int64(len(buf)), s.Length(); got != want {
+		t.Errorf("len(buf): got: %d, want: %d", got, want)
+	}
+}
+
+func TestSeek(t *testing.T) {
+	s, err := opus.DecodeWithoutResampling(bytes.NewReader(test_mono_opus))
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, err := io.ReadAll(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, offset := range []int64{0, 4 * 100, 4 * 48000, 4*300000 + 2, s.Length() - 4*100, s.Length()} {
+		pos, err := s.Seek(offset, io.SeekStart)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The position is rounded to the frame size.
+		if got, want := pos, offset/4*4; got != want {
+			t.Errorf("s.Seek(%d, io.SeekStart): got: %d, want: %d", offset, got, want)
+		}
+
+		buf, err := io.ReadAll(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := len(buf), len(all)-int(pos); got != want {
+			t.Fatalf("len(buf) after seeking to %d: got: %d, want: %d", pos, got, want)
+		}
+		// The decoder is expected to converge in the pre-roll before the target.
+		if !bytes.Equal(buf, all[pos:]) {
+			t.Errorf("the decoded data after seeking to %d doesn't match", pos)
+		}
+	}
+
+	// Seeking relative to the current position and the end.
+	if _, err := s.Seek(4*1000, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	pos, err := s.Seek(4*1000, io.SeekCurrent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := pos, int64(4*2000); got != want {
+		t.Errorf("s.Seek(4*1000, io.SeekCurrent): got: %d, want: %d", got, want)
+	}
+	pos, err = s.Seek(-4*1000, io.SeekEnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := pos, s.Length()-4*1000; got != want {
+		t.Errorf("s.Seek(-4*1000, io.SeekEnd): got: %d, want: %d", got, want)
+	}
+}
+
+type reader struct {
+	r io.Reader
+}
+
+func (r *reader) Read(buf []byte) (int, error) {
+	return r.r.Read(buf)
+}
+
+func TestNonSeeker(t *testing.T) {
+	s, err := opus.DecodeWithoutResampling(&reader{r: bytes.NewReader(test_mono_opus)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := s.Length(), int64(0); got != want {
+		t.Errorf("s.Length(): got: %d, want: %d", got, want)
+	}
+
+	buf, err := io.ReadAll(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s2, err := opus.DecodeWithoutResampling(bytes.NewReader(test_mono_opus))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := io.ReadAll(s2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf, want) {
+		t.Errorf("the decoded data from a non-seeker must be the same as from a seeker")
+	}
+
+	if _, err := s.Seek(0, io.SeekStart); err == nil {
+		t.Errorf("s.Seek must return an error for a non-seeker")
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	if _, err := opus.DecodeWithoutResampling(bytes.NewReader(test_mono_opus[:20])); err == nil {
+		t.Errorf("DecodeWithoutResampling must return an error for truncated data")
+	}
+
+	bs := make([]byte, len(test_mono_opus))
+	copy(bs, test_mono_opus)
+	// Break the magic signature of the identification header.
+	bs[28] = 'X'
+	if _, err := opus.DecodeWithoutResampling(bytes.NewReader(bs)); err == nil {
+		t.Errorf("DecodeWithoutResampling must return an error for an invalid header")
+	}
+}
@@ -15,7 +15,10 @@
 package audio
 
 import (
+	"encoding/binary"
+	"fmt"
 	"io"
+	"math"
 	"runtime"
 	"sync"
 	"time"
@@ -46,15 +49,17 @@ type context interface {
 type playerFactory struct {
 	context    context
 	sampleRate int
+	bufferSize time.Duration
 
 	m sync.Mutex
 }
 
 var driverForTesting context
 
-func newPlayerFactory(sampleRate int) *playerFactory {
+func newPlayerFactory(sampleRate int, bufferSize time.Duration) *playerFactory {
 	f := &playerFactory{
 		sampleRate: sampleRate,
+		bufferSize: bufferSize,
 	}
 	if driverForTesting != nil {
 		f.context = driverForTesting
@@ -123,7 +128,7 @@ func (f *playerFactory) initContextIfNeeded() (<-chan struct{}, error) {
 		return nil, nil
 	}
 
-	c, ready, err := newContext(f.sampleRate)
+	c, ready, err := newContext(f.sampleRate, f.bufferSize)
 	if err != nil {
 		return nil, err
 	}
@@ -180,6 +185,24 @@ func (p *playerImpl) Play() {
 	p.context.addPlayer(p)
 }
 
+func (p *playerImpl) PlayAt(samplePosition int64) {
+	clock := p.context.ensureClock()
+
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if err := p.ensurePlayer(); err != nil {
+		p.context.setError(err)
+		return
+	}
+	if p.player.IsPlaying() {
+		return
+	}
+	p.stream.startAt(clock, samplePosition)
+	p.player.Play()
+	p.context.addPlayer(p)
+}
+
 func (p *playerImpl) Pause() {
 	p.m.Lock()
 	defer p.m.Unlock()
@@ -243,6 +266,11 @@ func (p *playerImpl) Close() error {
 }
 
 func (p *playerImpl) Position() time.Duration {
+	samples := p.samplePosition()
+	return time.Duration(samples) * time.Second / time.Duration(p.factory.sampleRate)
+}
+
+func (p *playerImpl) samplePosition() int64 {
 	p.m.Lock()
 	defer p.m.Unlock()
 	if err := p.ensurePlayer(); err != nil {
@@ -250,8 +278,87 @@ func (p *playerImpl) Position() time.Duration {
 		return 0
 	}
 
-	samples := (p.stream.Current() - int64(p.player.BufferedSize())) / bytesPerSampleInt16
-	return time.Duration(samples) * time.Second / time.Duration(p.factory.sampleRate)
+	pos, _ := p.stream.playedPosition(int64(p.player.BufferedSize()))
+	return pos / bytesPerSampleInt16
+}
+
+func (p *playerImpl) PlaybackRate() float64 {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if err := p.ensurePlayer(); err != nil {
+		p.context.setError(err)
+		return 1
+	}
+	return p.stream.PlaybackRate()
+}
+
+func (p *playerImpl) SetPlaybackRate(rate float64) {
+	if !(rate > 0) || math.IsInf(rate, 0) {
+		panic(fmt.Sprintf("audio: rate must be positive and finite but %f at SetPlaybackRate", rate))
+	}
+
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if err := p.ensurePlayer(); err != nil {
+		p.context.setError(err)
+		return
+	}
+	if !p.stream.setPlaybackRate(rate) {
+		return
+	}
+
+	// Discard the buffered data to apply the new rate immediately.
+	// This is possible only when the source is seekable.
+	if _, ok := p.src.(io.Seeker); !ok {
+		return
+	}
+	pos, waiting := p.stream.playedPosition(int64(p.player.BufferedSize()))
+	if waiting {
+		// Seeking would cancel the wait by PlayAt.
+		return
+	}
+	if _, err := p.player.Seek(pos, io.SeekStart); err != nil {
+		p.context.setError(err)
+	}
+}
+
+func (p *playerImpl) Pan() float64 {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if err := p.ensurePlayer(); err != nil {
+		p.context.setError(err)
+		return 0
+	}
+	return p.stream.Pan()
+}
+
+func (p *playerImpl) SetPan(pan float64) {
+	if !(pan >= -1 && pan <= 1) {
+		panic(fmt.Sprintf("audio: pan must be in between -1 and 1 but %f at SetPan", pan))
+	}
+
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if err := p.ensurePlayer(); err != nil {
+		p.context.setError(err)
+		return
+	}
+	p.stream.SetPan(pan)
+}
+
+func (p *playerImpl) SetEffect(effect Effect) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if err := p.ensurePlayer(); err != nil {
+		p.context.setError(err)
+		return
+	}
+	p.stream.SetEffect(effect)
 }
 
 func (p *playerImpl) Rewind() error {
@@ -305,15 +412,48 @@ type timeStream struct {
 	sampleRate int
 	pos        int64
 
+	// clock and startSample are the clock and its sample position to start the source at.
+	// The number of silent samples is determined at the first Read after startAt.
+	clock       *playerImpl
+	startSample int64
+
+	// silence is the number of bytes of silence to be read before the source.
+	silence int64
+
+	playbackRate float64
+	resampler    *resampler
+
+	// pan is the stereo panning.
+	// prevPan is the panning applied at the end of the last Read, which is used to change the panning smoothly.
+	pan     float64
+	prevPan float64
+
+	effect    Effect
+	effectBuf []float32
+
+	// chunks records the numbers of the source bytes for the recent read bytes.
+	// chunks is used to calculate the played position in the source from the buffered size.
+	chunks     []streamChunk
+	chunksSize int64
+
 	// m is a mutex for this stream.
 	// All the exported functions are protected by this mutex as Read can be read from a different goroutine than Seek.
 	m sync.Mutex
 }
 
+type streamChunk struct {
+	// size is the number of the read bytes.
+	size int64
+
+	// srcSize is the number of the bytes consumed from the source for the read bytes.
+	srcSize int64
+}
+
 func newTimeStream(r io.Reader, sampleRate int) (*timeStream, error) {
 	s := &timeStream{
-		r:          r,
-		sampleRate: sampleRate,
+		r:            r,
+		sampleRate:   sampleRate,
+		playbackRate: 1,
 	}
 	if seeker, ok := s.r.(io.Seeker); ok {
 		// Get the current position of the source.
@@ -330,11 +470,232 @@ func (s *timeStream) Read(buf []byte) (int, error) {
 	s.m.Lock()
 	defer s.m.Unlock()
 
+	if s.clock != nil {
+		if n := s.startSample - s.clock.samplePosition(); n > 0 {
+			s.silence = n * bytesPerSampleInt16
+		}
+		s.clock = nil
+	}
+	if s.silence > 0 {
+		n := len(buf)
+		if int64(n) > s.silence {
+			n = int(s.silence)
+		}
+		for i := range buf[:n] {
+			buf[i] = 0
+		}
+		s.silence -= int64(n)
+		s.appendChunk(int64(n), 0)
+		return n, nil
+	}
+
+	// The resampler is used also for panning, as the resampler's results are aligned with samples.
+	direct := s.playbackRate == 1 && s.pan == 0 && s.prevPan == 0 && s.effect == nil
+	if s.resampler == nil && !direct {
+		s.resampler = &resampler{}
+	}
+	if s.resampler != nil {
+		n, consumed, err := s.resampler.read(s.r, buf, s.playbackRate, direct)
+		s.pos += consumed
+		s.appendChunk(int64(n), consumed)
+		s.applyPan(buf[:n])
+		s.applyEffect(buf[:n])
+		if direct && s.resampler.isEmpty() {
+			s.resampler = nil
+		}
+		if n > 0 || err != nil || s.resampler != nil {
+			return n, err
+		}
+		// The resampler is no longer needed. Read the source directly.
+	}
+
 	n, err := s.r.Read(buf)
 	s.pos += int64(n)
+	s.appendChunk(int64(n), int64(n))
 	return n, err
 }
 
+// applyPan applies the panning to buf.
+//
+// The panning changes linearly from the previous one over buf to avoid clicking noises.
+func (s *timeStream) applyPan(buf []byte) {
+	if s.pan == 0 && s.prevPan == 0 {
+		return
+	}
+
+	n := len(buf) / bytesPerSampleInt16
+	if n == 0 {
+		return
+	}
+	for i := 0; i < n; i++ {
+		pan := s.prevPan + (s.pan-s.prevPan)*float64(i+1)/float64(n)
+		l, r := panToVolumes(pan)
+		for ch, v := range [channelCount]float64{l, r} {
+			if v == 1 {
+				continue
+			}
+			idx := i*bytesPerSampleInt16 + ch*bitDepthInBytesInt16
+			sample := int16(binary.LittleEndian.Uint16(buf[idx:]))
+			binary.LittleEndian.PutUint16(buf[idx:], uint16(int16(float64(sample)*v)))
+		}
+	}
+	s.prevPan = s.pan
+}
+
+// applyEffect applies the effect to buf.
+func (s *timeStream) applyEffect(buf []byte) {
+	if s.effect == nil {
+		return
+	}
+
+	n := len(buf) / bitDepthInBytesInt16
+	if n == 0 {
+		return
+	}
+	if cap(s.effectBuf) < n {
+		s.effectBuf = make([]float32, n)
+	}
+	samples := s.effectBuf[:n]
+	for i := range samples {
+		samples[i] = float32(int16(binary.LittleEndian.Uint16(buf[2*i:]))) / (1 << 15)
+	}
+	s.effect.ProcessAudio(samples, s.sampleRate, channelCount)
+	for i, v := range samples {
+		// Converting NaN to an integer is platform-dependent.
+		if math.IsNaN(float64(v)) {
+			v = 0
+		}
+		v *= 1 << 15
+		if v > (1<<15)-1 {
+			v = (1 << 15) - 1
+		}
+		if v < -(1 << 15) {
+			v = -(1 << 15)
+		}
+		binary.LittleEndian.PutUint16(buf[2*i:], uint16(int16(v)))
+	}
+}
+
+// panToVolumes returns the volumes of the left and right channels for the panning.
+//
+// The panning works as a balance control: the channel on the opposite side is attenuated.
+func panToVolumes(pan float64) (float64, float64) {
+	if pan < 0 {
+		return 1, 1 + pan
+	}
+	return 1 - pan, 1
+}
+
+func (s *timeStream) appendChunk(size, srcSize int64) {
+	if size == 0 {
+		return
+	}
+
+	// Merge the chunk with the last one if their ratios are the same.
+	if len(s.chunks) > 0 {
+		if last := &s.chunks[len(s.chunks)-1]; last.srcSize*size == srcSize*last.size {
+			last.size += size
+			last.srcSize += srcSize
+		} else {
+			s.chunks = append(s.chunks, streamChunk{size: size, srcSize: srcSize})
+		}
+	} else {
+		s.chunks = append(s.chunks, streamChunk{size: size, srcSize: srcSize})
+	}
+	s.chunksSize += size
+
+	// Keep the chunks for enough long time that covers the player's buffer.
+	maxSize := int64(s.sampleRate) * bytesPerSampleInt16 * 10
+	for s.chunksSize > maxSize {
+		c := &s.chunks[0]
+		excess := s.chunksSize - maxSize
+		if c.size <= excess {
+			s.chunksSize -= c.size
+			s.chunks = s.chunks[1:]
+			continue
+		}
+		c.srcSize -= c.srcSize * excess / c.size
+		c.size -= excess
+		s.chunksSize -= excess
+	}
+}
+
+// playedPosition returns the position in the source that is already played, with the given buffered size.
+//
+// playedPosition also returns whether the stream is waiting to start by PlayAt.
+func (s *timeStream) playedPosition(buffered int64) (int64, bool) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	waiting := s.clock != nil || s.silence > 0
+	var srcSize int64
+	for i := len(s.chunks) - 1; i >= 0 && buffered > 0; i-- {
+		c := s.chunks[i]
+		if c.srcSize == 0 {
+			waiting = true
+		}
+		if c.size <= buffered {
+			srcSize += c.srcSize
+			buffered -= c.size
+			continue
+		}
+		srcSize += c.srcSize * buffered / c.size
+		buffered = 0
+	}
+	srcSize += buffered
+	return s.pos - srcSize, waiting
+}
+
+// startAt makes the stream start the source when clock reaches the sample position.
+func (s *timeStream) startAt(clock *playerImpl, samplePosition int64) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.clock = clock
+	s.startSample = samplePosition
+	s.silence = 0
+}
+
+func (s *timeStream) PlaybackRate() float64 {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return s.playbackRate
+}
+
+// setPlaybackRate sets the playback rate and reports whether the rate is changed.
+func (s *timeStream) setPlaybackRate(rate float64) bool {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.playbackRate == rate {
+		return false
+	}
+	s.playbackRate = rate
+	return true
+}
+
+func (s *timeStream) Pan() float64 {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return s.pan
+}
+
+func (s *timeStream) SetPan(pan float64) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.pan = pan
+}
+
+func (s *timeStream) SetEffect(effect Effect) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.effect = effect
+}
+
 func (s *timeStream) Seek(offset int64, whence int) (int64, error) {
 	s.m.Lock()
 	defer s.m.Unlock()
@@ -350,6 +711,12 @@ func (s *timeStream) Seek(offset int64, whence int) (int64, error) {
 	}
 
 	s.pos = pos
+	s.clock = nil
+	s.silence = 0
+	s.resampler = nil
+	s.prevPan = s.pan
+	s.chunks = s.chunks[:0]
+	s.chunksSize = 0
 	return pos, nil
 }
 
@@ -366,9 +733,12 @@ func (s *timeStream) timeDurationToPos(offset time.Duration) int64 {
 	return o
 }
 
-func (s *timeStream) Current() int64 {
-	s.m.Lock()
-	defer s.m.Unlock()
+// silentStream is an endless stream of silence.
+type silentStream struct{}
 
-	return s.pos
+func (silentStream) Read(buf []byte) (int, error) {
+	for i := range buf {
+		buf[i] = 0
+	}
+	return len(buf), nil
 }
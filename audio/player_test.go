@@ -0,0 +1,317 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio_test
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+const (
+	testSampleRate = 44100
+	bytesPerSample = 4
+)
+
+// newSamples returns stereo PCM bytes of n samples, where the value of each channel of the i-th sample is f(i).
+func newSamples(n int, f func(i int) int16) []byte {
+	buf := make([]byte, n*bytesPerSample)
+	for i := 0; i < n; i++ {
+		v := uint16(f(i))
+		binary.LittleEndian.PutUint16(buf[bytesPerSample*i:], v)
+		binary.LittleEndian.PutUint16(buf[bytesPerSample*i+2:], v)
+	}
+	return buf
+}
+
+// sampleAt returns the left and right values of the i-th sample in buf.
+func sampleAt(buf []byte, i int) (int16, int16) {
+	return int16(binary.LittleEndian.Uint16(buf[bytesPerSample*i:])), int16(binary.LittleEndian.Uint16(buf[bytesPerSample*i+2:]))
+}
+
+func setupManualReading(t *testing.T) *audio.Context {
+	audio.SetManualReadingForTesting(true)
+	t.Cleanup(func() {
+		audio.SetManualReadingForTesting(false)
+		audio.ResetContextForTesting()
+	})
+	return audio.NewContext(testSampleRate)
+}
+
+func TestPlayAt(t *testing.T) {
+	context := setupManualReading(t)
+
+	// Advance the clock by 100 samples.
+	if got, want := context.SamplePosition(), int64(0); got != want {
+		t.Errorf("SamplePosition(): got: %d, want: %d", got, want)
+	}
+	if _, err := audio.ReadClockForTesting(context, make([]byte, 100*bytesPerSample)); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := context.SamplePosition(), int64(100); got != want {
+		t.Errorf("SamplePosition(): got: %d, want: %d", got, want)
+	}
+
+	p := context.NewPlayerFromBytes(newSamples(1000, func(i int) int16 {
+		return 0x1000
+	}))
+	p.PlayAt(300)
+
+	// 200 samples of silence are inserted before the source.
+	buf := make([]byte, 250*bytesPerSample)
+	if _, err := audio.ReadPlayerForTesting(p, buf); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 250; i++ {
+		var want int16
+		if i >= 200 {
+			want = 0x1000
+		}
+		if l, r := sampleAt(buf, i); l != want || r != want {
+			t.Errorf("sample %d: got: (%d, %d), want: (%d, %d)", i, l, r, want, want)
+		}
+	}
+
+	// The silence is counted neither by the player's position nor by the audio clock.
+	if got, want := p.Position(), time.Duration(50)*time.Second/testSampleRate; got != want {
+		t.Errorf("Position(): got: %v, want: %v", got, want)
+	}
+	if got, want := context.SamplePosition(), int64(100); got != want {
+		t.Errorf("SamplePosition(): got: %d, want: %d", got, want)
+	}
+}
+
+func TestPlayAtPast(t *testing.T) {
+	context := setupManualReading(t)
+
+	if _, err := audio.ReadClockForTesting(context, make([]byte, 100*bytesPerSample)); err != nil {
+		t.Fatal(err)
+	}
+
+	// A sample position in the past starts the player immediately.
+	p := context.NewPlayerFromBytes(newSamples(100, func(i int) int16 {
+		return 0x1000
+	}))
+	p.PlayAt(50)
+
+	buf := make([]byte, 10*bytesPerSample)
+	if _, err := audio.ReadPlayerForTesting(p, buf); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		if l, r := sampleAt(buf, i); l != 0x1000 || r != 0x1000 {
+			t.Errorf("sample %d: got: (%d, %d), want: (%d, %d)", i, l, r, 0x1000, 0x1000)
+		}
+	}
+}
+
+func TestPositionWithPlaybackRate(t *testing.T) {
+	context := setupManualReading(t)
+
+	p := context.NewPlayerFromBytes(newSamples(testSampleRate, func(i int) int16 {
+		return int16(i)
+	}))
+	p.Play()
+
+	// At the double rate, 100 samples consume 200 samples of the source.
+	p.SetPlaybackRate(2)
+	buf := make([]byte, 100*bytesPerSample)
+	if _, err := audio.ReadPlayerForTesting(p, buf); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := p.Position(), time.Duration(200)*time.Second/testSampleRate; got != want {
+		t.Errorf("Position(): got: %v, want: %v", got, want)
+	}
+
+	// Back to the normal rate.
+	p.SetPlaybackRate(1)
+	if _, err := audio.ReadPlayerForTesting(p, buf); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := p.Position(), time.Duration(300)*time.Second/testSampleRate; got != want {
+		t.Errorf("Position(): got: %v, want: %v", got, want)
+	}
+	for i := 0; i < 100; i++ {
+		want := int16(200 + i)
+		if l, r := sampleAt(buf, i); l != want || r != want {
+			t.Errorf("sample %d: got: (%d, %d), want: (%d, %d)", i, l, r, want, want)
+		}
+	}
+}
+
+func TestPanToVolumes(t *testing.T) {
+	testCases := []struct {
+		Pan   float64
+		Left  float64
+		Right float64
+	}{
+		{Pan: -1, Left: 1, Right: 0},
+		{Pan: -0.5, Left: 1, Right: 0.5},
+		{Pan: 0, Left: 1, Right: 1},
+		{Pan: 0.5, Left: 0.5, Right: 1},
+		{Pan: 1, Left: 0, Right: 1},
+	}
+	for _, tc := range testCases {
+		l, r := audio.PanToVolumesForTesting(tc.Pan)
+		if l != tc.Left || r != tc.Right {
+			t.Errorf("panToVolumes(%v): got: (%v, %v), want: (%v, %v)", tc.Pan, l, r, tc.Left, tc.Right)
+		}
+	}
+}
+
+func TestPanSmoothing(t *testing.T) {
+	context := setupManualReading(t)
+
+	const v = 0x4000
+	p := context.NewPlayerFromBytes(newSamples(testSampleRate, func(i int) int16 {
+		return v
+	}))
+	p.Play()
+
+	testCases := []struct {
+		Pan float64
+		// Left and Right are the volumes of the read samples.
+		Left  []float64
+		Right []float64
+	}{
+		// The panning changes linearly from the previous panning over one read.
+		{
+			Pan:   1,
+			Left:  []float64{0.75, 0.5, 0.25, 0},
+			Right: []float64{1, 1, 1, 1},
+		},
+		// The panning is kept after the change.
+		{
+			Pan:   1,
+			Left:  []float64{0, 0, 0, 0},
+			Right: []float64{1, 1, 1, 1},
+		},
+		{
+			Pan:   -1,
+			Left:  []float64{0.5, 1, 1, 1},
+			Right: []float64{1, 1, 0.5, 0},
+		},
+		{
+			Pan:   0,
+			Left:  []float64{1, 1, 1, 1},
+			Right: []float64{0.25, 0.5, 0.75, 1},
+		},
+	}
+	for i, tc := range testCases {
+		p.SetPan(tc.Pan)
+		buf := make([]byte, len(tc.Left)*bytesPerSample)
+		if _, err := audio.ReadPlayerForTesting(p, buf); err != nil {
+			t.Fatal(err)
+		}
+		for j := range tc.Left {
+			l, r := sampleAt(buf, j)
+			if want := int16(v * tc.Left[j]); l != want {
+				t.Errorf("read %d, sample %d: left: got: %d, want: %d", i, j, l, want)
+			}
+			if want := int16(v * tc.Right[j]); r != want {
+				t.Errorf("read %d, sample %d: right: got: %d, want: %d", i, j, r, want)
+			}
+		}
+	}
+}
+
+type funcEffect func(samples []float32, sampleRate int, channelCount int)
+
+func (f funcEffect) ProcessAudio(samples []float32, sampleRate int, channelCount int) {
+	f(samples, sampleRate, channelCount)
+}
+
+func TestEffect(t *testing.T) {
+	context := setupManualReading(t)
+
+	const v = 0x2000
+	p := context.NewPlayerFromBytes(newSamples(testSampleRate, func(i int) int16 {
+		return v
+	}))
+	p.Play()
+
+	testCases := []struct {
+		Name   string
+		Effect audio.Effect
+		Left   int16
+		Right  int16
+	}{
+		{
+			Name:   "nil",
+			Effect: nil,
+			Left:   v,
+			Right:  v,
+		},
+		{
+			Name: "mute the left channel and double the right channel",
+			Effect: funcEffect(func(samples []float32, sampleRate int, channelCount int) {
+				if sampleRate != testSampleRate {
+					t.Errorf("sampleRate: got: %d, want: %d", sampleRate, testSampleRate)
+				}
+				if channelCount != 2 {
+					t.Errorf("channelCount: got: %d, want: %d", channelCount, 2)
+				}
+				for i := range samples {
+					if i%2 == 0 {
+						samples[i] = 0
+					} else {
+						samples[i] *= 2
+					}
+				}
+			}),
+			Left:  0,
+			Right: 2 * v,
+		},
+		{
+			Name: "clamping",
+			Effect: funcEffect(func(samples []float32, sampleRate int, channelCount int) {
+				for i := range samples {
+					if i%2 == 0 {
+						samples[i] = 10
+					} else {
+						samples[i] = -10
+					}
+				}
+			}),
+			Left:  0x7fff,
+			Right: -0x8000,
+		},
+		{
+			Name: "NaN",
+			Effect: funcEffect(func(samples []float32, sampleRate int, channelCount int) {
+				for i := range samples {
+					samples[i] = float32(math.NaN())
+				}
+			}),
+			Left:  0,
+			Right: 0,
+		},
+	}
+	for _, tc := range testCases {
+		p.SetEffect(tc.Effect)
+		buf := make([]byte, 16*bytesPerSample)
+		if _, err := audio.ReadPlayerForTesting(p, buf); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 16; i++ {
+			if l, r := sampleAt(buf, i); l != tc.Left || r != tc.Right {
+				t.Errorf("%s: sample %d: got: (%d, %d), want: (%d, %d)", tc.Name, i, l, r, tc.Left, tc.Right)
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// resampler changes the playback rate of a source with linear interpolation.
+//
+// Like a tape, changing the playback rate changes the pitch too.
+type resampler struct {
+	// frames is the source samples that are not consumed yet.
+	frames []int16
+
+	// pos is the position in the frames in samples.
+	pos float64
+
+	// rest is the source bytes that don't make a sample yet.
+	rest []byte
+
+	tmp []byte
+	err error
+}
+
+// read reads the resampled bytes into buf with the given playback rate.
+//
+// If drain is true, read doesn't read the source any more and only consumes the samples that are already read.
+// drain is available only when rate is 1.
+//
+// read returns the number of the read bytes and the number of the consumed bytes of the source.
+func (r *resampler) read(src io.Reader, buf []byte, rate float64, drain bool) (int, int64, error) {
+	if rate == 1 {
+		// Stop interpolating so that the resampler can be empty.
+		r.pos = float64(int(r.pos))
+	}
+
+	var n int
+	for n+bytesPerSampleInt16 <= len(buf) {
+		i := int(r.pos)
+		f := r.pos - float64(i)
+		count := len(r.frames) / channelCount
+
+		if i >= count || (f > 0 && i+1 >= count) {
+			if drain && rate == 1 && len(r.rest) == 0 {
+				// The source can be read directly without the resampler.
+				break
+			}
+			if r.err == nil {
+				if r.fill(src) {
+					continue
+				}
+				break
+			}
+			if i >= count {
+				break
+			}
+			// There is no next sample at the end of the source.
+			f = 0
+		}
+
+		for ch := 0; ch < channelCount; ch++ {
+			v := float64(r.frames[i*channelCount+ch])
+			if f > 0 {
+				v += (float64(r.frames[(i+1)*channelCount+ch]) - v) * f
+			}
+			binary.LittleEndian.PutUint16(buf[n+ch*bitDepthInBytesInt16:], uint16(int16(v)))
+		}
+		n += bytesPerSampleInt16
+		r.pos += rate
+	}
+
+	// Remove the consumed samples.
+	consumed := int(r.pos)
+	if count := len(r.frames) / channelCount; consumed > count {
+		consumed = count
+	}
+	r.frames = r.frames[:copy(r.frames, r.frames[consumed*channelCount:])]
+	r.pos -= float64(consumed)
+	if len(r.frames) == 0 && r.err != nil {
+		r.pos = 0
+	}
+
+	var err error
+	if n == 0 {
+		err = r.err
+	}
+	return n, int64(consumed) * bytesPerSampleInt16, err
+}
+
+// fill reads the source once, and reports whether there is a progress.
+func (r *resampler) fill(src io.Reader) bool {
+	if r.tmp == nil {
+		r.tmp = make([]byte, 4096)
+	}
+	n, err := src.Read(r.tmp)
+	r.rest = append(r.rest, r.tmp[:n]...)
+
+	m := len(r.rest) / bitDepthInBytesInt16 / channelCount * channelCount * bitDepthInBytesInt16
+	for i := 0; i < m; i += bitDepthInBytesInt16 {
+		r.frames = append(r.frames, int16(binary.LittleEndian.Uint16(r.rest[i:])))
+	}
+	r.rest = r.rest[:copy(r.rest, r.rest[m:])]
+
+	if err != nil {
+		r.err = err
+	}
+	return n > 0 || err != nil
+}
+
+// isEmpty reports whether the resampler has no source samples that are not consumed.
+func (r *resampler) isEmpty() bool {
+	return len(r.frames) == 0 && len(r.rest) == 0 && r.pos == 0
+}
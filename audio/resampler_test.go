@@ -0,0 +1,153 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+func TestResamplerRates(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Rate float64
+		// Want returns the value of the i-th output sample for the source where the i-th sample is 2*i.
+		Want func(i int) int16
+	}{
+		{
+			Name: "half",
+			Rate: 0.5,
+			// The samples between the source samples are interpolated linearly.
+			Want: func(i int) int16 { return int16(i) },
+		},
+		{
+			Name: "double",
+			Rate: 2,
+			Want: func(i int) int16 { return int16(4 * i) },
+		},
+		{
+			Name: "one and a half",
+			Rate: 1.5,
+			Want: func(i int) int16 { return int16(3 * i) },
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			const n = 1000
+			src := bytes.NewReader(newSamples(n, func(i int) int16 {
+				return int16(2 * i)
+			}))
+
+			var r audio.ResamplerForTesting
+			buf := make([]byte, 100*bytesPerSample)
+			var out []byte
+			var consumed int64
+			for {
+				m, c, err := r.Read(src, buf, tc.Rate, false)
+				out = append(out, buf[:m]...)
+				consumed += c
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if got, want := consumed, int64(n*bytesPerSample); got != want {
+				t.Errorf("consumed: got: %d, want: %d", got, want)
+			}
+			// The output length is the source length divided by the rate.
+			if got, want := len(out)/bytesPerSample, int(float64(n)/tc.Rate+0.5); got < want-1 || got > want+1 {
+				t.Errorf("output samples: got: %d, want: %d", got, want)
+			}
+			for i := 0; i < len(out)/bytesPerSample; i++ {
+				// The last sample has no next sample to interpolate.
+				if float64(i)*tc.Rate > n-1 {
+					break
+				}
+				want := tc.Want(i)
+				if l, r := sampleAt(out, i); l != want || r != want {
+					t.Errorf("sample %d: got: (%d, %d), want: (%d, %d)", i, l, r, want, want)
+				}
+			}
+		})
+	}
+}
+
+func TestResamplerBackToOne(t *testing.T) {
+	const n = 2000
+	src := bytes.NewReader(newSamples(n, func(i int) int16 {
+		return int16(i)
+	}))
+
+	var r audio.ResamplerForTesting
+	buf := make([]byte, 100*bytesPerSample)
+	var out []byte
+	var consumed int64
+
+	// Read 100 samples at the double rate.
+	m, c, err := r.Read(src, buf, 2, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out = append(out, buf[:m]...)
+	consumed += c
+
+	// Switch back to 1, and drain the samples in the resampler.
+	for {
+		m, c, err := r.Read(src, buf, 1, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, buf[:m]...)
+		consumed += c
+		if m == 0 {
+			break
+		}
+	}
+	if !r.IsEmpty() {
+		t.Errorf("IsEmpty(): got: false, want: true")
+	}
+
+	// The rest of the source is read directly.
+	rest, err := io.ReadAll(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out = append(out, rest...)
+	consumed += int64(len(rest))
+
+	if got, want := consumed, int64(n*bytesPerSample); got != want {
+		t.Errorf("consumed: got: %d, want: %d", got, want)
+	}
+	if got, want := len(out)/bytesPerSample, 100+(n-200); got != want {
+		t.Fatalf("output samples: got: %d, want: %d", got, want)
+	}
+	for i := 0; i < len(out)/bytesPerSample; i++ {
+		want := int16(2 * i)
+		if i >= 100 {
+			want = int16(i + 100)
+		}
+		if l, r := sampleAt(out, i); l != want || r != want {
+			t.Errorf("sample %d: got: (%d, %d), want: (%d, %d)", i, l, r, want, want)
+		}
+	}
+}
@@ -57,6 +57,13 @@ type Blend struct {
 
 	// BlendOperationAlpha is an operation for source and destination alpha values.
 	BlendOperationAlpha BlendOperation
+
+	// BlendConstantColor is a constant color used by BlendFactorConstantColor and BlendFactorOneMinusConstantColor.
+	// The values are R, G, B, and A in this order, and each value should be in [0, 1].
+	// Unlike the source and the destination colors, BlendConstantColor doesn't have to be premultiplied alpha.
+	//
+	// BlendConstantColor is ignored when no factor uses the constant color.
+	BlendConstantColor [4]float32
 }
 
 var (
@@ -75,7 +82,7 @@ func (b Blend) internalBlend() graphicsdriver.Blend {
 	if b == (Blend{}) {
 		return defaultBlendInternalBlend
 	}
-	return graphicsdriver.Blend{
+	blend := graphicsdriver.Blend{
 		BlendFactorSourceRGB:        b.BlendFactorSourceRGB.internalBlendFactor(true),
 		BlendFactorSourceAlpha:      b.BlendFactorSourceAlpha.internalBlendFactor(true),
 		BlendFactorDestinationRGB:   b.BlendFactorDestinationRGB.internalBlendFactor(false),
@@ -83,6 +90,16 @@ func (b Blend) internalBlend() graphicsdriver.Blend {
 		BlendOperationRGB:           b.BlendOperationRGB.internalBlendOperation(),
 		BlendOperationAlpha:         b.BlendOperationAlpha.internalBlendOperation(),
 	}
+	// Keep the constant color zero when it is not used so that draw calls with different constant colors can be batched.
+	if blend.UsesConstantColor() {
+		for i, v := range b.BlendConstantColor {
+			if !(v >= 0 && v <= 1) {
+				panic(fmt.Sprintf("ebiten: BlendConstantColor must be in [0, 1] but %v", b.BlendConstantColor))
+			}
+			blend.BlendConstantColor[i] = v
+		}
+	}
+	return blend
 }
 
 // BlendFactor is a factor for source and destination color values.
@@ -143,6 +160,18 @@ const (
 	//     1 - (destination alpha)
 	BlendFactorOneMinusDestinationAlpha
 
+	// BlendFactorConstantColor is a factor:
+	//
+	//     (BlendConstantColor)
+	//
+	// For RGB values, the RGB values of BlendConstantColor are used. For alpha values, the alpha value is used.
+	BlendFactorConstantColor
+
+	// BlendFactorOneMinusConstantColor is a factor:
+	//
+	//     1 - (BlendConstantColor)
+	BlendFactorOneMinusConstantColor
+
 	// TODO: Add BlendFactorSourceAlphaSaturated. This might not work well on some platforms like Steam SDK (#2382).
 )
 
@@ -174,6 +203,10 @@ func (b BlendFactor) internalBlendFactor(source bool) graphicsdriver.BlendFactor
 		return graphicsdriver.BlendFactorDestinationAlpha
 	case BlendFactorOneMinusDestinationAlpha:
 		return graphicsdriver.BlendFactorOneMinusDestinationAlpha
+	case BlendFactorConstantColor:
+		return graphicsdriver.BlendFactorConstantColor
+	case BlendFactorOneMinusConstantColor:
+		return graphicsdriver.BlendFactorOneMinusConstantColor
 	default:
 		panic(fmt.Sprintf("ebiten: invalid blend factor: %d", b))
 	}
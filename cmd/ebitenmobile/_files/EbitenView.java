@@ -155,7 +155,9 @@ public class EbitenView extends ViewGroup implements InputManager.InputDeviceLis
             int x = (int)e.getX(i);
             int y = (int)e.getY(i);
             int action = (i == touchIndex) ? e.getActionMasked() : MotionEvent.ACTION_MOVE;
-            Ebitenmobileview.updateTouchesOnAndroid(action, id, (int)pxToDp(x), (int)pxToDp(y));
+            double pressure = e.getPressure(i);
+            double radius = pxToDp(e.getTouchMajor(i)) / 2;
+            Ebitenmobileview.updateTouchesOnAndroid(action, id, (int)pxToDp(x), (int)pxToDp(y), pressure, radius);
         }
         return true;
     }
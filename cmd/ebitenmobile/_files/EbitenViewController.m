@@ -286,7 +286,12 @@
       }
     }
     CGPoint location = [touch locationInView:touch.view];
-    EbitenmobileviewUpdateTouchesOnIOS(touch.phase, (uintptr_t)touch, location.x, location.y);
+    // maximumPossibleForce is 0 when the device doesn't support 3D Touch or Apple Pencil.
+    double pressure = 1;
+    if (touch.maximumPossibleForce > 0) {
+      pressure = touch.force / touch.maximumPossibleForce;
+    }
+    EbitenmobileviewUpdateTouchesOnIOS(touch.phase, (uintptr_t)touch, location.x, location.y, pressure, touch.majorRadius);
   }
 }
 
@@ -111,6 +111,39 @@ func (c *ColorM) ChangeHSV(hueTheta float64, saturationScale float64, valueScale
 	c.impl = affine.ChangeHSV(c.affineColorM(), hueTheta, float32(saturationScale), float32(valueScale))
 }
 
+// ScaleSaturation scales the saturation by scale.
+// 0 makes colors grayscale, and a value greater than 1 makes colors more vivid.
+//
+// ScaleSaturation is the same as ChangeHSV(0, scale, 1).
+func (c *ColorM) ScaleSaturation(scale float64) {
+	c.ChangeHSV(0, scale, 1)
+}
+
+// Grayscale makes colors grayscale by their luminance.
+//
+// Grayscale is the same as ChangeHSV(0, 0, 1).
+func (c *ColorM) Grayscale() {
+	c.ChangeHSV(0, 0, 1)
+}
+
+// sepiaElements is a commonly used matrix for the sepia tone.
+var sepiaElements = [3][3]float64{
+	{0.393, 0.769, 0.189},
+	{0.349, 0.686, 0.168},
+	{0.272, 0.534, 0.131},
+}
+
+// Sepia makes colors sepia-toned.
+func (c *ColorM) Sepia() {
+	var m ColorM
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			m.SetElement(i, j, sepiaElements[i][j])
+		}
+	}
+	c.Concat(m)
+}
+
 // Element returns a value of a matrix at (i, j).
 func (c *ColorM) Element(i, j int) float64 {
 	return float64(c.affineColorM().At(i, j))
@@ -146,6 +179,31 @@ func (c *ColorM) ReadElements(body []float32, translation []float32) {
 	c.affineColorM().Elements(body, translation)
 }
 
+// scaleOnly returns the scaling factors in premultiplied-alpha format if c is merely a scaling matrix
+// whose effect can be achieved by a color scale.
+func (c *ColorM) scaleOnly() (r, g, b, a float32, ok bool) {
+	m := c.affineColorM()
+	if m.IsIdentity() {
+		return 1, 1, 1, 1, true
+	}
+	if !m.ScaleOnly() {
+		return 0, 0, 0, 0, false
+	}
+
+	r = m.At(0, 0)
+	g = m.At(1, 1)
+	b = m.At(2, 2)
+	a = m.At(3, 3)
+
+	// A color matrix is applied to a straight-alpha color and the result is clamped.
+	// A color scale is equivalent only when the matrix doesn't make any color out of range.
+	// See also colorMToScale in the package ebiten.
+	if r < 0 || g < 0 || b < 0 || a < 0 || r > 1 || g > 1 || b > 1 {
+		return 0, 0, 0, 0, false
+	}
+	return r * a, g * a, b * a, a, true
+}
+
 func uniforms(c ColorM) map[string]any {
 	var body [16]float32
 	var translation [4]float32
@@ -145,6 +145,48 @@ func TestColorMMonochrome(t *testing.T) {
 	}
 }
 
+func TestColorMGrayscaleAndSaturation(t *testing.T) {
+	var want colorm.ColorM
+	want.ChangeHSV(0, 0, 1)
+
+	var gray colorm.ColorM
+	gray.Grayscale()
+	var sat colorm.ColorM
+	sat.ScaleSaturation(0)
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 5; j++ {
+			if got, want := gray.Element(i, j), want.Element(i, j); got != want {
+				t.Errorf("gray.Element(%d, %d) = %f, want %f", i, j, got, want)
+			}
+			if got, want := sat.Element(i, j), want.Element(i, j); got != want {
+				t.Errorf("sat.Element(%d, %d) = %f, want %f", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestColorMSepia(t *testing.T) {
+	expected := [4][5]float64{
+		{0.393, 0.769, 0.189, 0, 0.5 * 0.393},
+		{0.349, 0.686, 0.168, 0, 0.5 * 0.349},
+		{0.272, 0.534, 0.131, 0, 0.5 * 0.272},
+		{0, 0, 0, 1, 0},
+	}
+	m := colorm.ColorM{}
+	// Sepia is applied after the existing matrix.
+	m.Translate(0.5, 0, 0, 0)
+	m.Sepia()
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 5; j++ {
+			got := m.Element(i, j)
+			want := expected[i][j]
+			if math.Abs(want-got) > 0.0001 {
+				t.Errorf("m.Element(%d, %d) = %f, want %f", i, j, got, want)
+			}
+		}
+	}
+}
+
 func TestColorMConcatSelf(t *testing.T) {
 	expected := [4][5]float64{
 		{30, 40, 30, 25, 30},
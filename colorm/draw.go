@@ -37,11 +37,26 @@ type DrawImageOptions struct {
 // DrawImage draws src onto dst.
 //
 // DrawImage is basically the same as ebiten.DrawImage, but with a color matrix.
+//
+// If colorM is merely a scaling matrix like the one made by Scale, DrawImage is translated into
+// ebiten.DrawImage with a color scale, which can be batched with other draw calls.
 func DrawImage(dst, src *ebiten.Image, colorM ColorM, op *DrawImageOptions) {
 	if op == nil {
 		op = &DrawImageOptions{}
 	}
 
+	if r, g, b, a, ok := colorM.scaleOnly(); ok {
+		opImage := &ebiten.DrawImageOptions{}
+		opImage.GeoM = op.GeoM
+		opImage.ColorScale.Scale(r, g, b, a)
+		opImage.Blend = op.Blend
+		opImage.Filter = op.Filter
+		// The color matrix path never uses mipmaps. Keep the same results.
+		opImage.Mipmap = ebiten.MipmapModeDisabled
+		dst.DrawImage(src, opImage)
+		return
+	}
+
 	opShader := &ebiten.DrawRectShaderOptions{}
 	opShader.GeoM = op.GeoM
 	opShader.CompositeMode = ebiten.CompositeModeCustom
@@ -92,22 +107,48 @@ type DrawTrianglesOptions struct {
 // DrawTriangles draws triangles onto dst.
 //
 // DrawTriangles is basically the same as ebiten.DrawTriangles, but with a color matrix.
+//
+// If colorM is merely a scaling matrix like the one made by Scale, DrawTriangles is translated into
+// ebiten.DrawTriangles with scaled vertex colors, which can be batched with other draw calls.
 func DrawTriangles(dst *ebiten.Image, vertices []ebiten.Vertex, indices []uint16, img *ebiten.Image, colorM ColorM, op *DrawTrianglesOptions) {
 	if op == nil {
 		op = &DrawTrianglesOptions{}
 	}
 
-	if op.ColorScaleMode == ebiten.ColorScaleModeStraightAlpha {
+	r, g, b, a, scaleOnly := colorM.scaleOnly()
+	if op.ColorScaleMode == ebiten.ColorScaleModeStraightAlpha || scaleOnly {
 		vs := make([]ebiten.Vertex, len(vertices))
 		copy(vs, vertices)
 		for i := range vertices {
-			vs[i].ColorR *= vs[i].ColorA
-			vs[i].ColorG *= vs[i].ColorA
-			vs[i].ColorB *= vs[i].ColorA
+			if op.ColorScaleMode == ebiten.ColorScaleModeStraightAlpha {
+				vs[i].ColorR *= vs[i].ColorA
+				vs[i].ColorG *= vs[i].ColorA
+				vs[i].ColorB *= vs[i].ColorA
+			}
+			if scaleOnly {
+				vs[i].ColorR *= r
+				vs[i].ColorG *= g
+				vs[i].ColorB *= b
+				vs[i].ColorA *= a
+			}
 		}
 		vertices = vs
 	}
 
+	if scaleOnly {
+		opTriangles := &ebiten.DrawTrianglesOptions{}
+		opTriangles.ColorScaleMode = ebiten.ColorScaleModePremultipliedAlpha
+		opTriangles.Blend = op.Blend
+		opTriangles.Filter = op.Filter
+		opTriangles.Address = op.Address
+		opTriangles.FillRule = op.FillRule
+		opTriangles.AntiAlias = op.AntiAlias
+		// The color matrix path never uses mipmaps. Keep the same results.
+		opTriangles.Mipmap = ebiten.MipmapModeDisabled
+		dst.DrawTriangles(vertices, indices, img, opTriangles)
+		return
+	}
+
 	opShader := &ebiten.DrawTrianglesShaderOptions{}
 	opShader.CompositeMode = ebiten.CompositeModeCustom
 	opShader.Blend = op.Blend
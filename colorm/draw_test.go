@@ -275,6 +275,76 @@ func TestColorMAndScale(t *testing.T) {
 	}
 }
 
+func TestColorMScaleOnly(t *testing.T) {
+	const w, h = 16, 16
+	src := ebiten.NewImage(w, h)
+	src.Fill(color.RGBA{R: 0x80, G: 0x40, B: 0x20, A: 0x80})
+
+	vs := []ebiten.Vertex{
+		{SrcX: 0, SrcY: 0, DstX: 0, DstY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 0.5},
+		{SrcX: w, SrcY: 0, DstX: w, DstY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 0.5},
+		{SrcX: 0, SrcY: h, DstX: 0, DstY: h, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 0.5},
+		{SrcX: w, SrcY: h, DstX: w, DstY: h, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 0.5},
+	}
+	is := []uint16{0, 1, 2, 1, 2, 3}
+
+	for _, tc := range []struct {
+		Name  string
+		Scale [4]float64
+		Want  color.RGBA
+	}{
+		{
+			// This is drawn with a color scale instead of the color matrix shader.
+			Name:  "darker",
+			Scale: [4]float64{0.5, 1, 1, 1},
+			Want:  color.RGBA{R: 0x40, G: 0x40, B: 0x20, A: 0x80},
+		},
+		{
+			// This is drawn with the color matrix shader as the red value is clamped.
+			Name:  "brighter",
+			Scale: [4]float64{2, 1, 1, 1},
+			Want:  color.RGBA{R: 0x80, G: 0x40, B: 0x20, A: 0x80},
+		},
+	} {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			var cm colorm.ColorM
+			cm.Scale(tc.Scale[0], tc.Scale[1], tc.Scale[2], tc.Scale[3])
+
+			dst := ebiten.NewImage(w, h)
+			colorm.DrawImage(dst, src, cm, nil)
+			if got := dst.At(0, 0).(color.RGBA); !sameColors(got, tc.Want, 1) {
+				t.Errorf("DrawImage: got: %v, want: %v", got, tc.Want)
+			}
+
+			for _, format := range []ebiten.ColorScaleMode{
+				ebiten.ColorScaleModeStraightAlpha,
+				ebiten.ColorScaleModePremultipliedAlpha,
+			} {
+				// The vertex color is translucent white in both formats.
+				vs := append([]ebiten.Vertex{}, vs...)
+				if format == ebiten.ColorScaleModePremultipliedAlpha {
+					for i := range vs {
+						vs[i].ColorR = 0.5
+						vs[i].ColorG = 0.5
+						vs[i].ColorB = 0.5
+					}
+				}
+
+				dst := ebiten.NewImage(w, h)
+				op := &colorm.DrawTrianglesOptions{}
+				op.ColorScaleMode = format
+				colorm.DrawTriangles(dst, vs, is, src, cm, op)
+
+				want := color.RGBA{R: tc.Want.R / 2, G: tc.Want.G / 2, B: tc.Want.B / 2, A: tc.Want.A / 2}
+				if got := dst.At(0, 0).(color.RGBA); !sameColors(got, want, 1) {
+					t.Errorf("DrawTriangles (format%d): got: %v, want: %v", format, got, want)
+				}
+			}
+		})
+	}
+}
+
 // Issue #1213
 func TestColorMCopy(t *testing.T) {
 	const w, h = 16, 16
@@ -75,6 +75,7 @@
 //	"directx":      DirectX. This works only on Windows.
 //	"metal":        Metal. This works only on macOS or iOS.
 //	"playstation5": PlayStation 5. This works only on PlayStation 5.
+//	"software":     Software renderer without GPU. This works only with the build tag `ebitengineheadless`.
 //
 // `EBITENGINE_DIRECTX` environment variable specifies various parameters for DirectX.
 // You can specify multiple values separated by a comma. The default value is empty (i.e. no parameters).
@@ -103,6 +104,15 @@
 // `ebitenginedebug` outputs a log of graphics commands. This is useful to know what happens in Ebitengine. In general, the
 // number of graphics commands affects the performance of your game.
 //
+// `ebitengineheadless` runs the game without any displays, GPUs, or input devices.
+// The screen is rendered to an offscreen framebuffer by a software renderer (GraphicsLibrarySoftware), and you can read its pixels by
+// (*Image).ReadPixels or (*Image).At in Draw. Update is called exactly once per frame regardless of the wall clock,
+// so the rendering result doesn't depend on the machine's speed. This is useful for golden-image tests on CI.
+// The window size can still be specified by SetWindowSize, and the default size is 640x480.
+// The software renderer is deterministic and its results are byte-identical on any platforms, so golden images are stable.
+// On the other hand, the software renderer is much slower than GPU, and some shader features like dfdx are not available.
+// `ebitengineheadless` works only with desktops.
+//
 // `ebitenginegldebug` enables a debug mode for OpenGL. This is valid only when the graphics library is OpenGL.
 // This affects performance very much.
 //
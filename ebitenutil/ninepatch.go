@@ -0,0 +1,192 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// NinePatchMode represents how the edges and the center of a nine-patch are resized.
+type NinePatchMode int
+
+const (
+	// NinePatchModeStretch stretches the edges and the center.
+	NinePatchModeStretch NinePatchMode = iota
+
+	// NinePatchModeTile repeats the edges and the center in their original sizes.
+	// The last tiles are cut at the ends.
+	NinePatchModeTile
+)
+
+// DrawNinePatchOptions represents options for DrawNinePatch.
+type DrawNinePatchOptions struct {
+	// GeoM is a geometry matrix to draw.
+	// GeoM is applied to the nine-patch laid out at (0, 0)-(width, height).
+	// The default (zero) value is identity, which draws the nine-patch at (0, 0).
+	GeoM ebiten.GeoM
+
+	// ColorScale is a scale of color.
+	// The default (zero) value is identity, which is (1, 1, 1, 1).
+	ColorScale ebiten.ColorScale
+
+	// Blend is a blending way of the source color and the destination color.
+	// The default (zero) value is the regular alpha blending.
+	Blend ebiten.Blend
+
+	// Filter is a type of texture filter.
+	// The default (zero) value is ebiten.FilterNearest.
+	Filter ebiten.Filter
+
+	// Mode is the way in which how the edges and the center are resized.
+	// The corners are never resized except when the size is smaller than the total size of the corners.
+	//
+	// The default (zero) value is NinePatchModeStretch.
+	Mode NinePatchMode
+}
+
+// DrawNinePatch draws the source image src on dst as a nine-patch (9-slice) image of the size (width, height).
+//
+// src is divided into nine parts by the insets left, top, right, and bottom in pixels.
+// The four corners are drawn in their original sizes, the top and bottom edges are resized horizontally,
+// the left and right edges are resized vertically, and the center is resized in both directions.
+// This is useful to draw UI frames like buttons and panels of arbitrary sizes from one source image.
+//
+// If width is less than left + right, the left and right parts are shrunk proportionally.
+// The same applies to height, top, and bottom.
+//
+// All the parts are drawn with one DrawTrianglesRaw call unless there are too many parts.
+// With NinePatchModeTile, a small edge or center might require many tiles, and then the parts are drawn with multiple calls.
+//
+// If an inset is negative, or the sum of the insets is bigger than src's size, DrawNinePatch panics.
+//
+// options can be nil. In this case, the default options are used.
+func DrawNinePatch(dst, src *ebiten.Image, width, height float64, left, top, right, bottom int, options *DrawNinePatchOptions) {
+	b := src.Bounds()
+	if left < 0 || top < 0 || right < 0 || bottom < 0 {
+		panic(fmt.Sprintf("ebitenutil: insets at DrawNinePatch must not be negative but (%d, %d, %d, %d)", left, top, right, bottom))
+	}
+	if left+right > b.Dx() || top+bottom > b.Dy() {
+		panic(fmt.Sprintf("ebitenutil: insets at DrawNinePatch must fit the source image size %dx%d but (%d, %d, %d, %d)", b.Dx(), b.Dy(), left, top, right, bottom))
+	}
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	if options == nil {
+		options = &DrawNinePatchOptions{}
+	}
+
+	tile := options.Mode == NinePatchModeTile
+	xs := appendNinePatchSegments(nil, width, b.Min.X, b.Max.X, left, right, tile)
+	ys := appendNinePatchSegments(nil, height, b.Min.Y, b.Max.Y, top, bottom, tile)
+
+	op := &ebiten.DrawTrianglesOptions{}
+	op.ColorScaleMode = ebiten.ColorScaleModePremultipliedAlpha
+	op.Blend = options.Blend
+	op.Filter = options.Filter
+
+	// The number of the tiles is unbounded with NinePatchModeTile.
+	// Split the draw calls so that each call doesn't exceed the maximum number of vertices.
+	quadCount := len(xs) * len(ys)
+	if quadCount > maxNinePatchQuadCount {
+		quadCount = maxNinePatchQuadCount
+	}
+
+	cr, cg, cb, ca := options.ColorScale.R(), options.ColorScale.G(), options.ColorScale.B(), options.ColorScale.A()
+	vs := make([]float32, 0, 4*quadCount*ebiten.RawVertexFloatCount)
+	is := make([]uint32, 0, 6*quadCount)
+	for _, y := range ys {
+		for _, x := range xs {
+			if len(is) == 6*maxNinePatchQuadCount {
+				dst.DrawTrianglesRaw(vs, is, src, op)
+				vs = vs[:0]
+				is = is[:0]
+			}
+			n := uint32(len(vs) / ebiten.RawVertexFloatCount)
+			for _, p := range [4][2]int{{0, 0}, {1, 0}, {0, 1}, {1, 1}} {
+				dx, dy := options.GeoM.Apply(x.dst[p[0]], y.dst[p[1]])
+				vs = append(vs, float32(dx), float32(dy), float32(x.src[p[0]]), float32(y.src[p[1]]), cr, cg, cb, ca)
+			}
+			is = append(is, n, n+1, n+2, n+1, n+2, n+3)
+		}
+	}
+	dst.DrawTrianglesRaw(vs, is, src, op)
+}
+
+// maxNinePatchQuadCount is the maximum number of quadrangles in one DrawTrianglesRaw call at DrawNinePatch.
+// This is much smaller than ebiten.MaxVertexCount / 4 so that the vertex buffer doesn't become too big.
+// Consecutive draw calls are merged internally anyway.
+const maxNinePatchQuadCount = 1 << 14
+
+// ninePatchSegment is a pair of ranges in the destination and the source in one direction.
+type ninePatchSegment struct {
+	dst [2]float64
+	src [2]float64
+}
+
+// appendNinePatchSegments appends the segments in one direction to segments and returns the extended buffer.
+//
+// size is the destination size, [srcMin, srcMax) is the source range, and inset0 and inset1 are the insets at both ends.
+func appendNinePatchSegments(segments []ninePatchSegment, size float64, srcMin, srcMax int, inset0, inset1 int, tile bool) []ninePatchSegment {
+	d0, d1 := float64(inset0), float64(inset1)
+	if d0+d1 > size {
+		scale := size / (d0 + d1)
+		d0 *= scale
+		d1 *= scale
+	}
+
+	s0 := float64(srcMin)
+	s1 := float64(srcMin + inset0)
+	s2 := float64(srcMax - inset1)
+	s3 := float64(srcMax)
+
+	if d0 > 0 {
+		segments = append(segments, ninePatchSegment{
+			dst: [2]float64{0, d0},
+			src: [2]float64{s0, s1},
+		})
+	}
+
+	if size-d1-d0 > 0 && s2 > s1 {
+		if !tile {
+			segments = append(segments, ninePatchSegment{
+				dst: [2]float64{d0, size - d1},
+				src: [2]float64{s1, s2},
+			})
+		} else {
+			unit := s2 - s1
+			for x := d0; x < size-d1; x += unit {
+				l := unit
+				if x+l > size-d1 {
+					l = size - d1 - x
+				}
+				segments = append(segments, ninePatchSegment{
+					dst: [2]float64{x, x + l},
+					src: [2]float64{s1, s1 + l},
+				})
+			}
+		}
+	}
+
+	if d1 > 0 {
+		segments = append(segments, ninePatchSegment{
+			dst: [2]float64{size - d1, size},
+			src: [2]float64{s2, s3},
+		})
+	}
+	return segments
+}
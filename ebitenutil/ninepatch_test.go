@@ -0,0 +1,165 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebitenutil_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	t "github.com/hajimehoshi/ebiten/v2/internal/testing"
+)
+
+func TestMain(m *testing.M) {
+	t.MainWithRunLoop(m)
+}
+
+var (
+	ninePatchCorner = color.RGBA{R: 0xff, A: 0xff}
+	ninePatchEdge   = color.RGBA{G: 0xff, A: 0xff}
+	ninePatchCenter = color.RGBA{B: 0xff, A: 0xff}
+	ninePatchWhite  = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+)
+
+// newNinePatchSource returns a 6x6 image with 2-pixel insets.
+// The corners are red, the edges are green, and the center is blue and white stripes.
+func newNinePatchSource() *ebiten.Image {
+	// Use a sub-image to check the source bounds are respected.
+	img := ebiten.NewImage(16, 16)
+	img.Fill(color.Black)
+	src := img.SubImage(image.Rect(5, 5, 11, 11)).(*ebiten.Image)
+	for j := 0; j < 6; j++ {
+		for i := 0; i < 6; i++ {
+			edgeX := i < 2 || i >= 4
+			edgeY := j < 2 || j >= 4
+			var clr color.Color
+			switch {
+			case edgeX && edgeY:
+				clr = ninePatchCorner
+			case edgeX || edgeY:
+				clr = ninePatchEdge
+			case i == 2:
+				clr = ninePatchCenter
+			default:
+				clr = ninePatchWhite
+			}
+			src.Set(5+i, 5+j, clr)
+		}
+	}
+	return src
+}
+
+func TestDrawNinePatch(t *testing.T) {
+	src := newNinePatchSource()
+
+	dst := ebiten.NewImage(32, 16)
+	op := &ebitenutil.DrawNinePatchOptions{}
+	op.GeoM.Translate(1, 1)
+	ebitenutil.DrawNinePatch(dst, src, 20, 10, 2, 2, 2, 2, op)
+
+	for _, tc := range []struct {
+		X, Y int
+		Want color.RGBA
+	}{
+		{0, 0, color.RGBA{}},
+		{1, 1, ninePatchCorner},
+		{2, 2, ninePatchCorner},
+		{20, 10, ninePatchCorner},
+		{10, 1, ninePatchEdge},
+		{1, 5, ninePatchEdge},
+		{20, 5, ninePatchEdge},
+		{10, 10, ninePatchEdge},
+		{3, 3, ninePatchCenter},
+		{18, 8, ninePatchWhite},
+		{21, 11, color.RGBA{}},
+	} {
+		if got := dst.At(tc.X, tc.Y); got != tc.Want {
+			t.Errorf("dst.At(%d, %d): got: %v, want: %v", tc.X, tc.Y, got, tc.Want)
+		}
+	}
+}
+
+func TestDrawNinePatchTile(t *testing.T) {
+	src := newNinePatchSource()
+
+	dst := ebiten.NewImage(32, 16)
+	op := &ebitenutil.DrawNinePatchOptions{}
+	op.Mode = ebitenutil.NinePatchModeTile
+	ebitenutil.DrawNinePatch(dst, src, 11, 8, 2, 2, 2, 2, op)
+
+	// The center is repeated every 2 pixels, and the last tile is cut.
+	for i, want := range []color.RGBA{ninePatchCorner, ninePatchCorner, ninePatchCenter, ninePatchWhite, ninePatchCenter, ninePatchWhite, ninePatchCenter, ninePatchWhite, ninePatchCenter, ninePatchCorner, ninePatchCorner, {}} {
+		y := 0
+		switch want {
+		case ninePatchCenter, ninePatchWhite:
+			y = 3
+		}
+		if got := dst.At(i, y); got != want {
+			t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, y, got, want)
+		}
+	}
+}
+
+func TestDrawNinePatchSmallerThanCorners(t *testing.T) {
+	src := newNinePatchSource()
+
+	dst := ebiten.NewImage(16, 16)
+	ebitenutil.DrawNinePatch(dst, src, 2, 2, 2, 2, 2, 2, nil)
+
+	// The corners are shrunk to fit the size.
+	for j := 0; j < 3; j++ {
+		for i := 0; i < 3; i++ {
+			want := ninePatchCorner
+			if i == 2 || j == 2 {
+				want = color.RGBA{}
+			}
+			if got := dst.At(i, j); got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestDrawNinePatchTileManyTiles(t *testing.T) {
+	// A 3x3 source image with 1-pixel insets has a 1-pixel center.
+	src := ebiten.NewImage(3, 3)
+	src.Fill(ninePatchEdge)
+	src.Set(1, 1, ninePatchCenter)
+
+	// The number of the center tiles is much more than the number of the vertices for one draw call.
+	const size = 300
+	dst := ebiten.NewImage(size, size)
+	op := &ebitenutil.DrawNinePatchOptions{}
+	op.Mode = ebitenutil.NinePatchModeTile
+	ebitenutil.DrawNinePatch(dst, src, size, size, 1, 1, 1, 1, op)
+
+	pix := make([]byte, 4*size*size)
+	dst.ReadPixels(pix)
+	for j := 0; j < size; j++ {
+		for i := 0; i < size; i++ {
+			want := ninePatchCenter
+			if i == 0 || j == 0 || i == size-1 || j == size-1 {
+				want = ninePatchEdge
+			}
+			idx := 4 * (j*size + i)
+			got := color.RGBA{R: pix[idx], G: pix[idx+1], B: pix[idx+2], A: pix[idx+3]}
+			if got != want {
+				t.Fatalf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
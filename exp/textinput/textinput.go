@@ -19,6 +19,8 @@
 package textinput
 
 import (
+	"image"
+	"sync"
 	"unicode/utf16"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/ui"
@@ -48,6 +50,55 @@ func Start(x, y int) (states chan State, close func()) {
 	return theTextInput.Start(int(cx), int(cy))
 }
 
+// SetTextInputRect sets the region of the text being input in the logical coordinate.
+//
+// An IME shows its candidate window around the region, typically just below the region.
+// Call SetTextInputRect after Start whenever the position of the text being input changes, e.g., when the caret moves.
+//
+// SetTextInputRect does nothing if the current environment doesn't support this package.
+func SetTextInputRect(rect image.Rectangle) {
+	x0, y0 := ui.Get().LogicalPositionToClientPosition(float64(rect.Min.X), float64(rect.Min.Y))
+	x1, y1 := ui.Get().LogicalPositionToClientPosition(float64(rect.Max.X), float64(rect.Max.Y))
+	theTextInput.setRect(int(x0), int(y0), int(x1-x0), int(y1-y0))
+}
+
+// Composition returns the current state of the text being composed by an IME, i.e., the pre-edit text and its selection.
+//
+// Composition returns false if there is no active text inputting session, or the current text is already committed.
+//
+// Composition is useful to render the text being composed, e.g., with an underline and a caret, at every frame
+// without keeping the last state received from the channel Start returns.
+//
+// Composition is concurrent-safe.
+func Composition() (State, bool) {
+	theComposition.m.Lock()
+	defer theComposition.m.Unlock()
+	return theComposition.state, theComposition.valid
+}
+
+// composition holds the last uncommitted state of text inputting.
+type composition struct {
+	state State
+	valid bool
+	m     sync.Mutex
+}
+
+var theComposition composition
+
+func (c *composition) set(state State) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.state = state
+	c.valid = !state.Committed
+}
+
+func (c *composition) reset() {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.state = State{}
+	c.valid = false
+}
+
 func convertUTF16CountToByteCount(text string, c int) int {
 	return len(string(utf16.Decode(utf16.Encode([]rune(text))[:c])))
 }
@@ -71,9 +122,11 @@ func (s *session) end() {
 	close(s.ch)
 	s.ch = nil
 	close(s.done)
+	theComposition.reset()
 }
 
 func (s *session) trySend(state State) {
+	theComposition.set(state)
 	for {
 		select {
 		case s.ch <- state:
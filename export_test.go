@@ -14,6 +14,22 @@
 
 package ebiten
 
+import (
+	"github.com/hajimehoshi/ebiten/v2/internal/restorable"
+	"github.com/hajimehoshi/ebiten/v2/internal/ui"
+)
+
 var (
-	ImageToBytes = imageToBytes
+	ImageToBytes              = imageToBytes
+	ImageToBytesWithAlphaMode = imageToBytesWithAlphaMode
 )
+
+// ResolvePendingPixelsForTesting ends the current frame, flushes the graphics commands,
+// and resolves the pending pixels requested by ReadPixelsOnNextFrame.
+func ResolvePendingPixelsForTesting() {
+	thePendingPixelsQueue.endFrame()
+	if err := restorable.FlushCommands(ui.Get().GraphicsDriverForTesting()); err != nil {
+		panic(err)
+	}
+	thePendingPixelsQueue.resolve()
+}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"image"
 	"math"
+	"sync"
 	"sync/atomic"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/atlas"
@@ -61,6 +62,8 @@ func setScreenFilterEnabled(enabled bool) {
 	atomic.StoreInt32(&screenFilterEnabled, v)
 }
 
+var theGameForUI atomic.Value
+
 type gameForUI struct {
 	game         Game
 	offscreen    *Image
@@ -68,6 +71,9 @@ type gameForUI struct {
 	screenShader *Shader
 	imageDumper  imageDumper
 	transparent  bool
+
+	// offscreenM protects offscreen from being accessed from other goroutines than the game's.
+	offscreenM sync.Mutex
 }
 
 func newGameForUI(game Game, transparent bool) *gameForUI {
@@ -82,10 +88,14 @@ func newGameForUI(game Game, transparent bool) *gameForUI {
 	}
 	g.screenShader = s
 
+	theGameForUI.Store(g)
 	return g
 }
 
 func (g *gameForUI) NewOffscreenImage(width, height int) *ui.Image {
+	g.offscreenM.Lock()
+	defer g.offscreenM.Unlock()
+
 	if g.offscreen != nil {
 		g.offscreen.Deallocate()
 		g.offscreen = nil
@@ -139,6 +149,7 @@ func (g *gameForUI) UpdateInputState(fn func(*ui.InputState)) {
 }
 
 func (g *gameForUI) Update() error {
+	thePendingPixelsQueue.resolve()
 	if err := g.game.Update(); err != nil {
 		return err
 	}
@@ -149,7 +160,9 @@ func (g *gameForUI) Update() error {
 }
 
 func (g *gameForUI) DrawOffscreen() error {
+	thePendingPixelsQueue.resolve()
 	g.game.Draw(g.offscreen)
+	thePendingPixelsQueue.endFrame()
 	if err := g.imageDumper.dump(g.offscreen, g.transparent); err != nil {
 		return err
 	}
@@ -184,3 +197,26 @@ func (g *gameForUI) DrawFinalScreen(scale, offsetX, offsetY float64) {
 		g.screen.DrawRectShader(w, h, g.screenShader, op)
 	}
 }
+
+// captureOffscreen returns a copy of the offscreen image's pixels.
+// If the screen is not transparent, the pixels are composited on a black background.
+//
+// captureOffscreen returns nil if the offscreen image doesn't exist yet.
+func (g *gameForUI) captureOffscreen() *image.RGBA {
+	g.offscreenM.Lock()
+	defer g.offscreenM.Unlock()
+
+	if g.offscreen == nil {
+		return nil
+	}
+
+	img := image.NewRGBA(g.offscreen.Bounds())
+	g.offscreen.ReadPixels(img.Pix)
+	if !g.transparent {
+		// As the pixels are premultiplied alpha, compositing them on a black background just means making them opaque.
+		for i := 3; i < len(img.Pix); i += 4 {
+			img.Pix[i] = 0xff
+		}
+	}
+	return img
+}
@@ -101,3 +101,35 @@ const (
 	StandardGamepadAxisRightStickVertical   StandardGamepadAxis = gamepaddb.StandardAxisRightStickVertical
 	StandardGamepadAxisMax                  StandardGamepadAxis = StandardGamepadAxisRightStickVertical
 )
+
+// GamepadBatteryStatus represents a battery status of a gamepad.
+type GamepadBatteryStatus = gamepad.BatteryStatus
+
+// GamepadBatteryStatuses
+const (
+	// GamepadBatteryStatusUnknown indicates that the battery status is not available.
+	GamepadBatteryStatusUnknown GamepadBatteryStatus = gamepad.BatteryStatusUnknown
+
+	// GamepadBatteryStatusNotPresent indicates that the gamepad doesn't have a battery, e.g., a wired gamepad.
+	GamepadBatteryStatusNotPresent GamepadBatteryStatus = gamepad.BatteryStatusNotPresent
+
+	// GamepadBatteryStatusDischarging indicates that the battery is being used.
+	GamepadBatteryStatusDischarging GamepadBatteryStatus = gamepad.BatteryStatusDischarging
+
+	// GamepadBatteryStatusCharging indicates that the battery is being charged.
+	GamepadBatteryStatusCharging GamepadBatteryStatus = gamepad.BatteryStatusCharging
+
+	// GamepadBatteryStatusNotCharging indicates that the gamepad is powered but the battery is neither charging nor discharging,
+	// e.g., the battery is fully charged.
+	GamepadBatteryStatusNotCharging GamepadBatteryStatus = gamepad.BatteryStatusNotCharging
+)
+
+// GamepadConnectionType represents how a gamepad is connected.
+type GamepadConnectionType = gamepad.ConnectionType
+
+// GamepadConnectionTypes
+const (
+	GamepadConnectionTypeUnknown  GamepadConnectionType = gamepad.ConnectionTypeUnknown
+	GamepadConnectionTypeWired    GamepadConnectionType = gamepad.ConnectionTypeWired
+	GamepadConnectionTypeWireless GamepadConnectionType = gamepad.ConnectionTypeWireless
+)
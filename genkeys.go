@@ -878,7 +878,7 @@ func main() {
 		case filepath.Join("internal", "ui", "keys_mobile.go"):
 			buildTag = "//go:build android || ios"
 		case filepath.Join("internal", "ui", "keys_glfw.go"):
-			buildTag = "//go:build !android && !ios && !js && !nintendosdk && !playstation5"
+			buildTag = "//go:build !android && !ios && !js && !nintendosdk && !playstation5 && !ebitengineheadless"
 		}
 		// NOTE: According to godoc, maps are automatically sorted by key.
 		if err := tmpl.Execute(f, struct {
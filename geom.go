@@ -165,6 +165,11 @@ func (g *GeoM) Skew(skewX, skewY float64) {
 	g.ty = ty
 }
 
+// isIntegerTranslation reports whether g is a translation by integers without scaling, rotation, or skewing.
+func (g *GeoM) isIntegerTranslation() bool {
+	return g.a_1 == 0 && g.b == 0 && g.c == 0 && g.d_1 == 0 && g.tx == math.Trunc(g.tx) && g.ty == math.Trunc(g.ty)
+}
+
 func (g *GeoM) det2x2() float64 {
 	return (g.a_1+1)*(g.d_1+1) - g.b*g.c
 }
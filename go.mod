@@ -1,6 +1,6 @@
 module github.com/hajimehoshi/ebiten/v2
 
-go 1.18
+go 1.24.0
 
 require (
 	github.com/ebitengine/oto/v3 v3.2.0-alpha.2.0.20231021101548-b794c0292b2b
@@ -12,6 +12,7 @@ require (
 	github.com/jezek/xgb v1.1.0
 	github.com/jfreymuth/oggvorbis v1.0.5
 	github.com/kisielk/errcheck v1.6.3
+	github.com/pion/opus v0.1.0
 	golang.org/x/image v0.14.0
 	golang.org/x/mobile v0.0.0-20231108233038-35478a0c49da
 	golang.org/x/sync v0.5.0
@@ -15,6 +15,8 @@
 package ebiten
 
 import (
+	"image"
+
 	"github.com/hajimehoshi/ebiten/v2/internal/builtinshader"
 	"github.com/hajimehoshi/ebiten/v2/internal/ui"
 )
@@ -30,6 +32,31 @@ const (
 	FilterLinear Filter = Filter(builtinshader.FilterLinear)
 )
 
+// MipmapMode represents whether mipmaps are used when an image is minified.
+//
+// Mipmaps are smaller versions of a source image, and reduce aliasing when the source image is drawn smaller than its size.
+// Mipmaps are generated lazily when the source image is drawn minified for the first time,
+// and are kept until the source image is modified.
+// Generating mipmaps costs draw calls and extra memory up to about one third of the source image.
+// If the source image is modified at every frame, mipmaps are regenerated at every frame.
+type MipmapMode int
+
+const (
+	// MipmapModeAuto uses mipmaps only when the filter is FilterLinear and the source image is minified.
+	MipmapModeAuto MipmapMode = iota
+
+	// MipmapModeEnabled uses mipmaps whenever the source image is minified, regardless of the filter.
+	//
+	// With FilterNearest, a pixel of the mipmap of the appropriate size is picked without interpolation.
+	// This reduces flickering of a minified image that moves, while keeping the rendering sharp.
+	MipmapModeEnabled
+
+	// MipmapModeDisabled never uses mipmaps, regardless of the filter.
+	//
+	// This is useful for pixel art drawn with FilterLinear, where the blurred mipmaps are unwanted.
+	MipmapModeDisabled
+)
+
 // GraphicsLibrary represents graphics libraries supported by the engine.
 type GraphicsLibrary int
 
@@ -52,6 +79,10 @@ const (
 
 	// GraphicsLibraryMetal represents the graphics library PlayStation 5.
 	GraphicsLibraryPlayStation5 GraphicsLibrary = GraphicsLibrary(ui.GraphicsLibraryPlayStation5)
+
+	// GraphicsLibrarySoftware represents the software renderer without GPU.
+	// This is used in the headless mode (the build tag ebitengineheadless).
+	GraphicsLibrarySoftware GraphicsLibrary = GraphicsLibrary(ui.GraphicsLibrarySoftware)
 )
 
 // String returns a string representing the graphics library.
@@ -66,9 +97,70 @@ var _ [GraphicsLibraryAuto]int = [0]int{}
 type DebugInfo struct {
 	// GraphicsLibrary represents the graphics library currently in use.
 	GraphicsLibrary GraphicsLibrary
+
+	// ShaderCount represents the number of shaders that are created in the graphics driver and not released yet.
+	//
+	// A shader is created in the graphics driver lazily when the shader is used for the first time.
+	ShaderCount int
+
+	// PendingShaderDisposalCount represents the number of shaders that are disposed or deallocated
+	// but whose resources are not released in the graphics driver yet.
+	PendingShaderDisposalCount int
 }
 
 // ReadDebugInfo writes debug info (e.g. current graphics library) into a provided struct.
 func ReadDebugInfo(d *DebugInfo) {
 	d.GraphicsLibrary = GraphicsLibrary(ui.Get().GraphicsLibrary())
+	d.ShaderCount = ui.Get().ShaderCount()
+	d.PendingShaderDisposalCount = ui.Get().PendingShaderDisposalCount()
+}
+
+// AtlasInfo represents information about an internal texture atlas.
+//
+// Ebitengine automatically packs small images into a shared texture called an atlas,
+// so that draw calls using the images can be merged.
+type AtlasInfo struct {
+	// Width and Height are the size of the atlas texture in pixels.
+	Width  int
+	Height int
+
+	// Source reports whether the atlas is mainly for images used as rendering sources.
+	// Images used as rendering destinations are put on other atlases than the ones for sources.
+	Source bool
+
+	// Regions are the regions of the images on the atlas in pixels, including paddings.
+	Regions []image.Rectangle
+}
+
+// AppendAtlasInfos appends information about the current internal texture atlases to infos and returns the extended buffer.
+// Giving a slice that already has enough capacity works efficiently.
+//
+// AppendAtlasInfos is for debugging, e.g. to see how images are packed, how fragmented the atlases are,
+// or why draw calls are not merged. Draw calls using images on different atlases cannot be merged.
+//
+// Images not on atlases, like big images, screen images, and unmanaged images, are not included.
+// As images are moved between atlases automatically, the result might change frame by frame.
+func AppendAtlasInfos(infos []AtlasInfo) []AtlasInfo {
+	for _, info := range ui.Get().AppendAtlasInfos(nil) {
+		infos = append(infos, AtlasInfo{
+			Width:   info.Width,
+			Height:  info.Height,
+			Source:  info.Source,
+			Regions: info.Regions,
+		})
+	}
+	return infos
+}
+
+// FlushDisposals executes the pending disposals of images and shaders, and blocks until the resources are released
+// in the graphics driver.
+//
+// Usually, disposing and deallocating are done lazily at the end of the frame, and you don't have to call FlushDisposals.
+// FlushDisposals is useful to make sure the old resources are released before creating new ones,
+// e.g., when reloading shaders repeatedly in development.
+//
+// FlushDisposals must be called from Update or Draw, or after the game starts.
+// FlushDisposals blocks until the next frame if necessary.
+func FlushDisposals() {
+	ui.Get().FlushDisposals()
 }
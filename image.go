@@ -43,6 +43,9 @@ type Image struct {
 	// tmpVertices must not be reused until ui.Image.Draw* is called.
 	tmpVertices []float32
 
+	// tmpIndices must not be reused until ui.Image.Draw* is called.
+	tmpIndices []uint32
+
 	// tmpUniforms must not be reused until ui.Image.Draw* is called.
 	tmpUniforms []uint32
 
@@ -97,9 +100,47 @@ func (i *Image) Fill(clr color.Color) {
 	i.image.Fill(crf, cgf, cbf, caf, i.adjustedBounds())
 }
 
-func canSkipMipmap(geom GeoM, filter builtinshader.Filter) bool {
-	if filter != builtinshader.FilterLinear {
+// ClearRegion replaces the pixels in the region r of the image with a solid color.
+//
+// Unlike Fill, the region r is specified in the image's coordinates, and r is clipped with the image's bounds.
+// The pixels outside r are not changed.
+// The pixels in r are replaced with clr without blending, even if clr is translucent.
+//
+// ClearRegion is executed as a clear command without drawing triangles when the graphics driver supports it,
+// which is faster than drawing a filled rectangle especially for a big image.
+//
+// When the image is disposed, ClearRegion does nothing.
+func (i *Image) ClearRegion(r image.Rectangle, clr color.Color) {
+	i.copyCheck()
+	if i.isDisposed() {
+		return
+	}
+
+	r = r.Intersect(i.Bounds())
+	if r.Empty() {
+		return
+	}
+
+	var crf, cgf, cbf, caf float32
+	cr, cg, cb, ca := clr.RGBA()
+	crf = float32(cr) / 0xffff
+	cgf = float32(cg) / 0xffff
+	cbf = float32(cb) / 0xffff
+	caf = float32(ca) / 0xffff
+
+	x, y := i.adjustPosition(r.Min.X, r.Min.Y)
+	i.image.ClearRegion(crf, cgf, cbf, caf, image.Rect(x, y, x+r.Dx(), y+r.Dy()))
+}
+
+func canSkipMipmap(geom GeoM, filter builtinshader.Filter, mipmap MipmapMode) bool {
+	switch mipmap {
+	case MipmapModeEnabled:
+	case MipmapModeDisabled:
 		return true
+	default:
+		if filter != builtinshader.FilterLinear {
+			return true
+		}
 	}
 	return geom.det2x2() >= 0.999
 }
@@ -139,6 +180,40 @@ type DrawImageOptions struct {
 	// Filter is a type of texture filter.
 	// The default (zero) value is FilterNearest.
 	Filter Filter
+
+	// Mipmap is the mode whether mipmaps are used when the source image is minified.
+	// See MipmapMode for the interaction with Filter and the cost of mipmaps.
+	// The default (zero) value is MipmapModeAuto.
+	Mipmap MipmapMode
+
+	// Address is a sampler address mode, which determines the colors sampled outside of the source image's bounds.
+	//
+	// With FilterLinear, the pixels just outside of the source image's bounds are sampled at the edges.
+	// When the source image is a sub-image, e.g. a part of a sprite sheet, the neighbor pixels can bleed into the edges,
+	// especially when the image is rotated or scaled.
+	// AddressClampToEdge prevents this by clamping the sampling positions to the sub-image's bounds.
+	// AddressClampToZero treats the outside as transparent, which makes the edges anti-aliased.
+	//
+	// With FilterNearest, pixels outside of the source image's bounds are rarely sampled, and Address doesn't matter
+	// in most cases.
+	//
+	// The default (zero) value is AddressUnsafe, which means there is no guarantee of the colors outside of
+	// the source image's bounds.
+	Address Address
+
+	// LinearBlending represents whether the colors are blended in the linear color space instead of the sRGB space.
+	// The default (zero) value is false.
+	//
+	// The colors of images are encoded in sRGB, and blending them as they are, which the graphics drivers do,
+	// is not physically correct. For example, anti-aliased edges of texts look too thin on a dark background
+	// and too thick on a light background.
+	// When LinearBlending is true, the colors are decoded into the linear space, blended, and then encoded into sRGB again.
+	//
+	// LinearBlending works only with the default blend (BlendSourceOver). Otherwise, LinearBlending is ignored.
+	//
+	// LinearBlending is much slower than the usual drawing as this requires offscreen images and
+	// prevents the draw call from being batched.
+	LinearBlending bool
 }
 
 // adjustPosition converts the position in the *ebiten.Image coordinate to the *ui.Image coordinate.
@@ -232,10 +307,23 @@ func (i *Image) DrawImage(img *Image, options *DrawImageOptions) {
 	}
 	filter := builtinshader.Filter(options.Filter)
 
+	if options.LinearBlending && blend == graphicsdriver.BlendSourceOver {
+		theLinearBlender.drawImage(i, img, options)
+		return
+	}
+
 	geoM := options.GeoM
 	if offsetX, offsetY := i.adjustPosition(0, 0); offsetX != 0 || offsetY != 0 {
 		geoM.Translate(float64(offsetX), float64(offsetY))
 	}
+
+	// Integer translations with the nearest filter are very common especially in tile-based games.
+	// Use a simpler path for them.
+	if filter == builtinshader.FilterNearest && geoM.isIntegerTranslation() && options.ColorM.affineColorM().IsIdentity() {
+		i.drawImageTranslated(img, float32(geoM.tx), float32(geoM.ty), &options.ColorScale, blend)
+		return
+	}
+
 	a, b, c, d, tx, ty := geoM.elements32()
 
 	bounds := img.Bounds()
@@ -250,7 +338,7 @@ func (i *Image) DrawImage(img *Image, options *DrawImageOptions) {
 	srcs := [graphics.ShaderImageCount]*ui.Image{img.image}
 
 	useColorM := !colorm.IsIdentity()
-	shader := builtinShader(filter, builtinshader.AddressUnsafe, useColorM)
+	shader := builtinShader(filter, builtinshader.Address(options.Address), useColorM)
 	i.tmpUniforms = i.tmpUniforms[:0]
 	if useColorM {
 		var body [16]float32
@@ -262,7 +350,22 @@ func (i *Image) DrawImage(img *Image, options *DrawImageOptions) {
 		})
 	}
 
-	i.image.DrawTriangles(srcs, vs, is, blend, i.adjustedBounds(), [graphics.ShaderImageCount]image.Rectangle{img.adjustedBounds()}, shader.shader, i.tmpUniforms, graphicsdriver.FillAll, canSkipMipmap(geoM, filter), false)
+	i.image.DrawTriangles(srcs, vs, is, blend, i.adjustedBounds(), [graphics.ShaderImageCount]image.Rectangle{img.adjustedBounds()}, shader.shader, i.tmpUniforms, graphicsdriver.FillAll, canSkipMipmap(geoM, filter, options.Mipmap), false)
+}
+
+// drawImageTranslated draws img on i with an integer translation (tx, ty) and the nearest filter.
+// tx and ty are in the *ui.Image coordinate.
+func (i *Image) drawImageTranslated(img *Image, tx, ty float32, colorScale *ColorScale, blend graphicsdriver.Blend) {
+	bounds := img.Bounds()
+	sx0, sy0 := img.adjustPosition(bounds.Min.X, bounds.Min.Y)
+	sx1, sy1 := img.adjustPosition(bounds.Max.X, bounds.Max.Y)
+	cr, cg, cb, ca := colorScale.apply(1, 1, 1, 1)
+	vs := i.ensureTmpVertices(4 * graphics.VertexFloatCount)
+	graphics.QuadVerticesTranslated(vs, float32(sx0), float32(sy0), float32(sx1), float32(sy1), tx, ty, cr, cg, cb, ca)
+	is := graphics.QuadIndices()
+
+	srcs := [graphics.ShaderImageCount]*ui.Image{img.image}
+	i.image.DrawTriangles(srcs, vs, is, blend, i.adjustedBounds(), [graphics.ShaderImageCount]image.Rectangle{img.adjustedBounds()}, ui.NearestFilterShader, nil, graphicsdriver.FillAll, true, false)
 }
 
 // Vertex represents a vertex passed to DrawTriangles.
@@ -305,6 +408,14 @@ const (
 
 	// AddressRepeat means that texture coordinates wrap to the other side of the texture.
 	AddressRepeat Address = Address(builtinshader.AddressRepeat)
+
+	// AddressClampToEdge means that out-of-range texture coordinates return the color at the nearest edge of
+	// the texture.
+	//
+	// The texture is the source image's bounds, so pixels outside of a sub-image are never sampled.
+	// This is useful to prevent the neighbor pixels from bleeding into the edges, e.g. when drawing a part of a sprite
+	// sheet with FilterLinear.
+	AddressClampToEdge Address = Address(builtinshader.AddressClampToEdge)
 )
 
 // FillRule is the rule whether an overlapped region is rendered with DrawTriangles(Shader).
@@ -386,6 +497,11 @@ type DrawTrianglesOptions struct {
 	//
 	// The default (zero) value is false.
 	AntiAlias bool
+
+	// Mipmap is the mode whether mipmaps are used when the source image is minified.
+	// See MipmapMode for the interaction with Filter and the cost of mipmaps.
+	// The default (zero) value is MipmapModeAuto.
+	Mipmap MipmapMode
 }
 
 // MaxIndicesCount is the maximum number of indices for DrawTriangles and DrawTrianglesShader.
@@ -450,19 +566,268 @@ func (i *Image) DrawTriangles(vertices []Vertex, indices []uint16, img *Image, o
 		}
 	}
 
+	i.drawTriangles(vertices, indices, nil, img, options)
+}
+
+// IndexRange represents a range of indices passed to DrawTrianglesMulti.
+type IndexRange struct {
+	// Start is the position of the first index in the indices.
+	Start int
+
+	// Count is the number of indices in the range.
+	// Count must be a multiple of 3.
+	Count int
+}
+
+// DrawTrianglesMulti draws multiple meshes sharing the same vertices with one call.
+//
+// vertices and indices are the same as DrawTriangles's.
+// ranges specifies sub-ranges of indices, and each sub-range represents one mesh.
+// Indices not covered by any range are not rendered.
+//
+// Each mesh is rasterized independently, i.e., the result is the same as calling DrawTriangles
+// for each range in order. This matters when FillRule is NonZero or EvenOdd,
+// where overlaps between different meshes are not taken into account.
+// DrawTrianglesMulti is more efficient than calling DrawTriangles for each range, as
+// the vertices are converted only once and non-overlapping meshes are batched into one draw command.
+//
+// If a range is out of indices, or a range's Count is not a multiple of 3, DrawTrianglesMulti panics.
+//
+// The other rules are the same as DrawTriangles's.
+func (i *Image) DrawTrianglesMulti(vertices []Vertex, indices []uint16, ranges []IndexRange, img *Image, options *DrawTrianglesOptions) {
+	i.copyCheck()
+
+	if img != nil && img.isDisposed() {
+		panic("ebiten: the given image to DrawTrianglesMulti must not be disposed")
+	}
+	if i.isDisposed() {
+		return
+	}
+
+	if len(vertices) > graphicscommand.MaxVertexCount {
+		// The last part cannot be specified by indices. Just omit them.
+		vertices = vertices[:graphicscommand.MaxVertexCount]
+	}
+	for j, r := range ranges {
+		if r.Start < 0 || r.Count < 0 || r.Start+r.Count > len(indices) {
+			panic(fmt.Sprintf("ebiten: ranges[%d] (start: %d, count: %d) is out of range of indices (%d)", j, r.Start, r.Count, len(indices)))
+		}
+		if r.Count%3 != 0 {
+			panic(fmt.Sprintf("ebiten: ranges[%d].Count %% 3 must be 0 but was %d", j, r.Count))
+		}
+		for k, idx := range indices[r.Start : r.Start+r.Count] {
+			if int(idx) >= len(vertices) {
+				panic(fmt.Sprintf("ebiten: indices[%d] must be less than len(vertices) (%d) but was %d", r.Start+k, len(vertices), idx))
+			}
+		}
+	}
+
+	if len(ranges) == 0 {
+		return
+	}
+
+	i.drawTriangles(vertices, indices, ranges, img, options)
+}
+
+// RawVertexFloatCount is the number of float32 values for one vertex in DrawTrianglesRaw.
+const RawVertexFloatCount = 8
+
+// DrawTrianglesRaw draws triangles with the specified vertices in a flat float32 slice and their indices.
+//
+// DrawTrianglesRaw is the same as DrawTriangles except for the formats of vertices and indices.
+// As DrawTrianglesRaw doesn't require a Vertex struct for each vertex, DrawTrianglesRaw is useful
+// when you build a large mesh every frame, e.g., particles or tile maps.
+//
+// vertices is a sequence of vertices, and each vertex consists of RawVertexFloatCount float32 values
+// in the same order as Vertex's fields:
+//
+//	DstX, DstY, SrcX, SrcY, ColorR, ColorG, ColorB, ColorA
+//
+// The stride of a vertex is RawVertexFloatCount.
+//
+// If len(vertices) is not a multiple of RawVertexFloatCount, DrawTrianglesRaw panics.
+//
+// If the number of vertices is more than MaxVertexCount, the exceeding part is ignored.
+//
+// If len(indices) is not multiple of 3, DrawTrianglesRaw panics.
+//
+// If a value in indices is out of range of vertices, DrawTrianglesRaw panics.
+//
+// The other rules are the same as DrawTriangles's.
+func (i *Image) DrawTrianglesRaw(vertices []float32, indices []uint32, img *Image, options *DrawTrianglesOptions) {
+	i.copyCheck()
+
+	if img != nil && img.isDisposed() {
+		panic("ebiten: the given image to DrawTrianglesRaw must not be disposed")
+	}
+	if i.isDisposed() {
+		return
+	}
+
+	if len(vertices)%RawVertexFloatCount != 0 {
+		panic(fmt.Sprintf("ebiten: len(vertices) %% %d must be 0 but len(vertices) was %d", RawVertexFloatCount, len(vertices)))
+	}
+	n := len(vertices) / RawVertexFloatCount
+	if n > graphicscommand.MaxVertexCount {
+		// The last part cannot be specified by indices. Just omit them.
+		n = graphicscommand.MaxVertexCount
+		vertices = vertices[:n*RawVertexFloatCount]
+	}
+	if len(indices)%3 != 0 {
+		panic("ebiten: len(indices) % 3 must be 0")
+	}
+	for i, idx := range indices {
+		if int(idx) >= n {
+			panic(fmt.Sprintf("ebiten: indices[%d] must be less than the number of vertices (%d) but was %d", i, n, idx))
+		}
+	}
+
 	if options == nil {
 		options = &DrawTrianglesOptions{}
 	}
 
-	var blend graphicsdriver.Blend
-	if options.CompositeMode == CompositeModeCustom {
-		blend = options.Blend.internalBlend()
-	} else {
-		blend = options.CompositeMode.blend().internalBlend()
+	colorm, cr, cg, cb, ca := colorMToScale(options.ColorM.affineColorM())
+
+	vs := i.ensureTmpVertices(n * graphics.VertexFloatCount)
+	dst := i
+	premultiply := options.ColorScaleMode == ColorScaleModeStraightAlpha
+	for i := 0; i < n; i++ {
+		v := vertices[i*RawVertexFloatCount : (i+1)*RawVertexFloatCount]
+		dx, dy := dst.adjustPositionF32(v[0], v[1])
+		vs[i*graphics.VertexFloatCount] = dx
+		vs[i*graphics.VertexFloatCount+1] = dy
+		sx, sy := img.adjustPositionF32(v[2], v[3])
+		vs[i*graphics.VertexFloatCount+2] = sx
+		vs[i*graphics.VertexFloatCount+3] = sy
+		r, g, b, a := v[4], v[5], v[6], v[7]
+		if premultiply {
+			r *= a
+			g *= a
+			b *= a
+		}
+		vs[i*graphics.VertexFloatCount+4] = r * cr
+		vs[i*graphics.VertexFloatCount+5] = g * cg
+		vs[i*graphics.VertexFloatCount+6] = b * cb
+		vs[i*graphics.VertexFloatCount+7] = a * ca
 	}
 
-	address := builtinshader.Address(options.Address)
-	filter := builtinshader.Filter(options.Filter)
+	i.drawConvertedTriangles(vs, indices, nil, img, options, colorm)
+}
+
+// Instance represents per-instance attributes for DrawTrianglesInstanced and DrawTrianglesShaderInstanced.
+type Instance struct {
+	// GeoM is a geometry matrix applied to the destination positions of the vertices.
+	GeoM GeoM
+
+	// ColorScale is a scale applied to the color values of the vertices.
+	ColorScale ColorScale
+}
+
+// DrawTrianglesInstanced draws the same mesh multiple times with per-instance attributes.
+//
+// vertices and indices represent a base mesh, and they are the same as DrawTriangles's.
+// For each instance, the mesh is drawn with the destination positions transformed by the instance's GeoM,
+// and the colors scaled by the instance's ColorScale. The instances are drawn in order.
+// The result is the same as calling DrawTriangles for each instance, except that the fill rule is applied to
+// the whole instances, i.e. overlaps between different instances are taken into account with NonZero and EvenOdd.
+//
+// DrawTrianglesInstanced is useful to draw many same shapes like particles or tiles.
+// The base mesh is validated only once and all the instances are sent as one draw command,
+// but the vertices of the instances are still calculated on CPU.
+//
+// The instance's ColorScale is applied to the premultiplied-alpha colors of the vertices.
+//
+// If the total number of vertices of the instances is more than MaxVertexCount, the exceeding instances are ignored.
+//
+// The other rules are the same as DrawTriangles's.
+func (i *Image) DrawTrianglesInstanced(vertices []Vertex, indices []uint16, instances []Instance, img *Image, options *DrawTrianglesOptions) {
+	i.copyCheck()
+
+	if img != nil && img.isDisposed() {
+		panic("ebiten: the given image to DrawTrianglesInstanced must not be disposed")
+	}
+	if i.isDisposed() {
+		return
+	}
+
+	if len(vertices) > graphicscommand.MaxVertexCount {
+		// The last part cannot be specified by indices. Just omit them.
+		vertices = vertices[:graphicscommand.MaxVertexCount]
+	}
+	if len(indices)%3 != 0 {
+		panic("ebiten: len(indices) % 3 must be 0")
+	}
+	for i, idx := range indices {
+		if int(idx) >= len(vertices) {
+			panic(fmt.Sprintf("ebiten: indices[%d] must be less than len(vertices) (%d) but was %d", i, len(vertices), idx))
+		}
+	}
+	if len(vertices) == 0 || len(indices) == 0 {
+		return
+	}
+	if n := graphicscommand.MaxVertexCount / len(vertices); len(instances) > n {
+		instances = instances[:n]
+	}
+	if len(instances) == 0 {
+		return
+	}
+
+	if options == nil {
+		options = &DrawTrianglesOptions{}
+	}
+
+	colorm, cr, cg, cb, ca := colorMToScale(options.ColorM.affineColorM())
+
+	vs := i.ensureTmpVertices(len(instances) * len(vertices) * graphics.VertexFloatCount)
+	is := i.ensureTmpIndices(len(instances) * len(indices))
+	dst := i
+	premultiply := options.ColorScaleMode == ColorScaleModeStraightAlpha
+	for k := range instances {
+		inst := &instances[k]
+		a, b, c, d, tx, ty := inst.GeoM.elements32()
+		ir, ig, ib, ia := inst.ColorScale.elements()
+		ir *= cr
+		ig *= cg
+		ib *= cb
+		ia *= ca
+
+		base := k * len(vertices)
+		for j, v := range vertices {
+			idx := (base + j) * graphics.VertexFloatCount
+			dx, dy := dst.adjustPositionF32(a*v.DstX+b*v.DstY+tx, c*v.DstX+d*v.DstY+ty)
+			vs[idx] = dx
+			vs[idx+1] = dy
+			sx, sy := img.adjustPositionF32(v.SrcX, v.SrcY)
+			vs[idx+2] = sx
+			vs[idx+3] = sy
+			vr, vg, vb, va := v.ColorR, v.ColorG, v.ColorB, v.ColorA
+			if premultiply {
+				vr *= va
+				vg *= va
+				vb *= va
+			}
+			vs[idx+4] = vr * ir
+			vs[idx+5] = vg * ig
+			vs[idx+6] = vb * ib
+			vs[idx+7] = va * ia
+		}
+		for j, idx := range indices {
+			is[k*len(indices)+j] = uint32(base) + uint32(idx)
+		}
+	}
+
+	i.drawConvertedTriangles(vs, is, nil, img, options, colorm)
+}
+
+// drawTriangles draws triangles for DrawTriangles and DrawTrianglesMulti.
+//
+// If ranges is nil, all the indices are drawn at once.
+// Otherwise, each range is drawn independently.
+// The arguments must be validated by the caller.
+func (i *Image) drawTriangles(vertices []Vertex, indices []uint16, ranges []IndexRange, img *Image, options *DrawTrianglesOptions) {
+	if options == nil {
+		options = &DrawTrianglesOptions{}
+	}
 
 	colorm, cr, cg, cb, ca := colorMToScale(options.ColorM.affineColorM())
 
@@ -495,11 +860,30 @@ func (i *Image) DrawTriangles(vertices []Vertex, indices []uint16, img *Image, o
 			vs[i*graphics.VertexFloatCount+7] = v.ColorA * ca
 		}
 	}
+
 	is := make([]uint32, len(indices))
 	for i := range is {
 		is[i] = uint32(indices[i])
 	}
 
+	i.drawConvertedTriangles(vs, is, ranges, img, options, colorm)
+}
+
+// drawConvertedTriangles draws triangles with the vertices in the internal format.
+//
+// The color matrix colorm must be the one returned by colorMToScale, and the color scales must be already applied to vs.
+// The arguments must be validated by the caller.
+func (i *Image) drawConvertedTriangles(vs []float32, indices []uint32, ranges []IndexRange, img *Image, options *DrawTrianglesOptions, colorm affine.ColorM) {
+	var blend graphicsdriver.Blend
+	if options.CompositeMode == CompositeModeCustom {
+		blend = options.Blend.internalBlend()
+	} else {
+		blend = options.CompositeMode.blend().internalBlend()
+	}
+
+	address := builtinshader.Address(options.Address)
+	filter := builtinshader.Filter(options.Filter)
+
 	srcs := [graphics.ShaderImageCount]*ui.Image{img.image}
 
 	useColorM := !colorm.IsIdentity()
@@ -515,7 +899,64 @@ func (i *Image) DrawTriangles(vertices []Vertex, indices []uint16, img *Image, o
 		})
 	}
 
-	i.image.DrawTriangles(srcs, vs, is, blend, i.adjustedBounds(), [graphics.ShaderImageCount]image.Rectangle{img.adjustedBounds()}, shader.shader, i.tmpUniforms, graphicsdriver.FillRule(options.FillRule), filter != builtinshader.FilterLinear, options.AntiAlias)
+	dstRegion := i.adjustedBounds()
+	srcRegions := [graphics.ShaderImageCount]image.Rectangle{img.adjustedBounds()}
+	fillRule := graphicsdriver.FillRule(options.FillRule)
+	var canSkipMipmap bool
+	switch options.Mipmap {
+	case MipmapModeEnabled:
+	case MipmapModeDisabled:
+		canSkipMipmap = true
+	default:
+		canSkipMipmap = filter != builtinshader.FilterLinear
+	}
+
+	if ranges == nil {
+		i.image.DrawTriangles(srcs, vs, indices, blend, dstRegion, srcRegions, shader.shader, i.tmpUniforms, fillRule, canSkipMipmap, options.AntiAlias)
+		return
+	}
+
+	// With FillAll, triangles never affect each other. Draw all the ranges at once.
+	if fillRule == graphicsdriver.FillAll {
+		var n int
+		for _, r := range ranges {
+			n += r.Count
+		}
+		is := make([]uint32, 0, n)
+		for _, r := range ranges {
+			is = append(is, indices[r.Start:r.Start+r.Count]...)
+		}
+		i.image.DrawTriangles(srcs, vs, is, blend, dstRegion, srcRegions, shader.shader, i.tmpUniforms, fillRule, canSkipMipmap, options.AntiAlias)
+		return
+	}
+
+	// Draw each range separately so that the fill rule is applied to each range independently.
+	// The command queue merges successive draw calls that don't overlap into one draw command.
+	// Only the vertices referred by the range are passed, as vertices are copied and modified in the lower layers.
+	var rangeVertices []float32
+	var is []uint32
+	for _, r := range ranges {
+		if r.Count == 0 {
+			continue
+		}
+		rangeIndices := indices[r.Start : r.Start+r.Count]
+		minIdx, maxIdx := rangeIndices[0], rangeIndices[0]
+		for _, idx := range rangeIndices {
+			if minIdx > idx {
+				minIdx = idx
+			}
+			if maxIdx < idx {
+				maxIdx = idx
+			}
+		}
+
+		rangeVertices = append(rangeVertices[:0], vs[int(minIdx)*graphics.VertexFloatCount:(int(maxIdx)+1)*graphics.VertexFloatCount]...)
+		is = is[:0]
+		for _, idx := range rangeIndices {
+			is = append(is, idx-minIdx)
+		}
+		i.image.DrawTriangles(srcs, rangeVertices, is, blend, dstRegion, srcRegions, shader.shader, i.tmpUniforms, fillRule, canSkipMipmap, options.AntiAlias)
+	}
 }
 
 // DrawTrianglesShaderOptions represents options for DrawTrianglesShader.
@@ -533,11 +974,30 @@ type DrawTrianglesShaderOptions struct {
 
 	// Uniforms is a set of uniform variables for the shader.
 	// The keys are the names of the uniform variables.
-	// The values must be a numeric type, or a slice or an array of a numeric type.
+	// The values must be a boolean or numeric type, or a slice or an array of a boolean or numeric type.
 	// If the uniform variable type is an array, a vector or a matrix,
 	// you have to specify linearly flattened values as a slice or an array.
 	// For example, if the uniform variable type is [4]vec4, the length will be 16.
 	//
+	// The values are converted to the uniform variable's element type as follows:
+	//
+	//   - For an int or ivec uniform variable, an integer value is passed as a 32-bit signed integer without precision loss,
+	//     and the higher bits of a larger value are discarded. A float value is truncated toward zero.
+	//     A bool value is passed as 1 for true or 0 for false.
+	//   - For a float, vec, or mat uniform variable, a numeric value is converted to a 32-bit float,
+	//     so an integer whose absolute value is greater than 2^24 might lose its precision.
+	//   - For a bool uniform variable, an integer value is treated as true if and only if it is not zero.
+	//
+	// A bool value for a float, vec, or mat uniform variable and a float value for a bool uniform variable are not allowed,
+	// and the draw function panics with such values.
+	//
+	// If the uniform variable type is an array, you can also specify a slice or an array of structs.
+	// A struct must consist of only boolean or numeric fields or arrays of them, and is flattened in the field order.
+	// Each struct corresponds to one or more consecutive elements of the array,
+	// and the structs must fill the whole array.
+	// For example, for a uniform variable of the type [8]vec4, a slice of 4 structs with 8 float32 fields is valid.
+	// This is useful to pass an array of structured data like lights without flattening them manually.
+	//
 	// If a uniform variable's name doesn't exist in Uniforms, this is treated as if zero values are specified.
 	Uniforms map[string]any
 
@@ -587,6 +1047,9 @@ var _ [len(DrawTrianglesShaderOptions{}.Images) - graphics.ShaderImageCount]stru
 //
 // If a specified uniform variable's length or type doesn't match with an expected one, DrawTrianglesShader panics.
 //
+// If the shader is created by NewShaderAsync and its compilation is not done yet,
+// DrawTrianglesShader uses the fallback shader or blocks until the compilation is done. See NewShaderAsync for details.
+//
 // When the image i is disposed, DrawTrianglesShader does nothing.
 func (i *Image) DrawTrianglesShader(vertices []Vertex, indices []uint16, shader *Shader, options *DrawTrianglesShaderOptions) {
 	i.copyCheck()
@@ -595,6 +1058,7 @@ func (i *Image) DrawTrianglesShader(vertices []Vertex, indices []uint16, shader
 		return
 	}
 
+	shader = shader.shaderForDrawing()
 	if shader.isDisposed() {
 		panic("ebiten: the given shader to DrawTrianglesShader must not be disposed")
 	}
@@ -616,13 +1080,6 @@ func (i *Image) DrawTrianglesShader(vertices []Vertex, indices []uint16, shader
 		options = &DrawTrianglesShaderOptions{}
 	}
 
-	var blend graphicsdriver.Blend
-	if options.CompositeMode == CompositeModeCustom {
-		blend = options.Blend.internalBlend()
-	} else {
-		blend = options.CompositeMode.blend().internalBlend()
-	}
-
 	vs := i.ensureTmpVertices(len(vertices) * graphics.VertexFloatCount)
 	dst := i
 	src := options.Images[0]
@@ -647,6 +1104,101 @@ func (i *Image) DrawTrianglesShader(vertices []Vertex, indices []uint16, shader
 		is[i] = uint32(indices[i])
 	}
 
+	i.drawConvertedTrianglesShader(vs, is, shader, options)
+}
+
+// DrawTrianglesShaderInstanced draws the same mesh multiple times with per-instance attributes with the specified shader.
+//
+// vertices and indices represent a base mesh, and they are the same as DrawTrianglesShader's.
+// For each instance, the mesh is drawn with the destination positions transformed by the instance's GeoM,
+// and the color values scaled by the instance's ColorScale. The color values are passed to the shader as they are.
+//
+// See DrawTrianglesInstanced for the details of instances.
+//
+// The other rules are the same as DrawTrianglesShader's.
+func (i *Image) DrawTrianglesShaderInstanced(vertices []Vertex, indices []uint16, instances []Instance, shader *Shader, options *DrawTrianglesShaderOptions) {
+	i.copyCheck()
+
+	if i.isDisposed() {
+		return
+	}
+
+	shader = shader.shaderForDrawing()
+	if shader.isDisposed() {
+		panic("ebiten: the given shader to DrawTrianglesShaderInstanced must not be disposed")
+	}
+
+	if len(vertices) > graphicscommand.MaxVertexCount {
+		// The last part cannot be specified by indices. Just omit them.
+		vertices = vertices[:graphicscommand.MaxVertexCount]
+	}
+	if len(indices)%3 != 0 {
+		panic("ebiten: len(indices) % 3 must be 0")
+	}
+	for i, idx := range indices {
+		if int(idx) >= len(vertices) {
+			panic(fmt.Sprintf("ebiten: indices[%d] must be less than len(vertices) (%d) but was %d", i, len(vertices), idx))
+		}
+	}
+	if len(vertices) == 0 || len(indices) == 0 {
+		return
+	}
+	if n := graphicscommand.MaxVertexCount / len(vertices); len(instances) > n {
+		instances = instances[:n]
+	}
+	if len(instances) == 0 {
+		return
+	}
+
+	if options == nil {
+		options = &DrawTrianglesShaderOptions{}
+	}
+
+	vs := i.ensureTmpVertices(len(instances) * len(vertices) * graphics.VertexFloatCount)
+	is := i.ensureTmpIndices(len(instances) * len(indices))
+	dst := i
+	src := options.Images[0]
+	for k := range instances {
+		inst := &instances[k]
+		a, b, c, d, tx, ty := inst.GeoM.elements32()
+		ir, ig, ib, ia := inst.ColorScale.elements()
+
+		base := k * len(vertices)
+		for j, v := range vertices {
+			idx := (base + j) * graphics.VertexFloatCount
+			dx, dy := dst.adjustPositionF32(a*v.DstX+b*v.DstY+tx, c*v.DstX+d*v.DstY+ty)
+			vs[idx] = dx
+			vs[idx+1] = dy
+			sx, sy := v.SrcX, v.SrcY
+			if src != nil {
+				sx, sy = src.adjustPositionF32(sx, sy)
+			}
+			vs[idx+2] = sx
+			vs[idx+3] = sy
+			vs[idx+4] = v.ColorR * ir
+			vs[idx+5] = v.ColorG * ig
+			vs[idx+6] = v.ColorB * ib
+			vs[idx+7] = v.ColorA * ia
+		}
+		for j, idx := range indices {
+			is[k*len(indices)+j] = uint32(base) + uint32(idx)
+		}
+	}
+
+	i.drawConvertedTrianglesShader(vs, is, shader, options)
+}
+
+// drawConvertedTrianglesShader draws triangles with the vertices in the internal format with the specified shader.
+//
+// The arguments must be validated by the caller.
+func (i *Image) drawConvertedTrianglesShader(vs []float32, is []uint32, shader *Shader, options *DrawTrianglesShaderOptions) {
+	var blend graphicsdriver.Blend
+	if options.CompositeMode == CompositeModeCustom {
+		blend = options.Blend.internalBlend()
+	} else {
+		blend = options.CompositeMode.blend().internalBlend()
+	}
+
 	var imgs [graphics.ShaderImageCount]*ui.Image
 	var imgSize image.Point
 	for i, img := range options.Images {
@@ -707,11 +1259,30 @@ type DrawRectShaderOptions struct {
 
 	// Uniforms is a set of uniform variables for the shader.
 	// The keys are the names of the uniform variables.
-	// The values must be a numeric type, or a slice or an array of a numeric type.
+	// The values must be a boolean or numeric type, or a slice or an array of a boolean or numeric type.
 	// If the uniform variable type is an array, a vector or a matrix,
 	// you have to specify linearly flattened values as a slice or an array.
 	// For example, if the uniform variable type is [4]vec4, the length will be 16.
 	//
+	// The values are converted to the uniform variable's element type as follows:
+	//
+	//   - For an int or ivec uniform variable, an integer value is passed as a 32-bit signed integer without precision loss,
+	//     and the higher bits of a larger value are discarded. A float value is truncated toward zero.
+	//     A bool value is passed as 1 for true or 0 for false.
+	//   - For a float, vec, or mat uniform variable, a numeric value is converted to a 32-bit float,
+	//     so an integer whose absolute value is greater than 2^24 might lose its precision.
+	//   - For a bool uniform variable, an integer value is treated as true if and only if it is not zero.
+	//
+	// A bool value for a float, vec, or mat uniform variable and a float value for a bool uniform variable are not allowed,
+	// and the draw function panics with such values.
+	//
+	// If the uniform variable type is an array, you can also specify a slice or an array of structs.
+	// A struct must consist of only boolean or numeric fields or arrays of them, and is flattened in the field order.
+	// Each struct corresponds to one or more consecutive elements of the array,
+	// and the structs must fill the whole array.
+	// For example, for a uniform variable of the type [8]vec4, a slice of 4 structs with 8 float32 fields is valid.
+	// This is useful to pass an array of structured data like lights without flattening them manually.
+	//
 	// If a uniform variable's name doesn't exist in Uniforms, this is treated as if zero values are specified.
 	Uniforms map[string]any
 
@@ -742,6 +1313,9 @@ var _ [len(DrawRectShaderOptions{}.Images)]struct{} = [graphics.ShaderImageCount
 // If no source images are specified, imageSrc0Size returns a valid size only when the unit is pixels,
 // but always returns 0 when the unit is texels (default).
 //
+// If the shader is created by NewShaderAsync and its compilation is not done yet,
+// DrawRectShader uses the fallback shader or blocks until the compilation is done. See NewShaderAsync for details.
+//
 // When the image i is disposed, DrawRectShader does nothing.
 func (i *Image) DrawRectShader(width, height int, shader *Shader, options *DrawRectShaderOptions) {
 	i.copyCheck()
@@ -750,6 +1324,7 @@ func (i *Image) DrawRectShader(width, height int, shader *Shader, options *DrawR
 		return
 	}
 
+	shader = shader.shaderForDrawing()
 	if shader.isDisposed() {
 		panic("ebiten: the given shader to DrawRectShader must not be disposed")
 	}
@@ -853,6 +1428,39 @@ func (i *Image) SubImage(r image.Rectangle) image.Image {
 	return img
 }
 
+// Clone returns a new image that has a copy of the pixels in the region r of the image.
+//
+// Unlike SubImage, the returned image doesn't share pixels with the original image.
+// The returned image is still available after the original image is disposed or modified,
+// and modifying the returned image doesn't affect the original image.
+// On the other hand, Clone allocates a new texture region of the size of r, while SubImage doesn't allocate any.
+// Prefer SubImage unless the independence is necessary.
+//
+// The returned image's bounds are the intersection of r and the image's bounds, as well as SubImage.
+//
+// If the image is disposed, Clone returns nil.
+//
+// If the intersection of r and the image's bounds is empty, Clone panics.
+//
+// Clone is a copy by a rendering command, so this is efficient as well as DrawImage.
+// Clone should be called only when necessary, in the same way as NewImage.
+func (i *Image) Clone(r image.Rectangle) *Image {
+	i.copyCheck()
+	if i.isDisposed() {
+		return nil
+	}
+
+	src := i.SubImage(r).(*Image)
+	img := newImage(src.Bounds(), atlas.ImageTypeRegular)
+
+	op := &DrawImageOptions{}
+	b := src.Bounds()
+	op.GeoM.Translate(float64(b.Min.X), float64(b.Min.Y))
+	op.Blend = BlendCopy
+	img.DrawImage(src, op)
+	return img
+}
+
 // Bounds returns the bounds of the image.
 //
 // Bounds implements the standard image.Image's Bounds.
@@ -1042,6 +1650,42 @@ func (i *Image) WritePixels(pixels []byte) {
 	i.image.WritePixels(pixels, i.adjustedBounds())
 }
 
+// WritePixelsRegion replaces the pixels of the image in the given region.
+//
+// The given pixels are treated as RGBA pre-multiplied alpha values.
+//
+// region is in the image's coordinates, and must be in the image's bounds.
+// If region is out of the bounds, WritePixelsRegion panics.
+// If region is empty, WritePixelsRegion does nothing.
+//
+// len(pix) must be 4 * (region width) * (region height).
+// If len(pix) is not correct, WritePixelsRegion panics.
+//
+// Only the pixels in the region are uploaded.
+// WritePixelsRegion is useful to update a part of a big image, e.g. a canvas rendered by CPU where only a part is changed.
+// WritePixelsRegion is the same as calling WritePixels of the sub-image of region, but doesn't create a sub-image.
+//
+// When the image is disposed, WritePixelsRegion does nothing.
+func (i *Image) WritePixelsRegion(pixels []byte, region image.Rectangle) {
+	i.copyCheck()
+
+	if i.isDisposed() {
+		return
+	}
+	if region.Empty() {
+		return
+	}
+	if !region.In(i.Bounds()) {
+		panic(fmt.Sprintf("ebiten: region at WritePixelsRegion must be in the image's bounds %v but %v", i.Bounds(), region))
+	}
+	if l := 4 * region.Dx() * region.Dy(); len(pixels) != l {
+		panic(fmt.Sprintf("ebiten: len(pixels) at WritePixelsRegion must be %d but %d", l, len(pixels)))
+	}
+
+	x, y := i.adjustPosition(region.Min.X, region.Min.Y)
+	i.image.WritePixels(pixels, image.Rect(x, y, x+region.Dx(), y+region.Dy()))
+}
+
 // ReplacePixels replaces the pixels of the image.
 //
 // Deprecated: as of v2.4. Use WritePixels instead.
@@ -1062,6 +1706,41 @@ func NewImage(width, height int) *Image {
 	return newImage(image.Rect(0, 0, width, height), atlas.ImageTypeRegular)
 }
 
+// AtlasMode represents whether an image is packed into an internal automatic texture atlas.
+//
+// Draw calls using images on the same atlas can be merged into one draw command,
+// which is efficient especially when many small images are rendered.
+// On the other hand, an image on an atlas shares a texture with other images with a padding around it,
+// and might be moved between atlases depending on whether the image is used as a rendering source or destination.
+//
+// An image not on an atlas always has its own texture. Its pixels are never adjacent to other images' pixels,
+// even when a shader reads pixels out of the image's bounds, and its texture is released independently.
+// On the other hand, draw calls using such an image cannot be merged with the ones using other images.
+//
+// An image bigger than the maximum atlas size always has its own texture regardless of AtlasMode.
+// Use AppendAtlasInfos to see how images are packed into atlases.
+type AtlasMode int
+
+const (
+	// AtlasModeAuto packs the image into an atlas automatically.
+	// An image used as a rendering destination is moved to an atlas for rendering sources
+	// after the image has been used only as a rendering source for a while.
+	// The more often the image is used as a destination, the longer it takes.
+	AtlasModeAuto AtlasMode = iota
+
+	// AtlasModeAlways packs the image into an atlas as soon as possible.
+	// An image used as a rendering destination is moved to an atlas for rendering sources
+	// at the end of a frame where the image is used as a rendering source.
+	//
+	// AtlasModeAlways is useful for an image that is rarely updated but drawn with many other images.
+	// Note that the image's pixels are copied every time the image is moved,
+	// so AtlasModeAlways is inefficient for an image used as both a source and a destination every frame.
+	AtlasModeAlways
+
+	// AtlasModeNever never packs the image into an atlas.
+	AtlasModeNever
+)
+
 // NewImageOptions represents options for NewImage.
 type NewImageOptions struct {
 	// Unmanaged represents whether the image is unmanaged or not.
@@ -1070,7 +1749,18 @@ type NewImageOptions struct {
 	// An unmanaged image is never on an internal automatic texture atlas.
 	// A regular image is a part of an internal texture atlas, and locating them is done automatically in Ebitengine.
 	// Unmanaged is useful when you want finer controls over the image for performance and memory reasons.
+	//
+	// Unmanaged true is the same as AtlasModeNever.
 	Unmanaged bool
+
+	// AtlasMode represents whether the image is packed into an internal automatic texture atlas.
+	// See AtlasMode for the tradeoffs.
+	//
+	// If Unmanaged is true, AtlasMode must be AtlasModeAuto or AtlasModeNever.
+	// Otherwise, NewImageWithOptions panics.
+	//
+	// The default (zero) value is AtlasModeAuto.
+	AtlasMode AtlasMode
 }
 
 // NewImageWithOptions returns an empty image with the given bounds and the options.
@@ -1090,8 +1780,22 @@ type NewImageOptions struct {
 // NewImageWithOptions panics if RunGame already finishes.
 func NewImageWithOptions(bounds image.Rectangle, options *NewImageOptions) *Image {
 	imageType := atlas.ImageTypeRegular
-	if options != nil && options.Unmanaged {
-		imageType = atlas.ImageTypeUnmanaged
+	if options != nil {
+		if options.Unmanaged && options.AtlasMode != AtlasModeAuto && options.AtlasMode != AtlasModeNever {
+			panic(fmt.Sprintf("ebiten: AtlasMode must be AtlasModeAuto or AtlasModeNever when Unmanaged is true but %d", options.AtlasMode))
+		}
+		switch options.AtlasMode {
+		case AtlasModeAuto:
+			if options.Unmanaged {
+				imageType = atlas.ImageTypeUnmanaged
+			}
+		case AtlasModeAlways:
+			imageType = atlas.ImageTypeAtlasPreferred
+		case AtlasModeNever:
+			imageType = atlas.ImageTypeUnmanaged
+		default:
+			panic(fmt.Sprintf("ebiten: invalid AtlasMode: %d", options.AtlasMode))
+		}
 	}
 	return newImage(bounds, imageType)
 }
@@ -1128,6 +1832,9 @@ func newImage(bounds image.Rectangle, imageType atlas.ImageType) *Image {
 // NewImageFromImage panics if RunGame already finishes.
 //
 // The returned image's upper-left position is always (0, 0). The source's bounds are not respected.
+//
+// NewImageFromImage interprets the source's colors with AlphaModeAuto.
+// To interpret them in a different way, use NewImageFromImageWithOptions.
 func NewImageFromImage(source image.Image) *Image {
 	return NewImageFromImageWithOptions(source, nil)
 }
@@ -1140,13 +1847,56 @@ type NewImageFromImageOptions struct {
 	// An unmanaged image is never on an internal automatic texture atlas.
 	// A regular image is a part of an internal texture atlas, and locating them is done automatically in Ebitengine.
 	// Unmanaged is useful when you want finer controls over the image for performance and memory reasons.
+	//
+	// See NewImageOptions.Unmanaged.
 	Unmanaged bool
 
+	// AtlasMode represents whether the image is packed into an internal automatic texture atlas.
+	// See NewImageOptions.AtlasMode.
+	AtlasMode AtlasMode
+
 	// PreserveBounds represents whether the new image's bounds are the same as the given image.
 	// The default (zero) value is false, that means the new image's upper-left position is adjusted to (0, 0).
 	PreserveBounds bool
+
+	// AlphaMode represents how the source image's color values are interpreted.
+	// The default (zero) value is AlphaModeAuto.
+	//
+	// AlphaMode is ignored when the source image is an *Image.
+	AlphaMode AlphaMode
 }
 
+// AlphaMode represents how the color values of an image.Image are interpreted in terms of alpha.
+//
+// Ebitengine images hold colors in premultiplied alpha internally.
+// An image.Image's colors are converted into premultiplied alpha when the image is uploaded.
+type AlphaMode int
+
+const (
+	// AlphaModeAuto follows the color model of the source image.
+	// As a color.Color's RGBA always returns premultiplied alpha values in Go, the values are used as they are.
+	// For example, *image.NRGBA is treated as straight alpha and *image.RGBA is treated as premultiplied alpha.
+	//
+	// This works correctly as long as the source image's pixels match its color model.
+	// If an *image.RGBA actually holds straight-alpha values, e.g., pixels copied from a straight-alpha PNG without conversion,
+	// semi-transparent pixels look too bright and edges have light halos.
+	// If an *image.NRGBA actually holds premultiplied-alpha values, the pixels are premultiplied twice
+	// and edges have dark halos.
+	AlphaModeAuto AlphaMode = iota
+
+	// AlphaModePremultiplied treats the source image's color values as premultiplied alpha regardless of its color model.
+	//
+	// For *image.NRGBA, the pixels are used without premultiplying them.
+	// For the other images, AlphaModePremultiplied is the same as AlphaModeAuto.
+	AlphaModePremultiplied
+
+	// AlphaModeStraight treats the source image's color values as straight alpha regardless of its color model.
+	//
+	// The color values in 8 bits are premultiplied by their alpha values at uploading.
+	// For *image.NRGBA, AlphaModeStraight is the same as AlphaModeAuto.
+	AlphaModeStraight
+)
+
 // NewImageFromImageWithOptions creates a new image with the given image (source) with the given options.
 //
 // If source's width or height is less than 1 or more than device-dependent maximum size, NewImageFromImageWithOptions panics.
@@ -1172,6 +1922,7 @@ func NewImageFromImageWithOptions(source image.Image, options *NewImageFromImage
 	}
 	i := NewImageWithOptions(r, &NewImageOptions{
 		Unmanaged: options.Unmanaged,
+		AtlasMode: options.AtlasMode,
 	})
 
 	// If the given image is an Ebitengine image, use DrawImage instead of reading pixels from the source.
@@ -1186,7 +1937,7 @@ func NewImageFromImageWithOptions(source image.Image, options *NewImageFromImage
 		return i
 	}
 
-	i.WritePixels(imageToBytes(source))
+	i.WritePixels(imageToBytesWithAlphaMode(source, options.AlphaMode))
 	return i
 }
 
@@ -1234,6 +1985,13 @@ func (i *Image) ensureTmpVertices(n int) []float32 {
 	return i.tmpVertices[:n]
 }
 
+func (i *Image) ensureTmpIndices(n int) []uint32 {
+	if cap(i.tmpIndices) < n {
+		i.tmpIndices = make([]uint32, n)
+	}
+	return i.tmpIndices[:n]
+}
+
 // private implements FinalScreen.
 func (*Image) private() {
 }
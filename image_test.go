@@ -50,6 +50,12 @@ func skipTooSlowTests(t *testing.T) bool {
 		t.Skip("too slow or fragile on Wasm")
 		return true
 	}
+	var d ebiten.DebugInfo
+	ebiten.ReadDebugInfo(&d)
+	if d.GraphicsLibrary == ebiten.GraphicsLibrarySoftware {
+		t.Skip("too slow with the software renderer")
+		return true
+	}
 	return false
 }
 
@@ -1358,6 +1364,44 @@ func TestImageAddressRepeat(t *testing.T) {
 	}
 }
 
+func TestImageDrawImageAddressClampToEdge(t *testing.T) {
+	const w, h = 16, 16
+	src := ebiten.NewImage(w, h)
+	// The left half is red, and the right half is blue, like a sprite sheet.
+	src.SubImage(image.Rect(0, 0, w/2, h)).(*ebiten.Image).Fill(color.RGBA{R: 0xff, A: 0xff})
+	src.SubImage(image.Rect(w/2, 0, w, h)).(*ebiten.Image).Fill(color.RGBA{B: 0xff, A: 0xff})
+
+	for _, address := range []ebiten.Address{ebiten.AddressUnsafe, ebiten.AddressClampToEdge} {
+		address := address
+		t.Run(fmt.Sprintf("address %d", address), func(t *testing.T) {
+			dst := ebiten.NewImage(32, 32)
+			op := &ebiten.DrawImageOptions{}
+			op.GeoM.Translate(-w/4, -h/2)
+			op.GeoM.Rotate(math.Pi / 6)
+			op.GeoM.Scale(2, 2)
+			op.GeoM.Translate(16, 16)
+			op.Filter = ebiten.FilterLinear
+			op.Address = address
+			dst.DrawImage(src.SubImage(image.Rect(0, 0, w/2, h)).(*ebiten.Image), op)
+
+			var bled bool
+			for j := 0; j < 32; j++ {
+				for i := 0; i < 32; i++ {
+					if got := dst.At(i, j).(color.RGBA); got.B != 0 {
+						bled = true
+						if address == ebiten.AddressClampToEdge {
+							t.Errorf("dst.At(%d, %d): got: %v, want: no blue", i, j, got)
+						}
+					}
+				}
+			}
+			if address == ebiten.AddressUnsafe && !bled {
+				t.Log("the neighbor pixels didn't bleed with AddressUnsafe")
+			}
+		})
+	}
+}
+
 func TestImageAddressRepeatNegativePosition(t *testing.T) {
 	const w, h = 16, 16
 	src := ebiten.NewImage(w, h)
@@ -4218,6 +4262,54 @@ func TestImageBlendFactor(t *testing.T) {
 	}
 }
 
+func TestImageBlendConstantColor(t *testing.T) {
+	const w, h = 16, 16
+	dst := ebiten.NewImage(w, h)
+	src := ebiten.NewImage(w, h)
+
+	dstClr := color.RGBA{R: 0x40, G: 0x80, B: 0xc0, A: 0xff}
+	srcClr := color.RGBA{R: 0xc0, G: 0x60, B: 0x20, A: 0x80}
+	src.Fill(srcClr)
+
+	for _, c := range [][4]float32{
+		{0, 0, 0, 0},
+		{1, 1, 1, 1},
+		{0.25, 0.5, 0.75, 1},
+		{0.75, 0.5, 0.25, 0.5},
+	} {
+		dst.Fill(dstClr)
+		op := &ebiten.DrawImageOptions{}
+		op.Blend = ebiten.Blend{
+			BlendFactorSourceRGB:        ebiten.BlendFactorConstantColor,
+			BlendFactorSourceAlpha:      ebiten.BlendFactorConstantColor,
+			BlendFactorDestinationRGB:   ebiten.BlendFactorOneMinusConstantColor,
+			BlendFactorDestinationAlpha: ebiten.BlendFactorOneMinusConstantColor,
+			BlendOperationRGB:           ebiten.BlendOperationAdd,
+			BlendOperationAlpha:         ebiten.BlendOperationAdd,
+			BlendConstantColor:          c,
+		}
+		dst.DrawImage(src, op)
+
+		mix := func(s, d byte, k float32) byte {
+			return byte(math.Round(float64(float32(s)*k + float32(d)*(1-k))))
+		}
+		want := color.RGBA{
+			R: mix(srcClr.R, dstClr.R, c[0]),
+			G: mix(srcClr.G, dstClr.G, c[1]),
+			B: mix(srcClr.B, dstClr.B, c[2]),
+			A: mix(srcClr.A, dstClr.A, c[3]),
+		}
+		for j := 0; j < h; j++ {
+			for i := 0; i < w; i++ {
+				got := dst.At(i, j).(color.RGBA)
+				if !sameColors(got, want, 1) {
+					t.Errorf("constant color: %v: dst.At(%d, %d): got: %v, want: %v", c, i, j, got, want)
+				}
+			}
+		}
+	}
+}
+
 func TestImageAntiAlias(t *testing.T) {
 	// This value depends on internal/ui.bigOffscreenScale. Sync this.
 	const bigOffscreenScale = 2
@@ -4582,3 +4674,887 @@ func TestImageDrawImageAfterDeallocation(t *testing.T) {
 		}
 	}
 }
+
+func TestImageDrawTrianglesMulti(t *testing.T) {
+	whiteImage := ebiten.NewImage(3, 3)
+	whiteImage.Fill(color.White)
+	emptySubImage := whiteImage.SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image)
+
+	// Three nested rectangles as disjoint meshes in one vertex slice.
+	var vs []ebiten.Vertex
+	var is []uint16
+	var ranges []ebiten.IndexRange
+	for k, clr := range []color.RGBA{{R: 0xff, A: 0xff}, {G: 0xff, A: 0xff}, {B: 0xff, A: 0xff}} {
+		x0, y0 := float32(1+k), float32(1+k)
+		x1, y1 := float32(15-k), float32(15-k)
+		r, g, b, a := float32(clr.R)/0xff, float32(clr.G)/0xff, float32(clr.B)/0xff, float32(clr.A)/0xff
+		base := uint16(len(vs))
+		vs = append(vs,
+			ebiten.Vertex{DstX: x0, DstY: y0, SrcX: 1, SrcY: 1, ColorR: r, ColorG: g, ColorB: b, ColorA: a},
+			ebiten.Vertex{DstX: x1, DstY: y0, SrcX: 1, SrcY: 1, ColorR: r, ColorG: g, ColorB: b, ColorA: a},
+			ebiten.Vertex{DstX: x0, DstY: y1, SrcX: 1, SrcY: 1, ColorR: r, ColorG: g, ColorB: b, ColorA: a},
+			ebiten.Vertex{DstX: x1, DstY: y1, SrcX: 1, SrcY: 1, ColorR: r, ColorG: g, ColorB: b, ColorA: a},
+		)
+		ranges = append(ranges, ebiten.IndexRange{Start: len(is), Count: 6})
+		is = append(is, base, base+1, base+2, base+1, base+2, base+3)
+	}
+
+	for _, fillRule := range []ebiten.FillRule{ebiten.FillAll, ebiten.NonZero, ebiten.EvenOdd} {
+		fillRule := fillRule
+		var name string
+		switch fillRule {
+		case ebiten.FillAll:
+			name = "FillAll"
+		case ebiten.NonZero:
+			name = "NonZero"
+		case ebiten.EvenOdd:
+			name = "EvenOdd"
+		}
+		t.Run(name, func(t *testing.T) {
+			op := &ebiten.DrawTrianglesOptions{
+				FillRule: fillRule,
+			}
+
+			dst := ebiten.NewImage(16, 16)
+			dst.DrawTrianglesMulti(vs, is, ranges, emptySubImage, op)
+
+			// The result must be the same as drawing each range separately.
+			// Even with the even-odd rule, the inner rectangles don't make holes in the outer rectangles.
+			for j := 0; j < 16; j++ {
+				for i := 0; i < 16; i++ {
+					got := dst.At(i, j)
+					var want color.RGBA
+					switch {
+					case 3 <= i && i < 13 && 3 <= j && j < 13:
+						want = color.RGBA{B: 0xff, A: 0xff}
+					case 2 <= i && i < 14 && 2 <= j && j < 14:
+						want = color.RGBA{G: 0xff, A: 0xff}
+					case 1 <= i && i < 15 && 1 <= j && j < 15:
+						want = color.RGBA{R: 0xff, A: 0xff}
+					default:
+						want = color.RGBA{}
+					}
+					if got != want {
+						t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+					}
+				}
+			}
+
+			// Only the specified ranges are rendered.
+			dst.Clear()
+			dst.DrawTrianglesMulti(vs, is, ranges[1:2], emptySubImage, op)
+			for j := 0; j < 16; j++ {
+				for i := 0; i < 16; i++ {
+					got := dst.At(i, j)
+					var want color.RGBA
+					if 2 <= i && i < 14 && 2 <= j && j < 14 {
+						want = color.RGBA{G: 0xff, A: 0xff}
+					}
+					if got != want {
+						t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestImageDrawTrianglesMultiInvalidRange(t *testing.T) {
+	src := ebiten.NewImage(16, 16)
+	dst := ebiten.NewImage(16, 16)
+	vs := make([]ebiten.Vertex, 4)
+	is := []uint16{0, 1, 2, 1, 2, 3}
+
+	for _, r := range []ebiten.IndexRange{
+		{Start: 0, Count: 7},
+		{Start: 3, Count: 6},
+		{Start: -1, Count: 3},
+		{Start: 0, Count: 4},
+	} {
+		r := r
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("DrawTrianglesMulti with %+v must panic but not", r)
+				}
+			}()
+			dst.DrawTrianglesMulti(vs, is, []ebiten.IndexRange{r}, src, nil)
+		}()
+	}
+}
+
+func appendSmallTriangles(vs []ebiten.Vertex, is []uint16, ranges []ebiten.IndexRange, n int) ([]ebiten.Vertex, []uint16, []ebiten.IndexRange) {
+	for k := 0; k < n; k++ {
+		x := float32(k % 32 * 8)
+		y := float32(k / 32 * 8)
+		base := uint16(len(vs))
+		vs = append(vs,
+			ebiten.Vertex{DstX: x, DstY: y, SrcX: 0, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+			ebiten.Vertex{DstX: x + 6, DstY: y, SrcX: 1, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+			ebiten.Vertex{DstX: x, DstY: y + 6, SrcX: 0, SrcY: 1, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		)
+		ranges = append(ranges, ebiten.IndexRange{Start: len(is), Count: 3})
+		is = append(is, base, base+1, base+2)
+	}
+	return vs, is, ranges
+}
+
+func TestImageDrawTrianglesInstanced(t *testing.T) {
+	whiteImage := ebiten.NewImage(3, 3)
+	whiteImage.Fill(color.White)
+	emptySubImage := whiteImage.SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image)
+
+	// A 4x4 white square as a base mesh.
+	vs := []ebiten.Vertex{
+		{DstX: 0, DstY: 0, SrcX: 1, SrcY: 1, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: 4, DstY: 0, SrcX: 1, SrcY: 1, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: 0, DstY: 4, SrcX: 1, SrcY: 1, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: 4, DstY: 4, SrcX: 1, SrcY: 1, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+	}
+	is := []uint16{0, 1, 2, 1, 2, 3}
+
+	instances := make([]ebiten.Instance, 3)
+	instances[0].ColorScale.Scale(1, 0, 0, 1)
+	instances[1].GeoM.Translate(8, 0)
+	instances[1].ColorScale.Scale(0, 1, 0, 1)
+	instances[2].GeoM.Scale(2, 2)
+	instances[2].GeoM.Translate(4, 8)
+	instances[2].ColorScale.ScaleAlpha(0.5)
+
+	dst0 := ebiten.NewImage(16, 16)
+	dst0.DrawTrianglesInstanced(vs, is, instances, emptySubImage, nil)
+
+	// The result must be the same as drawing each instance separately.
+	dst1 := ebiten.NewImage(16, 16)
+	for _, inst := range instances {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(4, 4)
+		op.GeoM.Concat(inst.GeoM)
+		op.ColorScale = inst.ColorScale
+		dst1.DrawImage(emptySubImage, op)
+	}
+
+	for j := 0; j < 16; j++ {
+		for i := 0; i < 16; i++ {
+			got := dst0.At(i, j)
+			want := dst1.At(i, j)
+			if !sameColors(got.(color.RGBA), want.(color.RGBA), 1) {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+	if got, want := dst0.At(9, 1), (color.RGBA{G: 0xff, A: 0xff}); got != want {
+		t.Errorf("dst.At(9, 1): got: %v, want: %v", got, want)
+	}
+
+	// DrawTrianglesShaderInstanced passes the scaled colors to the shader.
+	s, err := ebiten.NewShader([]byte(`//kage:unit pixels
+
+package main
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	return color
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst2 := ebiten.NewImage(16, 16)
+	dst2.DrawTrianglesShaderInstanced(vs, is, instances, s, nil)
+	for j := 0; j < 16; j++ {
+		for i := 0; i < 16; i++ {
+			got := dst2.At(i, j)
+			want := dst1.At(i, j)
+			if !sameColors(got.(color.RGBA), want.(color.RGBA), 1) {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func BenchmarkDrawTrianglesInstanced(b *testing.B) {
+	src := ebiten.NewImage(16, 16)
+	dst := ebiten.NewImage(256, 256)
+	vs := []ebiten.Vertex{
+		{DstX: 0, DstY: 0, SrcX: 0, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: 4, DstY: 0, SrcX: 4, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: 0, DstY: 4, SrcX: 0, SrcY: 4, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: 4, DstY: 4, SrcX: 4, SrcY: 4, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+	}
+	is := []uint16{0, 1, 2, 1, 2, 3}
+	instances := make([]ebiten.Instance, 1000)
+	for k := range instances {
+		instances[k].GeoM.Translate(float64(k%64*4), float64(k/64*4))
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst.DrawTrianglesInstanced(vs, is, instances, src, nil)
+	}
+}
+
+func BenchmarkDrawTrianglesMulti(b *testing.B) {
+	src := ebiten.NewImage(16, 16)
+	dst := ebiten.NewImage(256, 256)
+	vs, is, ranges := appendSmallTriangles(nil, nil, nil, 1000)
+	op := &ebiten.DrawTrianglesOptions{
+		FillRule: ebiten.NonZero,
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst.DrawTrianglesMulti(vs, is, ranges, src, op)
+	}
+}
+
+func BenchmarkDrawTrianglesSeparately(b *testing.B) {
+	src := ebiten.NewImage(16, 16)
+	dst := ebiten.NewImage(256, 256)
+	vs, is, ranges := appendSmallTriangles(nil, nil, nil, 1000)
+	op := &ebiten.DrawTrianglesOptions{
+		FillRule: ebiten.NonZero,
+	}
+	is = []uint16{0, 1, 2}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for k := range ranges {
+			dst.DrawTriangles(vs[3*k:3*k+3], is, src, op)
+		}
+	}
+}
+
+func TestImageClone(t *testing.T) {
+	const (
+		w = 16
+		h = 16
+	)
+
+	pix := make([]byte, 4*w*h)
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			idx := 4 * (i + j*w)
+			pix[idx] = byte(i)
+			pix[idx+1] = byte(j)
+			pix[idx+2] = 0
+			pix[idx+3] = 0xff
+		}
+	}
+
+	src := ebiten.NewImage(w, h)
+	src.WritePixels(pix)
+
+	r := image.Rect(4, 4, 12, 12)
+	img := src.Clone(r)
+	if got, want := img.Bounds(), r; got != want {
+		t.Errorf("img.Bounds(): got: %v, want: %v", got, want)
+	}
+
+	// The clone must be independent from the original image.
+	src.Fill(color.RGBA{B: 0xff, A: 0xff})
+	src.Dispose()
+
+	for j := r.Min.Y; j < r.Max.Y; j++ {
+		for i := r.Min.X; i < r.Max.X; i++ {
+			got := img.At(i, j)
+			want := color.RGBA{R: byte(i), G: byte(j), A: 0xff}
+			if got != want {
+				t.Errorf("img.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+
+	if got := src.Clone(r); got != nil {
+		t.Errorf("Clone for a disposed image: got: %v, want: nil", got)
+	}
+}
+
+func TestImageMipmapMode(t *testing.T) {
+	const (
+		w = 16
+		h = 16
+	)
+
+	// Stripes of black and white.
+	pix := make([]byte, 4*w*h)
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			idx := 4 * (i + j*w)
+			if i%2 == 0 {
+				pix[idx] = 0xff
+				pix[idx+1] = 0xff
+				pix[idx+2] = 0xff
+			}
+			pix[idx+3] = 0xff
+		}
+	}
+	src := ebiten.NewImage(w, h)
+	src.WritePixels(pix)
+
+	for _, mode := range []ebiten.MipmapMode{ebiten.MipmapModeAuto, ebiten.MipmapModeEnabled} {
+		dst := ebiten.NewImage(w/4, h/4)
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(0.25, 0.25)
+		op.Filter = ebiten.FilterNearest
+		op.Mipmap = mode
+		dst.DrawImage(src, op)
+
+		got := dst.At(0, 0).(color.RGBA)
+		switch mode {
+		case ebiten.MipmapModeAuto:
+			// Without mipmaps, one of the stripes is picked.
+			if got.R != 0 && got.R != 0xff {
+				t.Errorf("mode: %d: got: %v, want: black or white", mode, got)
+			}
+		case ebiten.MipmapModeEnabled:
+			// With mipmaps, the stripes are averaged.
+			want := color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff}
+			if !sameColors(got, want, 2) {
+				t.Errorf("mode: %d: got: %v, want: %v", mode, got, want)
+			}
+		}
+	}
+}
+
+func TestImageReadPixelsOnNextFrame(t *testing.T) {
+	const (
+		w = 16
+		h = 16
+	)
+
+	img := ebiten.NewImage(w, h)
+	img.Fill(color.RGBA{R: 0xff, A: 0xff})
+
+	ch := make(chan []byte, 1)
+	p := img.ReadPixelsOnNextFrame(make([]byte, 4*w*h), func(pixels []byte) {
+		ch <- pixels
+	})
+
+	// The pixels are read in the order of the graphics commands, so the modification after ReadPixelsOnNextFrame is not included.
+	img.Fill(color.RGBA{B: 0xff, A: 0xff})
+
+	if p.IsReady() {
+		t.Errorf("IsReady(): got: true, want: false")
+	}
+
+	// The pixels are resolved at the next frame, but this test runs in one Update.
+	ebiten.ResolvePendingPixelsForTesting()
+
+	pix := <-ch
+	if !p.IsReady() {
+		t.Errorf("IsReady(): got: false, want: true")
+	}
+	if got, want := len(p.Pixels()), len(pix); got != want {
+		t.Errorf("len(Pixels()): got: %d, want: %d", got, want)
+	}
+	for i := 0; i < w*h; i++ {
+		got := color.RGBA{R: pix[4*i], G: pix[4*i+1], B: pix[4*i+2], A: pix[4*i+3]}
+		want := color.RGBA{R: 0xff, A: 0xff}
+		if got != want {
+			t.Errorf("pixel %d: got: %v, want: %v", i, got, want)
+		}
+	}
+}
+
+func TestImageReadPixelsOnNextFrameSubImage(t *testing.T) {
+	img := ebiten.NewImage(16, 16)
+	img.Fill(color.RGBA{R: 0xff, A: 0xff})
+	img.SubImage(image.Rect(4, 4, 8, 8)).(*ebiten.Image).Fill(color.RGBA{G: 0xff, A: 0xff})
+
+	sub := img.SubImage(image.Rect(2, 2, 6, 6)).(*ebiten.Image)
+	p := sub.ReadPixelsOnNextFrame(make([]byte, 4*4*4), nil)
+	ebiten.ResolvePendingPixelsForTesting()
+
+	pix := p.Pixels()
+	if pix == nil {
+		t.Fatalf("Pixels(): got: nil, want: non-nil")
+	}
+	for j := 0; j < 4; j++ {
+		for i := 0; i < 4; i++ {
+			idx := 4 * (j*4 + i)
+			got := color.RGBA{R: pix[idx], G: pix[idx+1], B: pix[idx+2], A: pix[idx+3]}
+			want := color.RGBA{R: 0xff, A: 0xff}
+			if i >= 2 && j >= 2 {
+				want = color.RGBA{G: 0xff, A: 0xff}
+			}
+			if got != want {
+				t.Errorf("At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageReadPixelsOnNextFrameDisposed(t *testing.T) {
+	img := ebiten.NewImage(16, 16)
+	img.Fill(color.RGBA{R: 0xff, A: 0xff})
+	var called bool
+	p := img.ReadPixelsOnNextFrame(make([]byte, 4*16*16), func(pixels []byte) {
+		called = true
+	})
+	img.Dispose()
+
+	ebiten.ResolvePendingPixelsForTesting()
+
+	// Disposing the image doesn't cancel reading the pixels.
+	if !p.IsReady() {
+		t.Errorf("IsReady(): got: false, want: true")
+	}
+	if !called {
+		t.Errorf("the callback must be called even for a disposed image")
+	}
+	if got, want := p.Pixels()[0], byte(0xff); got != want {
+		t.Errorf("Pixels()[0]: got: %d, want: %d", got, want)
+	}
+}
+
+func TestImageClearRegion(t *testing.T) {
+	const (
+		w = 16
+		h = 16
+	)
+
+	dst := ebiten.NewImage(w, h)
+	dst.Fill(color.RGBA{R: 0xff, A: 0xff})
+
+	// A translucent color replaces the pixels without blending.
+	clr := color.RGBA{G: 0x40, A: 0x80}
+	r := image.Rect(4, 6, 12, 10)
+	dst.ClearRegion(r, clr)
+
+	// The region is clipped with the bounds.
+	sub := dst.SubImage(image.Rect(12, 12, 16, 16)).(*ebiten.Image)
+	sub.ClearRegion(image.Rect(8, 8, 14, 14), color.RGBA{B: 0xff, A: 0xff})
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j)
+			want := color.RGBA{R: 0xff, A: 0xff}
+			if image.Pt(i, j).In(r) {
+				want = clr
+			}
+			if image.Pt(i, j).In(image.Rect(12, 12, 14, 14)) {
+				want = color.RGBA{B: 0xff, A: 0xff}
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func BenchmarkClearRegion4K(b *testing.B) {
+	const (
+		w = 3840
+		h = 2160
+	)
+
+	dst := ebiten.NewImage(w, h)
+	clr := color.RGBA{R: 0x40, G: 0x80, B: 0xc0, A: 0xff}
+	b.Run("ClearRegion", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			dst.ClearRegion(dst.Bounds(), clr)
+			// Call At to flush the commands.
+			_ = dst.At(0, 0)
+		}
+	})
+	b.Run("Fill", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			dst.Fill(clr)
+			_ = dst.At(0, 0)
+		}
+	})
+}
+
+func TestImageLinearBlending(t *testing.T) {
+	const (
+		w = 16
+		h = 16
+	)
+
+	dst := ebiten.NewImage(w, h)
+	dst.Fill(color.Black)
+
+	// Translucent white.
+	src := ebiten.NewImage(4, 4)
+	src.Fill(color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0x80})
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(4, 4)
+	op.LinearBlending = true
+	dst.DrawImage(src, op)
+
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			got := dst.At(i, j).(color.RGBA)
+			want := color.RGBA{A: 0xff}
+			if 4 <= i && i < 8 && 4 <= j && j < 8 {
+				// 0.5 in the linear space is about 0.735 in the sRGB space.
+				want = color.RGBA{R: 0xbc, G: 0xbc, B: 0xbc, A: 0xff}
+			}
+			if !sameColors(got, want, 2) {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestImageDrawImageIntegerTranslation(t *testing.T) {
+	const (
+		w = 16
+		h = 16
+	)
+
+	pix := make([]byte, 4*w*h)
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			idx := 4 * (i + j*w)
+			pix[idx] = byte(i * 0x10)
+			pix[idx+1] = byte(j * 0x10)
+			pix[idx+2] = 0
+			pix[idx+3] = 0xff
+		}
+	}
+	src := ebiten.NewImage(w, h)
+	src.WritePixels(pix)
+
+	dst := ebiten.NewImage(w*2, h*2)
+	sub := dst.SubImage(image.Rect(4, 4, w*2, h*2)).(*ebiten.Image)
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(3, 5)
+	sub.DrawImage(src.SubImage(image.Rect(2, 2, 10, 10)).(*ebiten.Image), op)
+
+	for j := 0; j < h*2; j++ {
+		for i := 0; i < w*2; i++ {
+			got := dst.At(i, j)
+			want := color.RGBA{}
+			// The sub-image's origin is at the upper-left of the original image.
+			if sx, sy := i-3+2, j-5+2; 4 <= i && 4 <= j && 2 <= sx && sx < 10 && 2 <= sy && sy < 10 {
+				want = color.RGBA{R: byte(sx * 0x10), G: byte(sy * 0x10), A: 0xff}
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func BenchmarkDrawTilemap(b *testing.B) {
+	const (
+		tileSize = 16
+		tilesX   = 100
+		tilesY   = 100
+	)
+
+	tileset := ebiten.NewImage(tileSize*16, tileSize*16)
+	tileset.Fill(color.White)
+	tiles := make([]*ebiten.Image, 256)
+	for i := range tiles {
+		x, y := (i%16)*tileSize, (i/16)*tileSize
+		tiles[i] = tileset.SubImage(image.Rect(x, y, x+tileSize, y+tileSize)).(*ebiten.Image)
+	}
+
+	dst := ebiten.NewImage(tileSize*tilesX, tileSize*tilesY)
+	op := &ebiten.DrawImageOptions{}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for j := 0; j < tilesY; j++ {
+			for i := 0; i < tilesX; i++ {
+				op.GeoM.Reset()
+				op.GeoM.Translate(float64(i*tileSize), float64(j*tileSize))
+				dst.DrawImage(tiles[(i+j*tilesX)%len(tiles)], op)
+			}
+		}
+	}
+}
+
+func TestImageDrawTrianglesRaw(t *testing.T) {
+	const (
+		w = 16
+		h = 16
+	)
+
+	src := ebiten.NewImage(w, h)
+	src.Fill(color.White)
+
+	vs := []ebiten.Vertex{
+		{DstX: 2, DstY: 2, SrcX: 0, SrcY: 0, ColorR: 1, ColorG: 0.5, ColorB: 0, ColorA: 0.5},
+		{DstX: 14, DstY: 2, SrcX: w, SrcY: 0, ColorR: 1, ColorG: 0.5, ColorB: 0, ColorA: 0.5},
+		{DstX: 2, DstY: 14, SrcX: 0, SrcY: h, ColorR: 0, ColorG: 0.5, ColorB: 1, ColorA: 1},
+		{DstX: 14, DstY: 14, SrcX: w, SrcY: h, ColorR: 0, ColorG: 0.5, ColorB: 1, ColorA: 1},
+	}
+	var raw []float32
+	for _, v := range vs {
+		raw = append(raw, v.DstX, v.DstY, v.SrcX, v.SrcY, v.ColorR, v.ColorG, v.ColorB, v.ColorA)
+	}
+
+	for _, mode := range []ebiten.ColorScaleMode{ebiten.ColorScaleModeStraightAlpha, ebiten.ColorScaleModePremultipliedAlpha} {
+		op := &ebiten.DrawTrianglesOptions{}
+		op.ColorScaleMode = mode
+
+		want := ebiten.NewImage(w, h)
+		want.DrawTriangles(vs, []uint16{0, 1, 2, 1, 2, 3}, src, op)
+		got := ebiten.NewImage(w, h)
+		got.DrawTrianglesRaw(raw, []uint32{0, 1, 2, 1, 2, 3}, src, op)
+
+		for j := 0; j < h; j++ {
+			for i := 0; i < w; i++ {
+				if got, want := got.At(i, j), want.At(i, j); got != want {
+					t.Errorf("mode: %d, At(%d, %d): got: %v, want: %v", mode, i, j, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestImageDrawTrianglesRawInvalidVertices(t *testing.T) {
+	src := ebiten.NewImage(16, 16)
+	dst := ebiten.NewImage(16, 16)
+
+	for _, c := range []struct {
+		vs []float32
+		is []uint32
+	}{
+		{vs: make([]float32, ebiten.RawVertexFloatCount*3+1), is: []uint32{0, 1, 2}},
+		{vs: make([]float32, ebiten.RawVertexFloatCount*3), is: []uint32{0, 1, 3}},
+		{vs: make([]float32, ebiten.RawVertexFloatCount*3), is: []uint32{0, 1}},
+	} {
+		c := c
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("DrawTrianglesRaw with len(vertices) %d and indices %v must panic but not", len(c.vs), c.is)
+				}
+			}()
+			dst.DrawTrianglesRaw(c.vs, c.is, src, nil)
+		}()
+	}
+}
+
+func BenchmarkDrawTrianglesRaw(b *testing.B) {
+	src := ebiten.NewImage(16, 16)
+	dst := ebiten.NewImage(256, 256)
+	vs, is16, _ := appendSmallTriangles(nil, nil, nil, 10000)
+	raw := make([]float32, 0, len(vs)*ebiten.RawVertexFloatCount)
+	for _, v := range vs {
+		raw = append(raw, v.DstX, v.DstY, v.SrcX, v.SrcY, v.ColorR, v.ColorG, v.ColorB, v.ColorA)
+	}
+	is := make([]uint32, len(is16))
+	for i := range is16 {
+		is[i] = uint32(is16[i])
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst.DrawTrianglesRaw(raw, is, src, nil)
+	}
+}
+
+func TestCaptureScreenAsImage(t *testing.T) {
+	img := ebiten.CaptureScreenAsImage()
+	if img == nil {
+		t.Fatal("CaptureScreenAsImage must not return nil in the main loop")
+	}
+	// The size is the one returned by the test game's Layout.
+	if got, want := img.Bounds(), image.Rect(0, 0, 320, 240); got != want {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+	// The test game draws nothing, and the screen is not transparent. Then, the captured image must be opaque black.
+	// See internal/processtest/testdata/capturescreenasimage.go for the content drawn in Draw.
+	for j := 0; j < 240; j++ {
+		for i := 0; i < 320; i++ {
+			got := color.RGBAModel.Convert(img.At(i, j)).(color.RGBA)
+			want := color.RGBA{0, 0, 0, 0xff}
+			if got != want {
+				t.Fatalf("img.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestAppendAtlasInfos(t *testing.T) {
+	// Use an odd size so that the image can be identified.
+	const (
+		w = 13
+		h = 11
+	)
+	img := ebiten.NewImage(w, h)
+	img.WritePixels(make([]byte, 4*w*h))
+	// Read the pixels to make sure the image is allocated on an atlas.
+	_ = img.At(0, 0)
+
+	infos := ebiten.AppendAtlasInfos(nil)
+	if len(infos) == 0 {
+		t.Fatalf("AppendAtlasInfos(nil) must not be empty")
+	}
+	var found bool
+	for _, info := range infos {
+		bounds := image.Rect(0, 0, info.Width, info.Height)
+		for _, r := range info.Regions {
+			if !r.In(bounds) {
+				t.Errorf("region %v must be in the atlas %v", r, bounds)
+			}
+			// A region might include a padding.
+			if w <= r.Dx() && r.Dx() <= w+2 && h <= r.Dy() && r.Dy() <= h+2 {
+				found = true
+			}
+		}
+	}
+	if !found {
+		t.Errorf("the image's region was not found in %v", infos)
+	}
+	runtime.KeepAlive(img)
+}
+
+func TestImageAtlasMode(t *testing.T) {
+	testCases := []struct {
+		Name    string
+		Options *ebiten.NewImageOptions
+		OnAtlas bool
+	}{
+		{
+			Name:    "unmanaged",
+			Options: &ebiten.NewImageOptions{Unmanaged: true},
+			OnAtlas: false,
+		},
+		{
+			Name:    "never",
+			Options: &ebiten.NewImageOptions{AtlasMode: ebiten.AtlasModeNever},
+			OnAtlas: false,
+		},
+		{
+			Name:    "always",
+			Options: &ebiten.NewImageOptions{AtlasMode: ebiten.AtlasModeAlways},
+			OnAtlas: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			// Use an odd size so that the image can be identified.
+			const (
+				w = 17
+				h = 19
+			)
+			img := ebiten.NewImageWithOptions(image.Rect(0, 0, w, h), tc.Options)
+			img.WritePixels(make([]byte, 4*w*h))
+			// Read the pixels to make sure the image is allocated.
+			_ = img.At(0, 0)
+
+			var found bool
+			for _, info := range ebiten.AppendAtlasInfos(nil) {
+				for _, r := range info.Regions {
+					if w <= r.Dx() && r.Dx() <= w+2 && h <= r.Dy() && r.Dy() <= h+2 {
+						found = true
+					}
+				}
+			}
+			if found != tc.OnAtlas {
+				t.Errorf("on an atlas: got: %v, want: %v", found, tc.OnAtlas)
+			}
+			img.Deallocate()
+		})
+	}
+}
+
+func TestImageAtlasModeWithUnmanaged(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("NewImageWithOptions with Unmanaged and AtlasModeAlways must panic but not")
+		}
+	}()
+	ebiten.NewImageWithOptions(image.Rect(0, 0, 16, 16), &ebiten.NewImageOptions{
+		Unmanaged: true,
+		AtlasMode: ebiten.AtlasModeAlways,
+	})
+}
+
+func TestImageWritePixelsRegion(t *testing.T) {
+	dst := ebiten.NewImage(17, 31)
+	dst.Fill(color.RGBA{R: 0xff, A: 0xff})
+
+	pix := make([]byte, 4*5*3)
+	for i := 0; i < len(pix)/4; i++ {
+		pix[4*i+1] = 0xff
+		pix[4*i+3] = 0xff
+	}
+	r0 := image.Rect(4, 5, 9, 8)
+	dst.WritePixelsRegion(pix, r0)
+
+	// The region is in the sub-image's coordinates, which are the same as the original image's.
+	r1 := image.Rect(11, 10, 16, 13)
+	sub := dst.SubImage(image.Rect(10, 10, 17, 31)).(*ebiten.Image)
+	for i := 0; i < len(pix)/4; i++ {
+		pix[4*i+1] = 0
+		pix[4*i+2] = 0xff
+	}
+	sub.WritePixelsRegion(pix, r1)
+
+	for j := 0; j < 31; j++ {
+		for i := 0; i < 17; i++ {
+			got := dst.At(i, j)
+			want := color.RGBA{R: 0xff, A: 0xff}
+			if image.Pt(i, j).In(r0) {
+				want = color.RGBA{G: 0xff, A: 0xff}
+			}
+			if image.Pt(i, j).In(r1) {
+				want = color.RGBA{B: 0xff, A: 0xff}
+			}
+			if got != want {
+				t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+
+	for _, tc := range []struct {
+		Pix    []byte
+		Region image.Rectangle
+	}{
+		{
+			Pix:    make([]byte, 4*5*3),
+			Region: image.Rect(0, 0, 5, 3).Add(image.Pt(-1, 0)),
+		},
+		{
+			Pix:    make([]byte, 4*5*3),
+			Region: image.Rect(5, 10, 10, 13),
+		},
+		{
+			Pix:    make([]byte, 4*5*2),
+			Region: image.Rect(10, 10, 15, 13),
+		},
+	} {
+		tc := tc
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("WritePixelsRegion with %v must panic but not", tc.Region)
+				}
+			}()
+			sub.WritePixelsRegion(tc.Pix, tc.Region)
+		}()
+	}
+}
+
+func BenchmarkWritePixelsRegion(b *testing.B) {
+	const (
+		w  = 1024
+		h  = 1024
+		rw = 64
+		rh = 64
+	)
+
+	dst := ebiten.NewImage(w, h)
+	b.Run("WritePixels", func(b *testing.B) {
+		pix := make([]byte, 4*w*h)
+		for n := 0; n < b.N; n++ {
+			pix[0] = byte(n)
+			dst.WritePixels(pix)
+			// Call At to flush the commands.
+			_ = dst.At(0, 0)
+		}
+	})
+	b.Run("WritePixelsRegion", func(b *testing.B) {
+		pix := make([]byte, 4*rw*rh)
+		for n := 0; n < b.N; n++ {
+			pix[0] = byte(n)
+			x, y := n%(w/rw)*rw, n/(w/rw)%(h/rh)*rh
+			dst.WritePixelsRegion(pix, image.Rect(x, y, x+rw, y+rh))
+			_ = dst.At(0, 0)
+		}
+	})
+}
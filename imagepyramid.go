@@ -0,0 +1,162 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"fmt"
+)
+
+// ImagePyramid is a chain of offscreen images, each of which is half the size of the previous one.
+//
+// ImagePyramid is useful for effects like bloom and blur, which need downsampling and upsampling chains.
+// A typical usage is:
+//
+//  1. Draw a source image to the level 0 image (e.g. with a shader extracting bright pixels).
+//  2. Call Generate to downsample the level 0 image to the other levels.
+//  3. Use the levels (e.g. draw the levels from the smallest to the largest with BlendLighter to upsample them).
+type ImagePyramid struct {
+	levels   []*Image
+	vertices []Vertex
+}
+
+// ImagePyramidOptions represents options for ImagePyramid.Generate.
+type ImagePyramidOptions struct {
+	// Shader is a shader to downsample a level to the next level.
+	// The source image of the shader is the previous level, and the destination is the next level.
+	// The shader's srcPos is in the previous level's pixels, and covers the whole previous level.
+	//
+	// If Shader is nil, each level is downsampled by a linear filter, which averages 2x2 pixels.
+	//
+	// The default (zero) value is nil.
+	Shader *Shader
+
+	// Uniforms is a set of uniform variables for Shader.
+	// Uniforms is ignored when Shader is nil.
+	//
+	// The default (zero) value is nil.
+	Uniforms map[string]any
+}
+
+// NewImagePyramid creates a new ImagePyramid whose level 0 is width x height.
+//
+// The size of each level is the half of the previous level's size, rounded up.
+// levelCount is the number of levels including the level 0.
+// If levelCount is 0 or less, levels are created until the size becomes 1x1.
+// If levelCount is too big, the number of the levels is limited so that the last level is 1x1.
+//
+// If width or height is less than or equal to 0, NewImagePyramid panics.
+func NewImagePyramid(width, height int, levelCount int) *ImagePyramid {
+	if width <= 0 {
+		panic(fmt.Sprintf("ebiten: width at NewImagePyramid must be positive but %d", width))
+	}
+	if height <= 0 {
+		panic(fmt.Sprintf("ebiten: height at NewImagePyramid must be positive but %d", height))
+	}
+
+	p := &ImagePyramid{}
+	w, h := width, height
+	for {
+		p.levels = append(p.levels, NewImage(w, h))
+		if levelCount > 0 && len(p.levels) >= levelCount {
+			break
+		}
+		if w == 1 && h == 1 {
+			break
+		}
+		w = (w + 1) / 2
+		h = (h + 1) / 2
+	}
+	return p
+}
+
+// LevelCount returns the number of the levels.
+func (p *ImagePyramid) LevelCount() int {
+	return len(p.levels)
+}
+
+// Level returns the image at the given level.
+// The level 0 is the largest image.
+//
+// The returned image is owned by the pyramid.
+// Drawing to the level 0 image is the way to give a source to the pyramid.
+// The other levels are overwritten by Generate.
+//
+// If level is out of range, Level panics.
+func (p *ImagePyramid) Level(level int) *Image {
+	if level < 0 || level >= len(p.levels) {
+		panic(fmt.Sprintf("ebiten: level at ImagePyramid.Level is out of range: %d", level))
+	}
+	return p.levels[level]
+}
+
+// Generate downsamples the level 0 image to the other levels successively.
+// Each level is generated from the previous level, not from the level 0.
+//
+// options can be nil. In this case, the default options are used.
+func (p *ImagePyramid) Generate(options *ImagePyramidOptions) {
+	if options == nil {
+		options = &ImagePyramidOptions{}
+	}
+
+	for i := 1; i < len(p.levels); i++ {
+		src := p.levels[i-1]
+		dst := p.levels[i]
+		sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+		dw, dh := dst.Bounds().Dx(), dst.Bounds().Dy()
+
+		if options.Shader == nil {
+			op := &DrawImageOptions{}
+			op.GeoM.Scale(float64(dw)/float64(sw), float64(dh)/float64(sh))
+			op.Filter = FilterLinear
+			op.Address = AddressClampToEdge
+			op.Blend = BlendCopy
+			dst.DrawImage(src, op)
+			continue
+		}
+
+		p.vertices = p.vertices[:0]
+		for _, v := range [][4]float32{
+			{0, 0, 0, 0},
+			{float32(dw), 0, float32(sw), 0},
+			{0, float32(dh), 0, float32(sh)},
+			{float32(dw), float32(dh), float32(sw), float32(sh)},
+		} {
+			p.vertices = append(p.vertices, Vertex{
+				DstX:   v[0],
+				DstY:   v[1],
+				SrcX:   v[2],
+				SrcY:   v[3],
+				ColorR: 1,
+				ColorG: 1,
+				ColorB: 1,
+				ColorA: 1,
+			})
+		}
+		op := &DrawTrianglesShaderOptions{}
+		op.Uniforms = options.Uniforms
+		op.Images[0] = src
+		op.Blend = BlendCopy
+		dst.DrawTrianglesShader(p.vertices, []uint16{0, 1, 2, 1, 2, 3}, options.Shader, op)
+	}
+}
+
+// Deallocate deallocates all the levels.
+// Even after Deallocate is called, the pyramid is still available.
+// In this case, the levels' internal states are allocated again.
+func (p *ImagePyramid) Deallocate() {
+	for _, img := range p.levels {
+		img.Deallocate()
+	}
+}
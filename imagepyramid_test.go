@@ -0,0 +1,170 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestImagePyramidLevelSizes(t *testing.T) {
+	testCases := []struct {
+		Width      int
+		Height     int
+		LevelCount int
+		Sizes      []image.Point
+	}{
+		{
+			Width:      16,
+			Height:     8,
+			LevelCount: 0,
+			Sizes:      []image.Point{{16, 8}, {8, 4}, {4, 2}, {2, 1}, {1, 1}},
+		},
+		{
+			Width:      5,
+			Height:     3,
+			LevelCount: 0,
+			Sizes:      []image.Point{{5, 3}, {3, 2}, {2, 1}, {1, 1}},
+		},
+		{
+			Width:      16,
+			Height:     16,
+			LevelCount: 3,
+			Sizes:      []image.Point{{16, 16}, {8, 8}, {4, 4}},
+		},
+		{
+			Width:      2,
+			Height:     2,
+			LevelCount: 10,
+			Sizes:      []image.Point{{2, 2}, {1, 1}},
+		},
+	}
+	for _, tc := range testCases {
+		p := ebiten.NewImagePyramid(tc.Width, tc.Height, tc.LevelCount)
+		if got, want := p.LevelCount(), len(tc.Sizes); got != want {
+			t.Errorf("NewImagePyramid(%d, %d, %d).LevelCount(): got: %d, want: %d", tc.Width, tc.Height, tc.LevelCount, got, want)
+			continue
+		}
+		for i, s := range tc.Sizes {
+			if got, want := p.Level(i).Bounds().Size(), s; got != want {
+				t.Errorf("NewImagePyramid(%d, %d, %d).Level(%d).Bounds().Size(): got: %v, want: %v", tc.Width, tc.Height, tc.LevelCount, i, got, want)
+			}
+		}
+	}
+}
+
+func TestImagePyramidGenerate(t *testing.T) {
+	const w, h = 16, 16
+	p := ebiten.NewImagePyramid(w, h, 0)
+
+	// Fill the level 0 with a checker pattern of 2x2 blocks.
+	clr0 := color.RGBA{R: 0xff, A: 0xff}
+	clr1 := color.RGBA{B: 0xff, A: 0xff}
+	pix := make([]byte, 4*w*h)
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			clr := clr0
+			if (i/2+j/2)%2 == 1 {
+				clr = clr1
+			}
+			idx := 4 * (j*w + i)
+			pix[idx] = clr.R
+			pix[idx+1] = clr.G
+			pix[idx+2] = clr.B
+			pix[idx+3] = clr.A
+		}
+	}
+	p.Level(0).WritePixels(pix)
+	p.Generate(nil)
+
+	// The level 1 keeps the checker pattern of 1x1 blocks.
+	l1 := p.Level(1)
+	for j := 0; j < h/2; j++ {
+		for i := 0; i < w/2; i++ {
+			got := l1.At(i, j).(color.RGBA)
+			want := clr0
+			if (i+j)%2 == 1 {
+				want = clr1
+			}
+			if !sameColors(got, want, 1) {
+				t.Errorf("Level(1).At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+
+	// The other levels are averaged.
+	want := color.RGBA{R: 0x80, B: 0x80, A: 0xff}
+	for l := 2; l < p.LevelCount(); l++ {
+		img := p.Level(l)
+		s := img.Bounds().Size()
+		for j := 0; j < s.Y; j++ {
+			for i := 0; i < s.X; i++ {
+				got := img.At(i, j).(color.RGBA)
+				if !sameColors(got, want, 2) {
+					t.Errorf("Level(%d).At(%d, %d): got: %v, want: %v", l, i, j, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestImagePyramidGenerateWithShader(t *testing.T) {
+	const w, h = 8, 8
+	p := ebiten.NewImagePyramid(w, h, 0)
+	p.Level(0).Fill(color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff})
+
+	s, err := ebiten.NewShader([]byte(`//kage:unit pixels
+
+package main
+
+var Scale float
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	return imageSrc0At(srcPos) * Scale
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p.Generate(&ebiten.ImagePyramidOptions{
+		Shader: s,
+		Uniforms: map[string]any{
+			"Scale": 0.5,
+		},
+	})
+
+	// Each level is generated from the previous level, so the scale is applied successively.
+	for l, want := range []color.RGBA{
+		{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+		{R: 0x80, G: 0x80, B: 0x80, A: 0x80},
+		{R: 0x40, G: 0x40, B: 0x40, A: 0x40},
+		{R: 0x20, G: 0x20, B: 0x20, A: 0x20},
+	} {
+		img := p.Level(l)
+		s := img.Bounds().Size()
+		for j := 0; j < s.Y; j++ {
+			for i := 0; i < s.X; i++ {
+				got := img.At(i, j).(color.RGBA)
+				if !sameColors(got, want, 2) {
+					t.Errorf("Level(%d).At(%d, %d): got: %v, want: %v", l, i, j, got, want)
+				}
+			}
+		}
+	}
+}
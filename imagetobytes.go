@@ -88,3 +88,50 @@ func imageToBytesSlow(img image.Image) []byte {
 	draw.Draw(dstImg, image.Rect(0, 0, w, h), img, img.Bounds().Min, draw.Src)
 	return bs
 }
+
+// imageToBytesWithAlphaMode returns RGBA byte slice of premultiplied alpha colors from the given image
+// with interpreting the image's color values by the given alpha mode.
+//
+// Unlike imageToBytes, the returned slice never shares the underlying array with the image
+// when mode is not AlphaModeAuto.
+func imageToBytesWithAlphaMode(img image.Image, mode AlphaMode) []byte {
+	switch mode {
+	case AlphaModePremultiplied:
+		if img, ok := img.(*image.NRGBA); ok {
+			return copyPix(img.Pix, img.Stride, img.Bounds().Size())
+		}
+		return imageToBytes(img)
+	case AlphaModeStraight:
+		if _, ok := img.(*image.NRGBA); ok {
+			return imageToBytes(img)
+		}
+		var bs []byte
+		if img, ok := img.(*image.RGBA); ok {
+			bs = copyPix(img.Pix, img.Stride, img.Bounds().Size())
+		} else {
+			bs = imageToBytes(img)
+		}
+		for i := 0; i < len(bs)/4; i++ {
+			a := uint32(bs[4*i+3])
+			if a == 0xff {
+				continue
+			}
+			bs[4*i] = byte((uint32(bs[4*i])*a + 0x7f) / 0xff)
+			bs[4*i+1] = byte((uint32(bs[4*i+1])*a + 0x7f) / 0xff)
+			bs[4*i+2] = byte((uint32(bs[4*i+2])*a + 0x7f) / 0xff)
+		}
+		return bs
+	default:
+		return imageToBytes(img)
+	}
+}
+
+// copyPix copies 4-bytes-per-pixel pixels starting with the 0-th index into a new slice without paddings.
+func copyPix(pix []byte, stride int, size image.Point) []byte {
+	w, h := size.X, size.Y
+	bs := make([]byte, 4*w*h)
+	for j := 0; j < h; j++ {
+		copy(bs[4*w*j:4*w*(j+1)], pix[stride*j:stride*j+4*w])
+	}
+	return bs
+}
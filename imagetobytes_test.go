@@ -101,6 +101,68 @@ func TestImageToBytes(t *testing.T) {
 	}
 }
 
+func TestImageToBytesWithAlphaMode(t *testing.T) {
+	rgba := &image.RGBA{
+		Pix:    []byte{0x80, 0x40, 0x20, 0x80, 0xff, 0xff, 0xff, 0},
+		Stride: 8,
+		Rect:   image.Rect(0, 0, 2, 1),
+	}
+	nrgba := &image.NRGBA{
+		Pix:    []byte{0x80, 0x40, 0x20, 0x80, 0xff, 0xff, 0xff, 0},
+		Stride: 8,
+		Rect:   image.Rect(0, 0, 2, 1),
+	}
+
+	cases := []struct {
+		In   image.Image
+		Mode ebiten.AlphaMode
+		Out  []byte
+	}{
+		{
+			In:   rgba,
+			Mode: ebiten.AlphaModeAuto,
+			Out:  []byte{0x80, 0x40, 0x20, 0x80, 0xff, 0xff, 0xff, 0},
+		},
+		{
+			In:   rgba,
+			Mode: ebiten.AlphaModePremultiplied,
+			Out:  []byte{0x80, 0x40, 0x20, 0x80, 0xff, 0xff, 0xff, 0},
+		},
+		{
+			In:   rgba,
+			Mode: ebiten.AlphaModeStraight,
+			Out:  []byte{0x40, 0x20, 0x10, 0x80, 0, 0, 0, 0},
+		},
+		{
+			In:   nrgba,
+			Mode: ebiten.AlphaModeAuto,
+			Out:  []byte{0x40, 0x20, 0x10, 0x80, 0, 0, 0, 0},
+		},
+		{
+			In:   nrgba,
+			Mode: ebiten.AlphaModePremultiplied,
+			Out:  []byte{0x80, 0x40, 0x20, 0x80, 0xff, 0xff, 0xff, 0},
+		},
+		{
+			In:   nrgba,
+			Mode: ebiten.AlphaModeStraight,
+			Out:  []byte{0x40, 0x20, 0x10, 0x80, 0, 0, 0, 0},
+		},
+	}
+	for i, c := range cases {
+		got := ebiten.ImageToBytesWithAlphaMode(c.In, c.Mode)
+		want := c.Out
+		if !bytes.Equal(got, want) {
+			t.Errorf("Test %d: got: %v, want: %v", i, got, want)
+		}
+	}
+
+	// The source image must not be modified.
+	if got, want := rgba.Pix, []byte{0x80, 0x40, 0x20, 0x80, 0xff, 0xff, 0xff, 0}; !bytes.Equal(got, want) {
+		t.Errorf("rgba.Pix: got: %v, want: %v", got, want)
+	}
+}
+
 func BenchmarkImageToBytesRGBA(b *testing.B) {
 	img := image.NewRGBA(image.Rect(0, 0, 4096, 4096))
 	b.ResetTimer()
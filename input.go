@@ -92,6 +92,44 @@ func CursorPosition() (x, y int) {
 	return int(cx), int(cy)
 }
 
+// CursorPositionF returns a position of a mouse cursor relative to the game screen (window) with fractional values.
+//
+// CursorPositionF is the same as CursorPosition except that the result is not truncated to integers.
+// The cursor position is 'logical' position and the device scale factor is already considered,
+// so the fractional part represents a sub-pixel position on high-DPI displays or a scaled screen.
+// This is useful for e.g., drawing applications or effects smoothly following the cursor.
+//
+// CursorPositionF returns (0, 0) before the main loop on desktops and browsers.
+//
+// CursorPositionF always returns (0, 0) on mobiles.
+//
+// CursorPositionF is concurrent-safe.
+func CursorPositionF() (x, y float64) {
+	return theInputState.cursorPosition()
+}
+
+// CursorDelta returns the movement of a mouse cursor in the current tick.
+// The movement is in the same 'logical' coordinate as CursorPosition, but has fractional values.
+//
+// CursorDelta is useful with CursorModeCaptured, e.g., for a camera control of a first-person game.
+// While the cursor is captured, the cursor movement is reported without being interrupted by the screen edges:
+//
+//   - On desktops, the cursor is hidden and locked to the window, and the movement of the virtual cursor is reported.
+//   - On browsers, the Pointer Lock API is used, and the movement is based on movementX and movementY of the mouse events.
+//     See also SetCursorMode for the restrictions of capturing a cursor on browsers.
+//
+// CursorDelta reports the movement also when the cursor is not captured.
+// The cursor movement by entering or exiting fullscreen is not treated as a movement.
+//
+// CursorDelta returns (0, 0) before the main loop on desktops and browsers.
+//
+// CursorDelta always returns (0, 0) on mobiles.
+//
+// CursorDelta is concurrent-safe.
+func CursorDelta() (dx, dy float64) {
+	return theInputState.cursorDelta()
+}
+
 // Wheel returns x and y offsets of the mouse wheel or touchpad scroll.
 // It returns 0 if the wheel isn't being rolled.
 //
@@ -316,6 +354,36 @@ func IsStandardGamepadButtonAvailable(id GamepadID, button StandardGamepadButton
 	return g.IsStandardButtonAvailable(button)
 }
 
+// GamepadBatteryLevel returns the battery charge [0.0 - 1.0] and the battery status of the given gamepad (id).
+//
+// GamepadBatteryLevel returns 0 and GamepadBatteryStatusUnknown when the environment doesn't provide the battery information.
+// The battery information is available on Windows (XInput gamepads) and Xbox so far.
+// XInput reports only a coarse charge level and doesn't report whether the battery is charging,
+// so the level is one of 0, 1/3, 2/3, and 1, and the status is never GamepadBatteryStatusCharging on Windows.
+//
+// GamepadBatteryLevel is concurrent-safe.
+func GamepadBatteryLevel(id GamepadID) (level float64, status GamepadBatteryStatus) {
+	g := gamepad.Get(id)
+	if g == nil {
+		return 0, GamepadBatteryStatusUnknown
+	}
+	return g.BatteryLevel()
+}
+
+// GamepadConnection returns how the given gamepad (id) is connected, i.e., wired or wireless.
+//
+// GamepadConnection returns GamepadConnectionTypeUnknown when the environment doesn't provide the information.
+// The connection type is available on Windows (XInput gamepads) and Xbox so far.
+//
+// GamepadConnection is concurrent-safe.
+func GamepadConnection(id GamepadID) GamepadConnectionType {
+	g := gamepad.Get(id)
+	if g == nil {
+		return GamepadConnectionTypeUnknown
+	}
+	return g.ConnectionType()
+}
+
 // UpdateStandardGamepadLayoutMappings parses the specified string mappings in SDL_GameControllerDB format and
 // updates the gamepad layout definitions.
 //
@@ -387,6 +455,62 @@ func TouchPosition(id TouchID) (int, int) {
 	return theInputState.touchPosition(id)
 }
 
+// TouchPressure returns the pressure for the touch of the specified ID.
+//
+// The pressure is normalized in between 0 and 1.
+// If the environment doesn't report pressures, TouchPressure returns 1.
+// Pressures are reported on Android, iOS (devices with 3D Touch or Apple Pencil), and browsers supporting Touch.force.
+//
+// If the touch of the specified ID is not present, TouchPressure returns 0.
+//
+// TouchPressure is concurrent-safe.
+func TouchPressure(id TouchID) float64 {
+	return theInputState.touchPressure(id)
+}
+
+// TouchRadius returns the radius of the contact area for the touch of the specified ID.
+//
+// The radius is in the same 'logical' coordinate as TouchPosition.
+// If the environment doesn't report contact areas, TouchRadius returns 0.
+// Radii are reported on Android, iOS, and browsers supporting Touch.radiusX and Touch.radiusY.
+//
+// If the touch of the specified ID is not present, TouchRadius returns 0.
+//
+// TouchRadius is concurrent-safe.
+func TouchRadius(id TouchID) float64 {
+	return theInputState.touchRadius(id)
+}
+
+// ReadClipboardText returns the text in the system clipboard.
+//
+// ReadClipboardText returns an empty string when the clipboard doesn't have a text.
+// ReadClipboardText returns an error when the environment doesn't support the clipboard.
+// The clipboard is supported on desktops and browsers.
+//
+// On browsers, ReadClipboardText uses the asynchronous Clipboard API and waits for the result,
+// so ReadClipboardText might block the game for a while, e.g., while the browser asks the user for a permission.
+// The Clipboard API is available only in secure contexts (HTTPS), and reading might require a user gesture like
+// a key press or a click just before the call. Otherwise, ReadClipboardText returns an error.
+//
+// ReadClipboardText returns an error if it is called before the game starts on desktops.
+func ReadClipboardText() (string, error) {
+	return ui.Get().ReadClipboardText()
+}
+
+// WriteClipboardText writes the text to the system clipboard.
+//
+// WriteClipboardText returns an error when the environment doesn't support the clipboard.
+// The clipboard is supported on desktops and browsers.
+//
+// On browsers, WriteClipboardText uses the asynchronous Clipboard API and waits for the result.
+// The Clipboard API is available only in secure contexts (HTTPS), and writing might require a user gesture like
+// a key press or a click just before the call. Otherwise, WriteClipboardText returns an error.
+//
+// WriteClipboardText returns an error if it is called before the game starts on desktops.
+func WriteClipboardText(text string) error {
+	return ui.Get().WriteClipboardText(text)
+}
+
 var theInputState inputState
 
 type inputState struct {
@@ -434,6 +558,12 @@ func (i *inputState) cursorPosition() (float64, float64) {
 	return i.state.CursorX, i.state.CursorY
 }
 
+func (i *inputState) cursorDelta() (float64, float64) {
+	i.m.Lock()
+	defer i.m.Unlock()
+	return i.state.CursorDeltaX, i.state.CursorDeltaY
+}
+
 func (i *inputState) wheel() (float64, float64) {
 	i.m.Lock()
 	defer i.m.Unlock()
@@ -469,6 +599,32 @@ func (i *inputState) touchPosition(id TouchID) (int, int) {
 	return 0, 0
 }
 
+func (i *inputState) touchPressure(id TouchID) float64 {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	for _, t := range i.state.Touches {
+		if id != t.ID {
+			continue
+		}
+		return t.Pressure
+	}
+	return 0
+}
+
+func (i *inputState) touchRadius(id TouchID) float64 {
+	i.m.Lock()
+	defer i.m.Unlock()
+
+	for _, t := range i.state.Touches {
+		if id != t.ID {
+			continue
+		}
+		return t.Radius
+	}
+	return 0
+}
+
 func (i *inputState) windowBeingClosed() bool {
 	i.m.Lock()
 	defer i.m.Unlock()
@@ -480,3 +636,9 @@ func (i *inputState) droppedFiles() fs.FS {
 	defer i.m.Unlock()
 	return i.state.DroppedFiles
 }
+
+func (i *inputState) droppedFilesPosition() (float64, float64) {
+	i.m.Lock()
+	defer i.m.Unlock()
+	return i.state.DroppedFilesX, i.state.DroppedFilesY
+}
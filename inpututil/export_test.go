@@ -0,0 +1,37 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inpututil
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type TouchForTesting struct {
+	ID ebiten.TouchID
+	X  int
+	Y  int
+}
+
+func (g *GestureRecognizer) UpdateWithTouchesForTesting(touches []TouchForTesting) {
+	inputs := make([]touchInput, 0, len(touches))
+	for _, t := range touches {
+		inputs = append(inputs, touchInput{
+			id: t.ID,
+			x:  t.X,
+			y:  t.Y,
+		})
+	}
+	g.update(inputs)
+}
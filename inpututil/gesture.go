@@ -0,0 +1,403 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inpututil
+
+import (
+	"math"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// GestureType represents a type of a touch gesture.
+type GestureType int
+
+const (
+	// GestureTypeTap represents a short touch without moving.
+	GestureTypeTap GestureType = iota
+
+	// GestureTypeDoubleTap represents a tap following another tap quickly at a close position.
+	GestureTypeDoubleTap
+
+	// GestureTypeLongPress represents a long touch without moving.
+	GestureTypeLongPress
+
+	// GestureTypeSwipe represents a quick touch moving in a direction.
+	GestureTypeSwipe
+)
+
+// String returns a string representing the gesture type.
+func (g GestureType) String() string {
+	switch g {
+	case GestureTypeTap:
+		return "Tap"
+	case GestureTypeDoubleTap:
+		return "DoubleTap"
+	case GestureTypeLongPress:
+		return "LongPress"
+	case GestureTypeSwipe:
+		return "Swipe"
+	}
+	return ""
+}
+
+// SwipeDirection represents a direction of a swipe gesture.
+type SwipeDirection int
+
+const (
+	SwipeDirectionLeft SwipeDirection = iota
+	SwipeDirectionRight
+	SwipeDirectionUp
+	SwipeDirectionDown
+)
+
+// String returns a string representing the swipe direction.
+func (s SwipeDirection) String() string {
+	switch s {
+	case SwipeDirectionLeft:
+		return "Left"
+	case SwipeDirectionRight:
+		return "Right"
+	case SwipeDirectionUp:
+		return "Up"
+	case SwipeDirectionDown:
+		return "Down"
+	}
+	return ""
+}
+
+// Gesture represents a recognized touch gesture.
+type Gesture struct {
+	// Type is the type of the gesture.
+	Type GestureType
+
+	// TouchID is the ID of the touch that makes the gesture.
+	// For a double tap, TouchID is the ID of the second touch.
+	TouchID ebiten.TouchID
+
+	// StartX and StartY are the position where the touch started.
+	StartX int
+	StartY int
+
+	// X and Y are the position of the touch when the gesture is recognized.
+	// For a tap, a double tap, and a swipe, this is the position where the touch was released.
+	// For a long press, this is the current position of the touch.
+	X int
+	Y int
+
+	// Direction is the direction of the swipe.
+	// Direction is valid only when Type is GestureTypeSwipe.
+	Direction SwipeDirection
+}
+
+// GestureRecognizerOptions represents options for a GestureRecognizer.
+//
+// The durations are in ticks, and the distances are in the same units as ebiten.TouchPosition.
+// A zero value of each field means the default value.
+type GestureRecognizerOptions struct {
+	// TapMaxDuration is the maximum duration of a touch to be a tap.
+	//
+	// The default (zero) value is 15.
+	TapMaxDuration int
+
+	// DoubleTapMaxInterval is the maximum duration between the end of the first tap and the end of the second tap
+	// to be a double tap.
+	//
+	// The default (zero) value is 20.
+	DoubleTapMaxInterval int
+
+	// LongPressDuration is the duration of a touch to be a long press.
+	//
+	// The default (zero) value is 30.
+	LongPressDuration int
+
+	// SwipeMaxDuration is the maximum duration of a touch to be a swipe.
+	//
+	// The default (zero) value is 30.
+	SwipeMaxDuration int
+
+	// TapMaxDistance is the maximum distance that a touch can move to be a tap or a long press.
+	// TapMaxDistance is also used as the maximum distance between two taps to be a double tap.
+	//
+	// The default (zero) value is 10.
+	TapMaxDistance float64
+
+	// SwipeMinDistance is the minimum distance that a touch must move to be a swipe.
+	//
+	// The default (zero) value is 30.
+	SwipeMinDistance float64
+}
+
+type gestureTouch struct {
+	startX      int
+	startY      int
+	startTick   int
+	lastX       int
+	lastY       int
+	moved       bool
+	longPressed bool
+}
+
+// touchInput represents a touch pressed in a tick.
+type touchInput struct {
+	id ebiten.TouchID
+	x  int
+	y  int
+}
+
+type lastTap struct {
+	x    int
+	y    int
+	tick int
+}
+
+// GestureRecognizer recognizes touch gestures like taps, double taps, long presses, and swipes.
+//
+// Each touch is tracked by its touch ID, so gestures by multiple touches can be recognized at the same time.
+//
+// GestureRecognizer's Update must be called in every game's Update.
+type GestureRecognizer struct {
+	tapMaxDuration       int
+	doubleTapMaxInterval int
+	longPressDuration    int
+	swipeMaxDuration     int
+	tapMaxDistance       float64
+	swipeMinDistance     float64
+
+	tick     int
+	touches  map[ebiten.TouchID]*gestureTouch
+	lastTap  *lastTap
+	gestures []Gesture
+
+	touchIDsBuf []ebiten.TouchID
+	inputsBuf   []touchInput
+	releasedBuf []ebiten.TouchID
+}
+
+// NewGestureRecognizer creates a new GestureRecognizer.
+//
+// options can be nil. In this case, the default options are used.
+func NewGestureRecognizer(options *GestureRecognizerOptions) *GestureRecognizer {
+	if options == nil {
+		options = &GestureRecognizerOptions{}
+	}
+	g := &GestureRecognizer{
+		tapMaxDuration:       15,
+		doubleTapMaxInterval: 20,
+		longPressDuration:    30,
+		swipeMaxDuration:     30,
+		tapMaxDistance:       10,
+		swipeMinDistance:     30,
+		touches:              map[ebiten.TouchID]*gestureTouch{},
+	}
+	if options.TapMaxDuration > 0 {
+		g.tapMaxDuration = options.TapMaxDuration
+	}
+	if options.DoubleTapMaxInterval > 0 {
+		g.doubleTapMaxInterval = options.DoubleTapMaxInterval
+	}
+	if options.LongPressDuration > 0 {
+		g.longPressDuration = options.LongPressDuration
+	}
+	if options.SwipeMaxDuration > 0 {
+		g.swipeMaxDuration = options.SwipeMaxDuration
+	}
+	if options.TapMaxDistance > 0 {
+		g.tapMaxDistance = options.TapMaxDistance
+	}
+	if options.SwipeMinDistance > 0 {
+		g.swipeMinDistance = options.SwipeMinDistance
+	}
+	return g
+}
+
+// Update updates the state of the gesture recognizer with the current touches.
+//
+// Update must be called once in every game's Update, not Draw.
+// Otherwise, some touches might not be tracked correctly.
+func (g *GestureRecognizer) Update() {
+	g.touchIDsBuf = ebiten.AppendTouchIDs(g.touchIDsBuf[:0])
+	g.inputsBuf = g.inputsBuf[:0]
+	for _, id := range g.touchIDsBuf {
+		x, y := ebiten.TouchPosition(id)
+		g.inputsBuf = append(g.inputsBuf, touchInput{
+			id: id,
+			x:  x,
+			y:  y,
+		})
+	}
+	g.update(g.inputsBuf)
+}
+
+// update advances the tick and updates the state with the touches pressed in the tick.
+//
+// A touch tracked in the previous tick and missing in touches is treated as released at its last position.
+func (g *GestureRecognizer) update(touches []touchInput) {
+	g.tick++
+	g.gestures = g.gestures[:0]
+
+	// Handle the released touches first, so that a double tap can be recognized even when a new touch starts
+	// at the same tick.
+	g.releasedBuf = g.releasedBuf[:0]
+	for id := range g.touches {
+		if !containsTouchID(touches, id) {
+			g.releasedBuf = append(g.releasedBuf, id)
+		}
+	}
+	sort.Slice(g.releasedBuf, func(a, b int) bool {
+		return g.releasedBuf[a] < g.releasedBuf[b]
+	})
+	for _, id := range g.releasedBuf {
+		t := g.touches[id]
+		delete(g.touches, id)
+		g.release(id, t, t.lastX, t.lastY)
+	}
+
+	sort.Slice(touches, func(a, b int) bool {
+		return touches[a].id < touches[b].id
+	})
+	for _, touch := range touches {
+		id, x, y := touch.id, touch.x, touch.y
+		t, ok := g.touches[id]
+		if !ok {
+			g.touches[id] = &gestureTouch{
+				startX:    x,
+				startY:    y,
+				startTick: g.tick,
+				lastX:     x,
+				lastY:     y,
+			}
+			continue
+		}
+		t.lastX = x
+		t.lastY = y
+
+		if !t.moved && distance(t.startX, t.startY, x, y) > g.tapMaxDistance {
+			t.moved = true
+		}
+		if !t.moved && !t.longPressed && g.tick-t.startTick >= g.longPressDuration {
+			t.longPressed = true
+			g.gestures = append(g.gestures, Gesture{
+				Type:    GestureTypeLongPress,
+				TouchID: id,
+				StartX:  t.startX,
+				StartY:  t.startY,
+				X:       x,
+				Y:       y,
+			})
+		}
+	}
+}
+
+func (g *GestureRecognizer) release(id ebiten.TouchID, t *gestureTouch, x, y int) {
+	if t.longPressed {
+		return
+	}
+
+	d := g.tick - t.startTick
+	dist := distance(t.startX, t.startY, x, y)
+
+	if dist >= g.swipeMinDistance && d <= g.swipeMaxDuration {
+		dx, dy := x-t.startX, y-t.startY
+		var dir SwipeDirection
+		if abs(dx) >= abs(dy) {
+			if dx < 0 {
+				dir = SwipeDirectionLeft
+			} else {
+				dir = SwipeDirectionRight
+			}
+		} else {
+			if dy < 0 {
+				dir = SwipeDirectionUp
+			} else {
+				dir = SwipeDirectionDown
+			}
+		}
+		g.gestures = append(g.gestures, Gesture{
+			Type:      GestureTypeSwipe,
+			TouchID:   id,
+			StartX:    t.startX,
+			StartY:    t.startY,
+			X:         x,
+			Y:         y,
+			Direction: dir,
+		})
+		return
+	}
+
+	if t.moved || dist > g.tapMaxDistance || d > g.tapMaxDuration {
+		return
+	}
+
+	g.gestures = append(g.gestures, Gesture{
+		Type:    GestureTypeTap,
+		TouchID: id,
+		StartX:  t.startX,
+		StartY:  t.startY,
+		X:       x,
+		Y:       y,
+	})
+
+	if l := g.lastTap; l != nil && g.tick-l.tick <= g.doubleTapMaxInterval && distance(l.x, l.y, x, y) <= g.tapMaxDistance {
+		g.gestures = append(g.gestures, Gesture{
+			Type:    GestureTypeDoubleTap,
+			TouchID: id,
+			StartX:  t.startX,
+			StartY:  t.startY,
+			X:       x,
+			Y:       y,
+		})
+		// A third tap should not be another double tap.
+		g.lastTap = nil
+		return
+	}
+
+	g.lastTap = &lastTap{
+		x:    x,
+		y:    y,
+		tick: g.tick,
+	}
+}
+
+// AppendGestures appends the gestures recognized in the current tick to gestures and returns the extended buffer.
+// Giving a slice that already has enough capacity works efficiently.
+//
+// For a double tap, both GestureTypeTap and GestureTypeDoubleTap are reported for the second tap.
+// If a single tap must be distinguished from a double tap, wait for DoubleTapMaxInterval ticks after a tap.
+//
+// AppendGestures must be called after Update in a game's Update, not Draw.
+func (g *GestureRecognizer) AppendGestures(gestures []Gesture) []Gesture {
+	return append(gestures, g.gestures...)
+}
+
+func containsTouchID(touches []touchInput, id ebiten.TouchID) bool {
+	for _, t := range touches {
+		if t.id == id {
+			return true
+		}
+	}
+	return false
+}
+
+func distance(x0, y0, x1, y1 int) float64 {
+	return math.Hypot(float64(x1-x0), float64(y1-y0))
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
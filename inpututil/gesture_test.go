@@ -0,0 +1,285 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inpututil_test
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// touchTicks returns n ticks where a touch id is pressed at (x, y).
+func touchTicks(id ebiten.TouchID, x, y int, n int) [][]inpututil.TouchForTesting {
+	var ticks [][]inpututil.TouchForTesting
+	for i := 0; i < n; i++ {
+		ticks = append(ticks, []inpututil.TouchForTesting{{ID: id, X: x, Y: y}})
+	}
+	return ticks
+}
+
+// noTouchTicks returns n ticks where no touch is pressed.
+func noTouchTicks(n int) [][]inpututil.TouchForTesting {
+	return make([][]inpututil.TouchForTesting, n)
+}
+
+func concatTicks(ticks ...[][]inpututil.TouchForTesting) [][]inpututil.TouchForTesting {
+	var r [][]inpututil.TouchForTesting
+	for _, t := range ticks {
+		r = append(r, t...)
+	}
+	return r
+}
+
+// recognize feeds ticks to a new GestureRecognizer with the default options and returns all the recognized gestures.
+func recognize(ticks [][]inpututil.TouchForTesting) []inpututil.Gesture {
+	g := inpututil.NewGestureRecognizer(nil)
+	var gestures []inpututil.Gesture
+	for _, touches := range ticks {
+		g.UpdateWithTouchesForTesting(touches)
+		gestures = g.AppendGestures(gestures)
+	}
+	return gestures
+}
+
+func checkGestures(t *testing.T, got, want []inpututil.Gesture) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got: %v, want: %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("gesture %d: got: %v, want: %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestGestureTap(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Duration int
+		X        int
+		Y        int
+		Tap      bool
+	}{
+		{Name: "shortest", Duration: 1, Tap: true},
+		{Name: "longest", Duration: 15, Tap: true},
+		{Name: "too long", Duration: 16, Tap: false},
+		{Name: "moved within the distance", Duration: 2, X: 6, Y: 8, Tap: true},
+		{Name: "moved too far", Duration: 2, X: 11, Tap: false},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			got := recognize(concatTicks(
+				touchTicks(1, 100, 100, 1),
+				touchTicks(1, 100+tc.X, 100+tc.Y, tc.Duration-1),
+				noTouchTicks(1)))
+			var want []inpututil.Gesture
+			if tc.Tap {
+				want = append(want, inpututil.Gesture{
+					Type:    inpututil.GestureTypeTap,
+					TouchID: 1,
+					StartX:  100,
+					StartY:  100,
+					X:       100 + tc.X,
+					Y:       100 + tc.Y,
+				})
+			}
+			checkGestures(t, got, want)
+		})
+	}
+}
+
+func TestGestureDoubleTap(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Interval  int
+		X         int
+		Y         int
+		DoubleTap bool
+	}{
+		{Name: "shortest", Interval: 2, DoubleTap: true},
+		{Name: "longest", Interval: 20, DoubleTap: true},
+		{Name: "too long", Interval: 21, DoubleTap: false},
+		{Name: "close", Interval: 2, X: 10, DoubleTap: true},
+		{Name: "too far", Interval: 2, Y: 11, DoubleTap: false},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			// The interval is between the ends of the two taps. Each tap takes two ticks including its end.
+			got := recognize(concatTicks(
+				touchTicks(1, 100, 100, 1),
+				noTouchTicks(tc.Interval-1),
+				touchTicks(2, 100+tc.X, 100+tc.Y, 1),
+				noTouchTicks(1)))
+			want := []inpututil.Gesture{
+				{
+					Type:    inpututil.GestureTypeTap,
+					TouchID: 1,
+					StartX:  100,
+					StartY:  100,
+					X:       100,
+					Y:       100,
+				},
+				{
+					Type:    inpututil.GestureTypeTap,
+					TouchID: 2,
+					StartX:  100 + tc.X,
+					StartY:  100 + tc.Y,
+					X:       100 + tc.X,
+					Y:       100 + tc.Y,
+				},
+			}
+			if tc.DoubleTap {
+				want = append(want, inpututil.Gesture{
+					Type:    inpututil.GestureTypeDoubleTap,
+					TouchID: 2,
+					StartX:  100 + tc.X,
+					StartY:  100 + tc.Y,
+					X:       100 + tc.X,
+					Y:       100 + tc.Y,
+				})
+			}
+			checkGestures(t, got, want)
+		})
+	}
+}
+
+func TestGestureTripleTap(t *testing.T) {
+	got := recognize(concatTicks(
+		touchTicks(1, 0, 0, 1),
+		noTouchTicks(1),
+		touchTicks(2, 0, 0, 1),
+		noTouchTicks(1),
+		touchTicks(3, 0, 0, 1),
+		noTouchTicks(1)))
+	var doubleTaps int
+	for _, g := range got {
+		if g.Type == inpututil.GestureTypeDoubleTap {
+			doubleTaps++
+		}
+	}
+	// The third tap must not be another double tap.
+	if got, want := doubleTaps, 1; got != want {
+		t.Errorf("double taps: got: %d, want: %d", got, want)
+	}
+}
+
+func TestGestureLongPress(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Duration  int
+		X         int
+		Y         int
+		LongPress bool
+	}{
+		{Name: "too short", Duration: 30, LongPress: false},
+		{Name: "shortest", Duration: 31, LongPress: true},
+		{Name: "longer", Duration: 60, LongPress: true},
+		{Name: "moved within the distance", Duration: 31, X: 10, LongPress: true},
+		{Name: "moved too far", Duration: 31, X: 11, LongPress: false},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			// A long press is reported only once while the touch is pressed, and a tap is not reported on release.
+			got := recognize(concatTicks(
+				touchTicks(1, 100, 100, 1),
+				touchTicks(1, 100+tc.X, 100+tc.Y, tc.Duration-1),
+				noTouchTicks(1)))
+			var want []inpututil.Gesture
+			if tc.LongPress {
+				want = append(want, inpututil.Gesture{
+					Type:    inpututil.GestureTypeLongPress,
+					TouchID: 1,
+					StartX:  100,
+					StartY:  100,
+					X:       100 + tc.X,
+					Y:       100 + tc.Y,
+				})
+			}
+			checkGestures(t, got, want)
+		})
+	}
+}
+
+func TestGestureSwipe(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Duration  int
+		X         int
+		Y         int
+		Swipe     bool
+		Direction inpututil.SwipeDirection
+	}{
+		{Name: "right", Duration: 2, X: 30, Swipe: true, Direction: inpututil.SwipeDirectionRight},
+		{Name: "left", Duration: 2, X: -30, Swipe: true, Direction: inpututil.SwipeDirectionLeft},
+		{Name: "up", Duration: 2, Y: -30, Swipe: true, Direction: inpututil.SwipeDirectionUp},
+		{Name: "down", Duration: 2, Y: 30, Swipe: true, Direction: inpututil.SwipeDirectionDown},
+		{Name: "diagonal", Duration: 2, X: -30, Y: 30, Swipe: true, Direction: inpututil.SwipeDirectionLeft},
+		{Name: "mostly vertical", Duration: 2, X: 20, Y: -25, Swipe: true, Direction: inpututil.SwipeDirectionUp},
+		{Name: "too short distance", Duration: 2, X: 29, Swipe: false},
+		{Name: "longest", Duration: 30, X: 30, Swipe: true, Direction: inpututil.SwipeDirectionRight},
+		{Name: "too long", Duration: 31, X: 30, Swipe: false},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			got := recognize(concatTicks(
+				touchTicks(1, 100, 100, 1),
+				touchTicks(1, 100+tc.X, 100+tc.Y, tc.Duration-1),
+				noTouchTicks(1)))
+			var want []inpututil.Gesture
+			if tc.Swipe {
+				want = append(want, inpututil.Gesture{
+					Type:      inpututil.GestureTypeSwipe,
+					TouchID:   1,
+					StartX:    100,
+					StartY:    100,
+					X:         100 + tc.X,
+					Y:         100 + tc.Y,
+					Direction: tc.Direction,
+				})
+			}
+			checkGestures(t, got, want)
+		})
+	}
+}
+
+func TestGestureMultipleTouches(t *testing.T) {
+	got := recognize([][]inpututil.TouchForTesting{
+		{{ID: 2, X: 0, Y: 0}, {ID: 1, X: 100, Y: 0}},
+		{{ID: 2, X: 0, Y: 0}, {ID: 1, X: 140, Y: 0}},
+		{},
+	})
+	want := []inpututil.Gesture{
+		{
+			Type:      inpututil.GestureTypeSwipe,
+			TouchID:   1,
+			StartX:    100,
+			StartY:    0,
+			X:         140,
+			Y:         0,
+			Direction: inpututil.SwipeDirectionRight,
+		},
+		{
+			Type:    inpututil.GestureTypeTap,
+			TouchID: 2,
+		},
+	}
+	checkGestures(t, got, want)
+}
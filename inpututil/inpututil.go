@@ -16,6 +16,7 @@
 package inpututil
 
 import (
+	"fmt"
 	"sort"
 	"sync"
 
@@ -313,6 +314,55 @@ func KeyPressDuration(key ebiten.Key) int {
 	return s
 }
 
+// IsKeyJustPressedWithRepeat returns a boolean value indicating
+// whether the given key is pressed just in the current tick, or the press is repeated in the current tick
+// like the auto-repeat of OS keyboard inputs.
+//
+// IsKeyJustPressedWithRepeat returns true when the key is pressed, and then returns true every interval ticks
+// after the key is held for delay ticks.
+// This is useful for e.g., text fields and menu navigation.
+//
+// IsKeyJustPressedWithRepeat is equivalent to IsRepeatTick(KeyPressDuration(key), delay, interval).
+//
+// IsKeyJustPressedWithRepeat panics if delay is negative or interval is not positive.
+//
+// IsKeyJustPressedWithRepeat must be called in a game's Update, not Draw.
+//
+// IsKeyJustPressedWithRepeat is concurrent safe.
+func IsKeyJustPressedWithRepeat(key ebiten.Key, delay, interval int) bool {
+	return IsRepeatTick(KeyPressDuration(key), delay, interval)
+}
+
+// IsRepeatTick reports whether an input pressed for the given duration in ticks triggers an action in the current tick,
+// with an auto-repeat after delay ticks at every interval ticks.
+//
+// IsRepeatTick returns true when duration is 1, i.e., the input is just pressed,
+// and when duration is 1+delay, 1+delay+interval, 1+delay+2*interval, and so on.
+//
+// IsRepeatTick composes with the duration-based functions like KeyPressDuration, MouseButtonPressDuration,
+// GamepadButtonPressDuration, StandardGamepadButtonPressDuration, and TouchPressDuration.
+// For example, IsRepeatTick(GamepadButtonPressDuration(id, button), 30, 5) reports a gamepad button press repeated
+// every 5 ticks after the button is held for 30 ticks.
+//
+// IsRepeatTick panics if delay is negative or interval is not positive.
+func IsRepeatTick(duration int, delay, interval int) bool {
+	if delay < 0 {
+		panic(fmt.Sprintf("inpututil: delay must be non-negative but %d", delay))
+	}
+	if interval <= 0 {
+		panic(fmt.Sprintf("inpututil: interval must be positive but %d", interval))
+	}
+
+	if duration == 1 {
+		return true
+	}
+	d := duration - 1 - delay
+	if d < 0 {
+		return false
+	}
+	return d%interval == 0
+}
+
 // IsMouseButtonJustPressed returns a boolean value indicating
 // whether the given mouse button is pressed just in the current tick.
 //
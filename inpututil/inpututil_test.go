@@ -0,0 +1,113 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inpututil_test
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+func TestIsRepeatTick(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Delay    int
+		Interval int
+		// Want is the list of durations from 0 that trigger an action.
+		Want []int
+	}{
+		{
+			Name:     "no delay",
+			Delay:    0,
+			Interval: 1,
+			Want:     []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19},
+		},
+		{
+			Name:     "no delay with interval",
+			Delay:    0,
+			Interval: 3,
+			Want:     []int{1, 4, 7, 10, 13, 16, 19},
+		},
+		{
+			Name:     "delay",
+			Delay:    5,
+			Interval: 1,
+			Want:     []int{1, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19},
+		},
+		{
+			Name:     "delay with interval",
+			Delay:    10,
+			Interval: 4,
+			Want:     []int{1, 11, 15, 19},
+		},
+		{
+			Name:     "interval longer than delay",
+			Delay:    2,
+			Interval: 8,
+			Want:     []int{1, 3, 11, 19},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			want := map[int]bool{}
+			for _, d := range tc.Want {
+				want[d] = true
+			}
+			for d := 0; d < 20; d++ {
+				if got := inpututil.IsRepeatTick(d, tc.Delay, tc.Interval); got != want[d] {
+					t.Errorf("IsRepeatTick(%d, %d, %d): got: %t, want: %t", d, tc.Delay, tc.Interval, got, want[d])
+				}
+			}
+		})
+	}
+}
+
+func TestIsRepeatTickPanics(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Delay    int
+		Interval int
+	}{
+		{
+			Name:     "negative delay",
+			Delay:    -1,
+			Interval: 1,
+		},
+		{
+			Name:     "zero interval",
+			Delay:    0,
+			Interval: 0,
+		},
+		{
+			Name:     "negative interval",
+			Delay:    10,
+			Interval: -1,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("IsRepeatTick(1, %d, %d) must panic but not", tc.Delay, tc.Interval)
+				}
+			}()
+			inpututil.IsRepeatTick(1, tc.Delay, tc.Interval)
+		})
+	}
+}
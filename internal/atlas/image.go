@@ -67,7 +67,8 @@ func putImagesOnSourceBackend(graphicsDriver graphicsdriver.Graphics) {
 		if i.usedAsSourceCount < math.MaxInt {
 			i.usedAsSourceCount++
 		}
-		if int64(i.usedAsSourceCount) >= int64(baseCountToPutOnSourceBackend*(1<<uint(min(i.usedAsDestinationCount, 31)))) {
+		// An image of ImageTypeAtlasPreferred is put onto a source backend without waiting.
+		if i.imageType == ImageTypeAtlasPreferred || int64(i.usedAsSourceCount) >= int64(baseCountToPutOnSourceBackend*(1<<uint(min(i.usedAsDestinationCount, 31)))) {
 			i.putOnSourceBackend(graphicsDriver)
 			i.usedAsSourceCount = 0
 		}
@@ -130,8 +131,16 @@ const (
 	ImageTypeScreen
 	ImageTypeVolatile
 	ImageTypeUnmanaged
+
+	// ImageTypeAtlasPreferred is the same as ImageTypeRegular, but the image is put onto a source backend
+	// at the end of a frame where the image is used as a rendering source, regardless of usedAsDestinationCount.
+	ImageTypeAtlasPreferred
 )
 
+func (t ImageType) isRegular() bool {
+	return t == ImageTypeRegular || t == ImageTypeAtlasPreferred
+}
+
 // Image is a rectangle pixel set that might be on an atlas.
 type Image struct {
 	width     int
@@ -191,7 +200,7 @@ func (i *Image) resetUsedAsSourceCount() {
 }
 
 func (i *Image) paddingSize() int {
-	if i.imageType == ImageTypeRegular {
+	if i.imageType.isRegular() {
 		return 1
 	}
 	return 0
@@ -269,11 +278,11 @@ func (i *Image) putOnSourceBackend(graphicsDriver graphicsdriver.Graphics) {
 		panic("atlas: putOnSourceBackend cannot be called on a image that cannot be on an atlas")
 	}
 
-	if i.imageType != ImageTypeRegular {
-		panic(fmt.Sprintf("atlas: the image type must be ImageTypeRegular but %d", i.imageType))
+	if !i.imageType.isRegular() {
+		panic(fmt.Sprintf("atlas: the image type must be ImageTypeRegular or ImageTypeAtlasPreferred but %d", i.imageType))
 	}
 
-	newI := NewImage(i.width, i.height, ImageTypeRegular)
+	newI := NewImage(i.width, i.height, i.imageType)
 	newI.allocate(nil, true)
 
 	w, h := float32(i.width), float32(i.height)
@@ -542,6 +551,38 @@ func (i *Image) readPixels(graphicsDriver graphicsdriver.Graphics, pixels []byte
 	return i.backend.restorable.ReadPixels(graphicsDriver, pixels, region.Add(r.Min))
 }
 
+// ReadPixelsAsync reads pixels on the given region to the given slice pixels asynchronously.
+//
+// callback is called after the pixels are read, on the rendering thread or on the current goroutine.
+// The caller must not access the pixels until callback is called.
+//
+// ReadPixelsAsync blocks until BeginFrame is called if necessary in order to ensure this is called in a frame (between BeginFrame and EndFrame).
+func (i *Image) ReadPixelsAsync(pixels []byte, region image.Rectangle, callback func()) {
+	theFuncsInFrame.runFuncInFrame(func() {
+		i.readPixelsAsync(pixels, region, callback)
+	})
+}
+
+func (i *Image) readPixelsAsync(pixels []byte, region image.Rectangle, callback func()) {
+	backendsM.Lock()
+	defer backendsM.Unlock()
+
+	if !inFrame {
+		panic("atlas: inFrame must be true in readPixelsAsync")
+	}
+
+	if i.backend == nil || i.backend.restorable == nil {
+		for i := range pixels {
+			pixels[i] = 0
+		}
+		callback()
+		return
+	}
+
+	r := i.regionWithPadding()
+	i.backend.restorable.ReadPixelsAsync(pixels, region.Add(r.Min), callback)
+}
+
 // Deallocate deallocates the internal state.
 // Even after this call, the image is still available as a new cleared image.
 func (i *Image) Deallocate() {
@@ -613,7 +654,7 @@ func (i *Image) canBePutOnAtlas() bool {
 	if minSourceSize == 0 || minDestinationSize == 0 || maxSize == 0 {
 		panic("atlas: min*Size or maxSize must be initialized")
 	}
-	if i.imageType != ImageTypeRegular {
+	if !i.imageType.isRegular() {
 		return false
 	}
 	return i.width+i.paddingSize() <= maxSize && i.height+i.paddingSize() <= maxSize
@@ -828,6 +869,32 @@ func BeginFrame(graphicsDriver graphicsdriver.Graphics) error {
 	return nil
 }
 
+// AtlasInfo represents information about an atlas.
+type AtlasInfo struct {
+	Width   int
+	Height  int
+	Source  bool
+	Regions []image.Rectangle
+}
+
+// AppendAtlasInfos appends information about the current atlases to infos and returns the extended buffer.
+// Isolated backends that are not atlases are not included.
+func AppendAtlasInfos(infos []AtlasInfo) []AtlasInfo {
+	backendsM.Lock()
+	defer backendsM.Unlock()
+
+	for _, b := range theBackends {
+		w, h := b.page.Size()
+		infos = append(infos, AtlasInfo{
+			Width:   w,
+			Height:  h,
+			Source:  b.source,
+			Regions: b.page.AppendUsedRegions(nil),
+		})
+	}
+	return infos
+}
+
 func DumpImages(graphicsDriver graphicsdriver.Graphics, dir string) (string, error) {
 	backendsM.Lock()
 	defer backendsM.Unlock()
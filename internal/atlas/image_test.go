@@ -811,3 +811,44 @@ func TestIteratingImagesToPutOnSourceBackend(t *testing.T) {
 }
 
 // TODO: Add tests to extend image on an atlas out of the main loop
+
+func TestAtlasPreferredImageReputOnSourceBackend(t *testing.T) {
+	const w, h = 16, 16
+	src := atlas.NewImage(w, h, atlas.ImageTypeRegular)
+	defer src.Deallocate()
+	dst := atlas.NewImage(w, h, atlas.ImageTypeRegular)
+	defer dst.Deallocate()
+	img0 := atlas.NewImage(w, h, atlas.ImageTypeRegular)
+	defer img0.Deallocate()
+	img1 := atlas.NewImage(w, h, atlas.ImageTypeAtlasPreferred)
+	defer img1.Deallocate()
+
+	vs := quadVertices(w, h, 0, 0, 1)
+	is := graphics.QuadIndices()
+	dr := image.Rect(0, 0, w, h)
+
+	// Use img0 and img1 as destinations for a while.
+	for i := 0; i < 5; i++ {
+		for _, img := range []*atlas.Image{img0, img1} {
+			img.DrawTriangles([graphics.ShaderImageCount]*atlas.Image{src}, vs, is, graphicsdriver.BlendCopy, dr, [graphics.ShaderImageCount]image.Rectangle{}, atlas.NearestFilterShader, nil, graphicsdriver.FillAll)
+		}
+		atlas.PutImagesOnSourceBackendForTesting(ui.Get().GraphicsDriverForTesting())
+	}
+
+	// Use img0 and img1 as sources once.
+	for _, img := range []*atlas.Image{img0, img1} {
+		dst.DrawTriangles([graphics.ShaderImageCount]*atlas.Image{img}, vs, is, graphicsdriver.BlendCopy, dr, [graphics.ShaderImageCount]image.Rectangle{}, atlas.NearestFilterShader, nil, graphicsdriver.FillAll)
+	}
+	atlas.PutImagesOnSourceBackendForTesting(ui.Get().GraphicsDriverForTesting())
+
+	// img1 is put on a source backend immediately, while img0 is not.
+	if got, want := img0.IsOnSourceBackendForTesting(), false; got != want {
+		t.Errorf("img0.IsOnSourceBackendForTesting(): got: %v, want: %v", got, want)
+	}
+	if got, want := img1.IsOnSourceBackendForTesting(), true; got != want {
+		t.Errorf("img1.IsOnSourceBackendForTesting(): got: %v, want: %v", got, want)
+	}
+	if got, want := img1.PaddingSizeForTesting(), 1; got != want {
+		t.Errorf("img1.PaddingSizeForTesting(): got: %d, want: %d", got, want)
+	}
+}
@@ -17,6 +17,7 @@ package atlas
 import (
 	"runtime"
 
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
 	"github.com/hajimehoshi/ebiten/v2/internal/restorable"
 	"github.com/hajimehoshi/ebiten/v2/internal/shaderir"
 )
@@ -48,6 +49,24 @@ func (s *Shader) ensureShader() *restorable.Shader {
 	return s.shader
 }
 
+// Preload creates the internal state in advance, so that the first draw call with the shader doesn't have to wait for
+// the compilation by the graphics driver.
+//
+// If Preload is called out of a frame, the creation is deferred until the next frame.
+func (s *Shader) Preload() {
+	backendsM.Lock()
+	defer backendsM.Unlock()
+
+	if !inFrame {
+		appendDeferred(func() {
+			s.ensureShader()
+		})
+		return
+	}
+
+	s.ensureShader()
+}
+
 // Deallocate deallocates the internal state.
 func (s *Shader) Deallocate() {
 	backendsM.Lock()
@@ -72,7 +91,43 @@ func (s *Shader) deallocate() {
 	s.shader = nil
 }
 
+// FlushDeallocations executes the deferred deallocations and flushes the commands,
+// so that the deallocated resources are actually released in the graphics driver.
+//
+// FlushDeallocations blocks until BeginFrame is called if necessary in order to ensure this is called in a frame
+// (between BeginFrame and EndFrame).
+func FlushDeallocations(graphicsDriver graphicsdriver.Graphics) error {
+	var err error
+	theFuncsInFrame.runFuncInFrame(func() {
+		err = flushDeallocations(graphicsDriver)
+	})
+	return err
+}
+
+func flushDeallocations(graphicsDriver graphicsdriver.Graphics) error {
+	backendsM.Lock()
+	defer backendsM.Unlock()
+
+	if !inFrame {
+		panic("atlas: inFrame must be true in flushDeallocations")
+	}
+
+	flushDeferred()
+	return restorable.FlushCommands(graphicsDriver)
+}
+
+// ShaderCount returns the number of shaders that are created in the graphics driver and not disposed yet.
+func ShaderCount() int {
+	return restorable.ShaderCount()
+}
+
+// PendingShaderDisposalCount returns the number of shaders whose disposals are not done yet in the graphics driver.
+func PendingShaderDisposalCount() int {
+	return restorable.PendingShaderDisposalCount()
+}
+
 var (
 	NearestFilterShader = &Shader{shader: restorable.NearestFilterShader}
 	LinearFilterShader  = &Shader{shader: restorable.LinearFilterShader}
+	FillShader          = &Shader{shader: restorable.FillShader}
 )
@@ -75,6 +75,23 @@ func (i *Image) ReadPixels(graphicsDriver graphicsdriver.Graphics, pixels []byte
 	return nil
 }
 
+// ReadPixelsAsync reads the pixels on the given region asynchronously.
+//
+// callback is called after the pixels are read, on the rendering thread or on the current goroutine.
+func (i *Image) ReadPixelsAsync(pixels []byte, region image.Rectangle, callback func()) {
+	if restorable.AlwaysReadPixelsFromGPU() && i.pixels != nil {
+		lineWidth := 4 * region.Dx()
+		for j := 0; j < region.Dy(); j++ {
+			dstX := 4 * j * region.Dx()
+			srcX := 4 * ((region.Min.Y+j)*i.width + region.Min.X)
+			copy(pixels[dstX:dstX+lineWidth], i.pixels[srcX:srcX+lineWidth])
+		}
+		callback()
+		return
+	}
+	i.img.ReadPixelsAsync(pixels, region, callback)
+}
+
 func (i *Image) DumpScreenshot(graphicsDriver graphicsdriver.Graphics, name string, blackbg bool) (string, error) {
 	return i.img.DumpScreenshot(graphicsDriver, name, blackbg)
 }
@@ -36,9 +36,10 @@ const (
 	AddressUnsafe Address = iota
 	AddressClampToZero
 	AddressRepeat
+	AddressClampToEdge
 )
 
-const AddressCount = 3
+const AddressCount = 4
 
 const (
 	UniformColorMBody        = "ColorMBody"
@@ -67,25 +68,40 @@ func adjustTexelForAddressRepeat(p vec2) vec2 {
 }
 {{end}}
 
+{{if eq .Address .AddressClampToEdge}}
+func adjustTexelForAddressClampToEdge(p vec2) vec2 {
+	// Clamp the position to the centers of the edge texels so that the neighbor texels are never sampled.
+	origin := imageSrc0Origin()
+	size := imageSrc0Size()
+	return clamp(p, origin + 1/2.0, origin + size - 1/2.0)
+}
+{{end}}
+
 func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+{{if eq .Address .AddressClampToEdge}}
+	pos := adjustTexelForAddressClampToEdge(srcPos)
+{{else}}
+	pos := srcPos
+{{end}}
 {{if eq .Filter .FilterNearest}}
-{{if eq .Address .AddressUnsafe}}
-	clr := imageSrc0UnsafeAt(srcPos)
+{{if or (eq .Address .AddressUnsafe) (eq .Address .AddressClampToEdge)}}
+	clr := imageSrc0UnsafeAt(pos)
 {{else if eq .Address .AddressClampToZero}}
-	clr := imageSrc0At(srcPos)
+	clr := imageSrc0At(pos)
 {{else if eq .Address .AddressRepeat}}
-	clr := imageSrc0At(adjustTexelForAddressRepeat(srcPos))
+	clr := imageSrc0At(adjustTexelForAddressRepeat(pos))
 {{end}}
 {{else if eq .Filter .FilterLinear}}
-	p0 := srcPos - 1/2.0
-	p1 := srcPos + 1/2.0
+	p0 := pos - 1/2.0
+	p1 := pos + 1/2.0
 
 {{if eq .Address .AddressRepeat}}
 	p0 = adjustTexelForAddressRepeat(p0)
 	p1 = adjustTexelForAddressRepeat(p1)
 {{end}}
 
-{{if eq .Address .AddressUnsafe}}
+{{if or (eq .Address .AddressUnsafe) (eq .Address .AddressClampToEdge)}}
+	// With AddressClampToEdge, the texels out of the region might be sampled at the edges, but their weights are 0.
 	c0 := imageSrc0UnsafeAt(p0)
 	c1 := imageSrc0UnsafeAt(vec2(p1.x, p0.y))
 	c2 := imageSrc0UnsafeAt(vec2(p0.x, p1.y))
@@ -147,6 +163,7 @@ func Shader(filter Filter, address Address, useColorM bool) []byte {
 		AddressUnsafe      Address
 		AddressClampToZero Address
 		AddressRepeat      Address
+		AddressClampToEdge Address
 		UseColorM          bool
 	}{
 		Filter:             filter,
@@ -156,6 +173,7 @@ func Shader(filter Filter, address Address, useColorM bool) []byte {
 		AddressUnsafe:      AddressUnsafe,
 		AddressClampToZero: AddressClampToZero,
 		AddressRepeat:      AddressRepeat,
+		AddressClampToEdge: AddressClampToEdge,
 		UseColorM:          useColorM,
 	}); err != nil {
 		panic(fmt.Sprintf("builtinshader: tmpl.Execute failed: %v", err))
@@ -16,25 +16,34 @@
 package clock
 
 import (
+	"math"
 	"sync"
 	"time"
 )
 
 const (
-	DefaultTPS  = 60
-	SyncWithFPS = -1
+	DefaultTPS          = 60
+	SyncWithFPS         = -1
+	SyncWithRefreshRate = -2
 )
 
 var (
 	// tps represents TPS (ticks per second).
 	tps = DefaultTPS
 
+	// refreshRate represents the refresh rate of the current monitor in Hz.
+	// refreshRate is 0 when the refresh rate is unknown.
+	refreshRate float64
+
 	lastNow int64
 
 	// lastSystemTime is the last system time in the previous UpdateFrame.
 	// lastSystemTime indicates the logical time in the game, so this can be bigger than the curren time.
 	lastSystemTime int64
 
+	// tickFraction is the progress from the last tick to the next tick in [0, 1).
+	tickFraction float64
+
 	actualFPS   float64
 	actualTPS   float64
 	prevTPS     int64
@@ -64,6 +73,13 @@ func ActualTPS() float64 {
 	return actualTPS
 }
 
+// TickFraction returns the progress of the game time from the last tick to the next tick in [0, 1).
+func TickFraction() float64 {
+	m.Lock()
+	defer m.Unlock()
+	return tickFraction
+}
+
 func max(a, b int64) int64 {
 	if a < b {
 		return b
@@ -117,6 +133,23 @@ func calcCountFromTPS(tps int64, now int64) int {
 	return count
 }
 
+// calcTickFraction returns the progress to the next tick in [0, 1).
+// diff is the duration from the game time before the ticks of the current frame are counted.
+//
+// The game time can be ahead of the current time to stabilize the count, so diff can be negative.
+// The fraction is the position in the tick grid, so that it increases monotonically until the next tick.
+func calcTickFraction(tps int64, diff int64) float64 {
+	if tps <= 0 {
+		return 0
+	}
+	f := float64(diff) * float64(tps) / float64(time.Second)
+	f -= math.Floor(f)
+	if f < 0 || f >= 1 {
+		return 0
+	}
+	return f
+}
+
 func updateFPSAndTPS(now int64, count int) {
 	fpsCount++
 	tpsCount += count
@@ -137,6 +170,7 @@ func updateFPSAndTPS(now int64, count int) {
 // indicating how many times the game should update based on the current tps.
 //
 // If tps is SyncWithFPS, UpdateFrame always returns 1.
+// If tps is SyncWithRefreshRate, UpdateFrame treats the refresh rate set by SetRefreshRate as tps.
 // If tps <= 0 and not SyncWithFPS, UpdateFrame always returns 0.
 //
 // UpdateFrame is expected to be called once per frame.
@@ -152,24 +186,86 @@ func UpdateFrame() int {
 	lastNow = n
 
 	c := 0
-	if tps == SyncWithFPS {
+	tickFraction = 0
+	if t := effectiveTPS(); t == SyncWithFPS {
 		c = 1
-	} else if tps > 0 {
-		c = calcCountFromTPS(int64(tps), n)
+	} else if t > 0 {
+		// Calculate the fraction from the game time before calcCountFromTPS adjusts it.
+		// calcCountFromTPS can move lastSystemTime ahead of n, and then n-lastSystemTime is no longer the progress.
+		diff := n - lastSystemTime
+		c = calcCountFromTPS(int64(t), n)
+		if lastSystemTime == n {
+			// The game time is synced with the system clock.
+			tickFraction = 0
+		} else {
+			tickFraction = calcTickFraction(int64(t), diff)
+		}
 	}
 	updateFPSAndTPS(n, c)
 
 	return c
 }
 
+// effectiveTPS returns the TPS value used for the calculation of the update count.
+//
+// If tps is SyncWithRefreshRate, effectiveTPS returns the current refresh rate rounded to the nearest integer
+// (e.g. 60 for 59.94 Hz), or DefaultTPS if the refresh rate is unknown.
+func effectiveTPS() int {
+	if tps != SyncWithRefreshRate {
+		return tps
+	}
+	if refreshRate <= 0 {
+		return DefaultTPS
+	}
+	return int(math.Round(refreshRate))
+}
+
+// IntervalFromRefreshRate returns the duration of one frame for the given refresh rate in Hz.
+//
+// If refreshRate is not positive, IntervalFromRefreshRate returns 0.
+func IntervalFromRefreshRate(refreshRate float64) time.Duration {
+	if refreshRate <= 0 {
+		return 0
+	}
+	return time.Duration(float64(time.Second) / refreshRate)
+}
+
 func SetTPS(newTPS int) {
 	m.Lock()
 	defer m.Unlock()
 	tps = newTPS
 }
 
+// SetRefreshRate sets the refresh rate of the current monitor in Hz.
+// A non-positive value or NaN means that the refresh rate is unknown.
+//
+// SetRefreshRate is expected to be called by the UI before UpdateFrame.
+func SetRefreshRate(rate float64) {
+	m.Lock()
+	defer m.Unlock()
+	if !(rate > 0) {
+		rate = 0
+	}
+	refreshRate = rate
+}
+
+// RefreshRate returns the refresh rate set by SetRefreshRate.
+func RefreshRate() float64 {
+	m.Lock()
+	defer m.Unlock()
+	return refreshRate
+}
+
 func TPS() int {
 	m.Lock()
 	defer m.Unlock()
 	return tps
 }
+
+// EffectiveTPS returns the TPS value used for the calculation of the update count.
+// See effectiveTPS for the details.
+func EffectiveTPS() int {
+	m.Lock()
+	defer m.Unlock()
+	return effectiveTPS()
+}
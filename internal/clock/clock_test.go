@@ -0,0 +1,160 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clock_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/clock"
+)
+
+func TestIntervalFromRefreshRate(t *testing.T) {
+	testCases := []struct {
+		RefreshRate float64
+		Want        time.Duration
+	}{
+		{60, time.Second / 60},
+		{144, time.Second / 144},
+		{240, time.Second / 240},
+		{59.94, 16683350},
+		{0, 0},
+		{-1, 0},
+	}
+	for _, tc := range testCases {
+		if got := clock.IntervalFromRefreshRate(tc.RefreshRate); got != tc.Want {
+			t.Errorf("clock.IntervalFromRefreshRate(%v): got: %v, want: %v", tc.RefreshRate, got, tc.Want)
+		}
+	}
+}
+
+func TestTickFraction(t *testing.T) {
+	testCases := []struct {
+		TPS  int64
+		Diff time.Duration
+		Want float64
+	}{
+		{50, 0, 0},
+		{50, time.Second / 100, 0.5},
+		{50, time.Second / 50, 0},
+		{50, time.Second * 3 / 100, 0.5},
+		{50, time.Second, 0},
+		{50, -time.Second / 100, 0.5},
+		{50, -time.Second / 200, 0.75},
+		{50, time.Second / 200, 0.25},
+		{0, time.Second, 0},
+	}
+	for _, tc := range testCases {
+		if got := clock.CalcTickFractionForTesting(tc.TPS, int64(tc.Diff)); got != tc.Want {
+			t.Errorf("clock.CalcTickFractionForTesting(%d, %v): got: %v, want: %v", tc.TPS, tc.Diff, got, tc.Want)
+		}
+	}
+}
+
+func TestTickFractionMonotonic(t *testing.T) {
+	origTPS := clock.TPS()
+	defer clock.SetTPS(origTPS)
+	defer clock.ResetNowForTesting()
+
+	const tps = 50
+	const tick = int64(time.Second / tps)
+	clock.SetTPS(tps)
+
+	// Start far enough from the last game time so that the game time is synced with the fake clock.
+	start := int64(time.Hour)
+	var current int64
+	clock.SetNowForTesting(func() int64 {
+		return current
+	})
+
+	// Advance the time by 0.4 ticks per frame.
+	// The count is stabilized to 1 when the time is more than 0.5 ticks, and then the game time can be ahead of the current time.
+	const step = tick * 2 / 5
+	var prevTick int64 = -1
+	var prevFraction float64
+	for i := 0; i < 50; i++ {
+		current = start + int64(i)*step
+		clock.UpdateFrame()
+		f := clock.TickFraction()
+
+		if f < 0 || f >= 1 {
+			t.Fatalf("frame %d: the fraction must be in [0, 1) but was %v", i, f)
+		}
+		currentTick := (current - start) / tick
+		if currentTick == prevTick && f < prevFraction {
+			t.Errorf("frame %d: the fraction must not decrease within a tick: %v -> %v", i, prevFraction, f)
+		}
+		if want := float64((current-start)%tick) / float64(tick); math.Abs(f-want) > 1e-9 {
+			t.Errorf("frame %d: got: %v, want: %v", i, f, want)
+		}
+		prevTick = currentTick
+		prevFraction = f
+	}
+}
+
+type fakeMonitor struct {
+	refreshRate float64
+}
+
+func (f *fakeMonitor) RefreshRate() float64 {
+	return f.refreshRate
+}
+
+func TestSyncWithRefreshRate(t *testing.T) {
+	origTPS := clock.TPS()
+	origRefreshRate := clock.RefreshRate()
+	defer func() {
+		clock.SetTPS(origTPS)
+		clock.SetRefreshRate(origRefreshRate)
+	}()
+
+	clock.SetTPS(clock.SyncWithRefreshRate)
+
+	// Emulate the window moving across monitors with different refresh rates.
+	monitors := []struct {
+		Monitor *fakeMonitor
+		Want    int
+	}{
+		{&fakeMonitor{refreshRate: 60}, 60},
+		{&fakeMonitor{refreshRate: 144}, 144},
+		// An unknown refresh rate falls back to the default TPS.
+		{&fakeMonitor{refreshRate: 0}, clock.DefaultTPS},
+		{&fakeMonitor{refreshRate: 75}, 75},
+		// A non-integer refresh rate is rounded.
+		{&fakeMonitor{refreshRate: 59.94}, 60},
+		{&fakeMonitor{refreshRate: 143.4}, 143},
+		{&fakeMonitor{refreshRate: math.NaN()}, clock.DefaultTPS},
+	}
+	for _, m := range monitors {
+		clock.SetRefreshRate(m.Monitor.RefreshRate())
+		if got := clock.EffectiveTPS(); got != m.Want {
+			t.Errorf("refresh rate %v: got: %d, want: %d", m.Monitor.RefreshRate(), got, m.Want)
+		}
+	}
+
+	// A fixed TPS is not affected by the refresh rate.
+	clock.SetTPS(30)
+	clock.SetRefreshRate(144)
+	if got, want := clock.EffectiveTPS(), 30; got != want {
+		t.Errorf("got: %d, want: %d", got, want)
+	}
+
+	// SyncWithFPS is kept as it is.
+	clock.SetTPS(clock.SyncWithFPS)
+	if got, want := clock.EffectiveTPS(), clock.SyncWithFPS; got != want {
+		t.Errorf("got: %d, want: %d", got, want)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clock
+
+func CalcTickFractionForTesting(tps int64, diff int64) float64 {
+	return calcTickFraction(tps, diff)
+}
+
+func SetNowForTesting(f func() int64) {
+	m.Lock()
+	defer m.Unlock()
+	now = f
+}
+
+// ResetNowForTesting restores the system clock and resets the game time.
+func ResetNowForTesting() {
+	m.Lock()
+	defer m.Unlock()
+	now = systemNow
+	n := now()
+	lastNow = n
+	lastSystemTime = n
+	lastUpdated = n
+	fpsCount = 0
+	tpsCount = 0
+}
@@ -20,7 +20,10 @@ import (
 
 var initTime = time.Now()
 
-func now() int64 {
+// now returns the current time. now is replaced in tests.
+var now = systemNow
+
+func systemNow() int64 {
 	// time.Since() returns monotonic timer difference (#875):
 	// https://pkg.go.dev/time#hdr-Monotonic_Clocks
 	return int64(time.Since(initTime))
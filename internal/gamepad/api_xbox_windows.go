@@ -35,6 +35,16 @@ var (
 	procGameInputCreate = gameInput.NewProc("GameInputCreate")
 )
 
+type _GameInputBatteryStatus int32
+
+const (
+	_GameInputBatteryUnknown     _GameInputBatteryStatus = -1
+	_GameInputBatteryNotPresent  _GameInputBatteryStatus = 0
+	_GameInputBatteryDischarging _GameInputBatteryStatus = 1
+	_GameInputBatteryIdle        _GameInputBatteryStatus = 2
+	_GameInputBatteryCharging    _GameInputBatteryStatus = 3
+)
+
 type _GameInputCallbackToken uint64
 
 type _GameInputDeviceStatus int32
@@ -102,6 +112,14 @@ const (
 	_GameInputKindAny              _GameInputKind = 0x0FFFFFFF
 )
 
+type _GameInputBatteryState struct {
+	chargeRate         float32
+	maxChargeRate      float32
+	remainingCapacity  float32
+	fullChargeCapacity float32
+	status             _GameInputBatteryStatus
+}
+
 type _GameInputGamepadState struct {
 	buttons          _GameInputGamepadButtons
 	leftTrigger      float32
@@ -218,6 +236,17 @@ type _IGameInputDevice_Vtbl struct {
 	ReleaseExclusiveRawDeviceAccess uintptr
 }
 
+func (i *_IGameInputDevice) GetBatteryState() _GameInputBatteryState {
+	var state _GameInputBatteryState
+	_, _, _ = syscall.Syscall(i.vtbl.GetBatteryState, 2, uintptr(unsafe.Pointer(i)), uintptr(unsafe.Pointer(&state)), 0)
+	return state
+}
+
+func (i *_IGameInputDevice) GetDeviceStatus() _GameInputDeviceStatus {
+	r, _, _ := syscall.Syscall(i.vtbl.GetDeviceStatus, 1, uintptr(unsafe.Pointer(i)), 0, 0)
+	return _GameInputDeviceStatus(r)
+}
+
 func (i *_IGameInputDevice) SetRumbleState(params *_GameInputRumbleParams, timestamp uint64) {
 	_, _, _ = syscall.Syscall(i.vtbl.SetRumbleState, 3, uintptr(unsafe.Pointer(i)), uintptr(unsafe.Pointer(params)), uintptr(timestamp))
 	runtime.KeepAlive(params)
@@ -248,6 +248,9 @@ type nativeGamepad interface {
 	isButtonPressed(button int) bool
 	hatState(hat int) int
 	vibrate(duration time.Duration, strongMagnitude float64, weakMagnitude float64)
+	isVibrationAvailable() bool
+	batteryLevel() (float64, BatteryStatus)
+	connectionType() ConnectionType
 }
 
 func (g *Gamepad) update(gamepads *gamepads) error {
@@ -393,3 +396,27 @@ func (g *Gamepad) Vibrate(duration time.Duration, strongMagnitude float64, weakM
 
 	g.native.vibrate(duration, strongMagnitude, weakMagnitude)
 }
+
+// IsVibrationAvailable is concurrent-safe.
+func (g *Gamepad) IsVibrationAvailable() bool {
+	g.m.Lock()
+	defer g.m.Unlock()
+
+	return g.native.isVibrationAvailable()
+}
+
+// BatteryLevel is concurrent-safe.
+func (g *Gamepad) BatteryLevel() (float64, BatteryStatus) {
+	g.m.Lock()
+	defer g.m.Unlock()
+
+	return g.native.batteryLevel()
+}
+
+// ConnectionType is concurrent-safe.
+func (g *Gamepad) ConnectionType() ConnectionType {
+	g.m.Lock()
+	defer g.m.Unlock()
+
+	return g.native.connectionType()
+}
@@ -106,6 +106,10 @@ type nativeGamepadsDesktop struct {
 	procDirectInput8Create    uintptr
 	procXInputGetCapabilities uintptr
 	procXInputGetState        uintptr
+	procXInputSetState        uintptr
+
+	// procXInputGetBatteryInformation is available only on xinput1_4.dll.
+	procXInputGetBatteryInformation uintptr
 
 	origWndProc         uintptr
 	wndProcCallback     uintptr
@@ -169,6 +173,16 @@ func (g *nativeGamepadsDesktop) init(gamepads *gamepads) error {
 				}
 				g.procXInputGetState = p
 			}
+			{
+				p, err := windows.GetProcAddress(h, "XInputSetState")
+				if err != nil {
+					return err
+				}
+				g.procXInputSetState = p
+			}
+			if p, err := windows.GetProcAddress(h, "XInputGetBatteryInformation"); err == nil {
+				g.procXInputGetBatteryInformation = p
+			}
 			break
 		}
 	}
@@ -224,6 +238,26 @@ func (g *nativeGamepadsDesktop) xinputGetState(dwUserIndex uint32, pState *_XINP
 	return nil
 }
 
+func (g *nativeGamepadsDesktop) xinputSetState(dwUserIndex uint32, pVibration *_XINPUT_VIBRATION) error {
+	// XInputSetState doesn't call SetLastError and returns an error code directly.
+	r, _, _ := syscall.Syscall(g.procXInputSetState, 2,
+		uintptr(dwUserIndex), uintptr(unsafe.Pointer(pVibration)), 0)
+	if e := syscall.Errno(uint32(r)); e != windows.ERROR_SUCCESS {
+		return fmt.Errorf("gamepad: XInputSetState failed: %w", e)
+	}
+	return nil
+}
+
+func (g *nativeGamepadsDesktop) xinputGetBatteryInformation(dwUserIndex uint32, devType byte, pBatteryInformation *_XINPUT_BATTERY_INFORMATION) error {
+	// XInputGetBatteryInformation doesn't call SetLastError and returns an error code directly.
+	r, _, _ := syscall.Syscall(g.procXInputGetBatteryInformation, 3,
+		uintptr(dwUserIndex), uintptr(devType), uintptr(unsafe.Pointer(pBatteryInformation)))
+	if e := syscall.Errno(uint32(r)); e != windows.ERROR_SUCCESS {
+		return fmt.Errorf("gamepad: XInputGetBatteryInformation failed: %w", e)
+	}
+	return nil
+}
+
 func (g *nativeGamepadsDesktop) detectConnection(gamepads *gamepads) error {
 	if g.dinput8 != 0 {
 		if g.enumDevicesCallback == 0 {
@@ -280,7 +314,9 @@ func (g *nativeGamepadsDesktop) detectConnection(gamepads *gamepads) error {
 
 			gp := gamepads.add(name, sdlID)
 			gp.native = &nativeGamepadDesktop{
-				xinputIndex: i,
+				natives:        g,
+				xinputIndex:    i,
+				xinputWireless: xic.flags&_XINPUT_CAPS_WIRELESS != 0,
 			}
 		}
 	}
@@ -560,6 +596,8 @@ func (g *nativeGamepadsDesktop) setNativeWindow(nativeWindow uintptr) {
 }
 
 type nativeGamepadDesktop struct {
+	natives *nativeGamepadsDesktop
+
 	dinputDevice  *_IDirectInputDevice8W
 	dinputObjects []dinputObject
 	dinputGUID    windows.GUID
@@ -567,8 +605,12 @@ type nativeGamepadDesktop struct {
 	dinputButtons []bool
 	dinputHats    []int
 
-	xinputIndex int
-	xinputState _XINPUT_STATE
+	xinputIndex    int
+	xinputState    _XINPUT_STATE
+	xinputWireless bool
+
+	vib    bool
+	vibEnd time.Time
 }
 
 func (*nativeGamepadDesktop) hasOwnStandardLayoutMapping() bool {
@@ -695,6 +737,15 @@ func (g *nativeGamepadDesktop) update(gamepads *gamepads) (err error) {
 		return nil
 	}
 	g.xinputState = state
+
+	if g.vib && time.Now().Sub(g.vibEnd) >= 0 {
+		g.vib = false
+		if err := g.natives.xinputSetState(uint32(g.xinputIndex), &_XINPUT_VIBRATION{}); err != nil {
+			if !errors.Is(err, windows.ERROR_DEVICE_NOT_CONNECTED) {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
@@ -794,5 +845,82 @@ func (g *nativeGamepadDesktop) hatState(hat int) int {
 }
 
 func (g *nativeGamepadDesktop) vibrate(duration time.Duration, strongMagnitude float64, weakMagnitude float64) {
-	// TODO: Implement this (#1452)
+	if g.usesDInput() {
+		// TODO: Implement this with DirectInput force feedback (#1452)
+		return
+	}
+
+	if strongMagnitude <= 0 && weakMagnitude <= 0 {
+		g.vib = false
+		// An error is ignored as a gamepad can be disconnected at any time. update detects the disconnection.
+		_ = g.natives.xinputSetState(uint32(g.xinputIndex), &_XINPUT_VIBRATION{})
+		return
+	}
+
+	// The left motor of an XInput device is the low-frequency rumble motor, and the right motor is the high-frequency one.
+	g.vib = true
+	g.vibEnd = time.Now().Add(duration)
+	_ = g.natives.xinputSetState(uint32(g.xinputIndex), &_XINPUT_VIBRATION{
+		wLeftMotorSpeed:  xinputMotorSpeed(strongMagnitude),
+		wRightMotorSpeed: xinputMotorSpeed(weakMagnitude),
+	})
+}
+
+func (g *nativeGamepadDesktop) isVibrationAvailable() bool {
+	return !g.usesDInput()
+}
+
+func (g *nativeGamepadDesktop) batteryLevel() (float64, BatteryStatus) {
+	if g.usesDInput() {
+		return 0, BatteryStatusUnknown
+	}
+	if g.natives.procXInputGetBatteryInformation == 0 {
+		return 0, BatteryStatusUnknown
+	}
+
+	var info _XINPUT_BATTERY_INFORMATION
+	if err := g.natives.xinputGetBatteryInformation(uint32(g.xinputIndex), _BATTERY_DEVTYPE_GAMEPAD, &info); err != nil {
+		return 0, BatteryStatusUnknown
+	}
+
+	switch info.batteryType {
+	case _BATTERY_TYPE_DISCONNECTED, _BATTERY_TYPE_UNKNOWN:
+		return 0, BatteryStatusUnknown
+	case _BATTERY_TYPE_WIRED:
+		return 0, BatteryStatusNotPresent
+	}
+
+	// XInput reports only four levels of the charge, and doesn't report whether the battery is charging.
+	var level float64
+	switch info.batteryLevel {
+	case _BATTERY_LEVEL_EMPTY:
+		level = 0
+	case _BATTERY_LEVEL_LOW:
+		level = 1.0 / 3.0
+	case _BATTERY_LEVEL_MEDIUM:
+		level = 2.0 / 3.0
+	case _BATTERY_LEVEL_FULL:
+		level = 1
+	}
+	return level, BatteryStatusDischarging
+}
+
+func (g *nativeGamepadDesktop) connectionType() ConnectionType {
+	if g.usesDInput() {
+		return ConnectionTypeUnknown
+	}
+	if g.xinputWireless {
+		return ConnectionTypeWireless
+	}
+	return ConnectionTypeWired
+}
+
+func xinputMotorSpeed(magnitude float64) uint16 {
+	if magnitude <= 0 {
+		return 0
+	}
+	if magnitude >= 1 {
+		return 0xffff
+	}
+	return uint16(magnitude * 0xffff)
 }
@@ -208,3 +208,21 @@ func (g *nativeGamepadImpl) vibrate(duration time.Duration, strongMagnitude floa
 		return
 	}
 }
+
+func (g *nativeGamepadImpl) isVibrationAvailable() bool {
+	if va := g.value.Get("vibrationActuator"); va.Truthy() {
+		return va.Get("playEffect").Truthy()
+	}
+	if ha := g.value.Get("hapticActuators"); ha.Truthy() {
+		return ha.Length() > 0
+	}
+	return false
+}
+
+func (g *nativeGamepadImpl) batteryLevel() (float64, BatteryStatus) {
+	return 0, BatteryStatusUnknown
+}
+
+func (g *nativeGamepadImpl) connectionType() ConnectionType {
+	return ConnectionTypeUnknown
+}
@@ -623,3 +623,15 @@ func (g *nativeGamepadImpl) hatState(hat int) int {
 func (g *nativeGamepadImpl) vibrate(duration time.Duration, strongMagnitude float64, weakMagnitude float64) {
 	// TODO: Implement this (#1452)
 }
+
+func (g *nativeGamepadImpl) isVibrationAvailable() bool {
+	return false
+}
+
+func (g *nativeGamepadImpl) batteryLevel() (float64, BatteryStatus) {
+	return 0, BatteryStatusUnknown
+}
+
+func (g *nativeGamepadImpl) connectionType() ConnectionType {
+	return ConnectionTypeUnknown
+}
@@ -259,3 +259,41 @@ func (n *nativeGamepadXbox) vibrate(duration time.Duration, strongMagnitude floa
 		highFrequency: float32(weakMagnitude),
 	}, 0)
 }
+
+func (n *nativeGamepadXbox) isVibrationAvailable() bool {
+	return true
+}
+
+func (n *nativeGamepadXbox) batteryLevel() (float64, BatteryStatus) {
+	state := n.gameInputDevice.GetBatteryState()
+
+	var status BatteryStatus
+	switch state.status {
+	case _GameInputBatteryNotPresent:
+		return 0, BatteryStatusNotPresent
+	case _GameInputBatteryDischarging:
+		status = BatteryStatusDischarging
+	case _GameInputBatteryIdle:
+		status = BatteryStatusNotCharging
+	case _GameInputBatteryCharging:
+		status = BatteryStatusCharging
+	default:
+		return 0, BatteryStatusUnknown
+	}
+
+	if state.fullChargeCapacity <= 0 {
+		return 0, BatteryStatusUnknown
+	}
+	level := float64(state.remainingCapacity / state.fullChargeCapacity)
+	if level > 1 {
+		level = 1
+	}
+	return level, status
+}
+
+func (n *nativeGamepadXbox) connectionType() ConnectionType {
+	if n.gameInputDevice.GetDeviceStatus()&_GameInputDeviceWireless != 0 {
+		return ConnectionTypeWireless
+	}
+	return ConnectionTypeWired
+}
@@ -15,6 +15,7 @@
 package graphics_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
@@ -79,3 +80,181 @@ func BenchmarkAdjustPixel(b *testing.B) {
 		graphics.AdjustDestinationPixelForTesting(float32(i) / 17)
 	}
 }
+
+func TestQuadVerticesTranslated(t *testing.T) {
+	for _, c := range []struct {
+		sx0, sy0, sx1, sy1 float32
+		tx, ty             float32
+	}{
+		{0, 0, 16, 16, 0, 0},
+		{16, 32, 48, 40, 5, -7},
+		{1, 2, 3, 4, -100, 200},
+	} {
+		got := make([]float32, 4*graphics.VertexFloatCount)
+		want := make([]float32, 4*graphics.VertexFloatCount)
+		graphics.QuadVerticesTranslated(got, c.sx0, c.sy0, c.sx1, c.sy1, c.tx, c.ty, 0.1, 0.2, 0.3, 0.4)
+		graphics.QuadVertices(want, c.sx0, c.sy0, c.sx1, c.sy1, 1, 0, 0, 1, c.tx, c.ty, 0.1, 0.2, 0.3, 0.4)
+		for i := range got {
+			if got[i] != want[i] {
+				t.Errorf("QuadVerticesTranslated(%v)[%d]: got: %v, want: %v", c, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func TestDumpShader(t *testing.T) {
+	ir, err := graphics.CompileShader([]byte(`//kage:unit pixels
+
+package main
+
+var Flag bool
+var Colors [2]vec4
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	c := vec4(0)
+	for i := 0; i < 2; i++ {
+		if Flag {
+			c += Colors[i]
+		}
+	}
+	return c
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dump := graphics.DumpShader(ir, false)
+	if got := graphics.DumpShader(ir, false); got != dump {
+		t.Errorf("DumpShader must return the same result for the same program")
+	}
+	for _, want := range []string{
+		"unit pixels",
+		"U7 Flag bool // offset: 46, count: 1",
+		"U8 Colors [2]vec4 // offset: 47, count: 8",
+		"for l1 := int(0); l1 < 2; l1 += 1 {",
+		"fragment {",
+	} {
+		if !strings.Contains(dump, want) {
+			t.Errorf("DumpShader(ir, false) must contain %q but not:\n%s", want, dump)
+		}
+	}
+	if strings.Contains(dump, "// ---- GLSL") {
+		t.Errorf("DumpShader(ir, false) must not contain backend sources")
+	}
+
+	dumpWithSources := graphics.DumpShader(ir, true)
+	if !strings.HasPrefix(dumpWithSources, dump) {
+		t.Errorf("DumpShader(ir, true) must start with the result of DumpShader(ir, false)")
+	}
+	for _, want := range []string{
+		"// ---- GLSL fragment ----",
+		"// ---- Metal ----",
+		"// ---- HLSL pixel ----",
+	} {
+		if !strings.Contains(dumpWithSources, want) {
+			t.Errorf("DumpShader(ir, true) must contain %q but not", want)
+		}
+	}
+}
+
+func TestMarshalShader(t *testing.T) {
+	src := []byte(`//kage:unit pixels
+
+package main
+
+const scale = 1.0 / 3
+
+var Colors [2]vec4
+var Count int
+
+func colorAt(i int) vec4 {
+	return Colors[i] * scale
+}
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	c := vec4(0)
+	for i := 0; i < 2; i++ {
+		if i >= Count {
+			break
+		}
+		c += colorAt(i)
+	}
+	return c * imageSrc0At(srcPos)
+}
+`)
+	ir, err := graphics.CompileShader(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := graphics.MarshalShader(src, ir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotSrc, gotIR, err := graphics.UnmarshalShader(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(gotSrc) != string(src) {
+		t.Errorf("src: got: %q, want: %q", gotSrc, src)
+	}
+	if got, want := graphics.DumpShader(gotIR, true), graphics.DumpShader(ir, true); got != want {
+		t.Errorf("DumpShader(ir, true): got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Data with a broken magic must be rejected.
+	broken := append([]byte(nil), data...)
+	broken[0] ^= 0xff
+	if _, _, err := graphics.UnmarshalShader(broken); err == nil {
+		t.Errorf("UnmarshalShader with a broken magic must return an error but not")
+	}
+	// Data without the whole source must be rejected.
+	if _, _, err := graphics.UnmarshalShader(data[:len("ebitengine-shader")+16]); err == nil {
+		t.Errorf("UnmarshalShader with truncated data must return an error but not")
+	}
+}
+
+func TestUnmarshalShaderRecompile(t *testing.T) {
+	src := []byte(`//kage:unit pixels
+
+package main
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	return vec4(1, 0, 0, 1)
+}
+`)
+	ir, err := graphics.CompileShader(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := graphics.MarshalShader(src, ir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := graphics.DumpShader(ir, false)
+
+	if _, ir, err := graphics.UnmarshalShader(data); err != nil {
+		t.Error(err)
+	} else if got := graphics.DumpShader(ir, false); got != want {
+		t.Errorf("got: %s, want: %s", got, want)
+	}
+
+	// Data with a different fingerprint, e.g., encoded by an older compiler, is compiled again.
+	data2 := append([]byte(nil), data...)
+	data2[len("ebitengine-shader")] ^= 0xff
+	if gotSrc, ir, err := graphics.UnmarshalShader(data2); err != nil {
+		t.Error(err)
+	} else if got := graphics.DumpShader(ir, false); got != want || string(gotSrc) != string(src) {
+		t.Errorf("got: %s, want: %s", got, want)
+	}
+
+	// Data with a broken program is compiled again.
+	data3 := append([]byte(nil), data...)
+	data3 = data3[:len(data3)-8]
+	if _, ir, err := graphics.UnmarshalShader(data3); err != nil {
+		t.Error(err)
+	} else if got := graphics.DumpShader(ir, false); got != want {
+		t.Errorf("got: %s, want: %s", got, want)
+	}
+}
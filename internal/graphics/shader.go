@@ -136,6 +136,13 @@ func imageSrc%[1]dAt(pos vec2) vec4 {
 	in := step(__imageSrcRegionOrigins[0], pos) - step(__imageSrcRegionOrigins[0] + __imageSrcRegionSizes[%[1]d], pos)
 	return __texelAt(__t%[1]d, %[2]s) * in.x * in.y
 }
+
+// imageSrc%[1]dAtLod is the same as imageSrc%[1]dAt.
+// In pixels, a texel is always read exactly without sampling, so lod is not used.
+func imageSrc%[1]dAtLod(pos vec2, lod float) vec4 {
+	in := step(__imageSrcRegionOrigins[0], pos) - step(__imageSrcRegionOrigins[0] + __imageSrcRegionSizes[%[1]d], pos)
+	return __texelAtLod(__t%[1]d, %[2]s, lod) * in.x * in.y
+}
 `, i, pos)
 		case shaderir.Texels:
 			shaderSuffix += fmt.Sprintf(`
@@ -147,6 +154,17 @@ func imageSrc%[1]dAt(pos vec2) vec4 {
 	in := step(__imageSrcRegionOrigins[0], pos) - step(__imageSrcRegionOrigins[0] + __imageSrcRegionSizes[0], pos)
 	return __texelAt(__t%[1]d, %[2]s) * in.x * in.y
 }
+
+// imageSrc%[1]dAtLod is like imageSrc%[1]dAt, but samples the texture with the explicit level of detail lod
+// instead of the level calculated from the derivatives.
+// As lod doesn't depend on derivatives, imageSrc%[1]dAtLod is available in non-uniform control flow.
+//
+// All the graphics backends support the explicit level of detail.
+// As the source textures have only the base level so far, the base level is always sampled regardless of lod.
+func imageSrc%[1]dAtLod(pos vec2, lod float) vec4 {
+	in := step(__imageSrcRegionOrigins[0], pos) - step(__imageSrcRegionOrigins[0] + __imageSrcRegionSizes[0], pos)
+	return __texelAtLod(__t%[1]d, %[2]s, lod) * in.x * in.y
+}
 `, i, pos)
 		}
 	}
@@ -162,6 +180,11 @@ func __vertex(dstPos vec2, srcPos vec2, color vec4) (vec4, vec2, vec4) {
 }
 
 func CompileShader(src []byte) (*shaderir.Program, error) {
+	return CompileShaderWithFragmentEntry(src, "Fragment")
+}
+
+// CompileShaderWithFragmentEntry compiles a Kage program with the given function name as the fragment shader entry point.
+func CompileShaderWithFragmentEntry(src []byte, fragmentEntry string) (*shaderir.Program, error) {
 	unit, err := shader.ParseCompilerDirectives(src)
 	if err != nil {
 		return nil, err
@@ -175,10 +198,8 @@ func CompileShader(src []byte) (*shaderir.Program, error) {
 	buf.Write(src)
 	buf.WriteString(suffix)
 
-	const (
-		vert = "__vertex"
-		frag = "Fragment"
-	)
+	const vert = "__vertex"
+	frag := fragmentEntry
 	ir, err := shader.Compile(buf.Bytes(), vert, frag, ShaderImageCount)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,115 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphics
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/shader"
+	"github.com/hajimehoshi/ebiten/v2/internal/shaderir"
+)
+
+const shaderBinaryMagic = "ebitengine-shader"
+
+var (
+	shaderFingerprint     uint64
+	shaderFingerprintOnce sync.Once
+)
+
+// shaderEnvironmentFingerprint returns a hash of what a compiled program depends on other than its source,
+// i.e., the compiler version, the encoding version, and the suffix functions and variables.
+func shaderEnvironmentFingerprint() uint64 {
+	shaderFingerprintOnce.Do(func() {
+		h := fnv.New64a()
+		_ = binary.Write(h, binary.LittleEndian, uint32(shader.CompilerVersion))
+		_ = binary.Write(h, binary.LittleEndian, uint32(shaderir.EncodingVersion))
+		_ = binary.Write(h, binary.LittleEndian, uint32(ShaderImageCount))
+		for _, unit := range []shaderir.Unit{shaderir.Texels, shaderir.Pixels} {
+			suffix, err := shaderSuffix(unit)
+			if err != nil {
+				panic(fmt.Sprintf("graphics: shaderSuffix failed: %v", err))
+			}
+			_, _ = h.Write([]byte(suffix))
+		}
+		shaderFingerprint = h.Sum64()
+	})
+	return shaderFingerprint
+}
+
+// MarshalShader encodes a Kage source and its compiled program into bytes.
+//
+// The result includes a fingerprint of the current environment, so that UnmarshalShader can reject stale data.
+func MarshalShader(src []byte, ir *shaderir.Program) ([]byte, error) {
+	irBytes, err := ir.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(shaderBinaryMagic)
+	if err := binary.Write(&buf, binary.LittleEndian, shaderEnvironmentFingerprint()); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(&buf, binary.LittleEndian, uint32(len(src))); err != nil {
+		return nil, err
+	}
+	buf.Write(src)
+	buf.Write(irBytes)
+	return buf.Bytes(), nil
+}
+
+// UnmarshalShader decodes the bytes encoded by MarshalShader, and returns the Kage source and the compiled program.
+//
+// If the data was encoded in a different environment, e.g., a different version of Ebitengine,
+// or the compiled program in the data is broken, UnmarshalShader compiles the source in the data again.
+// UnmarshalShader returns an error if the data is broken and the source cannot be read.
+func UnmarshalShader(data []byte) ([]byte, *shaderir.Program, error) {
+	if !bytes.HasPrefix(data, []byte(shaderBinaryMagic)) {
+		return nil, nil, fmt.Errorf("graphics: the data is not an encoded shader")
+	}
+	data = data[len(shaderBinaryMagic):]
+
+	if len(data) < 12 {
+		return nil, nil, fmt.Errorf("graphics: the data is too short")
+	}
+	fingerprint := binary.LittleEndian.Uint64(data)
+	data = data[8:]
+
+	n := int(binary.LittleEndian.Uint32(data))
+	data = data[4:]
+	if n < 0 || len(data) < n {
+		return nil, nil, fmt.Errorf("graphics: the data is too short")
+	}
+	src := append([]byte(nil), data[:n]...)
+	data = data[n:]
+
+	if fingerprint == shaderEnvironmentFingerprint() {
+		var ir shaderir.Program
+		if err := ir.UnmarshalBinary(data); err == nil {
+			return src, &ir, nil
+		}
+	}
+
+	// The compiled program is not available. Compile the source again.
+	ir, err := CompileShader(src)
+	if err != nil {
+		return nil, nil, err
+	}
+	return src, ir, nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphics
+
+import (
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/shaderir"
+	"github.com/hajimehoshi/ebiten/v2/internal/shaderir/glsl"
+	"github.com/hajimehoshi/ebiten/v2/internal/shaderir/hlsl"
+	"github.com/hajimehoshi/ebiten/v2/internal/shaderir/msl"
+)
+
+// DumpShader returns a textual representation of the shader program for debugging.
+//
+// If backendSources is true, the result also includes the sources translated for the graphics backends,
+// which are the same as the sources the graphics drivers actually compile.
+// This is useful to find a difference between backends.
+//
+// The result is stable, so two results can be diffed.
+func DumpShader(ir *shaderir.Program, backendSources bool) string {
+	var b strings.Builder
+	b.WriteString(ir.Dump())
+	if !backendSources {
+		return b.String()
+	}
+
+	section := func(name, src string) {
+		b.WriteString("\n// ---- ")
+		b.WriteString(name)
+		b.WriteString(" ----\n\n")
+		b.WriteString(src)
+		if !strings.HasSuffix(src, "\n") {
+			b.WriteString("\n")
+		}
+	}
+
+	vs, fs := glsl.Compile(ir, glsl.GLSLVersionDefault)
+	section("GLSL vertex", vs)
+	section("GLSL fragment", fs)
+
+	vs, fs = glsl.Compile(ir, glsl.GLSLVersionES300)
+	section("GLSL ES 3.00 vertex", vs)
+	section("GLSL ES 3.00 fragment", fs)
+
+	// The entry point names are the same as the Metal graphics driver's.
+	section("Metal", msl.Compile(ir, "Vertex", "Fragment"))
+
+	vs, ps, _ := hlsl.Compile(ir)
+	section("HLSL vertex", vs)
+	section("HLSL pixel", ps)
+
+	return b.String()
+}
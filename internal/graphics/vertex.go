@@ -99,6 +99,53 @@ func QuadVertices(dst []float32, sx0, sy0, sx1, sy1 float32, a, b, c, d, tx, ty
 	dst[31] = ca
 }
 
+// QuadVerticesTranslated sets a float32 slice for a quadrangle that is translated by an integer offset (tx, ty).
+// The result is the same as QuadVertices with an identity matrix and the translation, but QuadVerticesTranslated is faster.
+//
+// tx and ty must be integers.
+func QuadVerticesTranslated(dst []float32, sx0, sy0, sx1, sy1 float32, tx, ty float32, cr, cg, cb, ca float32) {
+	x0, y0 := tx, ty
+	x1, y1 := tx+sx1-sx0, ty+sy1-sy0
+
+	dst = dst[:4*VertexFloatCount]
+
+	dst[0] = x0
+	dst[1] = y0
+	dst[2] = sx0
+	dst[3] = sy0
+	dst[4] = cr
+	dst[5] = cg
+	dst[6] = cb
+	dst[7] = ca
+
+	dst[8] = x1
+	dst[9] = y0
+	dst[10] = sx1
+	dst[11] = sy0
+	dst[12] = cr
+	dst[13] = cg
+	dst[14] = cb
+	dst[15] = ca
+
+	dst[16] = x0
+	dst[17] = y1
+	dst[18] = sx0
+	dst[19] = sy1
+	dst[20] = cr
+	dst[21] = cg
+	dst[22] = cb
+	dst[23] = ca
+
+	dst[24] = x1
+	dst[25] = y1
+	dst[26] = sx1
+	dst[27] = sy1
+	dst[28] = cr
+	dst[29] = cg
+	dst[30] = cb
+	dst[31] = ca
+}
+
 func adjustDestinationPixel(x float32) float32 {
 	// Avoid the center of the pixel, which is problematic (#929, #1171).
 	// Instead, align the vertices with about 1/3 pixels.
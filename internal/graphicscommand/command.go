@@ -19,6 +19,7 @@ import (
 	"image"
 	"math"
 	"strings"
+	"sync/atomic"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
@@ -117,9 +118,67 @@ func (c *drawTrianglesCommand) Exec(commandQueue *commandQueue, graphicsDriver g
 		imgs[i] = src.image.ID()
 	}
 
+	if clearer, ok := graphicsDriver.(graphicsdriver.RegionClearer); ok && c.canExecAsClear() {
+		for i, dstRegion := range c.dstRegions {
+			clr := c.vertices[i*4*graphics.VertexFloatCount+4 : i*4*graphics.VertexFloatCount+8]
+			if err := clearer.ClearRegion(c.dst.image.ID(), dstRegion.Region, clr[0], clr[1], clr[2], clr[3]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
 	return graphicsDriver.DrawTriangles(c.dst.image.ID(), imgs, c.shader.shader.ID(), c.dstRegions, indexOffset, c.blend, c.uniforms, c.fillRule)
 }
 
+// canExecAsClear reports whether the command can be executed as clear commands instead of drawing triangles.
+//
+// This is true when the command fills each destination region with one quadrangle of a solid color
+// by the fill shader and BlendCopy.
+func (c *drawTrianglesCommand) canExecAsClear() bool {
+	if fillShaderProgram == nil || c.shader.ir != fillShaderProgram {
+		return false
+	}
+	if c.blend != graphicsdriver.BlendCopy || c.fillRule != graphicsdriver.FillAll {
+		return false
+	}
+	// In OpenGL, the screen framebuffer's Y direction is different. Be conservative.
+	if c.dst.screen {
+		return false
+	}
+	const n = 4 * graphics.VertexFloatCount
+	if len(c.vertices) != n*len(c.dstRegions) {
+		return false
+	}
+	for i, dstRegion := range c.dstRegions {
+		if dstRegion.IndexCount != 6 {
+			return false
+		}
+		vs := c.vertices[i*n : (i+1)*n]
+		for j := 1; j < 4; j++ {
+			for k := 4; k < 8; k++ {
+				if vs[j*graphics.VertexFloatCount+k] != vs[k] {
+					return false
+				}
+			}
+		}
+		// The quadrangle must be an axis-aligned rectangle covering the whole region.
+		// Otherwise, clearing the region would change more pixels.
+		minX, minY, maxX, maxY := dstRegionFromVertices(vs)
+		for j := 0; j < 4; j++ {
+			x, y := vs[j*graphics.VertexFloatCount], vs[j*graphics.VertexFloatCount+1]
+			if (x != minX && x != maxX) || (y != minY && y != maxY) {
+				return false
+			}
+		}
+		r := dstRegion.Region
+		if minX > float32(r.Min.X) || minY > float32(r.Min.Y) || maxX < float32(r.Max.X) || maxY < float32(r.Max.Y) {
+			return false
+		}
+	}
+	return true
+}
+
 func (c *drawTrianglesCommand) NeedsSync() bool {
 	return false
 }
@@ -255,6 +314,9 @@ func (c *writePixelsCommand) NeedsSync() bool {
 type readPixelsCommand struct {
 	img  *Image
 	args []graphicsdriver.PixelsArgs
+
+	// callback is called after the pixels are read if the command is asynchronous.
+	callback func()
 }
 
 // Exec executes a readPixelsCommand.
@@ -262,11 +324,14 @@ func (c *readPixelsCommand) Exec(commandQueue *commandQueue, graphicsDriver grap
 	if err := c.img.image.ReadPixels(c.args); err != nil {
 		return err
 	}
+	if c.callback != nil {
+		c.callback()
+	}
 	return nil
 }
 
 func (c *readPixelsCommand) NeedsSync() bool {
-	return true
+	return c.callback == nil
 }
 
 func (c *readPixelsCommand) String() string {
@@ -295,6 +360,9 @@ func (c *disposeImageCommand) NeedsSync() bool {
 // disposeShaderCommand represents a command to dispose a shader.
 type disposeShaderCommand struct {
 	target *Shader
+
+	// counted reports whether the disposal is counted by ShaderCount and PendingShaderDisposalCount.
+	counted bool
 }
 
 func (c *disposeShaderCommand) String() string {
@@ -304,6 +372,10 @@ func (c *disposeShaderCommand) String() string {
 // Exec executes the disposeShaderCommand.
 func (c *disposeShaderCommand) Exec(commandQueue *commandQueue, graphicsDriver graphicsdriver.Graphics, indexOffset int) error {
 	c.target.shader.Dispose()
+	if c.counted {
+		atomic.AddInt64(&shaderCount, -1)
+		atomic.AddInt64(&pendingShaderDisposalCount, -1)
+	}
 	return nil
 }
 
@@ -342,6 +414,9 @@ func (c *newImageCommand) NeedsSync() bool {
 type newShaderCommand struct {
 	result *Shader
 	ir     *shaderir.Program
+
+	// counted reports whether the new shader is counted by ShaderCount.
+	counted bool
 }
 
 func (c *newShaderCommand) String() string {
@@ -355,6 +430,9 @@ func (c *newShaderCommand) Exec(commandQueue *commandQueue, graphicsDriver graph
 		return err
 	}
 	c.result.shader = s
+	if c.counted {
+		atomic.AddInt64(&shaderCount, 1)
+	}
 	return nil
 }
 
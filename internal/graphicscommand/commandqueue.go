@@ -78,6 +78,8 @@ type commandQueue struct {
 	uint32sBuffer uint32sBuffer
 	finalizers    []func()
 
+	reorderer commandReorderer
+
 	err atomic.Value
 }
 
@@ -282,6 +284,11 @@ func (q *commandQueue) flush(graphicsDriver graphicsdriver.Graphics, endFrame bo
 			}
 			nc++
 		}
+
+		// Reorder the commands to reduce the state changes in the graphics driver.
+		// The commands using the same vertex buffer are reordered together.
+		q.reorderer.reorder(cs[:nc], es[:ne])
+
 		if 0 < ne {
 			if err := graphicsDriver.SetVertices(vs[:nv], es[:ne]); err != nil {
 				return err
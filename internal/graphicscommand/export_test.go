@@ -0,0 +1,89 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphicscommand
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+)
+
+// DrawForTesting represents a draw-triangles command of a rectangle for testing.
+type DrawForTesting struct {
+	Dst    int
+	Src    int
+	Shader int
+	Blend  graphicsdriver.Blend
+	Region image.Rectangle
+}
+
+// ReorderDrawsForTesting reorders the given draws as the command queue does.
+// ReorderDrawsForTesting returns the indices of the draws in the new order,
+// and the numbers of the state switches before and after reordering.
+func ReorderDrawsForTesting(draws []DrawForTesting) (order []int, before, after int) {
+	images := map[int]*Image{}
+	imageByID := func(id int) *Image {
+		if id == 0 {
+			return nil
+		}
+		if img, ok := images[id]; ok {
+			return img
+		}
+		img := &Image{id: id}
+		images[id] = img
+		return img
+	}
+	shaders := map[int]*Shader{}
+
+	var cs []command
+	var indices []uint32
+	for i, d := range draws {
+		if _, ok := shaders[d.Shader]; !ok {
+			shaders[d.Shader] = &Shader{}
+		}
+		vs := make([]float32, 4*graphics.VertexFloatCount)
+		for j, p := range []image.Point{d.Region.Min, {d.Region.Max.X, d.Region.Min.Y}, {d.Region.Min.X, d.Region.Max.Y}, d.Region.Max} {
+			vs[j*graphics.VertexFloatCount] = float32(p.X)
+			vs[j*graphics.VertexFloatCount+1] = float32(p.Y)
+		}
+		base := uint32(4 * i)
+		indices = append(indices, base, base+1, base+2, base+1, base+2, base+3)
+		cs = append(cs, &drawTrianglesCommand{
+			dst:      imageByID(d.Dst),
+			srcs:     [graphics.ShaderImageCount]*Image{imageByID(d.Src)},
+			vertices: vs,
+			blend:    d.Blend,
+			dstRegions: []graphicsdriver.DstRegion{
+				{
+					Region:     d.Region,
+					IndexCount: 6,
+				},
+			},
+			shader:   shaders[d.Shader],
+			fillRule: graphicsdriver.FillAll,
+		})
+	}
+
+	before = countStateSwitches(cs)
+	var r commandReorderer
+	r.reorder(cs, indices)
+	after = countStateSwitches(cs)
+
+	for i := 0; i < len(indices); i += 6 {
+		order = append(order, int(indices[i]/4))
+	}
+	return order, before, after
+}
@@ -160,6 +160,21 @@ func (i *Image) ReadPixels(graphicsDriver graphicsdriver.Graphics, args []graphi
 	return nil
 }
 
+// ReadPixelsAsync enqueues a command to read the image's pixels without flushing the command queue.
+//
+// The pixels are read on the rendering thread when the command queue is flushed, in the same order as the other commands.
+// callback is called on the rendering thread after the pixels are read.
+// The caller must not access the pixels until callback is called.
+func (i *Image) ReadPixelsAsync(args []graphicsdriver.PixelsArgs, callback func()) {
+	i.flushBufferedWritePixels()
+	c := &readPixelsCommand{
+		img:      i,
+		args:     args,
+		callback: callback,
+	}
+	theCommandQueueManager.enqueueCommand(c)
+}
+
 func (i *Image) WritePixels(pixels *graphics.ManagedBytes, region image.Rectangle) {
 	i.bufferedWritePixelsArgs = append(i.bufferedWritePixelsArgs, writePixelsCommandArgs{
 		pixels: pixels,
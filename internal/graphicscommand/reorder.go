@@ -0,0 +1,179 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphicscommand
+
+import (
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+)
+
+// maxReorderDistance is the maximum number of commands a draw-triangles command can go over by reordering.
+// This limits the cost of reordering.
+const maxReorderDistance = 64
+
+// commandReorderer reorders draw-triangles commands so that the commands with the same states are consecutive.
+type commandReorderer struct {
+	commands []*drawTrianglesCommand
+	offsets  []int
+	order    []int
+	indices  []uint32
+}
+
+// reorder reorders the draw-triangles commands in cs in place to reduce the state changes in the graphics driver,
+// and rewrites indices to follow the new order.
+//
+// indices must be the indices for the commands cs in the current order.
+//
+// A command is moved only when the result is the same as the original order.
+// Commands other than draw-triangles commands are never moved, and no command goes over them.
+func (r *commandReorderer) reorder(cs []command, indices []uint32) {
+	var indexOffset int
+	start := -1
+	startIndexOffset := 0
+	for i := 0; i <= len(cs); i++ {
+		if i < len(cs) {
+			if dtc, ok := cs[i].(*drawTrianglesCommand); ok {
+				if start < 0 {
+					start = i
+					startIndexOffset = indexOffset
+				}
+				indexOffset += dtc.numIndices()
+				continue
+			}
+		}
+		if start >= 0 && i-start > 2 {
+			r.reorderRun(cs[start:i], indices[startIndexOffset:indexOffset])
+		}
+		start = -1
+	}
+}
+
+// reorderRun reorders a run of consecutive draw-triangles commands in place, and rewrites the indices for the run.
+func (r *commandReorderer) reorderRun(cs []command, indices []uint32) {
+	r.commands = r.commands[:0]
+	r.offsets = r.offsets[:0]
+	var offset int
+	for _, c := range cs {
+		dtc := c.(*drawTrianglesCommand)
+		r.commands = append(r.commands, dtc)
+		r.offsets = append(r.offsets, offset)
+		offset += dtc.numIndices()
+	}
+
+	// Insert each command just after the last command with the same states,
+	// if the command can go over all the commands in between.
+	r.order = r.order[:0]
+	var changed bool
+	for i, c := range r.commands {
+		pos := len(r.order)
+		for j := len(r.order) - 1; j >= 0 && len(r.order)-j <= maxReorderDistance; j-- {
+			other := r.commands[r.order[j]]
+			if other.hasSameStates(c) {
+				pos = j + 1
+				break
+			}
+			if !other.isIndependentOf(c) {
+				break
+			}
+		}
+		if pos < len(r.order) {
+			changed = true
+		}
+		r.order = append(r.order, 0)
+		copy(r.order[pos+1:], r.order[pos:])
+		r.order[pos] = i
+	}
+	if !changed {
+		return
+	}
+
+	r.indices = r.indices[:0]
+	for i, idx := range r.order {
+		c := r.commands[idx]
+		cs[i] = c
+		r.indices = append(r.indices, indices[r.offsets[idx]:r.offsets[idx]+c.numIndices()]...)
+	}
+	copy(indices, r.indices)
+
+	for i := range r.commands {
+		r.commands[i] = nil
+	}
+}
+
+// hasSameStates reports whether c and other use the same states in the graphics driver,
+// i.e., the same shader, destination, sources, blend, and fill rule.
+func (c *drawTrianglesCommand) hasSameStates(other *drawTrianglesCommand) bool {
+	return c.shader == other.shader &&
+		c.dst == other.dst &&
+		c.srcs == other.srcs &&
+		c.blend == other.blend &&
+		c.fillRule == other.fillRule
+}
+
+// isIndependentOf reports whether the result is the same regardless of the order of c and other.
+func (c *drawTrianglesCommand) isIndependentOf(other *drawTrianglesCommand) bool {
+	// A stencil buffer might be shared among the commands. Be conservative.
+	if c.fillRule != graphicsdriver.FillAll || other.fillRule != graphicsdriver.FillAll {
+		return false
+	}
+
+	for _, src := range c.srcs {
+		if src != nil && src == other.dst {
+			return false
+		}
+	}
+	for _, src := range other.srcs {
+		if src != nil && src == c.dst {
+			return false
+		}
+	}
+
+	if c.dst != other.dst {
+		return true
+	}
+	if !mightOverlapDstRegions(c.vertices, other.vertices) {
+		return true
+	}
+
+	// Even if the regions overlap, the order doesn't matter when the blend is commutative.
+	return c.blend == other.blend && isCommutativeBlend(c.blend)
+}
+
+// isCommutativeBlend reports whether the result of two draw calls with the blend doesn't depend on their order.
+func isCommutativeBlend(blend graphicsdriver.Blend) bool {
+	// dst' = src + dst is commutative. The clamping doesn't matter as the values are not negative.
+	return blend.BlendFactorSourceRGB == graphicsdriver.BlendFactorOne &&
+		blend.BlendFactorSourceAlpha == graphicsdriver.BlendFactorOne &&
+		blend.BlendFactorDestinationRGB == graphicsdriver.BlendFactorOne &&
+		blend.BlendFactorDestinationAlpha == graphicsdriver.BlendFactorOne &&
+		blend.BlendOperationRGB == graphicsdriver.BlendOperationAdd &&
+		blend.BlendOperationAlpha == graphicsdriver.BlendOperationAdd
+}
+
+// countStateSwitches returns the number of the state changes in the graphics driver to execute cs in the order.
+func countStateSwitches(cs []command) int {
+	var n int
+	var last *drawTrianglesCommand
+	for _, c := range cs {
+		dtc, ok := c.(*drawTrianglesCommand)
+		if !ok {
+			continue
+		}
+		if last == nil || !last.hasSameStates(dtc) {
+			n++
+		}
+		last = dtc
+	}
+	return n
+}
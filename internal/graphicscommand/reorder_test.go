@@ -0,0 +1,125 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphicscommand_test
+
+import (
+	"image"
+	"reflect"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicscommand"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+)
+
+var blendLighter = graphicsdriver.Blend{
+	BlendFactorSourceRGB:        graphicsdriver.BlendFactorOne,
+	BlendFactorSourceAlpha:      graphicsdriver.BlendFactorOne,
+	BlendFactorDestinationRGB:   graphicsdriver.BlendFactorOne,
+	BlendFactorDestinationAlpha: graphicsdriver.BlendFactorOne,
+	BlendOperationRGB:           graphicsdriver.BlendOperationAdd,
+	BlendOperationAlpha:         graphicsdriver.BlendOperationAdd,
+}
+
+func TestReorderDraws(t *testing.T) {
+	cases := []struct {
+		Name  string
+		Draws []graphicscommand.DrawForTesting
+		Order []int
+	}{
+		{
+			Name: "separated",
+			Draws: []graphicscommand.DrawForTesting{
+				{Dst: 1, Shader: 1, Blend: graphicsdriver.BlendSourceOver, Region: image.Rect(0, 0, 10, 10)},
+				{Dst: 1, Shader: 2, Blend: graphicsdriver.BlendSourceOver, Region: image.Rect(20, 0, 30, 10)},
+				{Dst: 1, Shader: 1, Blend: graphicsdriver.BlendSourceOver, Region: image.Rect(40, 0, 50, 10)},
+				{Dst: 1, Shader: 2, Blend: graphicsdriver.BlendSourceOver, Region: image.Rect(60, 0, 70, 10)},
+			},
+			Order: []int{0, 2, 1, 3},
+		},
+		{
+			Name: "overlapped",
+			Draws: []graphicscommand.DrawForTesting{
+				{Dst: 1, Shader: 1, Blend: graphicsdriver.BlendSourceOver, Region: image.Rect(0, 0, 10, 10)},
+				{Dst: 1, Shader: 2, Blend: graphicsdriver.BlendSourceOver, Region: image.Rect(5, 0, 15, 10)},
+				{Dst: 1, Shader: 1, Blend: graphicsdriver.BlendSourceOver, Region: image.Rect(10, 0, 20, 10)},
+			},
+			Order: []int{0, 1, 2},
+		},
+		{
+			Name: "overlapped with a commutative blend",
+			Draws: []graphicscommand.DrawForTesting{
+				{Dst: 1, Shader: 1, Blend: blendLighter, Region: image.Rect(0, 0, 10, 10)},
+				{Dst: 1, Shader: 2, Blend: blendLighter, Region: image.Rect(5, 0, 15, 10)},
+				{Dst: 1, Shader: 1, Blend: blendLighter, Region: image.Rect(10, 0, 20, 10)},
+			},
+			Order: []int{0, 2, 1},
+		},
+		{
+			Name: "dependent",
+			Draws: []graphicscommand.DrawForTesting{
+				{Dst: 1, Shader: 1, Blend: graphicsdriver.BlendSourceOver, Region: image.Rect(0, 0, 10, 10)},
+				{Dst: 2, Src: 1, Shader: 2, Blend: graphicsdriver.BlendSourceOver, Region: image.Rect(0, 0, 10, 10)},
+				{Dst: 1, Shader: 1, Blend: graphicsdriver.BlendSourceOver, Region: image.Rect(20, 0, 30, 10)},
+			},
+			Order: []int{0, 1, 2},
+		},
+		{
+			Name: "different destinations",
+			Draws: []graphicscommand.DrawForTesting{
+				{Dst: 1, Shader: 1, Blend: graphicsdriver.BlendSourceOver, Region: image.Rect(0, 0, 10, 10)},
+				{Dst: 2, Shader: 2, Blend: graphicsdriver.BlendSourceOver, Region: image.Rect(0, 0, 10, 10)},
+				{Dst: 1, Shader: 1, Blend: graphicsdriver.BlendSourceOver, Region: image.Rect(0, 0, 10, 10)},
+			},
+			Order: []int{0, 2, 1},
+		},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			got, _, _ := graphicscommand.ReorderDrawsForTesting(c.Draws)
+			if want := c.Order; !reflect.DeepEqual(got, want) {
+				t.Errorf("got: %v, want: %v", got, want)
+			}
+		})
+	}
+}
+
+func BenchmarkReorderMixedShaderDraws(b *testing.B) {
+	const (
+		drawCount   = 500
+		shaderCount = 4
+	)
+
+	// Sprites with different shaders are drawn alternately without overlapping.
+	draws := make([]graphicscommand.DrawForTesting, 0, drawCount)
+	for i := 0; i < drawCount; i++ {
+		x := (i % 25) * 20
+		y := (i / 25) * 20
+		draws = append(draws, graphicscommand.DrawForTesting{
+			Dst:    1,
+			Src:    2,
+			Shader: i%shaderCount + 1,
+			Blend:  graphicsdriver.BlendSourceOver,
+			Region: image.Rect(x, y, x+16, y+16),
+		})
+	}
+
+	var before, after int
+	for i := 0; i < b.N; i++ {
+		_, before, after = graphicscommand.ReorderDrawsForTesting(draws)
+	}
+	b.ReportMetric(float64(before), "switches-before/op")
+	b.ReportMetric(float64(after), "switches-after/op")
+}
@@ -15,28 +15,90 @@
 package graphicscommand
 
 import (
+	"sync/atomic"
+
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
 	"github.com/hajimehoshi/ebiten/v2/internal/shaderir"
 )
 
+var (
+	// shaderCount is the number of shaders created in the graphics driver and not disposed yet.
+	shaderCount int64
+
+	// pendingShaderDisposalCount is the number of shaders whose disposing commands are enqueued but not executed yet.
+	pendingShaderDisposalCount int64
+)
+
+// ShaderCount returns the number of shaders that are created in the graphics driver and not disposed yet.
+//
+// ShaderCount is concurrent-safe.
+func ShaderCount() int {
+	return int(atomic.LoadInt64(&shaderCount))
+}
+
+// PendingShaderDisposalCount returns the number of shaders whose disposals are requested but not done yet
+// in the graphics driver.
+//
+// PendingShaderDisposalCount is concurrent-safe.
+func PendingShaderDisposalCount() int {
+	return int(atomic.LoadInt64(&pendingShaderDisposalCount))
+}
+
+// fillShaderProgram is the program of the shader that fills triangles with the vertex colors.
+var fillShaderProgram *shaderir.Program
+
+// SetFillShaderProgram sets the program of the shader that fills triangles with the vertex colors.
+//
+// A draw-triangles command with the program and BlendCopy might be executed as a clear command
+// if the graphics driver implements graphicsdriver.RegionClearer.
+//
+// SetFillShaderProgram must be called before any command is flushed.
+func SetFillShaderProgram(ir *shaderir.Program) {
+	fillShaderProgram = ir
+}
+
 type Shader struct {
 	shader graphicsdriver.Shader
 	ir     *shaderir.Program
 }
 
 func NewShader(ir *shaderir.Program) *Shader {
+	return newShader(ir, true)
+}
+
+// NewShaderForRestoring creates a shader to replace a shader disposed by DisposeForRestoring.
+//
+// Unlike NewShader, the created shader is not counted by ShaderCount, as the shader replaces an existing shader.
+func NewShaderForRestoring(ir *shaderir.Program) *Shader {
+	return newShader(ir, false)
+}
+
+func newShader(ir *shaderir.Program, counted bool) *Shader {
 	s := &Shader{
 		ir: ir,
 	}
 	c := &newShaderCommand{
-		result: s,
-		ir:     ir,
+		result:  s,
+		ir:      ir,
+		counted: counted,
 	}
 	theCommandQueueManager.enqueueCommand(c)
 	return s
 }
 
 func (s *Shader) Dispose() {
+	atomic.AddInt64(&pendingShaderDisposalCount, 1)
+	c := &disposeShaderCommand{
+		target:  s,
+		counted: true,
+	}
+	theCommandQueueManager.enqueueCommand(c)
+}
+
+// DisposeForRestoring disposes the shader in order to recreate it with NewShaderForRestoring.
+//
+// Unlike Dispose, the disposal is not counted by ShaderCount or PendingShaderDisposalCount.
+func (s *Shader) DisposeForRestoring() {
 	c := &disposeShaderCommand{
 		target: s,
 	}
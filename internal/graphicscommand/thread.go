@@ -39,3 +39,8 @@ func runOnRenderThread(f func(), sync bool) {
 	// This blocking is expected as double-buffering is used.
 	theRenderThread.CallAsync(f)
 }
+
+// WaitForRenderThread blocks until all the functions queued to the rendering thread so far are executed.
+func WaitForRenderThread() {
+	runOnRenderThread(func() {}, true)
+}
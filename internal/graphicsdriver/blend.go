@@ -21,6 +21,24 @@ type Blend struct {
 	BlendFactorDestinationAlpha BlendFactor
 	BlendOperationRGB           BlendOperation
 	BlendOperationAlpha         BlendOperation
+	BlendConstantColor          [4]float32
+}
+
+// UsesConstantColor reports whether the blend uses the constant color.
+func (b Blend) UsesConstantColor() bool {
+	for _, f := range []BlendFactor{b.BlendFactorSourceRGB, b.BlendFactorSourceAlpha, b.BlendFactorDestinationRGB, b.BlendFactorDestinationAlpha} {
+		if f == BlendFactorConstantColor || f == BlendFactorOneMinusConstantColor {
+			return true
+		}
+	}
+	return false
+}
+
+// WithoutConstantColor returns the blend without the constant color.
+// This is useful as a key of a pipeline state, as the constant color is not a part of a pipeline state.
+func (b Blend) WithoutConstantColor() Blend {
+	b.BlendConstantColor = [4]float32{}
+	return b
 }
 
 type BlendFactor byte
@@ -37,6 +55,8 @@ const (
 	BlendFactorDestinationAlpha
 	BlendFactorOneMinusDestinationAlpha
 	BlendFactorSourceAlphaSaturated
+	BlendFactorConstantColor
+	BlendFactorOneMinusConstantColor
 )
 
 type BlendOperation byte
@@ -48,6 +48,9 @@ package directx
 //     void Ebitengine_ID3D12GraphicsCommandList_IASetVertexBuffers(void* i, uint32_t startSlot, uint32_t numViews, void* pViews) {
 //         static_cast<ID3D12GraphicsCommandList*>(i)->IASetVertexBuffers(startSlot, numViews, static_cast<D3D12_VERTEX_BUFFER_VIEW*>(pViews));
 //     }
+//     void Ebitengine_ID3D12GraphicsCommandList_OMSetBlendFactor(void* i, void* blendFactor) {
+//         static_cast<ID3D12GraphicsCommandList*>(i)->OMSetBlendFactor(static_cast<FLOAT*>(blendFactor));
+//     }
 //     void Ebitengine_ID3D12GraphicsCommandList_OMSetRenderTargets(void* i, uint32_t numRenderTargetDescriptors, void* pRenderTargetDescriptors, int rtsSingleHandleToDescriptorRange, void* pDepthStencilDescriptor) {
 //         static_cast<ID3D12GraphicsCommandList*>(i)->OMSetRenderTargets(numRenderTargetDescriptors, static_cast<D3D12_CPU_DESCRIPTOR_HANDLE*>(pRenderTargetDescriptors), static_cast<BOOL>(rtsSingleHandleToDescriptorRange), static_cast<D3D12_CPU_DESCRIPTOR_HANDLE*>(pDepthStencilDescriptor));
 //     }
@@ -94,6 +97,7 @@ package directx
 // void Ebitengine_ID3D12GraphicsCommandList_IASetIndexBuffer(void* i, void* pView);
 // void Ebitengine_ID3D12GraphicsCommandList_IASetPrimitiveTopology(void* i, int32_t primitiveTopology);
 // void Ebitengine_ID3D12GraphicsCommandList_IASetVertexBuffers(void* i, uint32_t startSlot, uint32_t numViews, void* pViews);
+// void Ebitengine_ID3D12GraphicsCommandList_OMSetBlendFactor(void* i, void* blendFactor);
 // void Ebitengine_ID3D12GraphicsCommandList_OMSetRenderTargets(void* i, uint32_t numRenderTargetDescriptors, void* pRenderTargetDescriptors, int rtsSingleHandleToDescriptorRange, void* pDepthStencilDescriptor);
 // void Ebitengine_ID3D12GraphicsCommandList_OMSetStencilRef(void* i, uint32_t stencilRef);
 // uint32_t Ebitengine_ID3D12GraphicsCommandList_Release(void* i);
@@ -158,6 +162,10 @@ func _ID3D12GraphicsCommandList_IASetVertexBuffers(i *_ID3D12GraphicsCommandList
 	C.Ebitengine_ID3D12GraphicsCommandList_IASetVertexBuffers(unsafe.Pointer(i), C.uint32_t(startSlot), C.uint32_t(len(views)), unsafe.Pointer(pViews))
 }
 
+func _ID3D12GraphicsCommandList_OMSetBlendFactor(i *_ID3D12GraphicsCommandList, blendFactor [4]float32) {
+	C.Ebitengine_ID3D12GraphicsCommandList_OMSetBlendFactor(unsafe.Pointer(i), unsafe.Pointer(&blendFactor[0]))
+}
+
 func _ID3D12GraphicsCommandList_OMSetRenderTargets(i *_ID3D12GraphicsCommandList, renderTargetDescriptors []_D3D12_CPU_DESCRIPTOR_HANDLE, rtsSingleHandleToDescriptorRange bool, pDepthStencilDescriptor *_D3D12_CPU_DESCRIPTOR_HANDLE) {
 	var pRenderTargetDescriptors *_D3D12_CPU_DESCRIPTOR_HANDLE
 	if len(renderTargetDescriptors) > 0 {
@@ -1836,6 +1836,14 @@ func (i *_ID3D12GraphicsCommandList) IASetVertexBuffers(startSlot uint32, views
 	runtime.KeepAlive(views)
 }
 
+func (i *_ID3D12GraphicsCommandList) OMSetBlendFactor(blendFactor [4]float32) {
+	if microsoftgdk.IsXbox() {
+		_ID3D12GraphicsCommandList_OMSetBlendFactor(i, blendFactor)
+		return
+	}
+	_, _, _ = syscall.Syscall(i.vtbl.OMSetBlendFactor, 2, uintptr(unsafe.Pointer(i)), uintptr(unsafe.Pointer(&blendFactor[0])), 0)
+}
+
 func (i *_ID3D12GraphicsCommandList) OMSetRenderTargets(renderTargetDescriptors []_D3D12_CPU_DESCRIPTOR_HANDLE, rtsSingleHandleToDescriptorRange bool, pDepthStencilDescriptor *_D3D12_CPU_DESCRIPTOR_HANDLE) {
 	if microsoftgdk.IsXbox() {
 		_ID3D12GraphicsCommandList_OMSetRenderTargets(i, renderTargetDescriptors, rtsSingleHandleToDescriptorRange, pDepthStencilDescriptor)
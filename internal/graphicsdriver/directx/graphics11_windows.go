@@ -93,6 +93,10 @@ func blendFactorToBlend11(f graphicsdriver.BlendFactor, alpha bool) _D3D11_BLEND
 		return _D3D11_BLEND_INV_DEST_ALPHA
 	case graphicsdriver.BlendFactorSourceAlphaSaturated:
 		return _D3D11_BLEND_SRC_ALPHA_SAT
+	case graphicsdriver.BlendFactorConstantColor:
+		return _D3D11_BLEND_BLEND_FACTOR
+	case graphicsdriver.BlendFactorOneMinusConstantColor:
+		return _D3D11_BLEND_INV_BLEND_FACTOR
 	default:
 		panic(fmt.Sprintf("directx: invalid blend factor: %d", f))
 	}
@@ -562,7 +566,7 @@ func (g *graphics11) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphic
 		if err != nil {
 			return err
 		}
-		g.deviceContext.OMSetBlendState(bs, nil, 0xffffffff)
+		g.deviceContext.OMSetBlendState(bs, &blend.BlendConstantColor, 0xffffffff)
 
 		dss, err := g.depthStencilState(noStencil)
 		if err != nil {
@@ -589,7 +593,7 @@ func (g *graphics11) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphic
 			if err != nil {
 				return err
 			}
-			g.deviceContext.OMSetBlendState(bs, nil, 0xffffffff)
+			g.deviceContext.OMSetBlendState(bs, &blend.BlendConstantColor, 0xffffffff)
 			dss, err := g.depthStencilState(incrementStencil)
 			if err != nil {
 				return err
@@ -601,7 +605,7 @@ func (g *graphics11) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphic
 			if err != nil {
 				return err
 			}
-			g.deviceContext.OMSetBlendState(bs, nil, 0xffffffff)
+			g.deviceContext.OMSetBlendState(bs, &blend.BlendConstantColor, 0xffffffff)
 			dss, err := g.depthStencilState(invertStencil)
 			if err != nil {
 				return err
@@ -615,7 +619,7 @@ func (g *graphics11) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphic
 			if err != nil {
 				return err
 			}
-			g.deviceContext.OMSetBlendState(bs, nil, 0xffffffff)
+			g.deviceContext.OMSetBlendState(bs, &blend.BlendConstantColor, 0xffffffff)
 			dss, err := g.depthStencilState(drawWithStencil)
 			if err != nil {
 				return err
@@ -646,8 +650,9 @@ func (g *graphics11) blendState(blend graphicsdriver.Blend, stencilMode stencilM
 		writeMask = uint8(_D3D11_COLOR_WRITE_ENABLE_ALL)
 	}
 
+	// The constant color is not a part of a blend state. This is specified at OMSetBlendState.
 	key := blendStateKey{
-		blend:     blend,
+		blend:     blend.WithoutConstantColor(),
 		writeMask: writeMask,
 	}
 	if bs, ok := g.blendStates[key]; ok {
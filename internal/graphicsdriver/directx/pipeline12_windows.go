@@ -94,6 +94,10 @@ func blendFactorToBlend12(f graphicsdriver.BlendFactor, alpha bool) _D3D12_BLEND
 		return _D3D12_BLEND_INV_DEST_ALPHA
 	case graphicsdriver.BlendFactorSourceAlphaSaturated:
 		return _D3D12_BLEND_SRC_ALPHA_SAT
+	case graphicsdriver.BlendFactorConstantColor:
+		return _D3D12_BLEND_BLEND_FACTOR
+	case graphicsdriver.BlendFactorOneMinusConstantColor:
+		return _D3D12_BLEND_INV_BLEND_FACTOR
 	default:
 		panic(fmt.Sprintf("directx: invalid blend factor: %d", f))
 	}
@@ -289,6 +293,10 @@ func (p *pipelineStates) drawTriangles(device *_ID3D12Device, commandList *_ID3D
 	}
 	commandList.SetGraphicsRootDescriptorTable(2, sh)
 
+	if blend.UsesConstantColor() {
+		commandList.OMSetBlendFactor(blend.BlendConstantColor)
+	}
+
 	if fillRule == graphicsdriver.FillAll {
 		s, err := shader.pipelineState(blend, noStencil, screen)
 		if err != nil {
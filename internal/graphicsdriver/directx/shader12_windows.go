@@ -68,8 +68,9 @@ func (s *shader12) disposeImpl() {
 }
 
 func (s *shader12) pipelineState(blend graphicsdriver.Blend, stencilMode stencilMode, screen bool) (*_ID3D12PipelineState, error) {
+	// The constant color is not a part of a pipeline state. This is specified at OMSetBlendFactor.
 	key := pipelineStateKey{
-		blend:       blend,
+		blend:       blend.WithoutConstantColor(),
 		stencilMode: stencilMode,
 		screen:      screen,
 	}
@@ -90,7 +90,7 @@ func constantBufferSize(uniformTypes []shaderir.Type, uniformOffsets []int) int
 		switch typ.Main {
 		case shaderir.Float:
 			size += 1
-		case shaderir.Int:
+		case shaderir.Bool, shaderir.Int:
 			size += 1
 		case shaderir.Vec2, shaderir.IVec2:
 			size += 2
@@ -109,7 +109,7 @@ func constantBufferSize(uniformTypes []shaderir.Type, uniformOffsets []int) int
 			switch typ.Sub[0].Main {
 			case shaderir.Float:
 				size += 4*(typ.Length-1) + 1
-			case shaderir.Int:
+			case shaderir.Bool, shaderir.Int:
 				size += 4*(typ.Length-1) + 1
 			case shaderir.Vec2, shaderir.IVec2:
 				size += 4*(typ.Length-1) + 2
@@ -145,7 +145,7 @@ func adjustUniforms(uniformTypes []shaderir.Type, uniformOffsets []int, uniforms
 		switch typ.Main {
 		case shaderir.Float:
 			fs = append(fs, uniforms[idx:idx+1]...)
-		case shaderir.Int:
+		case shaderir.Bool, shaderir.Int:
 			fs = append(fs, uniforms[idx:idx+1]...)
 		case shaderir.Vec2, shaderir.IVec2:
 			fs = append(fs, uniforms[idx:idx+2]...)
@@ -193,7 +193,7 @@ func adjustUniforms(uniformTypes []shaderir.Type, uniformOffsets []int, uniforms
 						fs = append(fs, 0, 0, 0)
 					}
 				}
-			case shaderir.Int:
+			case shaderir.Bool, shaderir.Int:
 				for j := 0; j < typ.Length; j++ {
 					fs = append(fs, uniforms[idx+j])
 					if j < typ.Length-1 {
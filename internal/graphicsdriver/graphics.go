@@ -76,6 +76,13 @@ type Resetter interface {
 	Reset() error
 }
 
+// RegionClearer is an optional interface of Graphics to fill a region of an image with a color without drawing triangles.
+//
+// ClearRegion replaces the pixels in region of dst with the given premultiplied color.
+type RegionClearer interface {
+	ClearRegion(dst ImageID, region image.Rectangle, red, green, blue, alpha float32) error
+}
+
 type Image interface {
 	ID() ImageID
 	Dispose()
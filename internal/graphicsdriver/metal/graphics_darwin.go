@@ -369,6 +369,10 @@ func blendFactorToMetalBlendFactor(c graphicsdriver.BlendFactor) mtl.BlendFactor
 		return mtl.BlendFactorOneMinusDestinationAlpha
 	case graphicsdriver.BlendFactorSourceAlphaSaturated:
 		return mtl.BlendFactorSourceAlphaSaturated
+	case graphicsdriver.BlendFactorConstantColor:
+		return mtl.BlendFactorBlendColor
+	case graphicsdriver.BlendFactorOneMinusConstantColor:
+		return mtl.BlendFactorOneMinusBlendColor
 	default:
 		panic(fmt.Sprintf("metal: invalid blend factor: %d", c))
 	}
@@ -587,6 +591,11 @@ func (g *Graphics) draw(dst *Image, dstRegions []graphicsdriver.DstRegion, srcs
 		drawWithStencilRpss = s
 	}
 
+	if blend.UsesConstantColor() {
+		c := blend.BlendConstantColor
+		g.rce.SetBlendColor(c[0], c[1], c[2], c[3])
+	}
+
 	for _, dstRegion := range dstRegions {
 		g.rce.SetScissorRect(mtl.ScissorRect{
 			X:      dstRegion.Region.Min.X,
@@ -655,6 +664,14 @@ func (g *Graphics) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.
 		n := t.Uint32Count()
 
 		switch t.Main {
+		case shaderir.Bool:
+			// bool is 1 byte in Metal. Put the value into the first byte.
+			// As the Metal platforms are little-endian, this byte is the lowest byte of the uint32 value.
+			var v1 uint32
+			if uniforms[idx] != 0 {
+				v1 = 1
+			}
+			uniformVars[i] = []uint32{v1}
 		case shaderir.Vec3, shaderir.IVec3:
 			// float3 requires 16-byte alignment (#2463).
 			v1 := make([]uint32, 4)
@@ -687,6 +704,15 @@ func (g *Graphics) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.
 					copy(v1[offset1+8:offset1+11], uniforms[idx+offset0+6:idx+offset0+9])
 				}
 				uniformVars[i] = v1
+			case shaderir.Bool:
+				// bool is 1 byte in Metal. Pack the values into bytes in the little-endian order.
+				v1 := make([]uint32, (t.Length+3)/4)
+				for j := 0; j < t.Length; j++ {
+					if uniforms[idx+j] != 0 {
+						v1[j/4] |= 1 << (8 * (j % 4))
+					}
+				}
+				uniformVars[i] = v1
 			default:
 				uniformVars[i] = uniforms[idx : idx+n]
 			}
@@ -87,8 +87,9 @@ func (s *Shader) init(device mtl.Device) error {
 }
 
 func (s *Shader) RenderPipelineState(view *view, blend graphicsdriver.Blend, stencilMode stencilMode, screen bool) (mtl.RenderPipelineState, error) {
+	// The constant color is not a part of a pipeline state. This is set to a render command encoder.
 	key := shaderRpsKey{
-		blend:       blend,
+		blend:       blend.WithoutConstantColor(),
 		stencilMode: stencilMode,
 		screen:      screen,
 	}
@@ -54,6 +54,10 @@ func convertBlendFactor(f graphicsdriver.BlendFactor) blendFactor {
 		return gl.ONE_MINUS_DST_ALPHA
 	case graphicsdriver.BlendFactorSourceAlphaSaturated:
 		return gl.SRC_ALPHA_SATURATE
+	case graphicsdriver.BlendFactorConstantColor:
+		return gl.CONSTANT_COLOR
+	case graphicsdriver.BlendFactorOneMinusConstantColor:
+		return gl.ONE_MINUS_CONSTANT_COLOR
 	default:
 		panic(fmt.Sprintf("opengl: invalid blend factor %d", f))
 	}
@@ -207,6 +211,14 @@ func (c *context) blend(blend graphicsdriver.Blend) {
 	if c.lastBlend == blend {
 		return
 	}
+	if c.lastBlend.BlendConstantColor != blend.BlendConstantColor {
+		clr := blend.BlendConstantColor
+		c.ctx.BlendColor(clr[0], clr[1], clr[2], clr[3])
+		if c.lastBlend.WithoutConstantColor() == blend.WithoutConstantColor() {
+			c.lastBlend = blend
+			return
+		}
+	}
 	c.lastBlend = blend
 	c.ctx.BlendFuncSeparate(
 		uint32(convertBlendFactor(blend.BlendFactorSourceRGB)),
@@ -451,7 +463,7 @@ func (c *context) uniforms(p program, location string, v []uint32, typ shaderir.
 	switch base {
 	case shaderir.Float:
 		c.ctx.Uniform1fv(int32(l), uint32sToFloat32s(v))
-	case shaderir.Int:
+	case shaderir.Bool, shaderir.Int:
 		c.ctx.Uniform1iv(int32(l), uint32sToInt32s(v))
 	case shaderir.Vec2:
 		c.ctx.Uniform2fv(int32(l), uint32sToFloat32s(v))
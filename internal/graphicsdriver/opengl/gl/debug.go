@@ -91,6 +91,14 @@ func (d *DebugContext) BindVertexArray(arg0 uint32) {
 	}
 }
 
+func (d *DebugContext) BlendColor(arg0 float32, arg1 float32, arg2 float32, arg3 float32) {
+	d.Context.BlendColor(arg0, arg1, arg2, arg3)
+	fmt.Fprintln(os.Stderr, "BlendColor")
+	if e := d.Context.GetError(); e != NO_ERROR {
+		panic(fmt.Sprintf("gl: GetError() returned %d at BlendColor", e))
+	}
+}
+
 func (d *DebugContext) BlendEquationSeparate(arg0 uint32, arg1 uint32) {
 	d.Context.BlendEquationSeparate(arg0, arg1)
 	fmt.Fprintln(os.Stderr, "BlendEquationSeparate")
@@ -140,6 +148,14 @@ func (d *DebugContext) Clear(arg0 uint32) {
 	}
 }
 
+func (d *DebugContext) ClearColor(arg0 float32, arg1 float32, arg2 float32, arg3 float32) {
+	d.Context.ClearColor(arg0, arg1, arg2, arg3)
+	fmt.Fprintln(os.Stderr, "ClearColor")
+	if e := d.Context.GetError(); e != NO_ERROR {
+		panic(fmt.Sprintf("gl: GetError() returned %d at ClearColor", e))
+	}
+}
+
 func (d *DebugContext) ColorMask(arg0 bool, arg1 bool, arg2 bool, arg3 bool) {
 	d.Context.ColorMask(arg0, arg1, arg2, arg3)
 	fmt.Fprintln(os.Stderr, "ColorMask")
@@ -52,6 +52,10 @@ package gl
 //   typedef void (*fn)(GLuint array);
 //   ((fn)(fnptr))(array);
 // }
+// static void glowBlendColor(uintptr_t fnptr, GLfloat red, GLfloat green, GLfloat blue, GLfloat alpha) {
+//   typedef void (*fn)(GLfloat red, GLfloat green, GLfloat blue, GLfloat alpha);
+//   ((fn)(fnptr))(red, green, blue, alpha);
+// }
 // static void glowBlendEquationSeparate(uintptr_t fnptr, GLenum modeRGB, GLenum modeAlpha) {
 //   typedef void (*fn)(GLenum modeRGB, GLenum modeAlpha);
 //   ((fn)(fnptr))(modeRGB, modeAlpha);
@@ -76,6 +80,10 @@ package gl
 //   typedef void (*fn)(GLbitfield mask);
 //   ((fn)(fnptr))(mask);
 // }
+// static void glowClearColor(uintptr_t fnptr, GLfloat red, GLfloat green, GLfloat blue, GLfloat alpha) {
+//   typedef void (*fn)(GLfloat red, GLfloat green, GLfloat blue, GLfloat alpha);
+//   ((fn)(fnptr))(red, green, blue, alpha);
+// }
 // static void glowColorMask(uintptr_t fnptr, GLboolean red, GLboolean green, GLboolean blue, GLboolean alpha) {
 //   typedef void (*fn)(GLboolean red, GLboolean green, GLboolean blue, GLboolean alpha);
 //   ((fn)(fnptr))(red, green, blue, alpha);
@@ -336,12 +344,14 @@ type defaultContext struct {
 	gpBindRenderbuffer         C.uintptr_t
 	gpBindTexture              C.uintptr_t
 	gpBindVertexArray          C.uintptr_t
+	gpBlendColor               C.uintptr_t
 	gpBlendEquationSeparate    C.uintptr_t
 	gpBlendFuncSeparate        C.uintptr_t
 	gpBufferData               C.uintptr_t
 	gpBufferSubData            C.uintptr_t
 	gpCheckFramebufferStatus   C.uintptr_t
 	gpClear                    C.uintptr_t
+	gpClearColor               C.uintptr_t
 	gpColorMask                C.uintptr_t
 	gpCompileShader            C.uintptr_t
 	gpCreateProgram            C.uintptr_t
@@ -460,6 +470,10 @@ func (c *defaultContext) BindVertexArray(array uint32) {
 	C.glowBindVertexArray(c.gpBindVertexArray, C.GLuint(array))
 }
 
+func (c *defaultContext) BlendColor(red float32, green float32, blue float32, alpha float32) {
+	C.glowBlendColor(c.gpBlendColor, C.GLfloat(red), C.GLfloat(green), C.GLfloat(blue), C.GLfloat(alpha))
+}
+
 func (c *defaultContext) BlendEquationSeparate(modeRGB uint32, modeAlpha uint32) {
 	C.glowBlendEquationSeparate(c.gpBlendEquationSeparate, C.GLenum(modeRGB), C.GLenum(modeAlpha))
 }
@@ -486,6 +500,10 @@ func (c *defaultContext) Clear(mask uint32) {
 	C.glowClear(c.gpClear, C.GLbitfield(mask))
 }
 
+func (c *defaultContext) ClearColor(red float32, green float32, blue float32, alpha float32) {
+	C.glowClearColor(c.gpClearColor, C.GLfloat(red), C.GLfloat(green), C.GLfloat(blue), C.GLfloat(alpha))
+}
+
 func (c *defaultContext) ColorMask(red bool, green bool, blue bool, alpha bool) {
 	C.glowColorMask(c.gpColorMask, C.GLboolean(boolToInt(red)), C.GLboolean(boolToInt(green)), C.GLboolean(boolToInt(blue)), C.GLboolean(boolToInt(alpha)))
 }
@@ -792,12 +810,14 @@ func (c *defaultContext) LoadFunctions() error {
 	c.gpBindRenderbuffer = C.uintptr_t(g.get("glBindRenderbuffer"))
 	c.gpBindTexture = C.uintptr_t(g.get("glBindTexture"))
 	c.gpBindVertexArray = C.uintptr_t(g.get("glBindVertexArray"))
+	c.gpBlendColor = C.uintptr_t(g.get("glBlendColor"))
 	c.gpBlendEquationSeparate = C.uintptr_t(g.get("glBlendEquationSeparate"))
 	c.gpBlendFuncSeparate = C.uintptr_t(g.get("glBlendFuncSeparate"))
 	c.gpBufferData = C.uintptr_t(g.get("glBufferData"))
 	c.gpBufferSubData = C.uintptr_t(g.get("glBufferSubData"))
 	c.gpCheckFramebufferStatus = C.uintptr_t(g.get("glCheckFramebufferStatus"))
 	c.gpClear = C.uintptr_t(g.get("glClear"))
+	c.gpClearColor = C.uintptr_t(g.get("glClearColor"))
 	c.gpColorMask = C.uintptr_t(g.get("glColorMask"))
 	c.gpCompileShader = C.uintptr_t(g.get("glCompileShader"))
 	c.gpCreateProgram = C.uintptr_t(g.get("glCreateProgram"))
@@ -30,12 +30,14 @@ type defaultContext struct {
 	fnBindRenderbuffer         js.Value
 	fnBindTexture              js.Value
 	fnBindVertexArray          js.Value
+	fnBlendColor               js.Value
 	fnBlendEquationSeparate    js.Value
 	fnBlendFuncSeparate        js.Value
 	fnBufferData               js.Value
 	fnBufferSubData            js.Value
 	fnCheckFramebufferStatus   js.Value
 	fnClear                    js.Value
+	fnClearColor               js.Value
 	fnColorMask                js.Value
 	fnCompileShader            js.Value
 	fnCreateBuffer             js.Value
@@ -161,12 +163,14 @@ func NewDefaultContext(v js.Value) (Context, error) {
 		fnBindRenderbuffer:         v.Get("bindRenderbuffer").Call("bind", v),
 		fnBindTexture:              v.Get("bindTexture").Call("bind", v),
 		fnBindVertexArray:          v.Get("bindVertexArray").Call("bind", v),
+		fnBlendColor:               v.Get("blendColor").Call("bind", v),
 		fnBlendEquationSeparate:    v.Get("blendEquationSeparate").Call("bind", v),
 		fnBlendFuncSeparate:        v.Get("blendFuncSeparate").Call("bind", v),
 		fnBufferData:               v.Get("bufferData").Call("bind", v),
 		fnBufferSubData:            v.Get("bufferSubData").Call("bind", v),
 		fnCheckFramebufferStatus:   v.Get("checkFramebufferStatus").Call("bind", v),
 		fnClear:                    v.Get("clear").Call("bind", v),
+		fnClearColor:               v.Get("clearColor").Call("bind", v),
 		fnColorMask:                v.Get("colorMask").Call("bind", v),
 		fnCompileShader:            v.Get("compileShader").Call("bind", v),
 		fnCreateBuffer:             v.Get("createBuffer").Call("bind", v),
@@ -280,6 +284,10 @@ func (c *defaultContext) BindVertexArray(array uint32) {
 	c.fnBindVertexArray.Invoke(c.vertexArrays.get(array))
 }
 
+func (c *defaultContext) BlendColor(red, green, blue, alpha float32) {
+	c.fnBlendColor.Invoke(red, green, blue, alpha)
+}
+
 func (c *defaultContext) BlendEquationSeparate(modeRGB uint32, modeAlpha uint32) {
 	c.fnBlendEquationSeparate.Invoke(modeRGB, modeAlpha)
 }
@@ -306,6 +314,10 @@ func (c *defaultContext) Clear(mask uint32) {
 	c.fnClear.Invoke(mask)
 }
 
+func (c *defaultContext) ClearColor(red, green, blue, alpha float32) {
+	c.fnClearColor.Invoke(red, green, blue, alpha)
+}
+
 func (c *defaultContext) ColorMask(red, green, blue, alpha bool) {
 	c.fnColorMask.Invoke(red, green, blue, alpha)
 }
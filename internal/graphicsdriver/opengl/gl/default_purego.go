@@ -17,6 +17,7 @@
 package gl
 
 import (
+	"math"
 	"runtime"
 	"unsafe"
 
@@ -32,12 +33,14 @@ type defaultContext struct {
 	gpBindRenderbuffer         uintptr
 	gpBindTexture              uintptr
 	gpBindVertexArray          uintptr
+	gpBlendColor               uintptr
 	gpBlendEquationSeparate    uintptr
 	gpBlendFuncSeparate        uintptr
 	gpBufferData               uintptr
 	gpBufferSubData            uintptr
 	gpCheckFramebufferStatus   uintptr
 	gpClear                    uintptr
+	gpClearColor               uintptr
 	gpColorMask                uintptr
 	gpCompileShader            uintptr
 	gpCreateProgram            uintptr
@@ -156,6 +159,11 @@ func (c *defaultContext) BindVertexArray(array uint32) {
 	purego.SyscallN(c.gpBindVertexArray, uintptr(array))
 }
 
+func (c *defaultContext) BlendColor(red float32, green float32, blue float32, alpha float32) {
+	// All the arguments are floats, so SyscallN can pass them via the float registers.
+	purego.SyscallN(c.gpBlendColor, uintptr(math.Float32bits(red)), uintptr(math.Float32bits(green)), uintptr(math.Float32bits(blue)), uintptr(math.Float32bits(alpha)))
+}
+
 func (c *defaultContext) BlendEquationSeparate(modeRGB uint32, modeAlpha uint32) {
 	purego.SyscallN(c.gpBlendEquationSeparate, uintptr(modeRGB), uintptr(modeAlpha))
 }
@@ -182,6 +190,11 @@ func (c *defaultContext) Clear(mask uint32) {
 	purego.SyscallN(c.gpClear, uintptr(mask))
 }
 
+func (c *defaultContext) ClearColor(red float32, green float32, blue float32, alpha float32) {
+	// All the arguments are floats, so SyscallN can pass them via the float registers.
+	purego.SyscallN(c.gpClearColor, uintptr(math.Float32bits(red)), uintptr(math.Float32bits(green)), uintptr(math.Float32bits(blue)), uintptr(math.Float32bits(alpha)))
+}
+
 func (c *defaultContext) ColorMask(red bool, green bool, blue bool, alpha bool) {
 	purego.SyscallN(c.gpColorMask, uintptr(boolToInt(red)), uintptr(boolToInt(green)), uintptr(boolToInt(blue)), uintptr(boolToInt(alpha)))
 }
@@ -488,12 +501,14 @@ func (c *defaultContext) LoadFunctions() error {
 	c.gpBindRenderbuffer = g.get("glBindRenderbuffer")
 	c.gpBindTexture = g.get("glBindTexture")
 	c.gpBindVertexArray = g.get("glBindVertexArray")
+	c.gpBlendColor = g.get("glBlendColor")
 	c.gpBlendEquationSeparate = g.get("glBlendEquationSeparate")
 	c.gpBlendFuncSeparate = g.get("glBlendFuncSeparate")
 	c.gpBufferData = g.get("glBufferData")
 	c.gpBufferSubData = g.get("glBufferSubData")
 	c.gpCheckFramebufferStatus = g.get("glCheckFramebufferStatus")
 	c.gpClear = g.get("glClear")
+	c.gpClearColor = g.get("glClearColor")
 	c.gpColorMask = g.get("glColorMask")
 	c.gpCompileShader = g.get("glCompileShader")
 	c.gpCreateProgram = g.get("glCreateProgram")
@@ -75,6 +75,10 @@ func (g *gomobileContext) BindVertexArray(array uint32) {
 	g.ctx.BindVertexArray(gl.VertexArray{Value: array})
 }
 
+func (g *gomobileContext) BlendColor(red, green, blue, alpha float32) {
+	g.ctx.BlendColor(red, green, blue, alpha)
+}
+
 func (g *gomobileContext) BlendEquationSeparate(modeRGB uint32, modeAlpha uint32) {
 	g.ctx.BlendEquationSeparate(gl.Enum(modeRGB), gl.Enum(modeAlpha))
 }
@@ -99,6 +103,10 @@ func (g *gomobileContext) Clear(mask uint32) {
 	g.ctx.Clear(gl.Enum(mask))
 }
 
+func (g *gomobileContext) ClearColor(red, green, blue, alpha float32) {
+	g.ctx.ClearColor(red, green, blue, alpha)
+}
+
 func (g *gomobileContext) ColorMask(red, green, blue, alpha bool) {
 	g.ctx.ColorMask(red, green, blue, alpha)
 }
@@ -33,12 +33,14 @@ type Context interface {
 	BindRenderbuffer(target uint32, renderbuffer uint32)
 	BindTexture(target uint32, texture uint32)
 	BindVertexArray(array uint32)
+	BlendColor(red, green, blue, alpha float32)
 	BlendEquationSeparate(modeRGB uint32, modeAlpha uint32)
 	BlendFuncSeparate(srcRGB uint32, dstRGB uint32, srcAlpha uint32, dstAlpha uint32)
 	BufferInit(target uint32, size int, usage uint32)
 	BufferSubData(target uint32, offset int, data []byte)
 	CheckFramebufferStatus(target uint32) uint32
 	Clear(mask uint32)
+	ClearColor(red, green, blue, alpha float32)
 	ColorMask(red, green, blue, alpha bool)
 	CompileShader(shader uint32)
 	CreateBuffer() uint32
@@ -16,6 +16,7 @@ package opengl
 
 import (
 	"fmt"
+	"image"
 	"runtime"
 	"unsafe"
 
@@ -289,6 +290,26 @@ func (g *Graphics) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.
 	return nil
 }
 
+// ClearRegion implements graphicsdriver.RegionClearer.
+func (g *Graphics) ClearRegion(dstID graphicsdriver.ImageID, region image.Rectangle, red, green, blue, alpha float32) error {
+	destination := g.images[dstID]
+
+	g.drawCalled = true
+
+	if err := destination.setViewport(); err != nil {
+		return err
+	}
+	g.context.ctx.Scissor(
+		int32(region.Min.X),
+		int32(region.Min.Y),
+		int32(region.Dx()),
+		int32(region.Dy()),
+	)
+	g.context.ctx.ClearColor(red, green, blue, alpha)
+	g.context.ctx.Clear(gl.COLOR_BUFFER_BIT)
+	return nil
+}
+
 func (g *Graphics) SetVsyncEnabled(enabled bool) {
 	// Do nothing
 }
@@ -0,0 +1,399 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package software
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/shaderir"
+)
+
+func (s *Shader) callBuiltin(f shaderir.BuiltinFunc, args []value) value {
+	switch f {
+	case shaderir.Len, shaderir.Cap:
+		return intValue(int32(len(args[0].arr)))
+	case shaderir.BoolF:
+		v := toFloat(args[0])
+		return boolValue(v.f[0] != 0)
+	case shaderir.IntF:
+		if args[0].isFloat() {
+			return intValue(floatToInt(args[0].f[0]))
+		}
+		return intValue(args[0].i[0])
+	case shaderir.FloatF:
+		return floatValue(toFloat(args[0]).f[0])
+	case shaderir.Vec2F, shaderir.Vec3F, shaderir.Vec4F:
+		return construct(vectorType(vectorSize(f), true), args)
+	case shaderir.IVec2F, shaderir.IVec3F, shaderir.IVec4F:
+		return construct(vectorType(vectorSize(f), false), args)
+	case shaderir.Mat2F:
+		return constructMatrix(shaderir.Mat2, args)
+	case shaderir.Mat3F:
+		return constructMatrix(shaderir.Mat3, args)
+	case shaderir.Mat4F:
+		return constructMatrix(shaderir.Mat4, args)
+	case shaderir.Radians:
+		return mapFloat(args, func(x, _, _ float32) float32 { return x * (math.Pi / 180) })
+	case shaderir.Degrees:
+		return mapFloat(args, func(x, _, _ float32) float32 { return x * (180 / math.Pi) })
+	case shaderir.Sin:
+		return mapFloat(args, func(x, _, _ float32) float32 { return sin32(x) })
+	case shaderir.Cos:
+		return mapFloat(args, func(x, _, _ float32) float32 { return cos32(x) })
+	case shaderir.Tan:
+		return mapFloat(args, func(x, _, _ float32) float32 { return tan32(x) })
+	case shaderir.Asin:
+		return mapFloat(args, func(x, _, _ float32) float32 { return asin32(x) })
+	case shaderir.Acos:
+		return mapFloat(args, func(x, _, _ float32) float32 { return acos32(x) })
+	case shaderir.Atan:
+		return mapFloat(args, func(x, _, _ float32) float32 { return atan32(x) })
+	case shaderir.Atan2:
+		return mapFloat(args, func(y, x, _ float32) float32 { return atan232(y, x) })
+	case shaderir.Pow:
+		return mapFloat(args, func(x, y, _ float32) float32 { return pow32(x, y) })
+	case shaderir.Exp:
+		return mapFloat(args, func(x, _, _ float32) float32 { return exp32(x) })
+	case shaderir.Log:
+		return mapFloat(args, func(x, _, _ float32) float32 { return log32(x) })
+	case shaderir.Exp2:
+		return mapFloat(args, func(x, _, _ float32) float32 { return exp232(x) })
+	case shaderir.Log2:
+		return mapFloat(args, func(x, _, _ float32) float32 { return log232(x) })
+	case shaderir.Sqrt:
+		return mapFloat(args, func(x, _, _ float32) float32 { return sqrt32(x) })
+	case shaderir.Inversesqrt:
+		return mapFloat(args, func(x, _, _ float32) float32 { return 1 / sqrt32(x) })
+	case shaderir.Abs:
+		return mapValue(args, func(x, _, _ float32) float32 {
+			if x < 0 {
+				return -x
+			}
+			return x
+		}, func(x, _, _ int32) int32 {
+			if x < 0 {
+				return -x
+			}
+			return x
+		})
+	case shaderir.Sign:
+		return mapValue(args, func(x, _, _ float32) float32 {
+			switch {
+			case x > 0:
+				return 1
+			case x < 0:
+				return -1
+			}
+			return 0
+		}, func(x, _, _ int32) int32 {
+			switch {
+			case x > 0:
+				return 1
+			case x < 0:
+				return -1
+			}
+			return 0
+		})
+	case shaderir.Floor:
+		return mapFloat(args, func(x, _, _ float32) float32 { return floor32(x) })
+	case shaderir.Ceil:
+		return mapFloat(args, func(x, _, _ float32) float32 { return -floor32(-x) })
+	case shaderir.Fract:
+		return mapFloat(args, func(x, _, _ float32) float32 { return x - floor32(x) })
+	case shaderir.Mod:
+		return mapFloat(args, func(x, y, _ float32) float32 { return mod32(x, y) })
+	case shaderir.Min:
+		return mapValue(args, min32, func(x, y, _ int32) int32 {
+			if y < x {
+				return y
+			}
+			return x
+		})
+	case shaderir.Max:
+		return mapValue(args, max32, func(x, y, _ int32) int32 {
+			if y > x {
+				return y
+			}
+			return x
+		})
+	case shaderir.Clamp:
+		return mapValue(args, func(x, minVal, maxVal float32) float32 {
+			return min32(max32(x, minVal, 0), maxVal, 0)
+		}, func(x, minVal, maxVal int32) int32 {
+			if x < minVal {
+				x = minVal
+			}
+			if x > maxVal {
+				x = maxVal
+			}
+			return x
+		})
+	case shaderir.Mix:
+		return mapFloat(args, func(x, y, a float32) float32 {
+			return float32(x*(1-a)) + float32(y*a)
+		})
+	case shaderir.Step:
+		return mapFloat(args, func(edge, x, _ float32) float32 {
+			if x < edge {
+				return 0
+			}
+			return 1
+		})
+	case shaderir.Smoothstep:
+		return mapFloat(args, func(edge0, edge1, x float32) float32 {
+			t := min32(max32((x-edge0)/(edge1-edge0), 0, 0), 1, 0)
+			return float32(t*t) * (3 - float32(2*t))
+		})
+	case shaderir.Length:
+		return floatValue(length(args[0]))
+	case shaderir.Distance:
+		return floatValue(length(binaryOp(shaderir.Sub, args[0], args[1])))
+	case shaderir.Dot:
+		return floatValue(dot(args[0], args[1]))
+	case shaderir.Cross:
+		x, y := args[0], args[1]
+		r := value{typ: shaderir.Vec3}
+		r.f[0] = float32(x.f[1]*y.f[2]) - float32(y.f[1]*x.f[2])
+		r.f[1] = float32(x.f[2]*y.f[0]) - float32(y.f[2]*x.f[0])
+		r.f[2] = float32(x.f[0]*y.f[1]) - float32(y.f[0]*x.f[1])
+		return r
+	case shaderir.Normalize:
+		return binaryOp(shaderir.Div, args[0], floatValue(length(args[0])))
+	case shaderir.Faceforward:
+		if dot(args[2], args[1]) < 0 {
+			return args[0]
+		}
+		return unaryOp(shaderir.Sub, args[0])
+	case shaderir.Reflect:
+		i, n := args[0], args[1]
+		d := float32(2 * dot(n, i))
+		return binaryOp(shaderir.Sub, i, binaryOp(shaderir.ComponentWiseMul, floatValue(d), n))
+	case shaderir.Refract:
+		i, n, eta := args[0], args[1], args[2].f[0]
+		d := dot(n, i)
+		k := 1 - float32(float32(eta*eta)*(1-float32(d*d)))
+		if k < 0 {
+			return value{typ: i.typ}
+		}
+		a := binaryOp(shaderir.ComponentWiseMul, floatValue(eta), i)
+		b := binaryOp(shaderir.ComponentWiseMul, floatValue(float32(eta*d)+sqrt32(k)), n)
+		return binaryOp(shaderir.Sub, a, b)
+	case shaderir.Transpose:
+		x := args[0]
+		d := matrixDim(x.typ)
+		r := value{typ: x.typ}
+		for c := 0; c < d; c++ {
+			for row := 0; row < d; row++ {
+				r.f[c*d+row] = x.f[row*d+c]
+			}
+		}
+		return r
+	case shaderir.Dfdx, shaderir.Dfdy, shaderir.Fwidth:
+		// Derivatives are not supported as fragments are not processed in 2x2 blocks.
+		return value{typ: args[0].typ}
+	case shaderir.TexelAt, shaderir.TexelAtLod:
+		// In pixels, a texel is fetched exactly without sampling, and the level of detail is not used.
+		return s.texelAt(int(args[0].i[0]), args[1].f[0], args[1].f[1])
+	default:
+		panic(fmt.Sprintf("software: unexpected builtin function: %s", f))
+	}
+}
+
+func (s *Shader) texelAt(index int, x, y float32) value {
+	r := value{typ: shaderir.Vec4}
+	img := s.textures[index]
+	if img == nil {
+		return r
+	}
+
+	var ix, iy int
+	switch s.ir.Unit {
+	case shaderir.Pixels:
+		// Fetching a texel out of the texture returns 0 on many GPUs.
+		ix, iy = int(floatToInt(x)), int(floatToInt(y))
+		if ix < 0 || iy < 0 || ix >= img.width || iy >= img.height {
+			return r
+		}
+	case shaderir.Texels:
+		// Sample the nearest texel with clamping to the edge.
+		ix = clampIndex(int(floatToInt(floor32(x*float32(img.width)))), img.width)
+		iy = clampIndex(int(floatToInt(floor32(y*float32(img.height)))), img.height)
+	}
+
+	idx := 4 * (iy*img.width + ix)
+	for i := 0; i < 4; i++ {
+		r.f[i] = float32(img.pixels[idx+i]) / 0xff
+	}
+	return r
+}
+
+func vectorSize(f shaderir.BuiltinFunc) int {
+	switch f {
+	case shaderir.Vec2F, shaderir.IVec2F:
+		return 2
+	case shaderir.Vec3F, shaderir.IVec3F:
+		return 3
+	case shaderir.Vec4F, shaderir.IVec4F:
+		return 4
+	default:
+		panic(fmt.Sprintf("software: unexpected builtin function: %s", f))
+	}
+}
+
+// construct constructs a vector from the arguments.
+func construct(t shaderir.BasicType, args []value) value {
+	r := value{typ: t}
+	n := componentCount(t)
+	float := isFloatType(t)
+
+	if len(args) == 1 && isScalarType(args[0].typ) {
+		for i := 0; i < n; i++ {
+			setComponent(&r, i, &args[0], 0, float)
+		}
+		return r
+	}
+
+	var idx int
+	for _, a := range args {
+		for i := 0; i < componentCount(a.typ) && idx < n; i++ {
+			setComponent(&r, idx, &a, i, float)
+			idx++
+		}
+	}
+	return r
+}
+
+func setComponent(dst *value, dstIdx int, src *value, srcIdx int, float bool) {
+	switch {
+	case float && src.isFloat():
+		dst.f[dstIdx] = src.f[srcIdx]
+	case float:
+		dst.f[dstIdx] = float32(src.i[srcIdx])
+	case src.isFloat():
+		dst.i[dstIdx] = floatToInt(src.f[srcIdx])
+	default:
+		dst.i[dstIdx] = src.i[srcIdx]
+	}
+}
+
+// constructMatrix constructs a matrix from the arguments.
+func constructMatrix(t shaderir.BasicType, args []value) value {
+	r := value{typ: t}
+	d := matrixDim(t)
+
+	switch {
+	case len(args) == 1 && isScalarType(args[0].typ):
+		// A diagonal matrix.
+		x := toFloat(args[0]).f[0]
+		for i := 0; i < d; i++ {
+			r.f[i*d+i] = x
+		}
+		return r
+	case len(args) == 1 && isMatrixType(args[0].typ):
+		// Take the upper-left part, and fill the rest with the identity matrix.
+		src := args[0]
+		sd := matrixDim(src.typ)
+		for c := 0; c < d; c++ {
+			for row := 0; row < d; row++ {
+				switch {
+				case c < sd && row < sd:
+					r.f[c*d+row] = src.f[c*sd+row]
+				case c == row:
+					r.f[c*d+row] = 1
+				}
+			}
+		}
+		return r
+	}
+
+	var idx int
+	for _, a := range args {
+		a := toFloat(a)
+		for i := 0; i < componentCount(a.typ) && idx < d*d; i++ {
+			r.f[idx] = a.f[i]
+			idx++
+		}
+	}
+	return r
+}
+
+// resultType returns the type of a component-wise function's result.
+// A scalar argument is applied to all the components of the other vector arguments.
+func resultType(args []value) (shaderir.BasicType, bool) {
+	float := false
+	for _, a := range args {
+		if a.isFloat() {
+			float = true
+			break
+		}
+	}
+	for _, a := range args {
+		if !isScalarType(a.typ) {
+			return vectorType(componentCount(a.typ), float), float
+		}
+	}
+	return vectorType(1, float), float
+}
+
+// mapFloat applies f to each component of the arguments as floats.
+func mapFloat(args []value, f func(x, y, z float32) float32) value {
+	return mapValue(args, f, nil)
+}
+
+// mapValue applies ff or fi to each component of the arguments.
+func mapValue(args []value, ff func(x, y, z float32) float32, fi func(x, y, z int32) int32) value {
+	t, float := resultType(args)
+	if !float && fi == nil {
+		t, float = vectorType(componentCount(t), true), true
+	}
+	if float {
+		for i := range args {
+			args[i] = toFloat(args[i])
+		}
+	}
+
+	r := value{typ: t}
+	for i := 0; i < componentCount(t); i++ {
+		var xs [3]float32
+		var is [3]int32
+		for j := 0; j < len(args) && j < 3; j++ {
+			k := i
+			if isScalarType(args[j].typ) {
+				k = 0
+			}
+			xs[j] = args[j].f[k]
+			is[j] = args[j].i[k]
+		}
+		if float {
+			r.f[i] = ff(xs[0], xs[1], xs[2])
+		} else {
+			r.i[i] = fi(is[0], is[1], is[2])
+		}
+	}
+	return r
+}
+
+func dot(x, y value) float32 {
+	var sum float32
+	for i := 0; i < componentCount(x.typ); i++ {
+		sum += float32(x.f[i] * y.f[i])
+	}
+	return sum
+}
+
+func length(x value) float32 {
+	return sqrt32(dot(x, x))
+}
@@ -0,0 +1,30 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package software
+
+var (
+	Sin32ForTesting   = sin32
+	Cos32ForTesting   = cos32
+	Tan32ForTesting   = tan32
+	Asin32ForTesting  = asin32
+	Acos32ForTesting  = acos32
+	Atan32ForTesting  = atan32
+	Atan232ForTesting = atan232
+	Pow32ForTesting   = pow32
+	Exp32ForTesting   = exp32
+	Log32ForTesting   = log32
+	Exp232ForTesting  = exp232
+	Log232ForTesting  = log232
+)
@@ -0,0 +1,415 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package software offers a graphics driver that renders with CPU without GPU.
+//
+// The driver interprets shader programs and rasterizes triangles in pure Go.
+// This is very slow compared with GPU, and is intended for environments without GPU like automated tests.
+//
+// The rendering result is deterministic: the same commands produce byte-identical pixels on any platforms.
+// The positions are snapped to the fixed-point format and the rasterization is done with integers.
+// The floating-point operations are done without fused multiply-adds, and the math functions are implemented
+// without the math package's functions that might depend on the architecture.
+//
+// There are some differences from the graphics drivers with GPU:
+//
+//   - dfdx, dfdy, and fwidth always return 0.
+//   - The varying variables are interpolated linearly in the screen space without the perspective correction.
+//   - The level of detail for a texel fetch (e.g. imageSrc0AtLod) is ignored, and the base level is always used.
+//   - The results of operations undefined in GLSL (e.g. pow with a negative base) might differ.
+//   - The maximum image size is 8192.
+package software
+
+import (
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+	"github.com/hajimehoshi/ebiten/v2/internal/shaderir"
+)
+
+const maxImageSize = 8192
+
+type Graphics struct {
+	images       map[graphicsdriver.ImageID]*Image
+	nextImageID  graphicsdriver.ImageID
+	shaders      map[graphicsdriver.ShaderID]*Shader
+	nextShaderID graphicsdriver.ShaderID
+
+	vertices []float32
+	indices  []uint32
+
+	// vertexOutputs is the cache of the vertex shader's results for the current draw call.
+	vertexOutputs []vertexOutput
+
+	// vertexOutputsCount is the ID of the current draw call to validate vertexOutputs.
+	vertexOutputsCount uint64
+
+	varyings []value
+}
+
+type vertexOutput struct {
+	// x and y are the position on the destination in the fixed-point format.
+	x int64
+	y int64
+
+	// z is the depth in [0, 1].
+	z float32
+
+	varyings []value
+
+	count uint64
+}
+
+func NewGraphics() *Graphics {
+	return &Graphics{}
+}
+
+func (g *Graphics) Initialize() error {
+	return nil
+}
+
+func (g *Graphics) Begin() error {
+	return nil
+}
+
+func (g *Graphics) End(present bool) error {
+	return nil
+}
+
+func (g *Graphics) SetTransparent(transparent bool) {
+}
+
+func (g *Graphics) SetVertices(vertices []float32, indices []uint32) error {
+	// The given slices might be reused by the caller. Copy them.
+	g.vertices = append(g.vertices[:0], vertices...)
+	g.indices = append(g.indices[:0], indices...)
+	return nil
+}
+
+func (g *Graphics) NewImage(width, height int) (graphicsdriver.Image, error) {
+	// Allocate the same size as a texture on GPU, as the callers assume the internal size for the projection.
+	return g.newImage(graphics.InternalImageSize(width), graphics.InternalImageSize(height), false)
+}
+
+func (g *Graphics) NewScreenFramebufferImage(width, height int) (graphicsdriver.Image, error) {
+	return g.newImage(width, height, true)
+}
+
+func (g *Graphics) newImage(width, height int, screen bool) (*Image, error) {
+	if width <= 0 || height <= 0 || width > maxImageSize || height > maxImageSize {
+		return nil, fmt.Errorf("software: invalid image size: (%d, %d)", width, height)
+	}
+	g.nextImageID++
+	i := &Image{
+		id:       g.nextImageID,
+		graphics: g,
+		width:    width,
+		height:   height,
+		screen:   screen,
+		pixels:   make([]byte, 4*width*height),
+	}
+	if g.images == nil {
+		g.images = map[graphicsdriver.ImageID]*Image{}
+	}
+	g.images[i.id] = i
+	return i, nil
+}
+
+func (g *Graphics) removeImage(img *Image) {
+	delete(g.images, img.id)
+}
+
+func (g *Graphics) SetVsyncEnabled(enabled bool) {
+}
+
+func (g *Graphics) NeedsRestoring() bool {
+	return false
+}
+
+func (g *Graphics) NeedsClearingScreen() bool {
+	return true
+}
+
+func (g *Graphics) MaxImageSize() int {
+	return maxImageSize
+}
+
+func (g *Graphics) NewShader(program *shaderir.Program) (graphicsdriver.Shader, error) {
+	g.nextShaderID++
+	s, err := newShader(g.nextShaderID, g, program)
+	if err != nil {
+		return nil, err
+	}
+	if g.shaders == nil {
+		g.shaders = map[graphicsdriver.ShaderID]*Shader{}
+	}
+	g.shaders[s.id] = s
+	return s, nil
+}
+
+func (g *Graphics) removeShader(shader *Shader) {
+	delete(g.shaders, shader.id)
+}
+
+// ClearRegion implements graphicsdriver.RegionClearer.
+func (g *Graphics) ClearRegion(dstID graphicsdriver.ImageID, region image.Rectangle, red, green, blue, alpha float32) error {
+	dst := g.images[dstID]
+	region = region.Intersect(dst.bounds())
+	c := [4]byte{toByte(red), toByte(green), toByte(blue), toByte(alpha)}
+	for j := region.Min.Y; j < region.Max.Y; j++ {
+		for i := region.Min.X; i < region.Max.X; i++ {
+			idx := 4 * (j*dst.width + i)
+			copy(dst.pixels[idx:idx+4], c[:])
+		}
+	}
+	return nil
+}
+
+func (g *Graphics) DrawTriangles(dstID graphicsdriver.ImageID, srcIDs [graphics.ShaderImageCount]graphicsdriver.ImageID, shaderID graphicsdriver.ShaderID, dstRegions []graphicsdriver.DstRegion, indexOffset int, blend graphicsdriver.Blend, uniforms []uint32, fillRule graphicsdriver.FillRule) error {
+	if shaderID == graphicsdriver.InvalidShaderID {
+		return fmt.Errorf("software: shader ID is invalid")
+	}
+
+	dst := g.images[dstID]
+	shader := g.shaders[shaderID]
+
+	shader.setUniforms(uniforms)
+	for i, srcID := range srcIDs {
+		shader.textures[i] = nil
+		if srcID == graphicsdriver.InvalidImageID {
+			continue
+		}
+		shader.textures[i] = g.images[srcID]
+	}
+	defer func() {
+		for i := range shader.textures {
+			shader.textures[i] = nil
+		}
+	}()
+
+	g.vertexOutputsCount++
+	if n := len(g.vertices) / graphics.VertexFloatCount; len(g.vertexOutputs) < n {
+		g.vertexOutputs = append(g.vertexOutputs, make([]vertexOutput, n-len(g.vertexOutputs))...)
+	}
+
+	if fillRule != graphicsdriver.FillAll {
+		dst.ensureStencilBuffer()
+	}
+
+	for _, dstRegion := range dstRegions {
+		clip := dstRegion.Region.Intersect(dst.bounds())
+		indices := g.indices[indexOffset : indexOffset+dstRegion.IndexCount]
+		indexOffset += dstRegion.IndexCount
+		if clip.Empty() {
+			continue
+		}
+
+		// Emulate the stencil buffer in the same way as the other graphics drivers.
+		if fillRule != graphicsdriver.FillAll {
+			for j := clip.Min.Y; j < clip.Max.Y; j++ {
+				for i := clip.Min.X; i < clip.Max.X; i++ {
+					dst.stencil[j*dst.width+i] = 0
+				}
+			}
+			for i := 0; i+2 < len(indices); i += 3 {
+				v0 := g.vertexOutput(shader, dst, indices[i])
+				v1 := g.vertexOutput(shader, dst, indices[i+1])
+				v2 := g.vertexOutput(shader, dst, indices[i+2])
+				rasterizeTriangle(clip, v0, v1, v2, func(x, y int, w0, w1, w2 float64, front bool) {
+					idx := y*dst.width + x
+					switch fillRule {
+					case graphicsdriver.NonZero:
+						if front {
+							dst.stencil[idx]++
+						} else {
+							dst.stencil[idx]--
+						}
+					case graphicsdriver.EvenOdd:
+						dst.stencil[idx] = ^dst.stencil[idx]
+					}
+				})
+			}
+		}
+
+		for i := 0; i+2 < len(indices); i += 3 {
+			v0 := g.vertexOutput(shader, dst, indices[i])
+			v1 := g.vertexOutput(shader, dst, indices[i+1])
+			v2 := g.vertexOutput(shader, dst, indices[i+2])
+			rasterizeTriangle(clip, v0, v1, v2, func(x, y int, w0, w1, w2 float64, front bool) {
+				if fillRule != graphicsdriver.FillAll && dst.stencil[y*dst.width+x] == 0 {
+					return
+				}
+				g.drawPixel(dst, shader, x, y, v0, v1, v2, w0, w1, w2, blend)
+			})
+		}
+	}
+
+	return nil
+}
+
+// vertexOutput returns the result of the vertex shader for the vertex at the index.
+func (g *Graphics) vertexOutput(shader *Shader, dst *Image, index uint32) *vertexOutput {
+	o := &g.vertexOutputs[index]
+	if o.count == g.vertexOutputsCount {
+		return o
+	}
+
+	n := graphics.VertexFloatCount
+	pos, varyings := shader.runVertex(g.vertices[int(index)*n : int(index+1)*n])
+
+	// Convert the normalized device coordinates to the pixel coordinates.
+	// In the destination, the Y direction of the normalized device coordinates is downward unlike OpenGL.
+	// This is the same as OpenGL's offscreen framebuffers, whose Y direction is inverted when presented.
+	x := float64(pos[0]) / float64(pos[3])
+	y := float64(pos[1]) / float64(pos[3])
+	z := float64(pos[2]) / float64(pos[3])
+	o.x = toFixed((x + 1) / 2 * float64(dst.width))
+	o.y = toFixed((y + 1) / 2 * float64(dst.height))
+	o.z = float32((z + 1) / 2)
+
+	if len(o.varyings) != len(varyings) {
+		o.varyings = make([]value, len(varyings))
+	}
+	copy(o.varyings, varyings)
+	o.count = g.vertexOutputsCount
+	return o
+}
+
+func (g *Graphics) drawPixel(dst *Image, shader *Shader, x, y int, v0, v1, v2 *vertexOutput, w0, w1, w2 float64, blend graphicsdriver.Blend) {
+	if len(g.varyings) != len(v0.varyings) {
+		g.varyings = make([]value, len(v0.varyings))
+	}
+	for i := range g.varyings {
+		g.varyings[i] = interpolate(&v0.varyings[i], &v1.varyings[i], &v2.varyings[i], w0, w1, w2)
+	}
+	z := interpolateFloat(v0.z, v1.z, v2.z, w0, w1, w2)
+
+	c, ok := shader.runFragment([4]float32{float32(x) + 0.5, float32(y) + 0.5, z, 1}, g.varyings)
+	if !ok {
+		return
+	}
+
+	idx := 4 * (y*dst.width + x)
+	blendPixel(dst.pixels[idx:idx+4], c, blend)
+}
+
+func interpolate(v0, v1, v2 *value, w0, w1, w2 float64) value {
+	if !v0.isFloat() {
+		// A non-float varying variable is not interpolated, and the value of the first vertex is used.
+		return *v0
+	}
+	r := value{typ: v0.typ}
+	for i := 0; i < componentCount(v0.typ); i++ {
+		r.f[i] = interpolateFloat(v0.f[i], v1.f[i], v2.f[i], w0, w1, w2)
+	}
+	return r
+}
+
+func interpolateFloat(x0, x1, x2 float32, w0, w1, w2 float64) float32 {
+	return float32(float64(float64(x0)*w0) + float64(float64(x1)*w1) + float64(float64(x2)*w2))
+}
+
+func blendPixel(dst []byte, src [4]float32, blend graphicsdriver.Blend) {
+	// The color of a fragment is clamped before blending as the destination is a normalized integer format.
+	for i := range src {
+		src[i] = clamp01(src[i])
+	}
+	var d [4]float32
+	for i := range d {
+		d[i] = float32(dst[i]) / 0xff
+	}
+
+	for i := 0; i < 4; i++ {
+		sf, df := blend.BlendFactorSourceRGB, blend.BlendFactorDestinationRGB
+		op := blend.BlendOperationRGB
+		if i == 3 {
+			sf, df = blend.BlendFactorSourceAlpha, blend.BlendFactorDestinationAlpha
+			op = blend.BlendOperationAlpha
+		}
+		s := src[i]
+		x := d[i]
+		var r float32
+		switch op {
+		case graphicsdriver.BlendOperationAdd:
+			r = float32(s*blendFactor(sf, i, src, d, blend.BlendConstantColor)) + float32(x*blendFactor(df, i, src, d, blend.BlendConstantColor))
+		case graphicsdriver.BlendOperationSubtract:
+			r = float32(s*blendFactor(sf, i, src, d, blend.BlendConstantColor)) - float32(x*blendFactor(df, i, src, d, blend.BlendConstantColor))
+		case graphicsdriver.BlendOperationReverseSubtract:
+			r = float32(x*blendFactor(df, i, src, d, blend.BlendConstantColor)) - float32(s*blendFactor(sf, i, src, d, blend.BlendConstantColor))
+		case graphicsdriver.BlendOperationMin:
+			// The factors are not used for min and max.
+			r = min32(s, x, 0)
+		case graphicsdriver.BlendOperationMax:
+			r = max32(s, x, 0)
+		default:
+			panic(fmt.Sprintf("software: unexpected blend operation: %d", op))
+		}
+		dst[i] = toByte(r)
+	}
+}
+
+func blendFactor(f graphicsdriver.BlendFactor, c int, src, dst, constant [4]float32) float32 {
+	switch f {
+	case graphicsdriver.BlendFactorZero:
+		return 0
+	case graphicsdriver.BlendFactorOne:
+		return 1
+	case graphicsdriver.BlendFactorSourceColor:
+		return src[c]
+	case graphicsdriver.BlendFactorOneMinusSourceColor:
+		return 1 - src[c]
+	case graphicsdriver.BlendFactorSourceAlpha:
+		return src[3]
+	case graphicsdriver.BlendFactorOneMinusSourceAlpha:
+		return 1 - src[3]
+	case graphicsdriver.BlendFactorDestinationColor:
+		return dst[c]
+	case graphicsdriver.BlendFactorOneMinusDestinationColor:
+		return 1 - dst[c]
+	case graphicsdriver.BlendFactorDestinationAlpha:
+		return dst[3]
+	case graphicsdriver.BlendFactorOneMinusDestinationAlpha:
+		return 1 - dst[3]
+	case graphicsdriver.BlendFactorSourceAlphaSaturated:
+		if c == 3 {
+			return 1
+		}
+		return min32(src[3], 1-dst[3], 0)
+	case graphicsdriver.BlendFactorConstantColor:
+		return constant[c]
+	case graphicsdriver.BlendFactorOneMinusConstantColor:
+		return 1 - constant[c]
+	default:
+		panic(fmt.Sprintf("software: unexpected blend factor: %d", f))
+	}
+}
+
+func clamp01(x float32) float32 {
+	// NaN is treated as 0.
+	if !(x > 0) {
+		return 0
+	}
+	if x > 1 {
+		return 1
+	}
+	return x
+}
+
+// toByte converts a value in a normalized format to a byte with rounding.
+func toByte(x float32) byte {
+	return byte(math.Floor(float64(clamp01(x)*0xff) + 0.5))
+}
@@ -0,0 +1,161 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package software_test
+
+import (
+	"hash/crc32"
+	"image"
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver/software"
+)
+
+// uniforms returns the uniform values including the preserved ones in the same way as the graphicscommand package does.
+func uniforms(dstWidth, dstHeight, srcWidth, srcHeight int, extra ...float32) []uint32 {
+	fs := make([]float32, graphics.PreservedUniformUint32Count)
+	fs[0], fs[1] = float32(dstWidth), float32(dstHeight)
+	fs[2], fs[3] = float32(srcWidth), float32(srcHeight)
+	fs[12], fs[13] = float32(dstWidth), float32(dstHeight)
+	fs[22], fs[23] = float32(srcWidth), float32(srcHeight)
+	// The projection matrix.
+	fs[30] = 2 / float32(dstWidth)
+	fs[35] = 2 / float32(dstHeight)
+	fs[40] = 1
+	fs[42] = -1
+	fs[43] = -1
+	fs[45] = 1
+	fs = append(fs, extra...)
+
+	us := make([]uint32, len(fs))
+	for i, f := range fs {
+		us[i] = math.Float32bits(f)
+	}
+	return us
+}
+
+func vertex(dstX, dstY, srcX, srcY float32) []float32 {
+	return []float32{dstX, dstY, srcX, srcY, 1, 1, 1, 1}
+}
+
+// TestDrawTrianglesDeterminism tests that the rendering result is exactly the same as the expected result on any platforms.
+func TestDrawTrianglesDeterminism(t *testing.T) {
+	const (
+		w    = 64
+		h    = 64
+		srcW = 4
+		srcH = 4
+	)
+
+	g := software.NewGraphics()
+
+	dst, err := g.NewImage(w, h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dst.Dispose()
+
+	src, err := g.NewImage(srcW, srcH)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Dispose()
+
+	srcPix := make([]byte, 4*srcW*srcH)
+	for i := 0; i < srcW*srcH; i++ {
+		srcPix[4*i] = byte(0x10 * i)
+		srcPix[4*i+1] = byte(0xff - 0x10*i)
+		srcPix[4*i+2] = 0x80
+		srcPix[4*i+3] = 0xff
+	}
+	if err := src.WritePixels([]graphicsdriver.PixelsArgs{
+		{
+			Pixels: srcPix,
+			Region: image.Rect(0, 0, srcW, srcH),
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	ir, err := graphics.CompileShader([]byte(`//kage:unit pixels
+
+package main
+
+var Time float
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	c := imageSrc0At(srcPos)
+	s := sin(dstPos.x*0.3 + Time)*0.5 + 0.5
+	p := pow(srcPos.y/4, 2.2)
+	a := atan2(dstPos.y-32, dstPos.x-32)/6.2831853 + 0.5
+	e := exp(-length(dstPos.xy-vec2(32))/16)
+	return vec4(mix(c.r, s, 0.5), p*c.g, fract(a+log2(1+e)), 1) * e
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	shader, err := g.NewShader(ir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shader.Dispose()
+
+	// A rotated quadrangle whose edges are not aligned with the pixels.
+	var vs []float32
+	vs = append(vs, vertex(32, 3.3, 0, 0)...)
+	vs = append(vs, vertex(60.7, 32, srcW, 0)...)
+	vs = append(vs, vertex(3.1, 32.4, 0, srcH)...)
+	vs = append(vs, vertex(32.2, 61.9, srcW, srcH)...)
+	is := []uint32{0, 1, 2, 1, 2, 3}
+	if err := g.SetVertices(vs, is); err != nil {
+		t.Fatal(err)
+	}
+
+	srcs := [graphics.ShaderImageCount]graphicsdriver.ImageID{src.ID()}
+	dstRegions := []graphicsdriver.DstRegion{
+		{
+			Region:     image.Rect(0, 0, w, h),
+			IndexCount: len(is),
+		},
+	}
+	if err := g.DrawTriangles(dst.ID(), srcs, shader.ID(), dstRegions, 0, graphicsdriver.BlendSourceOver, uniforms(w, h, srcW, srcH, 0.25), graphicsdriver.FillAll); err != nil {
+		t.Fatal(err)
+	}
+
+	pix := make([]byte, 4*w*h)
+	if err := dst.ReadPixels([]graphicsdriver.PixelsArgs{
+		{
+			Pixels: pix,
+			Region: image.Rect(0, 0, w, h),
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check some pixels to confirm the rendering is reasonable.
+	if got := pix[4*(0*w+0)+3]; got != 0 {
+		t.Errorf("alpha at (0, 0): got: %d, want: 0", got)
+	}
+	if got := pix[4*(32*w+32)+3]; got == 0 {
+		t.Errorf("alpha at (32, 32): got: %d, want: non-zero", got)
+	}
+
+	if got, want := crc32.ChecksumIEEE(pix), uint32(0x0947556e); got != want {
+		t.Errorf("checksum: got: 0x%08x, want: 0x%08x", got, want)
+	}
+}
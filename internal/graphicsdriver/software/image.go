@@ -0,0 +1,100 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package software
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+)
+
+// Image is an image whose pixels are on the main memory.
+//
+// The pixels are premultiplied-alpha RGBA, 8 bits per channel, like a texture on GPU.
+type Image struct {
+	id       graphicsdriver.ImageID
+	graphics *Graphics
+	width    int
+	height   int
+	screen   bool
+	pixels   []byte
+
+	// stencil is a stencil buffer used for the fill rules.
+	stencil []byte
+}
+
+func (i *Image) ID() graphicsdriver.ImageID {
+	return i.id
+}
+
+func (i *Image) Dispose() {
+	i.pixels = nil
+	i.stencil = nil
+	i.graphics.removeImage(i)
+}
+
+func (*Image) IsInvalidated() bool {
+	return false
+}
+
+func (i *Image) ReadPixels(args []graphicsdriver.PixelsArgs) error {
+	for _, a := range args {
+		if err := i.checkRegion(a.Region, len(a.Pixels)); err != nil {
+			return err
+		}
+		w := a.Region.Dx()
+		for j := 0; j < a.Region.Dy(); j++ {
+			idx := 4 * ((a.Region.Min.Y+j)*i.width + a.Region.Min.X)
+			copy(a.Pixels[4*j*w:4*(j+1)*w], i.pixels[idx:idx+4*w])
+		}
+	}
+	return nil
+}
+
+func (i *Image) WritePixels(args []graphicsdriver.PixelsArgs) error {
+	for _, a := range args {
+		if err := i.checkRegion(a.Region, len(a.Pixels)); err != nil {
+			return err
+		}
+		w := a.Region.Dx()
+		for j := 0; j < a.Region.Dy(); j++ {
+			idx := 4 * ((a.Region.Min.Y+j)*i.width + a.Region.Min.X)
+			copy(i.pixels[idx:idx+4*w], a.Pixels[4*j*w:4*(j+1)*w])
+		}
+	}
+	return nil
+}
+
+func (i *Image) checkRegion(region image.Rectangle, pixelsLen int) error {
+	if !region.In(i.bounds()) {
+		return fmt.Errorf("software: region %v is out of the image bounds %v", region, i.bounds())
+	}
+	if got, want := pixelsLen, 4*region.Dx()*region.Dy(); got != want {
+		return fmt.Errorf("software: len(pixels) must be %d but %d", want, got)
+	}
+	return nil
+}
+
+func (i *Image) bounds() image.Rectangle {
+	return image.Rect(0, 0, i.width, i.height)
+}
+
+func (i *Image) ensureStencilBuffer() {
+	if i.stencil != nil {
+		return
+	}
+	i.stencil = make([]byte, i.width*i.height)
+}
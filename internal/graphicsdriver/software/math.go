@@ -0,0 +1,350 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package software
+
+import (
+	"math"
+)
+
+// The math functions in this file are implemented only with the basic arithmetic operations, whose results are
+// defined by IEEE 754, so that the results are the same on all the platforms.
+// The transcendental functions in the math package might be implemented in assembly for some architectures,
+// and their results might differ in the last bits.
+//
+// Every product is explicitly converted before it is added. Otherwise, the compiler might fuse the multiplication
+// and the addition into one FMA instruction on some architectures and the result might change.
+//
+// math.Floor, math.Sqrt, math.Frexp, and math.Ldexp are used as they are exact on all the platforms.
+
+const (
+	ln2       = 6.93147180559945286227e-01
+	log2E     = 1.44269504088896338700e+00
+	piOver2   = 1.57079632679489655800e+00
+	piOver4   = 7.85398163397448278999e-01
+	twoOverPi = 6.36619772367581382433e-01
+
+	// piOver2Hi, piOver2Mid, and piOver2Lo are π/2 split into three parts.
+	// piOver2Hi and piOver2Mid have only 33 significant bits, and multiplying them by a small integer is exact.
+	piOver2Hi  = 1.57079632673412561417e+00
+	piOver2Mid = 6.07710050630396597660e-11
+	piOver2Lo  = 2.02226624871116645580e-21
+
+	// tan3PiOver8 is tan(3π/8).
+	tan3PiOver8 = 2.41421356237309504880e+00
+
+	// piOver2Extra is the rest of π/2 that is not represented by piOver2.
+	piOver2Extra = 6.12323399573676588613e-17
+
+	sqrtHalf = 7.07106781186547524401e-01
+)
+
+// sinCoeffs and cosCoeffs are the coefficients of the polynomials for sin and cos in [-π/4, π/4] from FDLIBM.
+var (
+	sinCoeffs = [...]float64{
+		-1.66666666666666324348e-01,
+		8.33333333332248946124e-03,
+		-1.98412698298579493134e-04,
+		2.75573137070700676789e-06,
+		-2.50507602534068634195e-08,
+		1.58969099521155010221e-10,
+	}
+	cosCoeffs = [...]float64{
+		4.16666666666666019037e-02,
+		-1.38888888888741095749e-03,
+		2.48015872894767294178e-05,
+		-2.75573143513906633035e-07,
+		2.08757232129817482790e-09,
+		-1.13596475577881948265e-11,
+	}
+)
+
+// atanP and atanQ are the coefficients of the rational function for atan from Cephes.
+var (
+	atanP = [...]float64{
+		-6.485021904942025371773e+01,
+		-1.228866684490136173410e+02,
+		-7.500855792314704667340e+01,
+		-1.615753718733365076637e+01,
+		-8.750608600031904122785e-01,
+	}
+	atanQ = [...]float64{
+		1.945506571482613964425e+02,
+		4.853903996359136964868e+02,
+		4.328810604912902668951e+02,
+		1.650270098316988542046e+02,
+		2.485846490142306297962e+01,
+		1,
+	}
+)
+
+// exp2Coeffs is the coefficients of the Taylor series of e^x, which is used for 2^x in [-0.5, 0.5].
+var exp2Coeffs = [...]float64{
+	1,
+	1,
+	1.0 / 2,
+	1.0 / 6,
+	1.0 / 24,
+	1.0 / 120,
+	1.0 / 720,
+	1.0 / 5040,
+	1.0 / 40320,
+	1.0 / 362880,
+	1.0 / 3628800,
+	1.0 / 39916800,
+}
+
+// logCoeffs is the coefficients of the series of log((1+s)/(1-s)) / 2s in terms of s^2.
+var logCoeffs = [...]float64{
+	1,
+	1.0 / 3,
+	1.0 / 5,
+	1.0 / 7,
+	1.0 / 9,
+	1.0 / 11,
+	1.0 / 13,
+	1.0 / 15,
+	1.0 / 17,
+	1.0 / 19,
+}
+
+// poly evaluates the polynomial whose coefficients are cs in the ascending order with Horner's method.
+func poly(x float64, cs []float64) float64 {
+	var r float64
+	for i := len(cs) - 1; i >= 0; i-- {
+		r = float64(r*x) + cs[i]
+	}
+	return r
+}
+
+func isNaNOrInf(x float64) bool {
+	return x != x || x > math.MaxFloat64 || x < -math.MaxFloat64
+}
+
+func floor32(x float32) float32 {
+	return float32(math.Floor(float64(x)))
+}
+
+func mod32(x, y float32) float32 {
+	return x - float32(y*floor32(x/y))
+}
+
+// min32 and max32 don't care NaN specially, and the results are deterministic unlike math.Min and math.Max.
+
+func min32(x, y, _ float32) float32 {
+	if y < x {
+		return y
+	}
+	return x
+}
+
+func max32(x, y, _ float32) float32 {
+	if y > x {
+		return y
+	}
+	return x
+}
+
+func sqrt32(x float32) float32 {
+	// math.Sqrt is exact and rounding the result to float32 gives the correctly rounded float32 square root.
+	return float32(math.Sqrt(float64(x)))
+}
+
+// sincos returns sin(x) and cos(x).
+func sincos(x float64) (float64, float64) {
+	if isNaNOrInf(x) {
+		return math.NaN(), math.NaN()
+	}
+
+	// Reduce x into [-π/4, π/4] with Cody and Waite's method.
+	// This is not accurate for a very big x, but the result is still deterministic.
+	k := math.Floor(float64(x*twoOverPi) + 0.5)
+	r := x - float64(k*piOver2Hi)
+	r -= float64(k * piOver2Mid)
+	r -= float64(k * piOver2Lo)
+
+	z := float64(r * r)
+	s := r + float64(float64(r*z)*poly(z, sinCoeffs[:]))
+	c := 1 - float64(z*0.5) + float64(float64(z*z)*poly(z, cosCoeffs[:]))
+
+	// q is k mod 4. All the operations here are exact.
+	q := k - float64(4*math.Floor(k/4))
+	switch q {
+	case 0:
+		return s, c
+	case 1:
+		return c, -s
+	case 2:
+		return -s, -c
+	default:
+		return -c, s
+	}
+}
+
+// xatan returns atan(x) for x in [-0.66, 0.66].
+func xatan(x float64) float64 {
+	z := float64(x * x)
+	z = float64(z*poly(z, atanP[:])) / poly(z, atanQ[:])
+	return float64(x*z) + x
+}
+
+func atan(x float64) float64 {
+	if x != x {
+		return x
+	}
+	if x < 0 {
+		return -atan(-x)
+	}
+	if x <= 0.66 {
+		return xatan(x)
+	}
+	if x > tan3PiOver8 {
+		return piOver2 - xatan(1/x) + piOver2Extra
+	}
+	return piOver4 + xatan((x-1)/(x+1)) + float64(0.5*piOver2Extra)
+}
+
+// atan2 returns atan(y/x) in the correct quadrant.
+// atan2 returns 0 when both x and y are 0.
+func atan2(y, x float64) float64 {
+	if x == 0 {
+		switch {
+		case y > 0:
+			return piOver2
+		case y < 0:
+			return -piOver2
+		}
+		return y
+	}
+	r := atan(y / x)
+	if x < 0 {
+		if y >= 0 {
+			return r + math.Pi
+		}
+		return r - math.Pi
+	}
+	return r
+}
+
+// exp2 returns 2^x.
+func exp2(x float64) float64 {
+	switch {
+	case x != x:
+		return x
+	case x > 1024:
+		return math.Inf(1)
+	case x < -1080:
+		return 0
+	}
+
+	// Split x into an integer k and f in [-0.5, 0.5].
+	k := math.Floor(x + 0.5)
+	f := x - k
+	return math.Ldexp(poly(float64(f*ln2), exp2Coeffs[:]), int(k))
+}
+
+// logAndExponent returns log(m) and e where x = m * 2^e and m is in [√2/2, √2).
+func logAndExponent(x float64) (float64, int) {
+	m, e := math.Frexp(x)
+	if m < sqrtHalf {
+		m *= 2
+		e--
+	}
+	// log(m) = log((1+s)/(1-s)) = 2s(1 + s^2/3 + s^4/5 + ...) where s = (m-1)/(m+1).
+	s := (m - 1) / (m + 1)
+	return float64(2*s) * poly(float64(s*s), logCoeffs[:]), e
+}
+
+// log2 returns log2(x).
+func log2(x float64) float64 {
+	switch {
+	case x != x || x > math.MaxFloat64:
+		return x
+	case x < 0:
+		return math.NaN()
+	case x == 0:
+		return math.Inf(-1)
+	}
+	l, e := logAndExponent(x)
+	return float64(l*log2E) + float64(e)
+}
+
+// log returns the natural logarithm of x.
+func log(x float64) float64 {
+	switch {
+	case x != x || x > math.MaxFloat64:
+		return x
+	case x < 0:
+		return math.NaN()
+	case x == 0:
+		return math.Inf(-1)
+	}
+	l, e := logAndExponent(x)
+	return l + float64(float64(e)*ln2)
+}
+
+func sin32(x float32) float32 {
+	s, _ := sincos(float64(x))
+	return float32(s)
+}
+
+func cos32(x float32) float32 {
+	_, c := sincos(float64(x))
+	return float32(c)
+}
+
+func tan32(x float32) float32 {
+	s, c := sincos(float64(x))
+	return float32(s / c)
+}
+
+func asin32(x float32) float32 {
+	// A value out of [-1, 1] makes NaN at math.Sqrt.
+	v := float64(x)
+	return float32(atan2(v, math.Sqrt(float64((1-v)*(1+v)))))
+}
+
+func acos32(x float32) float32 {
+	v := float64(x)
+	return float32(atan2(math.Sqrt(float64((1-v)*(1+v))), v))
+}
+
+func atan32(x float32) float32 {
+	return float32(atan(float64(x)))
+}
+
+func atan232(y, x float32) float32 {
+	return float32(atan2(float64(y), float64(x)))
+}
+
+// pow32 returns x^y. As well as GLSL, the result is undefined when x < 0, or x = 0 and y <= 0.
+func pow32(x, y float32) float32 {
+	return float32(exp2(float64(float64(y) * log2(float64(x)))))
+}
+
+func exp32(x float32) float32 {
+	return float32(exp2(float64(float64(x) * log2E)))
+}
+
+func log32(x float32) float32 {
+	return float32(log(float64(x)))
+}
+
+func exp232(x float32) float32 {
+	return float32(exp2(float64(x)))
+}
+
+func log232(x float32) float32 {
+	return float32(log2(float64(x)))
+}
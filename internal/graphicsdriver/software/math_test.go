@@ -0,0 +1,172 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package software_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver/software"
+)
+
+func TestMathAccuracy(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Func func(float32) float32
+		Want func(float64) float64
+		Min  float64
+		Max  float64
+	}{
+		{"sin", software.Sin32ForTesting, math.Sin, -100, 100},
+		{"cos", software.Cos32ForTesting, math.Cos, -100, 100},
+		{"tan", software.Tan32ForTesting, math.Tan, -1.5, 1.5},
+		{"asin", software.Asin32ForTesting, math.Asin, -1, 1},
+		{"acos", software.Acos32ForTesting, math.Acos, -1, 1},
+		{"atan", software.Atan32ForTesting, math.Atan, -100, 100},
+		{"exp", software.Exp32ForTesting, math.Exp, -80, 80},
+		{"log", software.Log32ForTesting, math.Log, 1.0 / 1024, 1000},
+		{"exp2", software.Exp232ForTesting, math.Exp2, -120, 120},
+		{"log2", software.Log232ForTesting, math.Log2, 1.0 / 1024, 1000},
+	}
+	const n = 10000
+	for _, tc := range testCases {
+		for i := 0; i <= n; i++ {
+			x := float32(tc.Min + (tc.Max-tc.Min)*float64(i)/n)
+			got := float64(tc.Func(x))
+			want := tc.Want(float64(x))
+			if math.Abs(got-want) > 1e-6*math.Max(1, math.Abs(want)) {
+				t.Errorf("%s(%v): got: %v, want: %v", tc.Name, x, got, want)
+			}
+		}
+	}
+}
+
+func TestMathAccuracyWithTwoArguments(t *testing.T) {
+	for _, y := range []float32{-3, -1, -0.5, 0, 0.5, 1, 3} {
+		for _, x := range []float32{-3, -1, -0.5, 0.5, 1, 3} {
+			got := float64(software.Atan232ForTesting(y, x))
+			want := math.Atan2(float64(y), float64(x))
+			if math.Abs(got-want) > 1e-6 {
+				t.Errorf("atan2(%v, %v): got: %v, want: %v", y, x, got, want)
+			}
+		}
+	}
+	for _, x := range []float32{0.001, 0.3, 1, 2, 10} {
+		for _, y := range []float32{-2, -0.5, 0, 0.5, 2.2} {
+			got := float64(software.Pow32ForTesting(x, y))
+			want := math.Pow(float64(x), float64(y))
+			if math.Abs(got-want) > 1e-6*math.Max(1, math.Abs(want)) {
+				t.Errorf("pow(%v, %v): got: %v, want: %v", x, y, got, want)
+			}
+		}
+	}
+}
+
+func TestMathSpecialValues(t *testing.T) {
+	inf := float32(math.Inf(1))
+	nan := float32(math.NaN())
+
+	isNaN := func(x float32) bool {
+		return x != x
+	}
+	if got := software.Sin32ForTesting(inf); !isNaN(got) {
+		t.Errorf("sin(+Inf): got: %v, want: NaN", got)
+	}
+	if got := software.Cos32ForTesting(nan); !isNaN(got) {
+		t.Errorf("cos(NaN): got: %v, want: NaN", got)
+	}
+	if got := software.Asin32ForTesting(2); !isNaN(got) {
+		t.Errorf("asin(2): got: %v, want: NaN", got)
+	}
+	if got, want := software.Atan32ForTesting(inf), float32(math.Pi/2); got != want {
+		t.Errorf("atan(+Inf): got: %v, want: %v", got, want)
+	}
+	if got, want := software.Atan232ForTesting(0, 0), float32(0); got != want {
+		t.Errorf("atan2(0, 0): got: %v, want: %v", got, want)
+	}
+	if got, want := software.Exp32ForTesting(1000), inf; got != want {
+		t.Errorf("exp(1000): got: %v, want: %v", got, want)
+	}
+	if got, want := software.Exp32ForTesting(-1000), float32(0); got != want {
+		t.Errorf("exp(-1000): got: %v, want: %v", got, want)
+	}
+	if got, want := software.Log32ForTesting(0), -inf; got != want {
+		t.Errorf("log(0): got: %v, want: %v", got, want)
+	}
+	if got := software.Log32ForTesting(-1); !isNaN(got) {
+		t.Errorf("log(-1): got: %v, want: NaN", got)
+	}
+	if got, want := software.Log232ForTesting(inf), inf; got != want {
+		t.Errorf("log2(+Inf): got: %v, want: %v", got, want)
+	}
+	if got, want := software.Pow32ForTesting(0, 2), float32(0); got != want {
+		t.Errorf("pow(0, 2): got: %v, want: %v", got, want)
+	}
+}
+
+// TestMathDeterminism tests that the results are exactly the same as the expected bits on any platforms.
+func TestMathDeterminism(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Func func(float32) float32
+		In   float32
+		Out  uint32
+	}{
+		{"sin", software.Sin32ForTesting, -100, 0x3f01a12e},
+		{"sin", software.Sin32ForTesting, -1, 0xbf576aa4},
+		{"sin", software.Sin32ForTesting, 0.5, 0x3ef57744},
+		{"sin", software.Sin32ForTesting, 3, 0x3e1081c3},
+		{"sin", software.Sin32ForTesting, 1000, 0x3f53ae61},
+		{"cos", software.Cos32ForTesting, -100, 0x3f5cc0ee},
+		{"cos", software.Cos32ForTesting, 0.5, 0x3f60a940},
+		{"cos", software.Cos32ForTesting, 3, 0xbf7d7026},
+		{"cos", software.Cos32ForTesting, 1000, 0x3f0ff813},
+		{"tan", software.Tan32ForTesting, 0.5, 0x3f0bda7b},
+		{"tan", software.Tan32ForTesting, 3, 0xbe11f7b9},
+		{"asin", software.Asin32ForTesting, -0.9, 0xbf8f549b},
+		{"asin", software.Asin32ForTesting, 0.25, 0x3e815f4e},
+		{"asin", software.Asin32ForTesting, 1, 0x3fc90fdb},
+		{"acos", software.Acos32ForTesting, -0.9, 0x402c323b},
+		{"acos", software.Acos32ForTesting, 0.25, 0x3fa8b807},
+		{"acos", software.Acos32ForTesting, 1, 0x00000000},
+		{"atan", software.Atan32ForTesting, -100, 0xbfc7c82f},
+		{"atan", software.Atan32ForTesting, 0.5, 0x3eed6338},
+		{"atan", software.Atan32ForTesting, 2, 0x3f8db70d},
+		{"exp", software.Exp32ForTesting, -10, 0x383e6bce},
+		{"exp", software.Exp32ForTesting, 0.5, 0x3fd3094c},
+		{"exp", software.Exp32ForTesting, 3, 0x41a0af2e},
+		{"log", software.Log32ForTesting, 0.001, 0xc0dd0c55},
+		{"log", software.Log32ForTesting, 2, 0x3f317218},
+		{"log", software.Log32ForTesting, 1000, 0x40dd0c55},
+		{"exp2", software.Exp232ForTesting, -10, 0x3a800000},
+		{"exp2", software.Exp232ForTesting, 0.5, 0x3fb504f3},
+		{"exp2", software.Exp232ForTesting, 3.25, 0x411837f0},
+		{"log2", software.Log232ForTesting, 0.001, 0xc11f73da},
+		{"log2", software.Log232ForTesting, 3, 0x3fcae00d},
+		{"log2", software.Log232ForTesting, 1000, 0x411f73da},
+	}
+	for _, tc := range testCases {
+		if got, want := math.Float32bits(tc.Func(tc.In)), tc.Out; got != want {
+			t.Errorf("%s(%v): got: 0x%08x, want: 0x%08x", tc.Name, tc.In, got, want)
+		}
+	}
+
+	if got, want := math.Float32bits(software.Pow32ForTesting(0.3, 2.2)), uint32(0x3d90e048); got != want {
+		t.Errorf("pow(0.3, 2.2): got: 0x%08x, want: 0x%08x", got, want)
+	}
+	if got, want := math.Float32bits(software.Atan232ForTesting(-2, 0.5)), uint32(0xbfa9b465); got != want {
+		t.Errorf("atan2(-2, 0.5): got: 0x%08x, want: 0x%08x", got, want)
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package software
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/shaderir"
+)
+
+// In this file, every product is converted to float32 explicitly before being added.
+// An explicit conversion prevents the compiler from fusing a multiplication and an addition (FMA),
+// which would make the results depend on the platform.
+
+func unaryOp(op shaderir.Op, x value) value {
+	switch op {
+	case shaderir.Add:
+		return x
+	case shaderir.Sub:
+		r := value{typ: x.typ}
+		for i := 0; i < componentCount(x.typ); i++ {
+			r.f[i] = -x.f[i]
+			r.i[i] = -x.i[i]
+		}
+		return r
+	case shaderir.NotOp:
+		return boolValue(!x.bool())
+	default:
+		panic(fmt.Sprintf("software: unexpected unary operator: %d", op))
+	}
+}
+
+func binaryOp(op shaderir.Op, lhs, rhs value) value {
+	// Convert an int to a float if the other is a float.
+	if lhs.isFloat() != rhs.isFloat() {
+		lhs = toFloat(lhs)
+		rhs = toFloat(rhs)
+	}
+
+	switch op {
+	case shaderir.MatrixMul:
+		return matrixMul(lhs, rhs)
+	case shaderir.LessThanOp, shaderir.LessThanEqualOp, shaderir.GreaterThanOp, shaderir.GreaterThanEqualOp:
+		return boolValue(compare(op, lhs, rhs))
+	case shaderir.EqualOp, shaderir.VectorEqualOp:
+		return boolValue(equal(lhs, rhs))
+	case shaderir.NotEqualOp, shaderir.VectorNotEqualOp:
+		return boolValue(!equal(lhs, rhs))
+	}
+
+	t := lhs.typ
+	if isScalarType(t) {
+		t = rhs.typ
+	}
+	r := value{typ: t}
+	ln, rn := componentCount(lhs.typ), componentCount(rhs.typ)
+	n := componentCount(t)
+
+	if r.isFloat() {
+		for i := 0; i < n; i++ {
+			x, y := lhs.f[0], rhs.f[0]
+			if ln > 1 {
+				x = lhs.f[i]
+			}
+			if rn > 1 {
+				y = rhs.f[i]
+			}
+			switch op {
+			case shaderir.Add:
+				r.f[i] = x + y
+			case shaderir.Sub:
+				r.f[i] = x - y
+			case shaderir.ComponentWiseMul:
+				r.f[i] = x * y
+			case shaderir.Div:
+				r.f[i] = x / y
+			case shaderir.ModOp:
+				r.f[i] = mod32(x, y)
+			default:
+				panic(fmt.Sprintf("software: unexpected binary operator for floats: %d", op))
+			}
+		}
+		return r
+	}
+
+	for i := 0; i < n; i++ {
+		x, y := lhs.i[0], rhs.i[0]
+		if ln > 1 {
+			x = lhs.i[i]
+		}
+		if rn > 1 {
+			y = rhs.i[i]
+		}
+		switch op {
+		case shaderir.Add:
+			r.i[i] = x + y
+		case shaderir.Sub:
+			r.i[i] = x - y
+		case shaderir.ComponentWiseMul:
+			r.i[i] = x * y
+		case shaderir.Div:
+			// A division by zero is undefined in GPU.
+			if y != 0 {
+				r.i[i] = x / y
+			}
+		case shaderir.ModOp:
+			if y != 0 {
+				r.i[i] = x % y
+			}
+		case shaderir.LeftShift:
+			r.i[i] = x << uint32(y&31)
+		case shaderir.RightShift:
+			r.i[i] = x >> uint32(y&31)
+		case shaderir.And:
+			r.i[i] = x & y
+		case shaderir.Xor:
+			r.i[i] = x ^ y
+		case shaderir.Or:
+			r.i[i] = x | y
+		default:
+			panic(fmt.Sprintf("software: unexpected binary operator for integers: %d", op))
+		}
+	}
+	return r
+}
+
+func compare(op shaderir.Op, lhs, rhs value) bool {
+	if lhs.isFloat() {
+		x, y := lhs.f[0], rhs.f[0]
+		switch op {
+		case shaderir.LessThanOp:
+			return x < y
+		case shaderir.LessThanEqualOp:
+			return x <= y
+		case shaderir.GreaterThanOp:
+			return x > y
+		case shaderir.GreaterThanEqualOp:
+			return x >= y
+		}
+	}
+	x, y := lhs.i[0], rhs.i[0]
+	switch op {
+	case shaderir.LessThanOp:
+		return x < y
+	case shaderir.LessThanEqualOp:
+		return x <= y
+	case shaderir.GreaterThanOp:
+		return x > y
+	case shaderir.GreaterThanEqualOp:
+		return x >= y
+	}
+	panic(fmt.Sprintf("software: unexpected comparison operator: %d", op))
+}
+
+func equal(lhs, rhs value) bool {
+	for i := 0; i < componentCount(lhs.typ); i++ {
+		if lhs.isFloat() {
+			if lhs.f[i] != rhs.f[i] {
+				return false
+			}
+			continue
+		}
+		if lhs.i[i] != rhs.i[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func matrixMul(lhs, rhs value) value {
+	ld, rd := matrixDim(lhs.typ), matrixDim(rhs.typ)
+	switch {
+	case ld > 0 && rd > 0:
+		// matrix * matrix
+		r := value{typ: lhs.typ}
+		for c := 0; c < rd; c++ {
+			for row := 0; row < ld; row++ {
+				var sum float32
+				for k := 0; k < ld; k++ {
+					sum += float32(lhs.f[k*ld+row] * rhs.f[c*rd+k])
+				}
+				r.f[c*ld+row] = sum
+			}
+		}
+		return r
+	case ld > 0 && isScalarType(rhs.typ):
+		return binaryOp(shaderir.ComponentWiseMul, lhs, rhs)
+	case rd > 0 && isScalarType(lhs.typ):
+		return binaryOp(shaderir.ComponentWiseMul, lhs, rhs)
+	case ld > 0:
+		// matrix * vector
+		r := value{typ: vectorType(ld, true)}
+		for row := 0; row < ld; row++ {
+			var sum float32
+			for k := 0; k < ld; k++ {
+				sum += float32(lhs.f[k*ld+row] * rhs.f[k])
+			}
+			r.f[row] = sum
+		}
+		return r
+	case rd > 0:
+		// vector * matrix
+		r := value{typ: vectorType(rd, true)}
+		for c := 0; c < rd; c++ {
+			var sum float32
+			for k := 0; k < rd; k++ {
+				sum += float32(lhs.f[k] * rhs.f[c*rd+k])
+			}
+			r.f[c] = sum
+		}
+		return r
+	default:
+		return binaryOp(shaderir.ComponentWiseMul, lhs, rhs)
+	}
+}
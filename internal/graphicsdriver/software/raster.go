@@ -0,0 +1,151 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package software
+
+import (
+	"image"
+	"math"
+)
+
+// Positions are snapped to the fixed-point format with 8 sub-pixel bits, like GPUs.
+// With integers, the rasterization result doesn't depend on the platform.
+const (
+	subpixelBits = 8
+	subpixelOne  = 1 << subpixelBits
+	subpixelHalf = subpixelOne / 2
+
+	// maxFixed is the limit of the absolute value of a fixed-point position so that the edge functions never overflow.
+	maxFixed = 1 << 29
+)
+
+func toFixed(x float64) int64 {
+	x = math.Floor(float64(x*subpixelOne) + 0.5)
+	switch {
+	case x != x:
+		return 0
+	case x > maxFixed:
+		return maxFixed
+	case x < -maxFixed:
+		return -maxFixed
+	}
+	return int64(x)
+}
+
+// floorDiv returns floor(x / y) for a positive y.
+func floorDiv(x, y int64) int64 {
+	q := x / y
+	if x%y != 0 && x < 0 {
+		q--
+	}
+	return q
+}
+
+// isTopLeftEdge reports whether the edge from (x0, y0) to (x1, y1) is a top edge or a left edge
+// of a triangle whose edge functions are positive inside.
+//
+// A pixel center just on an edge is covered only when the edge is a top edge or a left edge.
+// Then, a pixel is never drawn twice by two triangles sharing an edge.
+func isTopLeftEdge(x0, y0, x1, y1 int64) bool {
+	dx, dy := x1-x0, y1-y0
+	return dy < 0 || (dy == 0 && dx > 0)
+}
+
+func edgeFunction(x0, y0, x1, y1, x, y int64) int64 {
+	return (x1-x0)*(y-y0) - (y1-y0)*(x-x0)
+}
+
+// rasterizeTriangle calls f for each pixel whose center is covered by the triangle in the clip region.
+//
+// w0, w1, and w2 are the barycentric coordinates of the pixel center.
+// front reports whether the triangle is counterclockwise in the coordinate system where the Y direction is upward,
+// like OpenGL's front faces.
+func rasterizeTriangle(clip image.Rectangle, v0, v1, v2 *vertexOutput, f func(x, y int, w0, w1, w2 float64, front bool)) {
+	x0, y0 := v0.x, v0.y
+	x1, y1 := v1.x, v1.y
+	x2, y2 := v2.x, v2.y
+
+	area := edgeFunction(x0, y0, x1, y1, x2, y2)
+	if area == 0 {
+		return
+	}
+	front := area > 0
+	if !front {
+		// Make the triangle's orientation positive so that the inside is where all the edge functions are positive.
+		x1, y1, x2, y2 = x2, y2, x1, y1
+		area = -area
+	}
+
+	minX, maxX := x0, x0
+	minY, maxY := y0, y0
+	for _, x := range []int64{x1, x2} {
+		if minX > x {
+			minX = x
+		}
+		if maxX < x {
+			maxX = x
+		}
+	}
+	for _, y := range []int64{y1, y2} {
+		if minY > y {
+			minY = y
+		}
+		if maxY < y {
+			maxY = y
+		}
+	}
+
+	// Calculate the range of the pixels whose centers are in the bounding box.
+	bx0 := int(floorDiv(minX-subpixelHalf+subpixelOne-1, subpixelOne))
+	bx1 := int(floorDiv(maxX-subpixelHalf, subpixelOne)) + 1
+	by0 := int(floorDiv(minY-subpixelHalf+subpixelOne-1, subpixelOne))
+	by1 := int(floorDiv(maxY-subpixelHalf, subpixelOne)) + 1
+	r := image.Rect(bx0, by0, bx1, by1).Intersect(clip)
+	if r.Empty() {
+		return
+	}
+
+	tl0 := isTopLeftEdge(x1, y1, x2, y2)
+	tl1 := isTopLeftEdge(x2, y2, x0, y0)
+	tl2 := isTopLeftEdge(x0, y0, x1, y1)
+	farea := float64(area)
+
+	for j := r.Min.Y; j < r.Max.Y; j++ {
+		py := int64(j)*subpixelOne + subpixelHalf
+		for i := r.Min.X; i < r.Max.X; i++ {
+			px := int64(i)*subpixelOne + subpixelHalf
+
+			e0 := edgeFunction(x1, y1, x2, y2, px, py)
+			if e0 < 0 || (e0 == 0 && !tl0) {
+				continue
+			}
+			e1 := edgeFunction(x2, y2, x0, y0, px, py)
+			if e1 < 0 || (e1 == 0 && !tl1) {
+				continue
+			}
+			e2 := edgeFunction(x0, y0, x1, y1, px, py)
+			if e2 < 0 || (e2 == 0 && !tl2) {
+				continue
+			}
+
+			w0 := float64(e0) / farea
+			w1 := float64(e1) / farea
+			w2 := float64(e2) / farea
+			if !front {
+				w1, w2 = w2, w1
+			}
+			f(i, j, w0, w1, w2, front)
+		}
+	}
+}
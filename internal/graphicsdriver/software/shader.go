@@ -0,0 +1,703 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package software
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/graphics"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+	"github.com/hajimehoshi/ebiten/v2/internal/shaderir"
+)
+
+// flow represents how the control flows after a statement is executed.
+type flow int
+
+const (
+	flowNext flow = iota
+	flowBreak
+	flowContinue
+	flowReturn
+	flowDiscard
+)
+
+type (
+	execFunc func() flow
+	evalFunc func() value
+	refFunc  func() ref
+)
+
+// frame is the storage of a function's local variables.
+//
+// As a recursive call is not allowed in a shader program, each function has only one frame.
+type frame struct {
+	locals []value
+	ret    value
+}
+
+// ref is a reference to a variable or a part of a variable that can be assigned.
+type ref struct {
+	v *value
+
+	// comps is the indices of the components of v.
+	comps [4]int
+
+	// n is the number of the components. If n is 0, the reference is to the whole value.
+	n int
+}
+
+func (r *ref) store(v *value) {
+	if r.n == 0 {
+		copyValue(r.v, v)
+		return
+	}
+	if r.v.isFloat() {
+		src := toFloat(*v)
+		for i := 0; i < r.n; i++ {
+			r.v.f[r.comps[i]] = src.f[i]
+		}
+		return
+	}
+	for i := 0; i < r.n; i++ {
+		r.v.i[r.comps[i]] = v.i[i]
+	}
+}
+
+type function struct {
+	f     *shaderir.Func
+	frame *frame
+	body  execFunc
+}
+
+// Shader is a shader program that is interpreted on CPU.
+type Shader struct {
+	id       graphicsdriver.ShaderID
+	graphics *Graphics
+	ir       *shaderir.Program
+
+	uniforms []value
+	textures [graphics.ShaderImageCount]*Image
+
+	funcs map[int]*function
+
+	vertexFrame   *frame
+	vertex        execFunc
+	fragmentFrame *frame
+	fragment      execFunc
+}
+
+func newShader(id graphicsdriver.ShaderID, graphics *Graphics, program *shaderir.Program) (*Shader, error) {
+	s := &Shader{
+		id:       id,
+		graphics: graphics,
+		ir:       program,
+		funcs:    map[int]*function{},
+	}
+
+	for i := range program.Funcs {
+		f := &program.Funcs[i]
+		n := len(f.InParams) + len(f.OutParams)
+		s.funcs[f.Index] = &function{
+			f:     f,
+			frame: newFrame(n, f.Block),
+		}
+	}
+
+	if err := s.compile(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func newFrame(paramCount int, block *shaderir.Block) *frame {
+	return &frame{
+		locals: make([]value, localVariableCount(paramCount, block)),
+	}
+}
+
+// localVariableCount returns the number of the local variables in the function including the parameters.
+func localVariableCount(paramCount int, block *shaderir.Block) int {
+	n := paramCount
+	var walkExpr func(e *shaderir.Expr)
+	walkExpr = func(e *shaderir.Expr) {
+		if e.Type == shaderir.LocalVariable && n < e.Index+1 {
+			n = e.Index + 1
+		}
+		for i := range e.Exprs {
+			walkExpr(&e.Exprs[i])
+		}
+	}
+	var walkBlock func(b *shaderir.Block)
+	walkBlock = func(b *shaderir.Block) {
+		if b == nil {
+			return
+		}
+		if c := b.LocalVarIndexOffset + len(b.LocalVars); n < c {
+			n = c
+		}
+		for i := range b.Stmts {
+			s := &b.Stmts[i]
+			switch s.Type {
+			case shaderir.Init:
+				if n < s.InitIndex+1 {
+					n = s.InitIndex + 1
+				}
+			case shaderir.For:
+				if n < s.ForVarIndex+1 {
+					n = s.ForVarIndex + 1
+				}
+			}
+			for j := range s.Exprs {
+				walkExpr(&s.Exprs[j])
+			}
+			for _, b := range s.Blocks {
+				walkBlock(b)
+			}
+		}
+	}
+	walkBlock(block)
+	return n
+}
+
+func (s *Shader) compile() (err error) {
+	// A shader program is already validated by the shader compiler.
+	// A panic here means an unexpected program for this interpreter.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("software: compiling a shader failed: %v", r)
+		}
+	}()
+
+	for _, f := range s.funcs {
+		c := &compiler{
+			shader: s,
+			top:    f.f.Block,
+			frame:  f.frame,
+		}
+		f.body = c.block(f.f.Block)
+	}
+
+	if b := s.ir.VertexFunc.Block; b != nil {
+		s.vertexFrame = newFrame(len(s.ir.Attributes)+1+len(s.ir.Varyings), b)
+		c := &compiler{
+			shader: s,
+			top:    b,
+			frame:  s.vertexFrame,
+		}
+		s.vertex = c.block(b)
+	}
+
+	if b := s.ir.FragmentFunc.Block; b != nil {
+		s.fragmentFrame = newFrame(1+len(s.ir.Varyings), b)
+		c := &compiler{
+			shader: s,
+			top:    b,
+			frame:  s.fragmentFrame,
+		}
+		s.fragment = c.block(b)
+	}
+
+	return nil
+}
+
+func (s *Shader) ID() graphicsdriver.ShaderID {
+	return s.id
+}
+
+func (s *Shader) Dispose() {
+	s.graphics.removeShader(s)
+}
+
+// setUniforms sets the uniform variables for the next executions.
+func (s *Shader) setUniforms(uniforms []uint32) {
+	if len(s.uniforms) != len(s.ir.Uniforms) {
+		s.uniforms = make([]value, len(s.ir.Uniforms))
+	}
+	var idx int
+	for i, t := range s.ir.Uniforms {
+		n := t.Uint32Count()
+		s.uniforms[i] = uniformValue(&t, uniforms[idx:idx+n])
+		idx += n
+	}
+}
+
+// runVertex executes the vertex shader with the given vertex attributes.
+//
+// runVertex returns the position and the varying variables. The returned varying variables are valid until the next call.
+func (s *Shader) runVertex(vertex []float32) ([4]float32, []value) {
+	fr := s.vertexFrame
+	var idx int
+	for i, t := range s.ir.Attributes {
+		v := value{typ: t.Main}
+		n := componentCount(t.Main)
+		copy(v.f[:n], vertex[idx:idx+n])
+		fr.locals[i] = v
+		idx += n
+	}
+	na := len(s.ir.Attributes)
+	fr.locals[na] = value{typ: shaderir.Vec4}
+	for i, t := range s.ir.Varyings {
+		fr.locals[na+1+i] = zeroValue(&t)
+	}
+
+	s.vertex()
+
+	var pos [4]float32
+	copy(pos[:], fr.locals[na].f[:4])
+	return pos, fr.locals[na+1 : na+1+len(s.ir.Varyings)]
+}
+
+// runFragment executes the fragment shader with the given fragment coordinate and varying variables.
+//
+// runFragment returns the color, and false if the fragment is discarded.
+func (s *Shader) runFragment(fragCoord [4]float32, varyings []value) ([4]float32, bool) {
+	fr := s.fragmentFrame
+	v := value{typ: shaderir.Vec4}
+	copy(v.f[:4], fragCoord[:])
+	fr.locals[0] = v
+	copy(fr.locals[1:], varyings)
+
+	if s.fragment() == flowDiscard {
+		return [4]float32{}, false
+	}
+
+	var c [4]float32
+	copy(c[:], fr.ret.f[:4])
+	return c, true
+}
+
+type compiler struct {
+	shader *Shader
+	top    *shaderir.Block
+	frame  *frame
+}
+
+func (c *compiler) block(b *shaderir.Block) execFunc {
+	offset := b.LocalVarIndexOffset
+	zeros := make([]value, len(b.LocalVars))
+	for i := range b.LocalVars {
+		zeros[i] = zeroValue(&b.LocalVars[i])
+	}
+	stmts := make([]execFunc, 0, len(b.Stmts))
+	for i := range b.Stmts {
+		stmts = append(stmts, c.stmt(b, &b.Stmts[i]))
+	}
+
+	fr := c.frame
+	return func() flow {
+		for i := range zeros {
+			copyValue(&fr.locals[offset+i], &zeros[i])
+		}
+		for _, s := range stmts {
+			if f := s(); f != flowNext {
+				return f
+			}
+		}
+		return flowNext
+	}
+}
+
+func (c *compiler) stmt(b *shaderir.Block, s *shaderir.Stmt) execFunc {
+	fr := c.frame
+
+	switch s.Type {
+	case shaderir.ExprStmt:
+		e := c.expr(&s.Exprs[0])
+		return func() flow {
+			e()
+			return flowNext
+		}
+
+	case shaderir.BlockStmt:
+		return c.block(s.Blocks[0])
+
+	case shaderir.Assign:
+		lhs := c.ref(&s.Exprs[0])
+		rhs := c.expr(&s.Exprs[1])
+		return func() flow {
+			v := rhs()
+			r := lhs()
+			r.store(&v)
+			return flowNext
+		}
+
+	case shaderir.Init:
+		t := c.shader.ir.LocalVariableType(c.top, b, s.InitIndex)
+		zero := zeroValue(&t)
+		idx := s.InitIndex
+		return func() flow {
+			copyValue(&fr.locals[idx], &zero)
+			return flowNext
+		}
+
+	case shaderir.If:
+		cond := c.expr(&s.Exprs[0])
+		then := c.block(s.Blocks[0])
+		var els execFunc
+		if len(s.Blocks) > 1 {
+			els = c.block(s.Blocks[1])
+		}
+		return func() flow {
+			v := cond()
+			if v.bool() {
+				return then()
+			}
+			if els != nil {
+				return els()
+			}
+			return flowNext
+		}
+
+	case shaderir.For:
+		body := c.block(s.Blocks[0])
+		idx := s.ForVarIndex
+		op := s.ForOp
+		var init, end, delta value
+		if s.ForVarType.Main == shaderir.Float {
+			init = toFloat(constantValue(s.ForInit))
+			end = toFloat(constantValue(s.ForEnd))
+			delta = toFloat(constantValue(s.ForDelta))
+		} else {
+			init = constantValue(s.ForInit)
+			end = constantValue(s.ForEnd)
+			delta = constantValue(s.ForDelta)
+		}
+		return func() flow {
+			fr.locals[idx] = init
+			for {
+				if c := binaryOp(op, fr.locals[idx], end); !c.bool() {
+					break
+				}
+				switch body() {
+				case flowBreak:
+					return flowNext
+				case flowReturn:
+					return flowReturn
+				case flowDiscard:
+					return flowDiscard
+				}
+				fr.locals[idx] = binaryOp(shaderir.Add, fr.locals[idx], delta)
+			}
+			return flowNext
+		}
+
+	case shaderir.Continue:
+		return func() flow {
+			return flowContinue
+		}
+
+	case shaderir.Break:
+		return func() flow {
+			return flowBreak
+		}
+
+	case shaderir.Return:
+		if len(s.Exprs) == 0 {
+			return func() flow {
+				return flowReturn
+			}
+		}
+		e := c.expr(&s.Exprs[0])
+		return func() flow {
+			v := e()
+			copyValue(&fr.ret, &v)
+			return flowReturn
+		}
+
+	case shaderir.Discard:
+		return func() flow {
+			return flowDiscard
+		}
+
+	default:
+		panic(fmt.Sprintf("software: unexpected statement: %d", s.Type))
+	}
+}
+
+func (c *compiler) expr(e *shaderir.Expr) evalFunc {
+	fr := c.frame
+
+	switch e.Type {
+	case shaderir.NumberExpr:
+		v := constantValue(e.Const)
+		return func() value {
+			return v
+		}
+
+	case shaderir.UniformVariable:
+		s := c.shader
+		idx := e.Index
+		return func() value {
+			return s.uniforms[idx]
+		}
+
+	case shaderir.TextureVariable:
+		v := value{typ: shaderir.Texture}
+		v.i[0] = int32(e.Index)
+		return func() value {
+			return v
+		}
+
+	case shaderir.LocalVariable:
+		idx := e.Index
+		return func() value {
+			return fr.locals[idx]
+		}
+
+	case shaderir.Unary:
+		op := e.Op
+		x := c.expr(&e.Exprs[0])
+		return func() value {
+			return unaryOp(op, x())
+		}
+
+	case shaderir.Binary:
+		op := e.Op
+		lhs := c.expr(&e.Exprs[0])
+		rhs := c.expr(&e.Exprs[1])
+		switch op {
+		case shaderir.AndAnd:
+			return func() value {
+				if l := lhs(); !l.bool() {
+					return boolValue(false)
+				}
+				r := rhs()
+				return boolValue(r.bool())
+			}
+		case shaderir.OrOr:
+			return func() value {
+				if l := lhs(); l.bool() {
+					return boolValue(true)
+				}
+				r := rhs()
+				return boolValue(r.bool())
+			}
+		}
+		return func() value {
+			return binaryOp(op, lhs(), rhs())
+		}
+
+	case shaderir.Selection:
+		cond := c.expr(&e.Exprs[0])
+		x := c.expr(&e.Exprs[1])
+		y := c.expr(&e.Exprs[2])
+		return func() value {
+			if v := cond(); v.bool() {
+				return x()
+			}
+			return y()
+		}
+
+	case shaderir.Call:
+		return c.call(e)
+
+	case shaderir.FieldSelector:
+		x := c.expr(&e.Exprs[0])
+		idx := swizzlingIndices(e.Exprs[1].Swizzling)
+		n := len(e.Exprs[1].Swizzling)
+		return func() value {
+			v := x()
+			r := value{typ: vectorType(n, v.isFloat())}
+			if v.typ == shaderir.Bool {
+				r.typ = shaderir.Bool
+			}
+			for i := 0; i < n; i++ {
+				r.f[i] = v.f[idx[i]]
+				r.i[i] = v.i[idx[i]]
+			}
+			return r
+		}
+
+	case shaderir.Index:
+		x := c.expr(&e.Exprs[0])
+		idx := c.expr(&e.Exprs[1])
+		return func() value {
+			v := x()
+			i := idx()
+			return indexValue(&v, int(i.i[0]))
+		}
+
+	default:
+		panic(fmt.Sprintf("software: unexpected expression: %d", e.Type))
+	}
+}
+
+func (c *compiler) ref(e *shaderir.Expr) refFunc {
+	fr := c.frame
+
+	switch e.Type {
+	case shaderir.LocalVariable:
+		idx := e.Index
+		return func() ref {
+			return ref{v: &fr.locals[idx]}
+		}
+
+	case shaderir.FieldSelector:
+		x := c.ref(&e.Exprs[0])
+		idx := swizzlingIndices(e.Exprs[1].Swizzling)
+		n := len(e.Exprs[1].Swizzling)
+		return func() ref {
+			r := x()
+			var comps [4]int
+			for i := 0; i < n; i++ {
+				if r.n == 0 {
+					comps[i] = idx[i]
+				} else {
+					comps[i] = r.comps[idx[i]]
+				}
+			}
+			return ref{v: r.v, comps: comps, n: n}
+		}
+
+	case shaderir.Index:
+		x := c.ref(&e.Exprs[0])
+		idx := c.expr(&e.Exprs[1])
+		return func() ref {
+			r := x()
+			iv := idx()
+			i := int(iv.i[0])
+			if r.n != 0 {
+				i = clampIndex(i, r.n)
+				return ref{v: r.v, comps: [4]int{r.comps[i]}, n: 1}
+			}
+			if r.v.typ == shaderir.Array {
+				i = clampIndex(i, len(r.v.arr))
+				return ref{v: &r.v.arr[i]}
+			}
+			if d := matrixDim(r.v.typ); d > 0 {
+				i = clampIndex(i, d)
+				var comps [4]int
+				for j := 0; j < d; j++ {
+					comps[j] = i*d + j
+				}
+				return ref{v: r.v, comps: comps, n: d}
+			}
+			i = clampIndex(i, componentCount(r.v.typ))
+			return ref{v: r.v, comps: [4]int{i}, n: 1}
+		}
+
+	default:
+		panic(fmt.Sprintf("software: unexpected expression as an assignment target: %d", e.Type))
+	}
+}
+
+func (c *compiler) call(e *shaderir.Expr) evalFunc {
+	callee := &e.Exprs[0]
+	argExprs := e.Exprs[1:]
+
+	switch callee.Type {
+	case shaderir.BuiltinFuncExpr:
+		s := c.shader
+		f := callee.BuiltinFunc
+		fns := make([]evalFunc, len(argExprs))
+		for i := range argExprs {
+			fns[i] = c.expr(&argExprs[i])
+		}
+		args := make([]value, len(fns))
+		return func() value {
+			for i, fn := range fns {
+				args[i] = fn()
+			}
+			return s.callBuiltin(f, args)
+		}
+
+	case shaderir.FunctionExpr:
+		f, ok := c.shader.funcs[callee.Index]
+		if !ok {
+			panic(fmt.Sprintf("software: function %d is not found", callee.Index))
+		}
+		nin := len(f.f.InParams)
+		ins := make([]evalFunc, nin)
+		for i := 0; i < nin; i++ {
+			ins[i] = c.expr(&argExprs[i])
+		}
+		outs := make([]refFunc, len(f.f.OutParams))
+		outZeros := make([]value, len(f.f.OutParams))
+		for i := range f.f.OutParams {
+			outs[i] = c.ref(&argExprs[nin+i])
+			outZeros[i] = zeroValue(&f.f.OutParams[i])
+		}
+		args := make([]value, nin)
+		return func() value {
+			for i, in := range ins {
+				args[i] = in()
+			}
+			fr := f.frame
+			for i := range args {
+				copyValue(&fr.locals[i], &args[i])
+			}
+			for i := range outZeros {
+				copyValue(&fr.locals[nin+i], &outZeros[i])
+			}
+			f.body()
+			for i, out := range outs {
+				r := out()
+				r.store(&fr.locals[nin+i])
+			}
+			var v value
+			copyValue(&v, &fr.ret)
+			return v
+		}
+
+	default:
+		panic(fmt.Sprintf("software: unexpected callee: %d", callee.Type))
+	}
+}
+
+func swizzlingIndices(s string) [4]int {
+	var set string
+	for _, xs := range []string{"xyzw", "rgba", "strq"} {
+		if strings.IndexByte(xs, s[0]) >= 0 {
+			set = xs
+			break
+		}
+	}
+	var idx [4]int
+	for i := 0; i < len(s); i++ {
+		idx[i] = strings.IndexByte(set, s[i])
+	}
+	return idx
+}
+
+// clampIndex clamps an index to [0, n).
+//
+// An index out of range is undefined in GPU. Clamp it to keep the result deterministic.
+func clampIndex(i, n int) int {
+	if i < 0 {
+		return 0
+	}
+	if i >= n {
+		return n - 1
+	}
+	return i
+}
+
+func indexValue(v *value, i int) value {
+	if v.typ == shaderir.Array {
+		return v.arr[clampIndex(i, len(v.arr))]
+	}
+	if d := matrixDim(v.typ); d > 0 {
+		i = clampIndex(i, d)
+		r := value{typ: vectorType(d, true)}
+		copy(r.f[:d], v.f[i*d:(i+1)*d])
+		return r
+	}
+	i = clampIndex(i, componentCount(v.typ))
+	if v.isFloat() {
+		return floatValue(v.f[i])
+	}
+	return intValue(v.i[i])
+}
@@ -0,0 +1,257 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package software
+
+import (
+	"fmt"
+	"go/constant"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/shaderir"
+)
+
+// value is a value in a shader program.
+//
+// A float, a float vector, and a matrix use f. A matrix is stored in the column-major order.
+// A bool, an int, an int vector, and a texture use i. A bool is 0 or 1.
+// An array uses arr.
+type value struct {
+	typ shaderir.BasicType
+	f   [16]float32
+	i   [4]int32
+	arr []value
+}
+
+func (v *value) isFloat() bool {
+	return isFloatType(v.typ)
+}
+
+func (v *value) bool() bool {
+	return v.i[0] != 0
+}
+
+func isFloatType(t shaderir.BasicType) bool {
+	switch t {
+	case shaderir.Float, shaderir.Vec2, shaderir.Vec3, shaderir.Vec4, shaderir.Mat2, shaderir.Mat3, shaderir.Mat4:
+		return true
+	}
+	return false
+}
+
+func isMatrixType(t shaderir.BasicType) bool {
+	switch t {
+	case shaderir.Mat2, shaderir.Mat3, shaderir.Mat4:
+		return true
+	}
+	return false
+}
+
+func isScalarType(t shaderir.BasicType) bool {
+	switch t {
+	case shaderir.Bool, shaderir.Int, shaderir.Float:
+		return true
+	}
+	return false
+}
+
+// componentCount returns the number of the scalar components of the type.
+func componentCount(t shaderir.BasicType) int {
+	switch t {
+	case shaderir.Bool, shaderir.Int, shaderir.Float, shaderir.Texture:
+		return 1
+	case shaderir.Vec2, shaderir.IVec2:
+		return 2
+	case shaderir.Vec3, shaderir.IVec3:
+		return 3
+	case shaderir.Vec4, shaderir.IVec4, shaderir.Mat2:
+		return 4
+	case shaderir.Mat3:
+		return 9
+	case shaderir.Mat4:
+		return 16
+	default:
+		return 0
+	}
+}
+
+// matrixDim returns the number of the columns (and the rows) of the matrix type.
+func matrixDim(t shaderir.BasicType) int {
+	switch t {
+	case shaderir.Mat2:
+		return 2
+	case shaderir.Mat3:
+		return 3
+	case shaderir.Mat4:
+		return 4
+	default:
+		return 0
+	}
+}
+
+func vectorType(n int, float bool) shaderir.BasicType {
+	if float {
+		switch n {
+		case 1:
+			return shaderir.Float
+		case 2:
+			return shaderir.Vec2
+		case 3:
+			return shaderir.Vec3
+		case 4:
+			return shaderir.Vec4
+		}
+	} else {
+		switch n {
+		case 1:
+			return shaderir.Int
+		case 2:
+			return shaderir.IVec2
+		case 3:
+			return shaderir.IVec3
+		case 4:
+			return shaderir.IVec4
+		}
+	}
+	panic(fmt.Sprintf("software: unexpected vector size: %d", n))
+}
+
+func floatValue(x float32) value {
+	v := value{typ: shaderir.Float}
+	v.f[0] = x
+	return v
+}
+
+func intValue(x int32) value {
+	v := value{typ: shaderir.Int}
+	v.i[0] = x
+	return v
+}
+
+func boolValue(x bool) value {
+	v := value{typ: shaderir.Bool}
+	if x {
+		v.i[0] = 1
+	}
+	return v
+}
+
+func constantValue(c constant.Value) value {
+	switch c.Kind() {
+	case constant.Bool:
+		return boolValue(constant.BoolVal(c))
+	case constant.Int:
+		x, _ := constant.Int64Val(c)
+		return intValue(int32(x))
+	case constant.Float:
+		x, _ := constant.Float64Val(c)
+		return floatValue(float32(x))
+	default:
+		panic(fmt.Sprintf("software: unexpected constant: %s", c))
+	}
+}
+
+// zeroValue returns the zero value of the type.
+func zeroValue(t *shaderir.Type) value {
+	v := value{typ: t.Main}
+	if t.Main == shaderir.Array {
+		v.arr = make([]value, t.Length)
+		for i := range v.arr {
+			v.arr[i] = zeroValue(&t.Sub[0])
+		}
+	}
+	return v
+}
+
+// copyValue copies src to dst deeply.
+func copyValue(dst *value, src *value) {
+	arr := dst.arr
+	*dst = *src
+	if src.arr == nil {
+		return
+	}
+	if len(arr) != len(src.arr) {
+		arr = make([]value, len(src.arr))
+	}
+	for i := range src.arr {
+		copyValue(&arr[i], &src.arr[i])
+	}
+	dst.arr = arr
+}
+
+// toFloat converts an int-based value to a float-based value.
+func toFloat(v value) value {
+	if v.isFloat() {
+		return v
+	}
+	var t shaderir.BasicType
+	switch v.typ {
+	case shaderir.Bool, shaderir.Int:
+		t = shaderir.Float
+	case shaderir.IVec2:
+		t = shaderir.Vec2
+	case shaderir.IVec3:
+		t = shaderir.Vec3
+	case shaderir.IVec4:
+		t = shaderir.Vec4
+	default:
+		return v
+	}
+	r := value{typ: t}
+	for i := 0; i < componentCount(v.typ); i++ {
+		r.f[i] = float32(v.i[i])
+	}
+	return r
+}
+
+// floatToInt converts a float to an int by truncating.
+//
+// Unlike a Go's conversion, the result doesn't depend on the platform even when x is NaN or out of range.
+func floatToInt(x float32) int32 {
+	switch {
+	case x != x:
+		return 0
+	case x >= math.MaxInt32:
+		return math.MaxInt32
+	case x <= math.MinInt32:
+		return math.MinInt32
+	}
+	return int32(x)
+}
+
+// uniformValue makes a value from the uniform variable's raw data.
+func uniformValue(t *shaderir.Type, data []uint32) value {
+	v := value{typ: t.Main}
+	switch {
+	case t.Main == shaderir.Array:
+		v.arr = make([]value, t.Length)
+		n := t.Sub[0].Uint32Count()
+		for i := range v.arr {
+			v.arr[i] = uniformValue(&t.Sub[0], data[i*n:(i+1)*n])
+		}
+	case isFloatType(t.Main):
+		for i := 0; i < componentCount(t.Main); i++ {
+			v.f[i] = math.Float32frombits(data[i])
+		}
+	case t.Main == shaderir.Bool:
+		if data[0] != 0 {
+			v.i[0] = 1
+		}
+	default:
+		for i := 0; i < componentCount(t.Main); i++ {
+			v.i[i] = int32(data[i])
+		}
+	}
+	return v
+}
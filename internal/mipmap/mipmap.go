@@ -27,7 +27,7 @@ import (
 
 func canUseMipmap(imageType atlas.ImageType) bool {
 	switch imageType {
-	case atlas.ImageTypeRegular, atlas.ImageTypeUnmanaged:
+	case atlas.ImageTypeRegular, atlas.ImageTypeUnmanaged, atlas.ImageTypeAtlasPreferred:
 		return true
 	}
 	return false
@@ -65,6 +65,10 @@ func (m *Mipmap) ReadPixels(graphicsDriver graphicsdriver.Graphics, pixels []byt
 	return m.orig.ReadPixels(graphicsDriver, pixels, region)
 }
 
+func (m *Mipmap) ReadPixelsAsync(pixels []byte, region image.Rectangle, callback func()) {
+	m.orig.ReadPixelsAsync(pixels, region, callback)
+}
+
 func (m *Mipmap) DrawTriangles(srcs [graphics.ShaderImageCount]*Mipmap, vertices []float32, indices []uint32, blend graphicsdriver.Blend, dstRegion image.Rectangle, srcRegions [graphics.ShaderImageCount]image.Rectangle, shader *atlas.Shader, uniforms []uint32, fillRule graphicsdriver.FillRule, canSkipMipmap bool) {
 	if len(indices) == 0 {
 		return
@@ -193,6 +193,20 @@ func (p *Page) Free(node *Node) {
 	}
 }
 
+// AppendUsedRegions appends the regions of the allocated nodes to regions and returns the extended buffer.
+func (p *Page) AppendUsedRegions(regions []image.Rectangle) []image.Rectangle {
+	if p.root == nil {
+		return regions
+	}
+	_ = walk(p.root, func(n *Node) error {
+		if n.used {
+			regions = append(regions, n.region)
+		}
+		return nil
+	})
+	return regions
+}
+
 func walk(n *Node, f func(n *Node) error) error {
 	if err := f(n); err != nil {
 		return err
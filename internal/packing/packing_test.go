@@ -16,6 +16,8 @@ package packing_test
 
 import (
 	"image"
+	"reflect"
+	"sort"
 	"testing"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/packing"
@@ -449,3 +451,26 @@ func TestRemoveAtRootsChild(t *testing.T) {
 	n6 := p.Alloc(18, 18)
 	p.Free(n6)
 }
+
+func TestAppendUsedRegions(t *testing.T) {
+	p := packing.NewPage(1024, 1024, 1024)
+	if got := p.AppendUsedRegions(nil); len(got) != 0 {
+		t.Errorf("AppendUsedRegions(nil) for an empty page: got: %v, want: empty", got)
+	}
+
+	n0 := p.Alloc(100, 200)
+	n1 := p.Alloc(300, 50)
+	got := p.AppendUsedRegions(nil)
+	want := []image.Rectangle{n0.Region(), n1.Region()}
+	sort.Slice(got, func(i, j int) bool {
+		return got[i].Dx() < got[j].Dx()
+	})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AppendUsedRegions(nil): got: %v, want: %v", got, want)
+	}
+
+	p.Free(n0)
+	if got, want := p.AppendUsedRegions(nil), []image.Rectangle{n1.Region()}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AppendUsedRegions(nil) after Free: got: %v, want: %v", got, want)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build ignore
+
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+var (
+	bgColor   = color.RGBA{0x40, 0x80, 0xc0, 0xff}
+	rectColor = color.RGBA{0xff, 0, 0, 0xff}
+	rect      = image.Rect(10, 20, 30, 60)
+)
+
+type Game struct {
+	drawCount int
+}
+
+func (g *Game) Update() error {
+	// Before the first Draw, the screen has nothing to compare with.
+	if g.drawCount == 0 {
+		return nil
+	}
+
+	img := ebiten.CaptureScreenAsImage()
+	if img == nil {
+		return fmt.Errorf("CaptureScreenAsImage must not return nil after Draw")
+	}
+	if got, want := img.Bounds(), image.Rect(0, 0, 320, 240); got != want {
+		return fmt.Errorf("Bounds(): got: %v, want: %v", got, want)
+	}
+	for j := 0; j < 240; j++ {
+		for i := 0; i < 320; i++ {
+			got := color.RGBAModel.Convert(img.At(i, j)).(color.RGBA)
+			want := bgColor
+			if image.Pt(i, j).In(rect) {
+				want = rectColor
+			}
+			if got != want {
+				return fmt.Errorf("At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+	return ebiten.Termination
+}
+
+func (g *Game) Draw(screen *ebiten.Image) {
+	screen.Fill(bgColor)
+	screen.SubImage(rect).(*ebiten.Image).Fill(rectColor)
+	g.drawCount++
+}
+
+func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return 320, 240
+}
+
+func main() {
+	if err := ebiten.RunGame(&Game{}); err != nil {
+		panic(err)
+	}
+	if img := ebiten.CaptureScreenAsImage(); img != nil {
+		panic("CaptureScreenAsImage must return nil after RunGame returns")
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build ignore
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/internal/clock"
+	"github.com/hajimehoshi/ebiten/v2/internal/ui"
+)
+
+// refreshRates are the refresh rates of the current monitor emulated in order.
+// Each TPS is different from the previous one so that the game can detect when the game loop applies the refresh rate.
+var refreshRates = []struct {
+	RefreshRate float64
+	TPS         int
+}{
+	{144, 144},
+	// An unknown refresh rate falls back to the default TPS.
+	{0, ebiten.DefaultTPS},
+	{240, 240},
+	// A non-integer refresh rate is rounded.
+	{59.94, 60},
+	{75, 75},
+}
+
+// maxUpdatesToApply is the maximum number of updates until the game loop applies a new refresh rate.
+// Update might be called more than once in one frame.
+const maxUpdatesToApply = 10
+
+type Game struct {
+	index   int
+	updates int
+}
+
+func (g *Game) Update() error {
+	if got, want := ebiten.TPS(), clock.EffectiveTPS(); got != want {
+		return fmt.Errorf("ebiten.TPS(): got: %d, want: %d (clock.EffectiveTPS())", got, want)
+	}
+
+	r := refreshRates[g.index]
+	if ebiten.TPS() != r.TPS {
+		g.updates++
+		if g.updates > maxUpdatesToApply {
+			return fmt.Errorf("refresh rate %v: ebiten.TPS(): got: %d, want: %d", r.RefreshRate, ebiten.TPS(), r.TPS)
+		}
+		return nil
+	}
+
+	g.index++
+	g.updates = 0
+	if g.index >= len(refreshRates) {
+		return ebiten.Termination
+	}
+
+	// The new refresh rate must not be applied until the game loop passes it to the clock.
+	tps := ebiten.TPS()
+	ui.SetRefreshRateForTesting(refreshRates[g.index].RefreshRate)
+	if got := ebiten.TPS(); got != tps {
+		return fmt.Errorf("refresh rate %v: ebiten.TPS() must not change before the next frame: got: %d, want: %d", refreshRates[g.index].RefreshRate, got, tps)
+	}
+	return nil
+}
+
+func (g *Game) Draw(screen *ebiten.Image) {
+}
+
+func (g *Game) Layout(w, h int) (int, int) {
+	return 320, 240
+}
+
+func main() {
+	ebiten.SetTPS(ebiten.SyncWithRefreshRate)
+	ui.SetRefreshRateForTesting(refreshRates[0].RefreshRate)
+	if err := ebiten.RunGame(&Game{}); err != nil {
+		panic(err)
+	}
+}
@@ -430,6 +430,19 @@ func (i *Image) ReadPixels(graphicsDriver graphicsdriver.Graphics, pixels []byte
 	return nil
 }
 
+// ReadPixelsAsync reads the pixels on the given region from GPU asynchronously.
+//
+// callback is called on the rendering thread after the pixels are read.
+func (i *Image) ReadPixelsAsync(pixels []byte, region image.Rectangle, callback func()) {
+	// The pixels on GPU are always the latest, even when the pixels are cached for restoring.
+	i.image.ReadPixelsAsync([]graphicsdriver.PixelsArgs{
+		{
+			Pixels: pixels,
+			Region: region,
+		},
+	}, callback)
+}
+
 // makeStaleIfDependingOn makes the image stale if the image depends on target.
 func (i *Image) makeStaleIfDependingOn(target *Image) {
 	if i.stale {
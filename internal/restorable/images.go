@@ -133,6 +133,16 @@ func DumpImages(graphicsDriver graphicsdriver.Graphics, dir string) (string, err
 	return graphicscommand.DumpImages(images, graphicsDriver, dir)
 }
 
+// FlushCommands flushes the enqueued commands, including disposing commands, and blocks until they are executed.
+func FlushCommands(graphicsDriver graphicsdriver.Graphics) error {
+	if err := graphicscommand.FlushCommands(graphicsDriver, false, nil); err != nil {
+		return err
+	}
+	// Flushing might be asynchronous. Wait for the rendering thread.
+	graphicscommand.WaitForRenderThread()
+	return nil
+}
+
 // add adds img to the images.
 func (i *images) add(img *Image) {
 	i.images[img] = struct{}{}
@@ -201,7 +211,7 @@ func (i *images) restore(graphicsDriver graphicsdriver.Graphics) error {
 
 	// Dispose all the shaders ahead of restoring. A current shader ID and a new shader ID can be duplicated.
 	for s := range i.shaders {
-		s.shader.Dispose()
+		s.shader.DisposeForRestoring()
 		s.shader = nil
 	}
 	for s := range i.shaders {
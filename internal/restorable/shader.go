@@ -46,8 +46,19 @@ func (s *Shader) Dispose() {
 	s.ir = nil
 }
 
+// ShaderCount returns the number of shaders that are created in the graphics driver and not disposed yet.
+func ShaderCount() int {
+	return graphicscommand.ShaderCount()
+}
+
+// PendingShaderDisposalCount returns the number of shaders whose disposals are not done yet in the graphics driver.
+func PendingShaderDisposalCount() int {
+	return graphicscommand.PendingShaderDisposalCount()
+}
+
 func (s *Shader) restore() {
-	s.shader = graphicscommand.NewShader(s.ir)
+	// A restored shader replaces the disposed one. Don't count this as a new shader.
+	s.shader = graphicscommand.NewShaderForRestoring(s.ir)
 }
 
 func (s *Shader) Unit() shaderir.Unit {
@@ -57,12 +68,13 @@ func (s *Shader) Unit() shaderir.Unit {
 var (
 	NearestFilterShader *Shader
 	LinearFilterShader  *Shader
+	FillShader          *Shader
 	clearShader         *Shader
 )
 
 func init() {
 	var wg errgroup.Group
-	var nearestIR, linearIR, clearIR *shaderir.Program
+	var nearestIR, linearIR, fillIR, clearIR *shaderir.Program
 	wg.Go(func() error {
 		ir, err := graphics.CompileShader([]byte(builtinshader.Shader(builtinshader.FilterNearest, builtinshader.AddressUnsafe, false)))
 		if err != nil {
@@ -84,6 +96,20 @@ func init() {
 
 package main
 
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	return color
+}`))
+		if err != nil {
+			return fmt.Errorf("restorable: compiling the fill shader failed: %w", err)
+		}
+		fillIR = ir
+		return nil
+	})
+	wg.Go(func() error {
+		ir, err := graphics.CompileShader([]byte(`//kage:unit pixels
+
+package main
+
 func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
 	return vec4(0)
 }`))
@@ -98,5 +124,7 @@ func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
 	}
 	NearestFilterShader = NewShader(nearestIR)
 	LinearFilterShader = NewShader(linearIR)
+	FillShader = NewShader(fillIR)
+	graphicscommand.SetFillShaderProgram(fillIR)
 	clearShader = NewShader(clearIR)
 }
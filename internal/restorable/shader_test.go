@@ -198,3 +198,40 @@ func TestShaderDispose(t *testing.T) {
 		t.Errorf("got %v, want %v", got, want)
 	}
 }
+
+func TestShaderCountAfterRestoring(t *testing.T) {
+	s := restorable.NewShader(etesting.ShaderProgramFill(0xff, 0, 0, 0xff))
+	if err := restorable.FlushCommands(ui.Get().GraphicsDriverForTesting()); err != nil {
+		t.Fatal(err)
+	}
+	n := restorable.ShaderCount()
+
+	// Restoring recreates all the shaders, but this must not change the counts.
+	if err := restorable.ResolveStaleImages(ui.Get().GraphicsDriverForTesting()); err != nil {
+		t.Fatal(err)
+	}
+	if err := restorable.RestoreIfNeeded(ui.Get().GraphicsDriverForTesting()); err != nil {
+		t.Fatal(err)
+	}
+	// The disposals for restoring are not pending disposals requested by users.
+	if got, want := restorable.PendingShaderDisposalCount(), 0; got != want {
+		t.Errorf("PendingShaderDisposalCount(): got: %d, want: %d", got, want)
+	}
+	if err := restorable.FlushCommands(ui.Get().GraphicsDriverForTesting()); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := restorable.ShaderCount(), n; got != want {
+		t.Errorf("ShaderCount(): got: %d, want: %d", got, want)
+	}
+	if got, want := restorable.PendingShaderDisposalCount(), 0; got != want {
+		t.Errorf("PendingShaderDisposalCount(): got: %d, want: %d", got, want)
+	}
+
+	s.Dispose()
+	if err := restorable.FlushCommands(ui.Get().GraphicsDriverForTesting()); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := restorable.ShaderCount(), n-1; got != want {
+		t.Errorf("ShaderCount(): got: %d, want: %d", got, want)
+	}
+}
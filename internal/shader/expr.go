@@ -161,6 +161,12 @@ func (cs *compileState) parseExpr(block *block, fname string, expr ast.Expr, mar
 			}, []shaderir.Type{t}, stmts, true
 		}
 
+		if op == token.LAND || op == token.LOR {
+			if e, ok := foldLogicalOp(op, lhs[0], rhs[0]); ok {
+				return []shaderir.Expr{e}, []shaderir.Type{t}, stmts, true
+			}
+		}
+
 		return []shaderir.Expr{
 			{
 				Type:  shaderir.Binary,
@@ -400,6 +406,28 @@ func (cs *compileState) parseExpr(block *block, fname string, expr ast.Expr, mar
 					return nil, nil, nil, false
 				}
 				t = shaderir.Type{Main: shaderir.Vec4}
+			case shaderir.TexelAtLod:
+				if len(args) != 3 {
+					cs.addError(e.Pos(), fmt.Sprintf("number of %s's arguments must be 3 but %d", callee.BuiltinFunc, len(args)))
+					return nil, nil, nil, false
+				}
+				if argts[0].Main != shaderir.Texture {
+					cs.addError(e.Pos(), fmt.Sprintf("cannot use %s as texture value in argument to %s", argts[0].String(), callee.BuiltinFunc))
+					return nil, nil, nil, false
+				}
+				if argts[1].Main != shaderir.Vec2 {
+					cs.addError(e.Pos(), fmt.Sprintf("cannot use %s as vec2 value in argument to %s", argts[1].String(), callee.BuiltinFunc))
+					return nil, nil, nil, false
+				}
+				if args[2].Const != nil {
+					args[2].Const = gconstant.ToFloat(args[2].Const)
+					argts[2] = shaderir.Type{Main: shaderir.Float}
+				}
+				if argts[2].Main != shaderir.Float {
+					cs.addError(e.Pos(), fmt.Sprintf("cannot use %s as float value in argument to %s", argts[2].String(), callee.BuiltinFunc))
+					return nil, nil, nil, false
+				}
+				t = shaderir.Type{Main: shaderir.Vec4}
 			case shaderir.DiscardF:
 				if len(args) != 0 {
 					cs.addError(e.Pos(), fmt.Sprintf("number of %s's arguments must be 0 but %d", callee.BuiltinFunc, len(args)))
@@ -994,3 +1022,23 @@ func isValidSwizzling(swizzling string, t shaderir.Type) bool {
 		return false
 	}
 }
+
+// foldLogicalOp folds a logical operator op (&& or ||) when either operand is a boolean constant.
+// For example, `true && x` is folded into `x`, and `false && x` is folded into `false`.
+//
+// An expression doesn't have side effects in Kage, so an operand can be removed safely.
+func foldLogicalOp(op token.Token, lhs, rhs shaderir.Expr) (shaderir.Expr, bool) {
+	c, x := lhs, rhs
+	if c.Const == nil {
+		c, x = rhs, lhs
+	}
+	if c.Const == nil || c.Const.Kind() != gconstant.Bool {
+		return shaderir.Expr{}, false
+	}
+	if gconstant.BoolVal(c.Const) == (op == token.LAND) {
+		// true && x, x && true, false || x, or x || false
+		return x, true
+	}
+	// false && x, x && false, true || x, or x || true
+	return c, true
+}
@@ -28,6 +28,12 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/internal/shaderir"
 )
 
+// CompilerVersion is the version of the compiler.
+//
+// CompilerVersion must be updated whenever the compiler's output for the same source is changed,
+// so that programs compiled by an older compiler and cached somewhere are not used.
+const CompilerVersion = 1
+
 type variable struct {
 	name           string
 	typ            shaderir.Type
@@ -204,6 +204,188 @@ func TestCompile(t *testing.T) {
 			// Just check that Compile doesn't cause panic.
 			// TODO: Should the results be tested?
 			msl.Compile(s, "Vertex", "Fragmentp")
+
+			// The program must be valid after encoding and decoding.
+			data, err := s.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var s2 shaderir.Program
+			if err := s2.UnmarshalBinary(data); err != nil {
+				t.Fatal(err)
+			}
+			vs2, fs2 := glsl.Compile(&s2, glsl.GLSLVersionDefault)
+			if vs2 != vs || fs2 != fs {
+				t.Errorf("GLSL for the decoded program doesn't match")
+			}
 		})
 	}
 }
+
+func TestCompileConstantFolding(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Src  string
+		Want string
+	}{
+		{
+			Name: "if",
+			Src: `package main
+
+const mode = 1
+
+var U float
+
+func Fragment(position vec4) vec4 {
+	c := vec4(0)
+	if mode == 0 {
+		c = vec4(U)
+	} else {
+		c = vec4(1)
+	}
+	return c
+}
+`,
+			Want: `package main
+
+var U float
+
+func Fragment(position vec4) vec4 {
+	c := vec4(0)
+	{
+		c = vec4(1)
+	}
+	return c
+}
+`,
+		},
+		{
+			Name: "if without else",
+			Src: `package main
+
+const debug = false
+
+var U float
+
+func Fragment(position vec4) vec4 {
+	c := vec4(0)
+	if debug {
+		c = vec4(U)
+	}
+	return c
+}
+`,
+			Want: `package main
+
+var U float
+
+func Fragment(position vec4) vec4 {
+	c := vec4(0)
+	return c
+}
+`,
+		},
+		{
+			Name: "else if",
+			Src: `package main
+
+const mode = 2
+
+var U float
+
+func Fragment(position vec4) vec4 {
+	c := vec4(0)
+	if mode == 0 {
+		c = vec4(1)
+	} else if mode == 1 {
+		c = vec4(2)
+	} else {
+		c = vec4(U)
+	}
+	return c
+}
+`,
+			Want: `package main
+
+var U float
+
+func Fragment(position vec4) vec4 {
+	c := vec4(0)
+	{
+		{
+			c = vec4(U)
+		}
+	}
+	return c
+}
+`,
+		},
+		{
+			Name: "logical operators",
+			Src: `package main
+
+const enabled = true
+
+var U float
+
+func Fragment(position vec4) vec4 {
+	c := vec4(0)
+	if enabled && U > 0 {
+		c = vec4(1)
+	}
+	if !enabled || U > 1 {
+		c = vec4(2)
+	}
+	return c
+}
+`,
+			Want: `package main
+
+var U float
+
+func Fragment(position vec4) vec4 {
+	c := vec4(0)
+	if U > 0 {
+		c = vec4(1)
+	}
+	if U > 1 {
+		c = vec4(2)
+	}
+	return c
+}
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			got, err := shader.Compile([]byte(tc.Src), "Vertex", "Fragment", 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := shader.Compile([]byte(tc.Want), "Vertex", "Fragment", 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := got.Dump(), want.Dump(); got != want {
+				compare(t, "Dump", got, want)
+			}
+		})
+	}
+
+	// Errors in a dead branch must be reported.
+	if _, err := shader.Compile([]byte(`package main
+
+const mode = 1
+
+func Fragment(position vec4) vec4 {
+	if mode == 0 {
+		return vec3(0)
+	}
+	return vec4(0)
+}
+`), "Vertex", "Fragment", 0); err == nil {
+		t.Errorf("error must be non-nil but was nil")
+	}
+}
@@ -247,7 +247,8 @@ func (cs *compileState) parseStmt(block *block, fname string, stmt ast.Stmt, inP
 		if !ok {
 			return nil, false
 		}
-		if len(ts) != 1 || ts[0].Main != shaderir.Bool {
+		// An untyped boolean constant is also available as a condition.
+		if len(ts) != 1 || (ts[0].Main != shaderir.Bool && (exprs[0].Const == nil || exprs[0].Const.Kind() != gconstant.Bool)) {
 			var tss []string
 			for _, t := range ts {
 				tss = append(tss, t.String())
@@ -281,6 +282,24 @@ func (cs *compileState) parseStmt(block *block, fname string, stmt ast.Stmt, inP
 			}
 		}
 
+		// Eliminate the statically dead branch when the condition is a constant.
+		// The branches are still parsed above in order to report errors in them.
+		if exprs[0].Const != nil && exprs[0].Const.Kind() == gconstant.Bool {
+			var b *shaderir.Block
+			if gconstant.BoolVal(exprs[0].Const) {
+				b = bs[0]
+			} else if len(bs) > 1 {
+				b = bs[1]
+			}
+			if b != nil {
+				stmts = append(stmts, shaderir.Stmt{
+					Type:   shaderir.BlockStmt,
+					Blocks: []*shaderir.Block{b},
+				})
+			}
+			break
+		}
+
 		stmts = append(stmts, shaderir.Stmt{
 			Type:   shaderir.If,
 			Exprs:  exprs,
@@ -1,7 +1,7 @@
 in vec2 A0;
 
 void main(void) {
-	if (true) {
+	{
 		gl_Position = vec4(A0, 0.0, 1.0);
 		return;
 	}
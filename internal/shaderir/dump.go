@@ -0,0 +1,319 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shaderir
+
+import (
+	"fmt"
+	"go/constant"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Dump returns a textual representation of the program for debugging.
+//
+// The result is stable: the same program always results in the same text, and the items are always in the same order.
+// This is useful to diff two programs.
+//
+// The result includes the uniform variables with their layouts in uint32 values, the attributes, the varyings,
+// and the bodies of the functions and the entry points.
+// The syntax of the function bodies is similar to Go, but this is not a valid Kage program.
+func (p *Program) Dump() string {
+	var lines []string
+
+	switch p.Unit {
+	case Texels:
+		lines = append(lines, "unit texels")
+	case Pixels:
+		lines = append(lines, "unit pixels")
+	default:
+		lines = append(lines, fmt.Sprintf("unit ?(unexpected unit: %d)", p.Unit))
+	}
+
+	lines = append(lines, "", "uniforms {")
+	var offset int
+	for i, t := range p.Uniforms {
+		var name string
+		if i < len(p.UniformNames) {
+			name = p.UniformNames[i]
+		}
+		n := t.Uint32Count()
+		lines = append(lines, fmt.Sprintf("\tU%d %s %s // offset: %d, count: %d", i, name, t.String(), offset, n))
+		offset += n
+	}
+	lines = append(lines, "}")
+
+	lines = append(lines, fmt.Sprintf("textures %d", p.TextureCount))
+
+	lines = append(lines, "", "attributes {")
+	for i, t := range p.Attributes {
+		lines = append(lines, fmt.Sprintf("\tA%d %s", i, t.String()))
+	}
+	lines = append(lines, "}")
+
+	lines = append(lines, "", "varyings {")
+	for i, t := range p.Varyings {
+		lines = append(lines, fmt.Sprintf("\tV%d %s", i, t.String()))
+	}
+	lines = append(lines, "}")
+
+	funcs := make([]*Func, 0, len(p.Funcs))
+	for i := range p.Funcs {
+		funcs = append(funcs, &p.Funcs[i])
+	}
+	sort.SliceStable(funcs, func(i, j int) bool {
+		return funcs[i].Index < funcs[j].Index
+	})
+	for _, f := range funcs {
+		lines = append(lines, "")
+		lines = append(lines, p.dumpFunc(f)...)
+	}
+
+	if p.VertexFunc.Block != nil {
+		lines = append(lines, "", "vertex {")
+		lines = append(lines, p.dumpBlock(p.VertexFunc.Block, p.VertexFunc.Block, 0)...)
+		lines = append(lines, "}")
+	}
+
+	if p.FragmentFunc.Block != nil {
+		lines = append(lines, "", "fragment {")
+		lines = append(lines, p.dumpBlock(p.FragmentFunc.Block, p.FragmentFunc.Block, 0)...)
+		lines = append(lines, "}")
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func (p *Program) dumpFunc(f *Func) []string {
+	var params []string
+	var idx int
+	for _, t := range f.InParams {
+		params = append(params, fmt.Sprintf("l%d %s", idx, t.String()))
+		idx++
+	}
+	for _, t := range f.OutParams {
+		params = append(params, fmt.Sprintf("out l%d %s", idx, t.String()))
+		idx++
+	}
+	sig := fmt.Sprintf("func F%d(%s)", f.Index, strings.Join(params, ", "))
+	if f.Return.Main != None {
+		sig += " " + f.Return.String()
+	}
+
+	lines := []string{sig + " {"}
+	lines = append(lines, p.dumpBlock(f.Block, f.Block, 0)...)
+	lines = append(lines, "}")
+	return lines
+}
+
+func (p *Program) dumpLocalVariableName(topBlock *Block, idx int) string {
+	switch topBlock {
+	case p.VertexFunc.Block:
+		na := len(p.Attributes)
+		nv := len(p.Varyings)
+		switch {
+		case idx < na:
+			return fmt.Sprintf("A%d", idx)
+		case idx == na:
+			return "position"
+		case idx < na+nv+1:
+			return fmt.Sprintf("V%d", idx-na-1)
+		default:
+			return fmt.Sprintf("l%d", idx-(na+nv+1))
+		}
+	case p.FragmentFunc.Block:
+		nv := len(p.Varyings)
+		switch {
+		case idx == 0:
+			return "fragCoord"
+		case idx < nv+1:
+			return fmt.Sprintf("V%d", idx-1)
+		default:
+			return fmt.Sprintf("l%d", idx-(nv+1))
+		}
+	default:
+		return fmt.Sprintf("l%d", idx)
+	}
+}
+
+func dumpConstant(v constant.Value) string {
+	switch v.Kind() {
+	case constant.Bool:
+		if constant.BoolVal(v) {
+			return "true"
+		}
+		return "false"
+	case constant.Int:
+		x, _ := constant.Int64Val(v)
+		return strconv.FormatInt(x, 10)
+	case constant.Float:
+		x, _ := constant.Float64Val(v)
+		if i := math.Floor(x); i == x && math.Abs(x) < 1e15 {
+			return strconv.FormatFloat(x, 'f', 1, 64)
+		}
+		return strconv.FormatFloat(x, 'g', -1, 64)
+	}
+	return fmt.Sprintf("?(unexpected constant: %s)", v)
+}
+
+func dumpOp(op Op) string {
+	switch op {
+	case Add:
+		return "+"
+	case Sub:
+		return "-"
+	case NotOp:
+		return "!"
+	case ComponentWiseMul:
+		return "*"
+	case MatrixMul:
+		return "matmul"
+	case Div:
+		return "/"
+	case ModOp:
+		return "%"
+	case LeftShift:
+		return "<<"
+	case RightShift:
+		return ">>"
+	case LessThanOp:
+		return "<"
+	case LessThanEqualOp:
+		return "<="
+	case GreaterThanOp:
+		return ">"
+	case GreaterThanEqualOp:
+		return ">="
+	case EqualOp:
+		return "=="
+	case NotEqualOp:
+		return "!="
+	case VectorEqualOp:
+		return "vec=="
+	case VectorNotEqualOp:
+		return "vec!="
+	case And:
+		return "&"
+	case Xor:
+		return "^"
+	case Or:
+		return "|"
+	case AndAnd:
+		return "&&"
+	case OrOr:
+		return "||"
+	}
+	return fmt.Sprintf("?(unexpected op: %d)", op)
+}
+
+func (p *Program) dumpExpr(topBlock *Block, e *Expr) string {
+	switch e.Type {
+	case Blank:
+		return "_"
+	case NumberExpr:
+		return dumpConstant(e.Const)
+	case UniformVariable:
+		return fmt.Sprintf("U%d", e.Index)
+	case TextureVariable:
+		return fmt.Sprintf("T%d", e.Index)
+	case LocalVariable:
+		return p.dumpLocalVariableName(topBlock, e.Index)
+	case StructMember:
+		return fmt.Sprintf("M%d", e.Index)
+	case BuiltinFuncExpr:
+		return string(e.BuiltinFunc)
+	case SwizzlingExpr:
+		return e.Swizzling
+	case FunctionExpr:
+		return fmt.Sprintf("F%d", e.Index)
+	case Unary:
+		return fmt.Sprintf("%s(%s)", dumpOp(e.Op), p.dumpExpr(topBlock, &e.Exprs[0]))
+	case Binary:
+		return fmt.Sprintf("(%s) %s (%s)", p.dumpExpr(topBlock, &e.Exprs[0]), dumpOp(e.Op), p.dumpExpr(topBlock, &e.Exprs[1]))
+	case Selection:
+		return fmt.Sprintf("(%s) ? (%s) : (%s)", p.dumpExpr(topBlock, &e.Exprs[0]), p.dumpExpr(topBlock, &e.Exprs[1]), p.dumpExpr(topBlock, &e.Exprs[2]))
+	case Call:
+		args := make([]string, 0, len(e.Exprs)-1)
+		for i := range e.Exprs[1:] {
+			args = append(args, p.dumpExpr(topBlock, &e.Exprs[i+1]))
+		}
+		return fmt.Sprintf("%s(%s)", p.dumpExpr(topBlock, &e.Exprs[0]), strings.Join(args, ", "))
+	case FieldSelector:
+		return fmt.Sprintf("(%s).%s", p.dumpExpr(topBlock, &e.Exprs[0]), p.dumpExpr(topBlock, &e.Exprs[1]))
+	case Index:
+		return fmt.Sprintf("(%s)[%s]", p.dumpExpr(topBlock, &e.Exprs[0]), p.dumpExpr(topBlock, &e.Exprs[1]))
+	}
+	return fmt.Sprintf("?(unexpected expr: %d)", e.Type)
+}
+
+func (p *Program) dumpBlock(topBlock, block *Block, level int) []string {
+	if block == nil {
+		return nil
+	}
+
+	idt := strings.Repeat("\t", level+1)
+
+	var lines []string
+	for i, t := range block.LocalVars {
+		lines = append(lines, fmt.Sprintf("%svar %s %s", idt, p.dumpLocalVariableName(topBlock, block.LocalVarIndexOffset+i), t.String()))
+	}
+
+	for _, s := range block.Stmts {
+		switch s.Type {
+		case ExprStmt:
+			lines = append(lines, idt+p.dumpExpr(topBlock, &s.Exprs[0]))
+		case BlockStmt:
+			lines = append(lines, idt+"{")
+			lines = append(lines, p.dumpBlock(topBlock, s.Blocks[0], level+1)...)
+			lines = append(lines, idt+"}")
+		case Assign:
+			lines = append(lines, fmt.Sprintf("%s%s = %s", idt, p.dumpExpr(topBlock, &s.Exprs[0]), p.dumpExpr(topBlock, &s.Exprs[1])))
+		case Init:
+			lines = append(lines, fmt.Sprintf("%sinit %s", idt, p.dumpLocalVariableName(topBlock, s.InitIndex)))
+		case If:
+			lines = append(lines, fmt.Sprintf("%sif %s {", idt, p.dumpExpr(topBlock, &s.Exprs[0])))
+			lines = append(lines, p.dumpBlock(topBlock, s.Blocks[0], level+1)...)
+			if len(s.Blocks) > 1 {
+				lines = append(lines, idt+"} else {")
+				lines = append(lines, p.dumpBlock(topBlock, s.Blocks[1], level+1)...)
+			}
+			lines = append(lines, idt+"}")
+		case For:
+			v := p.dumpLocalVariableName(topBlock, s.ForVarIndex)
+			lines = append(lines, fmt.Sprintf("%sfor %s := %s(%s); %s %s %s; %s += %s {", idt,
+				v, s.ForVarType.String(), dumpConstant(s.ForInit),
+				v, dumpOp(s.ForOp), dumpConstant(s.ForEnd),
+				v, dumpConstant(s.ForDelta)))
+			lines = append(lines, p.dumpBlock(topBlock, s.Blocks[0], level+1)...)
+			lines = append(lines, idt+"}")
+		case Continue:
+			lines = append(lines, idt+"continue")
+		case Break:
+			lines = append(lines, idt+"break")
+		case Return:
+			if len(s.Exprs) == 0 {
+				lines = append(lines, idt+"return")
+			} else {
+				lines = append(lines, fmt.Sprintf("%sreturn %s", idt, p.dumpExpr(topBlock, &s.Exprs[0])))
+			}
+		case Discard:
+			lines = append(lines, idt+"discard")
+		default:
+			lines = append(lines, fmt.Sprintf("%s?(unexpected stmt: %d)", idt, s.Type))
+		}
+	}
+	return lines
+}
@@ -0,0 +1,423 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shaderir
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"go/constant"
+	"go/token"
+	"math/big"
+)
+
+// encodingMagic is the header of an encoded program.
+const encodingMagic = "ebitengine-shaderir"
+
+// EncodingVersion is the version of the encoding format of a program.
+//
+// EncodingVersion must be updated whenever the structure of Program or the encoding format is changed,
+// so that data encoded by an older version is rejected.
+const EncodingVersion = 1
+
+// encodedProgram is a mirror of Program that can be encoded by encoding/gob.
+type encodedProgram struct {
+	UniformNames []string
+	Uniforms     []Type
+	TextureCount int
+	Attributes   []Type
+	Varyings     []Type
+	Funcs        []encodedFunc
+	VertexFunc   *encodedBlock
+	FragmentFunc *encodedBlock
+	Unit         Unit
+}
+
+type encodedFunc struct {
+	Index     int
+	InParams  []Type
+	OutParams []Type
+	Return    Type
+	Block     *encodedBlock
+}
+
+type encodedBlock struct {
+	LocalVars           []Type
+	LocalVarIndexOffset int
+	Stmts               []encodedStmt
+}
+
+type encodedStmt struct {
+	Type        StmtType
+	Exprs       []encodedExpr
+	Blocks      []*encodedBlock
+	ForVarType  Type
+	ForVarIndex int
+	ForInit     *encodedConst
+	ForEnd      *encodedConst
+	ForOp       Op
+	ForDelta    *encodedConst
+	InitIndex   int
+}
+
+type encodedExpr struct {
+	Type        ExprType
+	Exprs       []encodedExpr
+	Const       *encodedConst
+	BuiltinFunc BuiltinFunc
+	Swizzling   string
+	Index       int
+	Op          Op
+}
+
+type encodedConst struct {
+	Kind  constant.Kind
+	Value string
+}
+
+// MarshalBinary encodes the program into bytes.
+//
+// The result starts with a header including EncodingVersion.
+func (p *Program) MarshalBinary() ([]byte, error) {
+	ep := encodedProgram{
+		UniformNames: p.UniformNames,
+		Uniforms:     p.Uniforms,
+		TextureCount: p.TextureCount,
+		Attributes:   p.Attributes,
+		Varyings:     p.Varyings,
+		VertexFunc:   encodeBlock(p.VertexFunc.Block),
+		FragmentFunc: encodeBlock(p.FragmentFunc.Block),
+		Unit:         p.Unit,
+	}
+	for _, f := range p.Funcs {
+		ep.Funcs = append(ep.Funcs, encodedFunc{
+			Index:     f.Index,
+			InParams:  f.InParams,
+			OutParams: f.OutParams,
+			Return:    f.Return,
+			Block:     encodeBlock(f.Block),
+		})
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(encodingMagic)
+	if err := binary.Write(&buf, binary.LittleEndian, uint32(EncodingVersion)); err != nil {
+		return nil, err
+	}
+	if err := gob.NewEncoder(&buf).Encode(&ep); err != nil {
+		return nil, fmt.Errorf("shaderir: encoding a program failed: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes the bytes encoded by MarshalBinary and sets the result to p.
+//
+// UnmarshalBinary returns an error if the data is broken or the data's version doesn't match EncodingVersion.
+// The decoded program is validated, and UnmarshalBinary returns an error if it has inconsistent references.
+func (p *Program) UnmarshalBinary(data []byte) error {
+	if !bytes.HasPrefix(data, []byte(encodingMagic)) {
+		return fmt.Errorf("shaderir: the data is not an encoded program")
+	}
+	data = data[len(encodingMagic):]
+	if len(data) < 4 {
+		return fmt.Errorf("shaderir: the data is too short")
+	}
+	if v := binary.LittleEndian.Uint32(data); v != EncodingVersion {
+		return fmt.Errorf("shaderir: the encoding version %d doesn't match the current version %d", v, EncodingVersion)
+	}
+	data = data[4:]
+
+	var ep encodedProgram
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&ep); err != nil {
+		return fmt.Errorf("shaderir: decoding a program failed: %w", err)
+	}
+
+	np := Program{
+		UniformNames: ep.UniformNames,
+		Uniforms:     ep.Uniforms,
+		TextureCount: ep.TextureCount,
+		Attributes:   ep.Attributes,
+		Varyings:     ep.Varyings,
+		Unit:         ep.Unit,
+	}
+	var err error
+	if np.VertexFunc.Block, err = decodeBlock(ep.VertexFunc); err != nil {
+		return err
+	}
+	if np.FragmentFunc.Block, err = decodeBlock(ep.FragmentFunc); err != nil {
+		return err
+	}
+	for _, f := range ep.Funcs {
+		b, err := decodeBlock(f.Block)
+		if err != nil {
+			return err
+		}
+		np.Funcs = append(np.Funcs, Func{
+			Index:     f.Index,
+			InParams:  f.InParams,
+			OutParams: f.OutParams,
+			Return:    f.Return,
+			Block:     b,
+		})
+	}
+
+	if err := np.validate(); err != nil {
+		return err
+	}
+
+	*p = np
+	return nil
+}
+
+// validate checks that the references in the program are consistent,
+// so that a program decoded from broken data doesn't make a backend panic.
+func (p *Program) validate() error {
+	if p.Unit != Texels && p.Unit != Pixels {
+		return fmt.Errorf("shaderir: invalid unit: %d", p.Unit)
+	}
+	if len(p.UniformNames) != len(p.Uniforms) {
+		return fmt.Errorf("shaderir: the numbers of the uniform names and the uniforms don't match")
+	}
+	if p.TextureCount < 0 {
+		return fmt.Errorf("shaderir: invalid texture count: %d", p.TextureCount)
+	}
+	for i, f := range p.Funcs {
+		if f.Index != i {
+			return fmt.Errorf("shaderir: invalid function index: %d", f.Index)
+		}
+		if err := p.validateBlock(f.Block); err != nil {
+			return err
+		}
+	}
+	if err := p.validateBlock(p.VertexFunc.Block); err != nil {
+		return err
+	}
+	if err := p.validateBlock(p.FragmentFunc.Block); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *Program) validateBlock(b *Block) error {
+	if b == nil {
+		return nil
+	}
+	if b.LocalVarIndexOffset < 0 {
+		return fmt.Errorf("shaderir: invalid local variable index offset: %d", b.LocalVarIndexOffset)
+	}
+	for _, s := range b.Stmts {
+		if err := p.validateExprs(s.Exprs); err != nil {
+			return err
+		}
+		for _, b := range s.Blocks {
+			if err := p.validateBlock(b); err != nil {
+				return err
+			}
+		}
+		switch s.Type {
+		case If:
+			if len(s.Exprs) != 1 || len(s.Blocks) == 0 || len(s.Blocks) > 2 {
+				return fmt.Errorf("shaderir: invalid if statement")
+			}
+		case For:
+			if len(s.Blocks) != 1 || s.ForInit == nil || s.ForEnd == nil || s.ForDelta == nil {
+				return fmt.Errorf("shaderir: invalid for statement")
+			}
+		}
+	}
+	return nil
+}
+
+func (p *Program) validateExprs(exprs []Expr) error {
+	for _, e := range exprs {
+		switch e.Type {
+		case NumberExpr:
+			if e.Const == nil {
+				return fmt.Errorf("shaderir: a number expression doesn't have a constant")
+			}
+		case UniformVariable:
+			if e.Index < 0 || e.Index >= len(p.Uniforms) {
+				return fmt.Errorf("shaderir: invalid uniform variable index: %d", e.Index)
+			}
+		case TextureVariable:
+			if e.Index < 0 || e.Index >= p.TextureCount {
+				return fmt.Errorf("shaderir: invalid texture variable index: %d", e.Index)
+			}
+		case LocalVariable:
+			if e.Index < 0 {
+				return fmt.Errorf("shaderir: invalid local variable index: %d", e.Index)
+			}
+		case FunctionExpr:
+			if e.Index < 0 || e.Index >= len(p.Funcs) {
+				return fmt.Errorf("shaderir: invalid function index: %d", e.Index)
+			}
+		}
+		if err := p.validateExprs(e.Exprs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeBlock(b *Block) *encodedBlock {
+	if b == nil {
+		return nil
+	}
+	eb := &encodedBlock{
+		LocalVars:           b.LocalVars,
+		LocalVarIndexOffset: b.LocalVarIndexOffset,
+	}
+	for _, s := range b.Stmts {
+		es := encodedStmt{
+			Type:        s.Type,
+			Exprs:       encodeExprs(s.Exprs),
+			ForVarType:  s.ForVarType,
+			ForVarIndex: s.ForVarIndex,
+			ForInit:     encodeConst(s.ForInit),
+			ForEnd:      encodeConst(s.ForEnd),
+			ForOp:       s.ForOp,
+			ForDelta:    encodeConst(s.ForDelta),
+			InitIndex:   s.InitIndex,
+		}
+		for _, b := range s.Blocks {
+			es.Blocks = append(es.Blocks, encodeBlock(b))
+		}
+		eb.Stmts = append(eb.Stmts, es)
+	}
+	return eb
+}
+
+func encodeExprs(exprs []Expr) []encodedExpr {
+	if len(exprs) == 0 {
+		return nil
+	}
+	ees := make([]encodedExpr, 0, len(exprs))
+	for _, e := range exprs {
+		ees = append(ees, encodedExpr{
+			Type:        e.Type,
+			Exprs:       encodeExprs(e.Exprs),
+			Const:       encodeConst(e.Const),
+			BuiltinFunc: e.BuiltinFunc,
+			Swizzling:   e.Swizzling,
+			Index:       e.Index,
+			Op:          e.Op,
+		})
+	}
+	return ees
+}
+
+func encodeConst(v constant.Value) *encodedConst {
+	if v == nil {
+		return nil
+	}
+	return &encodedConst{
+		Kind:  v.Kind(),
+		Value: v.ExactString(),
+	}
+}
+
+func decodeBlock(eb *encodedBlock) (*Block, error) {
+	if eb == nil {
+		return nil, nil
+	}
+	b := &Block{
+		LocalVars:           eb.LocalVars,
+		LocalVarIndexOffset: eb.LocalVarIndexOffset,
+	}
+	for _, es := range eb.Stmts {
+		exprs, err := decodeExprs(es.Exprs)
+		if err != nil {
+			return nil, err
+		}
+		s := Stmt{
+			Type:        es.Type,
+			Exprs:       exprs,
+			ForVarType:  es.ForVarType,
+			ForVarIndex: es.ForVarIndex,
+			ForOp:       es.ForOp,
+			InitIndex:   es.InitIndex,
+		}
+		if s.ForInit, err = decodeConst(es.ForInit); err != nil {
+			return nil, err
+		}
+		if s.ForEnd, err = decodeConst(es.ForEnd); err != nil {
+			return nil, err
+		}
+		if s.ForDelta, err = decodeConst(es.ForDelta); err != nil {
+			return nil, err
+		}
+		for _, eb := range es.Blocks {
+			b, err := decodeBlock(eb)
+			if err != nil {
+				return nil, err
+			}
+			s.Blocks = append(s.Blocks, b)
+		}
+		b.Stmts = append(b.Stmts, s)
+	}
+	return b, nil
+}
+
+func decodeExprs(ees []encodedExpr) ([]Expr, error) {
+	if len(ees) == 0 {
+		return nil, nil
+	}
+	exprs := make([]Expr, 0, len(ees))
+	for _, ee := range ees {
+		sub, err := decodeExprs(ee.Exprs)
+		if err != nil {
+			return nil, err
+		}
+		c, err := decodeConst(ee.Const)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, Expr{
+			Type:        ee.Type,
+			Exprs:       sub,
+			Const:       c,
+			BuiltinFunc: ee.BuiltinFunc,
+			Swizzling:   ee.Swizzling,
+			Index:       ee.Index,
+			Op:          ee.Op,
+		})
+	}
+	return exprs, nil
+}
+
+func decodeConst(ec *encodedConst) (constant.Value, error) {
+	if ec == nil {
+		return nil, nil
+	}
+	switch ec.Kind {
+	case constant.Bool:
+		return constant.MakeBool(ec.Value == "true"), nil
+	case constant.Int:
+		v := constant.MakeFromLiteral(ec.Value, token.INT, 0)
+		if v.Kind() != constant.Int {
+			return nil, fmt.Errorf("shaderir: invalid integer constant: %s", ec.Value)
+		}
+		return v, nil
+	case constant.Float:
+		r, ok := new(big.Rat).SetString(ec.Value)
+		if !ok {
+			return nil, fmt.Errorf("shaderir: invalid float constant: %s", ec.Value)
+		}
+		return constant.ToFloat(constant.Make(r)), nil
+	default:
+		return nil, fmt.Errorf("shaderir: unexpected constant kind: %d", ec.Kind)
+	}
+}
@@ -126,6 +126,12 @@ func (c *compileContext) builtinFuncString(f shaderir.BuiltinFunc) string {
 			return "texelFetch"
 		}
 		return "texture"
+	case shaderir.TexelAtLod:
+		// In pixels, a texel is fetched exactly without sampling, and the level of detail is not used.
+		if c.unit == shaderir.Pixels {
+			return "texelFetch"
+		}
+		return "textureLod"
 	default:
 		return string(f)
 	}
@@ -478,6 +478,16 @@ func (c *compileContext) block(p *shaderir.Program, topBlock, block *shaderir.Bl
 					default:
 						panic(fmt.Sprintf("hlsl: unexpected unit: %d", p.Unit))
 					}
+				case shaderir.TexelAtLod:
+					switch c.unit {
+					case shaderir.Pixels:
+						// In pixels, a texel is loaded exactly without sampling, and the level of detail is not used.
+						return fmt.Sprintf("%s.Load(int3(%s, 0))", args[0], args[1])
+					case shaderir.Texels:
+						return fmt.Sprintf("%s.SampleLevel(samp, %s, %s)", args[0], args[1], args[2])
+					default:
+						panic(fmt.Sprintf("hlsl: unexpected unit: %d", p.Unit))
+					}
 				}
 			}
 			return fmt.Sprintf("%s(%s)", expr(&e.Exprs[0]), strings.Join(args, ", "))
@@ -43,7 +43,7 @@ func calculateMemoryOffsets(uniforms []shaderir.Type) []int {
 		case shaderir.Float:
 			offsets = append(offsets, head)
 			head += 4
-		case shaderir.Int:
+		case shaderir.Bool, shaderir.Int:
 			offsets = append(offsets, head)
 			head += 4
 		case shaderir.Vec2, shaderir.IVec2:
@@ -145,6 +145,8 @@ func (c *compileContext) builtinFuncString(f shaderir.BuiltinFunc) string {
 		return "ddy"
 	case shaderir.TexelAt:
 		return "?(__texelAt)"
+	case shaderir.TexelAtLod:
+		return "?(__texelAtLod)"
 	default:
 		return string(f)
 	}
@@ -1099,3 +1099,60 @@ void main(void) {
 		})
 	}
 }
+
+func TestUnmarshalBinaryValidation(t *testing.T) {
+	for _, tc := range []struct {
+		Name    string
+		Program shaderir.Program
+		Valid   bool
+	}{
+		{
+			Name: "valid",
+			Program: shaderir.Program{
+				Uniforms:     []shaderir.Type{{Main: shaderir.Float}},
+				UniformNames: []string{"U0"},
+				FragmentFunc: shaderir.FragmentFunc{
+					Block: block(nil, 0, exprStmt(uniformVariableExpr(0))),
+				},
+			},
+			Valid: true,
+		},
+		{
+			Name: "uniform index out of range",
+			Program: shaderir.Program{
+				FragmentFunc: shaderir.FragmentFunc{
+					Block: block(nil, 0, exprStmt(uniformVariableExpr(0))),
+				},
+			},
+		},
+		{
+			Name: "function index out of range",
+			Program: shaderir.Program{
+				FragmentFunc: shaderir.FragmentFunc{
+					Block: block(nil, 0, exprStmt(callExpr(functionExpr(1)))),
+				},
+			},
+		},
+		{
+			Name: "uniform names mismatch",
+			Program: shaderir.Program{
+				Uniforms: []shaderir.Type{{Main: shaderir.Float}},
+			},
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			data, err := tc.Program.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var p shaderir.Program
+			err = p.UnmarshalBinary(data)
+			if tc.Valid && err != nil {
+				t.Errorf("UnmarshalBinary must succeed but: %v", err)
+			}
+			if !tc.Valid && err == nil {
+				t.Errorf("UnmarshalBinary must return an error but not")
+			}
+		})
+	}
+}
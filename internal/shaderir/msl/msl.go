@@ -404,6 +404,17 @@ func (c *compileContext) block(p *shaderir.Program, topBlock, block *shaderir.Bl
 					panic(fmt.Sprintf("msl: unexpected unit: %d", p.Unit))
 				}
 			}
+			if callee.Type == shaderir.BuiltinFuncExpr && callee.BuiltinFunc == shaderir.TexelAtLod {
+				switch p.Unit {
+				case shaderir.Texels:
+					return fmt.Sprintf("%s.sample(texture_sampler, %s, level(%s))", args[0], args[1], args[2])
+				case shaderir.Pixels:
+					// In pixels, a texel is read exactly without sampling, and the level of detail is not used.
+					return fmt.Sprintf("%s.read(static_cast<uint2>(%s))", args[0], args[1])
+				default:
+					panic(fmt.Sprintf("msl: unexpected unit: %d", p.Unit))
+				}
+			}
 			return fmt.Sprintf("%s(%s)", expr(&callee), strings.Join(args, ", "))
 		case shaderir.FieldSelector:
 			return fmt.Sprintf("(%s).%s", expr(&e.Exprs[0]), expr(&e.Exprs[1]))
@@ -151,6 +151,8 @@ func builtinFuncString(f shaderir.BuiltinFunc) string {
 		return "rsqrt"
 	case shaderir.TexelAt:
 		return "?(__texelAt)"
+	case shaderir.TexelAtLod:
+		return "?(__texelAtLod)"
 	}
 	return string(f)
 }
@@ -274,6 +274,7 @@ const (
 	Fwidth      BuiltinFunc = "fwidth"
 	DiscardF    BuiltinFunc = "discard"
 	TexelAt     BuiltinFunc = "__texelAt"
+	TexelAtLod  BuiltinFunc = "__texelAtLod"
 )
 
 func ParseBuiltinFunc(str string) (BuiltinFunc, bool) {
@@ -331,7 +332,8 @@ func ParseBuiltinFunc(str string) (BuiltinFunc, bool) {
 		Dfdy,
 		Fwidth,
 		DiscardF,
-		TexelAt:
+		TexelAt,
+		TexelAtLod:
 		return BuiltinFunc(str), true
 	}
 	return "", false
@@ -89,6 +89,8 @@ func (t *Type) String() string {
 
 func (t *Type) Uint32Count() int {
 	switch t.Main {
+	case Bool:
+		return 1
 	case Int:
 		return 1
 	case Float:
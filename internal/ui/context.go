@@ -27,6 +27,7 @@ import (
 var (
 	NearestFilterShader = &Shader{shader: atlas.NearestFilterShader}
 	LinearFilterShader  = &Shader{shader: atlas.LinearFilterShader}
+	FillShader          = &Shader{shader: atlas.FillShader}
 )
 
 type Game interface {
@@ -259,6 +260,16 @@ func (c *context) clientPositionToLogicalPosition(x, y float64, deviceScaleFacto
 	return (x*deviceScaleFactor - ox) / s, (y*deviceScaleFactor - oy) / s
 }
 
+// clientLengthToLogicalLength converts a length like a distance or a radius in the client coordinate to the logical coordinate.
+func (c *context) clientLengthToLogicalLength(l float64, deviceScaleFactor float64) float64 {
+	s, _, _ := c.screenScaleAndOffsets()
+	// The scale 0 indicates that the screen is not initialized yet.
+	if s == 0 {
+		return 0
+	}
+	return l * deviceScaleFactor / s
+}
+
 func (c *context) logicalPositionToClientPosition(x, y float64, deviceScaleFactor float64) (float64, float64) {
 	s, ox, oy := c.screenScaleAndOffsets()
 	return (x*s + ox) / deviceScaleFactor, (y*s + oy) / deviceScaleFactor
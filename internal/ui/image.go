@@ -94,7 +94,7 @@ func (i *Image) DrawTriangles(srcs [graphics.ShaderImageCount]*Image, vertices [
 		if i.bigOffscreenBuffer == nil {
 			var imageType atlas.ImageType
 			switch i.imageType {
-			case atlas.ImageTypeRegular, atlas.ImageTypeUnmanaged:
+			case atlas.ImageTypeRegular, atlas.ImageTypeUnmanaged, atlas.ImageTypeAtlasPreferred:
 				imageType = atlas.ImageTypeUnmanaged
 			case atlas.ImageTypeScreen, atlas.ImageTypeVolatile:
 				imageType = atlas.ImageTypeVolatile
@@ -176,6 +176,21 @@ func (i *Image) ReadPixels(pixels []byte, region image.Rectangle) {
 	}
 }
 
+// ReadPixelsAsync reads the pixels on the given region asynchronously.
+//
+// callback is called after the pixels are read, on the rendering thread or on the current goroutine.
+// If an error happened, callback is never called, and the error is reported by the main loop.
+func (i *Image) ReadPixelsAsync(pixels []byte, region image.Rectangle, callback func()) {
+	// Check the error existence and avoid unnecessary calls.
+	if i.ui.error() != nil {
+		return
+	}
+
+	i.flushBigOffscreenBufferIfNeeded()
+	i.flushDotsBufferIfNeeded()
+	i.mipmap.ReadPixelsAsync(pixels, region, callback)
+}
+
 func (i *Image) DumpScreenshot(name string, blackbg bool) (string, error) {
 	i.flushBufferIfNeeded()
 	return i.ui.dumpScreenshot(i.mipmap, name, blackbg)
@@ -293,6 +308,24 @@ func (i *Image) Fill(r, g, b, a float32, region image.Rectangle) {
 	i.DrawTriangles(srcs, i.tmpVerticesForFill, is, blend, region, [graphics.ShaderImageCount]image.Rectangle{}, NearestFilterShader, nil, graphicsdriver.FillAll, true, false)
 }
 
+// ClearRegion replaces the pixels in region with the given premultiplied color.
+//
+// Unlike Fill, ClearRegion uses the fill shader so that the graphics driver can execute this as a clear command.
+func (i *Image) ClearRegion(r, g, b, a float32, region image.Rectangle) {
+	if len(i.tmpVerticesForFill) < 4*graphics.VertexFloatCount {
+		i.tmpVerticesForFill = make([]float32, 4*graphics.VertexFloatCount)
+	}
+	// i.tmpVerticesForFill can be reused as this is sent to DrawTriangles immediately.
+	graphics.QuadVertices(
+		i.tmpVerticesForFill,
+		0, 0, float32(region.Dx()), float32(region.Dy()),
+		1, 0, 0, 1, float32(region.Min.X), float32(region.Min.Y),
+		r, g, b, a)
+	is := graphics.QuadIndices()
+
+	i.DrawTriangles([graphics.ShaderImageCount]*Image{}, i.tmpVerticesForFill, is, graphicsdriver.BlendCopy, region, [graphics.ShaderImageCount]image.Rectangle{}, FillShader, nil, graphicsdriver.FillAll, true, false)
+}
+
 type bigOffscreenImage struct {
 	ui *UserInterface
 
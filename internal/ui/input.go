@@ -15,10 +15,13 @@
 package ui
 
 import (
+	"errors"
 	"io/fs"
 	"unicode"
 )
 
+var errClipboardNotSupported = errors.New("ui: the clipboard is not supported in this environment")
+
 type MouseButton int
 
 const (
@@ -36,6 +39,12 @@ type Touch struct {
 	ID TouchID
 	X  int
 	Y  int
+
+	// Pressure is the normalized pressure of the touch. Pressure is 1 if the environment doesn't report pressures.
+	Pressure float64
+
+	// Radius is the radius of the contact area in the logical coordinate. Radius is 0 if the environment doesn't report radii.
+	Radius float64
 }
 
 type InputState struct {
@@ -43,12 +52,16 @@ type InputState struct {
 	MouseButtonPressed [MouseButtonMax + 1]bool
 	CursorX            float64
 	CursorY            float64
+	CursorDeltaX       float64
+	CursorDeltaY       float64
 	WheelX             float64
 	WheelY             float64
 	Touches            []Touch
 	Runes              []rune
 	WindowBeingClosed  bool
 	DroppedFiles       fs.FS
+	DroppedFilesX      float64
+	DroppedFilesY      float64
 }
 
 func (i *InputState) copyAndReset(dst *InputState) {
@@ -56,14 +69,20 @@ func (i *InputState) copyAndReset(dst *InputState) {
 	dst.MouseButtonPressed = i.MouseButtonPressed
 	dst.CursorX = i.CursorX
 	dst.CursorY = i.CursorY
+	dst.CursorDeltaX = i.CursorDeltaX
+	dst.CursorDeltaY = i.CursorDeltaY
 	dst.WheelX = i.WheelX
 	dst.WheelY = i.WheelY
 	dst.Touches = append(dst.Touches[:0], i.Touches...)
 	dst.Runes = append(dst.Runes[:0], i.Runes...)
 	dst.WindowBeingClosed = i.WindowBeingClosed
 	dst.DroppedFiles = i.DroppedFiles
+	dst.DroppedFilesX = i.DroppedFilesX
+	dst.DroppedFilesY = i.DroppedFilesY
 
 	// Reset the members that are updated by deltas, rather than absolute values.
+	i.CursorDeltaX = 0
+	i.CursorDeltaY = 0
 	i.WheelX = 0
 	i.WheelY = 0
 	i.Runes = i.Runes[:0]
@@ -12,12 +12,15 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-//go:build !android && !ios && !js && !nintendosdk && !playstation5
+//go:build !android && !ios && !js && !nintendosdk && !playstation5 && !ebitengineheadless
 
 package ui
 
 import (
+	"errors"
+	"image"
 	"math"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/gamepad"
 	"github.com/hajimehoshi/ebiten/v2/internal/glfw"
@@ -57,11 +60,139 @@ func (u *UserInterface) registerInputCallbacks() error {
 func (u *UserInterface) updateInputState() error {
 	var err error
 	u.mainThread.Call(func() {
-		err = u.updateInputStateImpl()
+		if err = u.updateInputStateImpl(); err != nil {
+			return
+		}
+		// Update the window dragging without locking u.m, as manipulating the window can invoke callbacks.
+		err = u.updateWindowDragging()
 	})
 	return err
 }
 
+// dragRegionDoubleClickInterval is the maximum interval between two clicks on a drag region to be treated as a double click.
+const dragRegionDoubleClickInterval = 500 * time.Millisecond
+
+// updateWindowDragging moves the window when the left mouse button is dragged on a drag region,
+// and toggles the maximized state when a drag region is double-clicked.
+//
+// updateWindowDragging must be called from the main thread.
+func (u *UserInterface) updateWindowDragging() error {
+	a, err := u.window.GetMouseButton(glfw.MouseButtonLeft)
+	if err != nil {
+		return err
+	}
+	pressed := a == glfw.Press
+	justPressed := pressed && !u.dragRegionPressed
+	u.dragRegionPressed = pressed
+
+	if !pressed {
+		u.windowDragging = false
+		return nil
+	}
+
+	if u.windowDragging {
+		wx, wy, err := u.window.GetPos()
+		if err != nil {
+			return err
+		}
+		cx, cy, err := u.window.GetCursorPos()
+		if err != nil {
+			return err
+		}
+		// Use the cursor position in the screen coordinate, as the window moves.
+		dx := float64(wx) + cx - u.windowDragStartCursorX
+		dy := float64(wy) + cy - u.windowDragStartCursorY
+		if dx == 0 && dy == 0 {
+			return nil
+		}
+		if err := u.window.SetPos(u.windowDragStartX+int(math.Round(dx)), u.windowDragStartY+int(math.Round(dy))); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	if !justPressed {
+		return nil
+	}
+
+	regions := u.getDragRegions()
+	if len(regions) == 0 {
+		return nil
+	}
+
+	mode, err := u.window.GetInputMode(glfw.CursorMode)
+	if err != nil {
+		return err
+	}
+	if mode == glfw.CursorDisabled {
+		return nil
+	}
+
+	f, err := u.isFullscreen()
+	if err != nil {
+		return err
+	}
+	if f {
+		return nil
+	}
+
+	u.m.RLock()
+	x, y := u.inputState.CursorX, u.inputState.CursorY
+	u.m.RUnlock()
+	p := image.Pt(int(math.Floor(x)), int(math.Floor(y)))
+	var in bool
+	for _, r := range regions {
+		if p.In(r) {
+			in = true
+			break
+		}
+	}
+	if !in {
+		return nil
+	}
+
+	maximized, err := u.isWindowMaximized()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if !u.lastDragRegionClickTime.IsZero() && now.Sub(u.lastDragRegionClickTime) < dragRegionDoubleClickInterval {
+		u.lastDragRegionClickTime = time.Time{}
+		if maximized {
+			return u.restoreWindow()
+		}
+		u.m.RLock()
+		resizable := u.windowResizingMode == WindowResizingModeEnabled
+		u.m.RUnlock()
+		if resizable && u.isWindowMaximizable() {
+			return u.maximizeWindow()
+		}
+		return nil
+	}
+	u.lastDragRegionClickTime = now
+
+	// A maximized window is not moved by dragging.
+	if maximized {
+		return nil
+	}
+
+	wx, wy, err := u.window.GetPos()
+	if err != nil {
+		return err
+	}
+	cx, cy, err := u.window.GetCursorPos()
+	if err != nil {
+		return err
+	}
+	u.windowDragging = true
+	u.windowDragStartX = wx
+	u.windowDragStartY = wy
+	u.windowDragStartCursorX = float64(wx) + cx
+	u.windowDragStartCursorY = float64(wy) + cy
+	return nil
+}
+
 // updateInputStateImpl must be called from the main thread.
 func (u *UserInterface) updateInputStateImpl() error {
 	u.m.Lock()
@@ -94,7 +225,8 @@ func (u *UserInterface) updateInputStateImpl() error {
 		u.savedCursorY = math.NaN()
 	}()
 
-	if !math.IsNaN(cx) && !math.IsNaN(cy) {
+	restored := !math.IsNaN(cx) && !math.IsNaN(cy)
+	if restored {
 		cx2, cy2 := u.context.logicalPositionToClientPosition(cx, cy, s)
 		cx2 = dipToGLFWPixel(cx2, m)
 		cy2 = dipToGLFWPixel(cy2, m)
@@ -113,7 +245,19 @@ func (u *UserInterface) updateInputStateImpl() error {
 
 	// AdjustPosition can return NaN at the initialization.
 	if !math.IsNaN(cx) && !math.IsNaN(cy) {
+		// When the cursor is captured, GLFW reports a virtual cursor position that is not bounded by the window,
+		// so the difference is the actual movement.
+		// A restored position is not a movement.
+		if u.cursorPosInited && !restored {
+			u.inputState.CursorDeltaX += cx - u.inputState.CursorX
+			u.inputState.CursorDeltaY += cy - u.inputState.CursorY
+		}
 		u.inputState.CursorX, u.inputState.CursorY = cx, cy
+		u.cursorPosInited = true
+	}
+	if u.filesDropped {
+		u.inputState.DroppedFilesX, u.inputState.DroppedFilesY = u.inputState.CursorX, u.inputState.CursorY
+		u.filesDropped = false
 	}
 
 	if err := gamepad.Update(); err != nil {
@@ -147,6 +291,37 @@ func (u *UserInterface) KeyName(key Key) string {
 	return name
 }
 
+func (u *UserInterface) ReadClipboardText() (string, error) {
+	if !u.isRunning() {
+		return "", errors.New("ui: the clipboard is not available before the game starts")
+	}
+
+	var text string
+	var err error
+	u.mainThread.Call(func() {
+		if u.isTerminated() {
+			return
+		}
+		text, err = glfw.GetClipboardString()
+	})
+	return text, err
+}
+
+func (u *UserInterface) WriteClipboardText(text string) error {
+	if !u.isRunning() {
+		return errors.New("ui: the clipboard is not available before the game starts")
+	}
+
+	var err error
+	u.mainThread.Call(func() {
+		if u.isTerminated() {
+			return
+		}
+		err = u.window.SetClipboardString(text)
+	})
+	return err
+}
+
 func (u *UserInterface) saveCursorPosition() {
 	u.m.Lock()
 	defer u.m.Unlock()
@@ -15,6 +15,8 @@
 package ui
 
 import (
+	"errors"
+	"fmt"
 	"math"
 	"syscall/js"
 	"unicode"
@@ -33,9 +35,11 @@ var (
 )
 
 type touchInClient struct {
-	id TouchID
-	x  float64
-	y  float64
+	id       TouchID
+	x        float64
+	y        float64
+	pressure float64
+	radius   float64
 }
 
 func jsKeyToID(key js.Value) Key {
@@ -122,6 +126,10 @@ func (u *UserInterface) setMouseCursorFromEvent(e js.Value) {
 	u.origCursorXInClient = e.Get("clientX").Float()
 	u.origCursorYInClient = e.Get("clientY").Float()
 
+	// movementX and movementY are available even when the pointer is locked by the Pointer Lock API.
+	u.cursorDXInClient += e.Get("movementX").Float()
+	u.cursorDYInClient += e.Get("movementY").Float()
+
 	if u.cursorMode == CursorModeCaptured {
 		u.cursorXInClient += e.Get("movementX").Float()
 		u.cursorYInClient += e.Get("movementY").Float()
@@ -143,10 +151,24 @@ func (u *UserInterface) updateTouchesFromEvent(e js.Value) {
 	touches := e.Get("targetTouches")
 	for i := 0; i < touches.Length(); i++ {
 		t := touches.Call("item", i)
+
+		// force is 0 when the device doesn't detect pressures.
+		// radiusX and radiusY are 0 or undefined when the device doesn't detect contact areas.
+		pressure := 1.0
+		if f := t.Get("force"); f.Truthy() {
+			pressure = f.Float()
+		}
+		var radius float64
+		if rx, ry := t.Get("radiusX"), t.Get("radiusY"); rx.Truthy() && ry.Truthy() {
+			radius = (rx.Float() + ry.Float()) / 2
+		}
+
 		u.touchesInClient = append(u.touchesInClient, touchInClient{
-			id: TouchID(t.Get("identifier").Int()),
-			x:  t.Get("clientX").Float(),
-			y:  t.Get("clientY").Float(),
+			id:       TouchID(t.Get("identifier").Int()),
+			x:        t.Get("clientX").Float(),
+			y:        t.Get("clientY").Float(),
+			pressure: pressure,
+			radius:   radius,
 		})
 	}
 }
@@ -227,6 +249,59 @@ func (u *UserInterface) UpdateInputFromEvent(e js.Value) {
 	u.updateInputFromEvent(e)
 }
 
+func (u *UserInterface) ReadClipboardText() (string, error) {
+	clipboard := js.Global().Get("navigator").Get("clipboard")
+	if !clipboard.Truthy() || !clipboard.Get("readText").Truthy() {
+		return "", errors.New("ui: the Clipboard API is not available")
+	}
+	v, err := awaitPromise(clipboard.Call("readText"))
+	if err != nil {
+		return "", err
+	}
+	return v.String(), nil
+}
+
+func (u *UserInterface) WriteClipboardText(text string) error {
+	clipboard := js.Global().Get("navigator").Get("clipboard")
+	if !clipboard.Truthy() || !clipboard.Get("writeText").Truthy() {
+		return errors.New("ui: the Clipboard API is not available")
+	}
+	if _, err := awaitPromise(clipboard.Call("writeText", text)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// awaitPromise waits for the promise to be settled.
+// awaitPromise must not be called from a JavaScript callback, or this blocks forever.
+func awaitPromise(promise js.Value) (js.Value, error) {
+	chValue := make(chan js.Value, 1)
+	cbThen := js.FuncOf(func(this js.Value, args []js.Value) any {
+		if len(args) > 0 {
+			chValue <- args[0]
+		} else {
+			chValue <- js.Undefined()
+		}
+		return nil
+	})
+	defer cbThen.Release()
+
+	chError := make(chan js.Value, 1)
+	cbCatch := js.FuncOf(func(this js.Value, args []js.Value) any {
+		chError <- args[0]
+		return nil
+	})
+	defer cbCatch.Release()
+
+	promise.Call("then", cbThen).Call("catch", cbCatch)
+	select {
+	case v := <-chValue:
+		return v, nil
+	case err := <-chError:
+		return js.Undefined(), fmt.Errorf("ui: %s", err.Call("toString").String())
+	}
+}
+
 func (u *UserInterface) saveCursorPosition() {
 	u.savedCursorX = u.inputState.CursorX
 	u.savedCursorY = u.inputState.CursorY
@@ -270,15 +345,22 @@ func (u *UserInterface) updateInputState() error {
 		cx, cy := u.context.clientPositionToLogicalPosition(u.cursorXInClient, u.cursorYInClient, s)
 		u.inputState.CursorX = cx
 		u.inputState.CursorY = cy
+
+		u.inputState.CursorDeltaX += u.context.clientLengthToLogicalLength(u.cursorDXInClient, s)
+		u.inputState.CursorDeltaY += u.context.clientLengthToLogicalLength(u.cursorDYInClient, s)
 	}
+	u.cursorDXInClient = 0
+	u.cursorDYInClient = 0
 
 	u.inputState.Touches = u.inputState.Touches[:0]
 	for _, t := range u.touchesInClient {
 		x, y := u.context.clientPositionToLogicalPosition(t.x, t.y, s)
 		u.inputState.Touches = append(u.inputState.Touches, Touch{
-			ID: t.id,
-			X:  int(x),
-			Y:  int(y),
+			ID:       t.id,
+			X:        int(x),
+			Y:        int(y),
+			Pressure: t.pressure,
+			Radius:   u.context.clientLengthToLogicalLength(t.radius, s),
 		})
 	}
 
@@ -24,6 +24,12 @@ type TouchForInput struct {
 
 	// Y is in device-independent pixels.
 	Y float64
+
+	// Pressure is the normalized pressure in between 0 and 1.
+	Pressure float64
+
+	// Radius is in device-independent pixels.
+	Radius float64
 }
 
 func (u *UserInterface) updateInputStateFromOutside(keys map[Key]struct{}, runes []rune, touches []TouchForInput) {
@@ -53,9 +59,11 @@ func (u *UserInterface) updateInputState() error {
 	for _, t := range u.touches {
 		x, y := u.context.clientPositionToLogicalPosition(t.X, t.Y, s)
 		u.inputState.Touches = append(u.inputState.Touches, Touch{
-			ID: t.ID,
-			X:  int(x),
-			Y:  int(y),
+			ID:       t.ID,
+			X:        int(x),
+			Y:        int(y),
+			Pressure: t.Pressure,
+			Radius:   u.context.clientLengthToLogicalLength(t.Radius, s),
 		})
 	}
 	return nil
@@ -65,3 +73,11 @@ func (u *UserInterface) KeyName(key Key) string {
 	// TODO: Implement this.
 	return ""
 }
+
+func (u *UserInterface) ReadClipboardText() (string, error) {
+	return "", errClipboardNotSupported
+}
+
+func (u *UserInterface) WriteClipboardText(text string) error {
+	return errClipboardNotSupported
+}
@@ -62,9 +62,10 @@ func (u *UserInterface) updateInputStateImpl() error {
 	for _, t := range u.nativeTouches {
 		x, y := u.context.clientPositionToLogicalPosition(float64(t.x), float64(t.y), deviceScaleFactor)
 		u.inputState.Touches = append(u.inputState.Touches, Touch{
-			ID: TouchID(t.id),
-			X:  int(x),
-			Y:  int(y),
+			ID:       TouchID(t.id),
+			X:        int(x),
+			Y:        int(y),
+			Pressure: 1,
 		})
 	}
 
@@ -74,3 +75,11 @@ func (u *UserInterface) updateInputStateImpl() error {
 func (u *UserInterface) KeyName(key Key) string {
 	return ""
 }
+
+func (u *UserInterface) ReadClipboardText() (string, error) {
+	return "", errClipboardNotSupported
+}
+
+func (u *UserInterface) WriteClipboardText(text string) error {
+	return errClipboardNotSupported
+}
@@ -24,3 +24,11 @@ func (u *UserInterface) updateInputState() error {
 func (u *UserInterface) KeyName(key Key) string {
 	return ""
 }
+
+func (u *UserInterface) ReadClipboardText() (string, error) {
+	return "", errClipboardNotSupported
+}
+
+func (u *UserInterface) WriteClipboardText(text string) error {
+	return errClipboardNotSupported
+}
@@ -12,7 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-//go:build !android && !ios && !js && !nintendosdk && !playstation5
+//go:build !android && !ios && !js && !nintendosdk && !playstation5 && !ebitengineheadless
 
 package ui
 
@@ -29,7 +29,6 @@ type Monitor struct {
 	m         *glfw.Monitor
 	videoMode *glfw.VidMode
 
-	id                 int
 	name               string
 	boundsInGLFWPixels image.Rectangle
 	contentScale       float64
@@ -40,6 +39,29 @@ func (m *Monitor) Name() string {
 	return m.name
 }
 
+// RefreshRate returns the monitor's refresh rate in Hz.
+// RefreshRate returns 0 if the refresh rate is unknown.
+func (m *Monitor) RefreshRate() int {
+	if m == nil || m.videoMode == nil {
+		return 0
+	}
+	return m.videoMode.RefreshRate
+}
+
+// Bounds returns the monitor's bounds in device-independent pixels.
+func (m *Monitor) Bounds() image.Rectangle {
+	b := m.boundsInGLFWPixels
+	w, h := m.sizeInDIP()
+	x := int(dipFromGLFWPixel(float64(b.Min.X), m))
+	y := int(dipFromGLFWPixel(float64(b.Min.Y), m))
+	return image.Rect(x, y, x+int(w), y+int(h))
+}
+
+// DeviceScaleFactor returns the monitor's device scale factor.
+func (m *Monitor) DeviceScaleFactor() float64 {
+	return m.deviceScaleFactor()
+}
+
 func (m *Monitor) deviceScaleFactor() float64 {
 	// It is rare, but monitor can be nil when glfw.GetPrimaryMonitor returns nil.
 	// In this case, return 1 as a tentative scale (#1878).
@@ -89,11 +111,17 @@ func (m *monitors) primaryMonitor() *Monitor {
 	return m.monitors[0]
 }
 
-func (m *monitors) monitorFromID(id int) *Monitor {
+// contains reports whether the given monitor is still connected.
+func (m *monitors) contains(monitor *Monitor) bool {
 	m.m.Lock()
 	defer m.m.Unlock()
 
-	return m.monitors[id]
+	for _, mon := range m.monitors {
+		if mon == monitor {
+			return true
+		}
+	}
+	return false
 }
 
 // monitorFromPosition returns a monitor for the given position (x, y),
@@ -119,8 +147,12 @@ func (m *monitors) update() error {
 	if err != nil {
 		return err
 	}
+	m.m.Lock()
+	oldMonitors := m.monitors
+	m.m.Unlock()
+
 	newMonitors := make([]*Monitor, 0, len(glfwMonitors))
-	for i, m := range glfwMonitors {
+	for _, m := range glfwMonitors {
 		x, y, err := m.GetPos()
 		if err != nil {
 			return err
@@ -157,10 +189,35 @@ func (m *monitors) update() error {
 			return err
 		}
 		b := image.Rect(x, y, x+w, y+h)
+
+		// Reuse the existing Monitor if nothing is changed, so that the identity of a Monitor is kept
+		// e.g. when another monitor is connected or disconnected.
+		// The GLFW monitor must be the same one, or a stale handle of a disconnected monitor would be kept.
+		var found *Monitor
+		for _, old := range oldMonitors {
+			if old.m != m {
+				continue
+			}
+			if old.name != name || old.boundsInGLFWPixels != b || old.contentScale != contentScale {
+				continue
+			}
+			if (old.videoMode == nil) != (videoMode == nil) {
+				continue
+			}
+			if old.videoMode != nil && *old.videoMode != *videoMode {
+				continue
+			}
+			found = old
+			break
+		}
+		if found != nil {
+			newMonitors = append(newMonitors, found)
+			continue
+		}
+
 		newMonitors = append(newMonitors, &Monitor{
 			m:                  m,
 			videoMode:          videoMode,
-			id:                 i,
 			name:               name,
 			boundsInGLFWPixels: b,
 			contentScale:       contentScale,
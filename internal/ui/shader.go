@@ -40,6 +40,10 @@ func NewShader(ir *shaderir.Program) *Shader {
 	}
 }
 
+func (s *Shader) Preload() {
+	s.shader.Preload()
+}
+
 func (s *Shader) Deallocate() {
 	s.shader.Deallocate()
 }
@@ -69,42 +73,45 @@ func (s *Shader) AppendUniforms(dst []uint32, uniforms map[string]any) []uint32
 		if uv, ok := uniforms[name]; ok {
 			v := reflect.ValueOf(uv)
 			t := v.Type()
+			base := uniformBasicType(typ)
 			switch t.Kind() {
-			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-				if typ.Uint32Count() != 1 {
-					panic(fmt.Sprintf("ui: unexpected uniform value for %s (%s)", name, typ.String()))
-				}
-				dst[idx] = uint32(v.Int())
-			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			case reflect.Bool,
+				reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+				reflect.Float32, reflect.Float64:
 				if typ.Uint32Count() != 1 {
 					panic(fmt.Sprintf("ui: unexpected uniform value for %s (%s)", name, typ.String()))
 				}
-				dst[idx] = uint32(v.Uint())
-			case reflect.Float32, reflect.Float64:
-				if typ.Uint32Count() != 1 {
-					panic(fmt.Sprintf("ui: unexpected uniform value for %s (%s)", name, typ.String()))
+				u, err := uniformUint32(base, v)
+				if err != nil {
+					panic(fmt.Sprintf("ui: unexpected uniform value for %s (%s): %v", name, typ.String(), err))
 				}
-				dst[idx] = math.Float32bits(float32(v.Float()))
+				dst[idx] = u
 			case reflect.Slice, reflect.Array:
+				if t.Elem().Kind() == reflect.Struct {
+					if err := checkUniformStructs(typ, t.Elem(), v.Len()); err != nil {
+						panic(fmt.Sprintf("ui: unexpected uniform value for %s (%s): %v", name, typ.String(), err))
+					}
+					j := idx
+					for i := 0; i < v.Len(); i++ {
+						var err error
+						j, err = putUniformValues(dst, j, base, v.Index(i))
+						if err != nil {
+							panic(fmt.Sprintf("ui: unexpected uniform value for %s (%s): %v", name, typ.String(), err))
+						}
+					}
+					break
+				}
 				l := v.Len()
 				if typ.Uint32Count() != l {
 					panic(fmt.Sprintf("ui: unexpected uniform value for %s (%s)", name, typ.String()))
 				}
-				switch t.Elem().Kind() {
-				case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-					for i := 0; i < l; i++ {
-						dst[idx+i] = uint32(v.Index(i).Int())
-					}
-				case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-					for i := 0; i < l; i++ {
-						dst[idx+i] = uint32(v.Index(i).Uint())
-					}
-				case reflect.Float32, reflect.Float64:
-					for i := 0; i < l; i++ {
-						dst[idx+i] = math.Float32bits(float32(v.Index(i).Float()))
+				for i := 0; i < l; i++ {
+					u, err := uniformUint32(base, v.Index(i))
+					if err != nil {
+						panic(fmt.Sprintf("ui: unexpected uniform value for %s (%s): %v", name, typ.String(), err))
 					}
-				default:
-					panic(fmt.Sprintf("ui: unexpected uniform value type: %s (%s)", name, v.Kind().String()))
+					dst[idx+i] = u
 				}
 			default:
 				panic(fmt.Sprintf("ui: unexpected uniform value type: %s (%s)", name, v.Kind().String()))
@@ -116,3 +123,155 @@ func (s *Shader) AppendUniforms(dst []uint32, uniforms map[string]any) []uint32
 
 	return dst
 }
+
+// uniformBasicType returns the basic type of each value of a uniform variable of the type t.
+// The result is Bool, Int, or Float.
+func uniformBasicType(t shaderir.Type) shaderir.BasicType {
+	switch t.Main {
+	case shaderir.Array:
+		return uniformBasicType(t.Sub[0])
+	case shaderir.Bool:
+		return shaderir.Bool
+	case shaderir.Int, shaderir.IVec2, shaderir.IVec3, shaderir.IVec4:
+		return shaderir.Int
+	default:
+		return shaderir.Float
+	}
+}
+
+// uniformUint32 converts a boolean or numeric value v to a uint32 representation of the basic type base.
+// uniformUint32 returns an error if v cannot be converted to base.
+//
+// An integer is represented as a 32-bit two's complement integer, and the higher bits are discarded.
+// A float is represented as a 32-bit floating-point number. A float value for an integer is truncated toward zero.
+// A bool is represented as 1 for true or 0 for false. An integer value for a bool is true if and only if it is not zero.
+// A bool value for a float and a float value for a bool are not allowed.
+func uniformUint32(base shaderir.BasicType, v reflect.Value) (uint32, error) {
+	switch v.Kind() {
+	case reflect.Bool:
+		if base == shaderir.Float {
+			return 0, fmt.Errorf("a bool value cannot be used for a float")
+		}
+		if v.Bool() {
+			return 1, nil
+		}
+		return 0, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch base {
+		case shaderir.Bool:
+			if v.Int() != 0 {
+				return 1, nil
+			}
+			return 0, nil
+		case shaderir.Int:
+			return uint32(v.Int()), nil
+		default:
+			return math.Float32bits(float32(v.Int())), nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		switch base {
+		case shaderir.Bool:
+			if v.Uint() != 0 {
+				return 1, nil
+			}
+			return 0, nil
+		case shaderir.Int:
+			return uint32(v.Uint()), nil
+		default:
+			return math.Float32bits(float32(v.Uint())), nil
+		}
+	case reflect.Float32, reflect.Float64:
+		switch base {
+		case shaderir.Bool:
+			return 0, fmt.Errorf("a float value cannot be used for a bool")
+		case shaderir.Int:
+			return uint32(int32(v.Float())), nil
+		default:
+			return math.Float32bits(float32(v.Float())), nil
+		}
+	default:
+		return 0, fmt.Errorf("unexpected value type: %s", v.Kind().String())
+	}
+}
+
+// uniformValueCount returns the number of the boolean or numeric values in a value of the type t.
+// uniformValueCount returns false if t includes a non-boolean and non-numeric type.
+func uniformValueCount(t reflect.Type) (int, bool) {
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return 1, true
+	case reflect.Array:
+		n, ok := uniformValueCount(t.Elem())
+		if !ok {
+			return 0, false
+		}
+		return t.Len() * n, true
+	case reflect.Struct:
+		var n int
+		for i := 0; i < t.NumField(); i++ {
+			c, ok := uniformValueCount(t.Field(i).Type)
+			if !ok {
+				return 0, false
+			}
+			n += c
+		}
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+// checkUniformStructs checks that count structs of the type structType can be the value for a uniform variable of the type typ.
+//
+// The uniform variable must be an array. Each struct is flattened and corresponds to one or more consecutive elements of the array.
+func checkUniformStructs(typ shaderir.Type, structType reflect.Type, count int) error {
+	if typ.Main != shaderir.Array {
+		return fmt.Errorf("structs are available only for an array")
+	}
+	n, ok := uniformValueCount(structType)
+	if !ok {
+		return fmt.Errorf("the struct %s must consist of only boolean or numeric fields or arrays of them", structType)
+	}
+	elemCount := typ.Sub[0].Uint32Count()
+	if n == 0 || n%elemCount != 0 {
+		return fmt.Errorf("the number of the values in the struct %s must be a multiple of %d but was %d", structType, elemCount, n)
+	}
+	if got, want := count*(n/elemCount), typ.Length; got != want {
+		return fmt.Errorf("the structs must fill %d elements but filled %d elements (%d structs)", want, got, count)
+	}
+	return nil
+}
+
+// putUniformValues puts the values of v as the basic type base into dst from the index idx, and returns the next index.
+func putUniformValues(dst []uint32, idx int, base shaderir.BasicType, v reflect.Value) (int, error) {
+	switch v.Kind() {
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			var err error
+			idx, err = putUniformValues(dst, idx, base, v.Index(i))
+			if err != nil {
+				return 0, err
+			}
+		}
+		return idx, nil
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			var err error
+			idx, err = putUniformValues(dst, idx, base, v.Field(i))
+			if err != nil {
+				return 0, err
+			}
+		}
+		return idx, nil
+	default:
+		u, err := uniformUint32(base, v)
+		if err != nil {
+			return 0, err
+		}
+		dst[idx] = u
+		return idx + 1, nil
+	}
+}
@@ -19,8 +19,10 @@ import (
 	"image"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2/internal/atlas"
+	"github.com/hajimehoshi/ebiten/v2/internal/clock"
 	"github.com/hajimehoshi/ebiten/v2/internal/mipmap"
 	"github.com/hajimehoshi/ebiten/v2/internal/thread"
 )
@@ -77,6 +79,11 @@ type UserInterface struct {
 	graphicsLibrary           int32
 	running                   int32
 	terminated                int32
+	maxFPS                    int32
+
+	// nextFrameTime is the time when the next frame should start with the max FPS.
+	// nextFrameTime is accessed only from the game loop.
+	nextFrameTime time.Time
 
 	whiteImage *Image
 
@@ -137,6 +144,29 @@ func (u *UserInterface) dumpImages(dir string) (string, error) {
 	return atlas.DumpImages(u.graphicsDriver, dir)
 }
 
+// FlushDisposals executes the pending disposals of images and shaders, and blocks until they are done in the graphics driver.
+func (u *UserInterface) FlushDisposals() {
+	// Check the error existence and avoid unnecessary calls.
+	if u.error() != nil {
+		return
+	}
+	if err := atlas.FlushDeallocations(u.graphicsDriver); err != nil {
+		u.setError(err)
+	}
+}
+
+func (u *UserInterface) ShaderCount() int {
+	return atlas.ShaderCount()
+}
+
+func (u *UserInterface) PendingShaderDisposalCount() int {
+	return atlas.PendingShaderDisposalCount()
+}
+
+func (u *UserInterface) AppendAtlasInfos(infos []atlas.AtlasInfo) []atlas.AtlasInfo {
+	return atlas.AppendAtlasInfos(infos)
+}
+
 type RunOptions struct {
 	GraphicsLibrary   GraphicsLibrary
 	InitUnfocused     bool
@@ -176,6 +206,56 @@ func (u *UserInterface) SetScreenClearedEveryFrame(cleared bool) {
 	atomic.StoreInt32(&u.isScreenClearedEveryFrame, v)
 }
 
+func (u *UserInterface) MaxFPS() int {
+	return int(atomic.LoadInt32(&u.maxFPS))
+}
+
+func (u *UserInterface) SetMaxFPS(fps int) {
+	atomic.StoreInt32(&u.maxFPS, int32(fps))
+}
+
+// refreshRateForTesting is the refresh rate passed to the clock instead of the current monitor's one.
+// This value is set only on testing.
+var refreshRateForTesting atomic.Value
+
+// SetRefreshRateForTesting makes the game loop treat rate as the refresh rate of the current monitor for TPS.
+// A non-positive value means that the refresh rate is unknown.
+func SetRefreshRateForTesting(rate float64) {
+	refreshRateForTesting.Store(rate)
+}
+
+// updateClockRefreshRate passes the refresh rate of the current monitor in Hz to the clock.
+//
+// updateClockRefreshRate must be called once per frame from the game loop before the game is updated.
+func updateClockRefreshRate(refreshRate float64) {
+	if r, ok := refreshRateForTesting.Load().(float64); ok {
+		refreshRate = r
+	}
+	clock.SetRefreshRate(refreshRate)
+}
+
+// waitForNextFrame sleeps until the next frame to limit the frame rate by the max FPS.
+//
+// waitForNextFrame must be called once per frame from the game loop.
+func (u *UserInterface) waitForNextFrame() {
+	fps := u.MaxFPS()
+	if fps <= 0 {
+		u.nextFrameTime = time.Time{}
+		return
+	}
+
+	interval := time.Second / time.Duration(fps)
+	now := time.Now()
+	// If the frame is too late, give up catching up.
+	if u.nextFrameTime.IsZero() || now.Sub(u.nextFrameTime) > interval {
+		u.nextFrameTime = now
+	}
+	if d := u.nextFrameTime.Sub(now); d > 0 {
+		time.Sleep(d)
+	}
+	u.nextFrameTime = u.nextFrameTime.Add(interval)
+}
+
 func (u *UserInterface) setGraphicsLibrary(library GraphicsLibrary) {
 	atomic.StoreInt32(&u.graphicsLibrary, int32(library))
 }
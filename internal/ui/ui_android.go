@@ -120,6 +120,10 @@ func (*graphicsDriverCreatorImpl) newPlayStation5() (graphicsdriver.Graphics, er
 	return nil, errors.New("ui: PlayStation 5 is not supported in this environment")
 }
 
+func (*graphicsDriverCreatorImpl) newSoftware() (graphicsdriver.Graphics, error) {
+	return nil, errors.New("ui: Software is not supported in this environment")
+}
+
 func deviceScaleFactorImpl() float64 {
 	var s float64
 	if err := app.RunOnJVM(func(vm, env, ctx uintptr) error {
@@ -12,7 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-//go:build !android && !ios && !js && !nintendosdk && !playstation5
+//go:build !android && !ios && !js && !nintendosdk && !playstation5 && !ebitengineheadless
 
 package ui
 
@@ -96,6 +96,23 @@ type userInterfaceImpl struct {
 	savedCursorX float64
 	savedCursorY float64
 
+	// cursorPosInited reports whether inputState has a valid cursor position to calculate the cursor delta.
+	cursorPosInited bool
+
+	// filesDropped reports whether files are dropped and the position is not updated yet.
+	filesDropped bool
+
+	dragRegions []image.Rectangle
+
+	// The fields for dragging the window must be accessed from the main thread.
+	windowDragging          bool
+	windowDragStartCursorX  float64
+	windowDragStartCursorY  float64
+	windowDragStartX        int
+	windowDragStartY        int
+	dragRegionPressed       bool
+	lastDragRegionClickTime time.Time
+
 	sizeCallback                   glfw.SizeCallback
 	closeCallback                  glfw.CloseCallback
 	framebufferSizeCallback        glfw.FramebufferSizeCallback
@@ -287,6 +304,11 @@ func (u *UserInterface) setWindowMonitor(monitor *Monitor) error {
 		return nil
 	}
 
+	// Ignore if the monitor is already disconnected.
+	if !theMonitors.contains(monitor) {
+		return nil
+	}
+
 	// Ignore if it is the same monitor.
 	m, err := u.currentMonitor()
 	if err != nil {
@@ -563,6 +585,18 @@ func (u *UserInterface) isWindowClosingHandled() bool {
 	return v
 }
 
+func (u *UserInterface) getDragRegions() []image.Rectangle {
+	u.m.RLock()
+	defer u.m.RUnlock()
+	return u.dragRegions
+}
+
+func (u *UserInterface) setDragRegions(regions []image.Rectangle) {
+	u.m.Lock()
+	defer u.m.Unlock()
+	u.dragRegions = regions
+}
+
 func (u *UserInterface) setWindowClosingHandled(handled bool) {
 	u.m.Lock()
 	u.windowClosingHandled = handled
@@ -1008,6 +1042,9 @@ func (u *UserInterface) registerDropCallback() error {
 			u.m.Lock()
 			defer u.m.Unlock()
 			u.inputState.DroppedFiles = file.NewVirtualFS(names)
+			// The drop callback doesn't have a position. The files are dropped at the cursor position,
+			// and the cursor position is updated at updateInputStateImpl.
+			u.filesDropped = true
 		}
 	}
 	if _, err := u.window.SetDropCallback(u.dropCallback); err != nil {
@@ -1452,6 +1489,7 @@ func (u *UserInterface) updateGame() error {
 
 	var outsideWidth, outsideHeight float64
 	var deviceScaleFactor float64
+	var refreshRate int
 	var err error
 	if u.mainThread.Call(func() {
 		outsideWidth, outsideHeight, err = u.update()
@@ -1463,10 +1501,13 @@ func (u *UserInterface) updateGame() error {
 			return
 		}
 		deviceScaleFactor = m.deviceScaleFactor()
+		refreshRate = m.RefreshRate()
 	}); err != nil {
 		return err
 	}
 
+	updateClockRefreshRate(float64(refreshRate))
+
 	if err := u.context.updateFrame(u.graphicsDriver, outsideWidth, outsideHeight, deviceScaleFactor, u, func() {
 		// Call updateVsync even though fpsMode is not updated.
 		// When toggling to fullscreen, vsync state might be reset unexpectedly (#1787).
@@ -1504,6 +1545,8 @@ func (u *UserInterface) updateGame() error {
 		}
 	}
 
+	u.waitForNextFrame()
+
 	return nil
 }
 
@@ -0,0 +1,214 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build ebitengineheadless && !android && !ios && !js && !nintendosdk && !playstation5
+
+package ui
+
+import (
+	"errors"
+	"image"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver/software"
+)
+
+type graphicsDriverCreatorImpl struct{}
+
+func (g *graphicsDriverCreatorImpl) newAuto() (graphicsdriver.Graphics, GraphicsLibrary, error) {
+	graphics, err := g.newSoftware()
+	return graphics, GraphicsLibrarySoftware, err
+}
+
+func (*graphicsDriverCreatorImpl) newOpenGL() (graphicsdriver.Graphics, error) {
+	return nil, errors.New("ui: OpenGL is not supported in this environment")
+}
+
+func (*graphicsDriverCreatorImpl) newDirectX() (graphicsdriver.Graphics, error) {
+	return nil, errors.New("ui: DirectX is not supported in this environment")
+}
+
+func (*graphicsDriverCreatorImpl) newMetal() (graphicsdriver.Graphics, error) {
+	return nil, errors.New("ui: Metal is not supported in this environment")
+}
+
+func (*graphicsDriverCreatorImpl) newPlayStation5() (graphicsdriver.Graphics, error) {
+	return nil, errors.New("ui: PlayStation 5 is not supported in this environment")
+}
+
+func (*graphicsDriverCreatorImpl) newSoftware() (graphicsdriver.Graphics, error) {
+	return software.NewGraphics(), nil
+}
+
+const (
+	defaultWindowWidth  = 640
+	defaultWindowHeight = 480
+	deviceScaleFactor   = 1
+)
+
+// userInterfaceImpl is a user interface without any displays.
+//
+// The screen is rendered to an offscreen framebuffer by the software graphics driver.
+// One Update is called per frame regardless of the wall clock so that the result is deterministic.
+type userInterfaceImpl struct {
+	graphicsDriver graphicsdriver.Graphics
+
+	context *context
+	window  headlessWindow
+}
+
+func (u *UserInterface) init() error {
+	return nil
+}
+
+func (u *UserInterface) initOnMainThread(options *RunOptions) error {
+	g, lib, err := newGraphicsDriver(&graphicsDriverCreatorImpl{}, options.GraphicsLibrary)
+	if err != nil {
+		return err
+	}
+	u.graphicsDriver = g
+	u.setGraphicsLibrary(lib)
+
+	return nil
+}
+
+func (u *UserInterface) loopGame() error {
+	for {
+		updateClockRefreshRate(float64(theMonitor.RefreshRate()))
+		w, h := u.window.Size()
+		if err := u.context.updateFrameImpl(u.graphicsDriver, 1, float64(w), float64(h), deviceScaleFactor, u, false, nil); err != nil {
+			return err
+		}
+		u.waitForNextFrame()
+	}
+}
+
+func (*UserInterface) DeviceScaleFactor() float64 {
+	return deviceScaleFactor
+}
+
+func (*UserInterface) IsFocused() bool {
+	return true
+}
+
+func (*UserInterface) ScreenSizeInFullscreen() (int, int) {
+	return 0, 0
+}
+
+func (u *UserInterface) readInputState(inputState *InputState) {
+}
+
+func (*UserInterface) CursorMode() CursorMode {
+	return CursorModeHidden
+}
+
+func (*UserInterface) SetCursorMode(mode CursorMode) {
+}
+
+func (*UserInterface) CursorShape() CursorShape {
+	return CursorShapeDefault
+}
+
+func (*UserInterface) SetCursorShape(shape CursorShape) {
+}
+
+func (*UserInterface) IsFullscreen() bool {
+	return false
+}
+
+func (*UserInterface) SetFullscreen(fullscreen bool) {
+}
+
+func (*UserInterface) IsRunnableOnUnfocused() bool {
+	return true
+}
+
+func (*UserInterface) SetRunnableOnUnfocused(runnableOnUnfocused bool) {
+}
+
+func (*UserInterface) FPSMode() FPSModeType {
+	return FPSModeVsyncOffMaximum
+}
+
+func (*UserInterface) SetFPSMode(mode FPSModeType) {
+}
+
+func (*UserInterface) ScheduleFrame() {
+}
+
+func (u *UserInterface) Window() Window {
+	return &u.window
+}
+
+func (u *UserInterface) updateIconIfNeeded() error {
+	return nil
+}
+
+// headlessWindow is a virtual window that has only its size.
+type headlessWindow struct {
+	nullWindow
+
+	width  int
+	height int
+	m      sync.Mutex
+}
+
+func (w *headlessWindow) Size() (int, int) {
+	w.m.Lock()
+	defer w.m.Unlock()
+	if w.width == 0 || w.height == 0 {
+		return defaultWindowWidth, defaultWindowHeight
+	}
+	return w.width, w.height
+}
+
+func (w *headlessWindow) SetSize(width, height int) {
+	w.m.Lock()
+	defer w.m.Unlock()
+	w.width = width
+	w.height = height
+}
+
+type Monitor struct{}
+
+var theMonitor = &Monitor{}
+
+func (m *Monitor) Bounds() image.Rectangle {
+	return image.Rectangle{}
+}
+
+func (m *Monitor) Name() string {
+	return ""
+}
+
+func (m *Monitor) RefreshRate() int {
+	return 0
+}
+
+func (m *Monitor) DeviceScaleFactor() float64 {
+	return theUI.DeviceScaleFactor()
+}
+
+func (u *UserInterface) AppendMonitors(mons []*Monitor) []*Monitor {
+	return append(mons, theMonitor)
+}
+
+func (u *UserInterface) Monitor() *Monitor {
+	return theMonitor
+}
+
+func IsScreenTransparentAvailable() bool {
+	return false
+}
@@ -70,6 +70,10 @@ func (*graphicsDriverCreatorImpl) newPlayStation5() (graphicsdriver.Graphics, er
 	return nil, errors.New("ui: PlayStation 5 is not supported in this environment")
 }
 
+func (*graphicsDriverCreatorImpl) newSoftware() (graphicsdriver.Graphics, error) {
+	return nil, errors.New("ui: Software is not supported in this environment")
+}
+
 func (u *UserInterface) SetUIView(uiview uintptr) error {
 	select {
 	case err := <-u.errCh:
@@ -54,6 +54,10 @@ func (*graphicsDriverCreatorImpl) newPlayStation5() (graphicsdriver.Graphics, er
 	return nil, errors.New("ui: PlayStation 5 is not supported in this environment")
 }
 
+func (*graphicsDriverCreatorImpl) newSoftware() (graphicsdriver.Graphics, error) {
+	return nil, errors.New("ui: Software is not supported in this environment")
+}
+
 var (
 	stringNone        = js.ValueOf("none")
 	stringTransparent = js.ValueOf("transparent")
@@ -106,6 +110,8 @@ type userInterfaceImpl struct {
 	cursorYInClient     float64
 	origCursorXInClient float64
 	origCursorYInClient float64
+	cursorDXInClient    float64
+	cursorDYInClient    float64
 	touchesInClient     []touchInClient
 
 	savedCursorX              float64
@@ -414,6 +420,7 @@ func (u *UserInterface) loopGame() error {
 				return
 			}
 		}
+		u.waitForNextFrame()
 		switch u.fpsMode {
 		case FPSModeVsyncOn:
 			requestAnimationFrame.Invoke(cf)
@@ -708,12 +715,13 @@ func (u *UserInterface) setCanvasEventHandlers(v js.Value) {
 			return nil
 		}
 
-		go u.appendDroppedFiles(data)
+		x, y := e.Get("clientX").Float(), e.Get("clientY").Float()
+		go u.appendDroppedFiles(data, x, y)
 		return nil
 	}))
 }
 
-func (u *UserInterface) appendDroppedFiles(data js.Value) {
+func (u *UserInterface) appendDroppedFiles(data js.Value, xInClient, yInClient float64) {
 	u.dropFileM.Lock()
 	defer u.dropFileM.Unlock()
 
@@ -724,6 +732,11 @@ func (u *UserInterface) appendDroppedFiles(data js.Value) {
 
 	fs := items.Index(0).Call("webkitGetAsEntry").Get("filesystem").Get("root")
 	u.inputState.DroppedFiles = file.NewFileEntryFS(fs)
+	if u.context != nil {
+		x, y := u.context.clientPositionToLogicalPosition(xInClient, yInClient, u.DeviceScaleFactor())
+		u.inputState.DroppedFilesX = x
+		u.inputState.DroppedFilesY = y
+	}
 }
 
 func (u *UserInterface) forceUpdateOnMinimumFPSMode() {
@@ -802,6 +815,16 @@ func (m *Monitor) Name() string {
 	return ""
 }
 
+// RefreshRate returns 0 as browsers don't expose the display's refresh rate.
+// requestAnimationFrame is usually called at the display's refresh rate, but its cadence is not reliable.
+func (m *Monitor) RefreshRate() int {
+	return 0
+}
+
+func (m *Monitor) DeviceScaleFactor() float64 {
+	return theUI.DeviceScaleFactor()
+}
+
 func (u *UserInterface) AppendMonitors(mons []*Monitor) []*Monitor {
 	return append(mons, theMonitor)
 }
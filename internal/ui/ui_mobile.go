@@ -183,10 +183,12 @@ func (u *UserInterface) appMain(a app.App) {
 			switch e.Type {
 			case touch.TypeBegin, touch.TypeMove:
 				s := u.DeviceScaleFactor()
+				// gomobile's touch events don't have pressures or radii.
 				touches[e.Sequence] = TouchForInput{
-					ID: TouchID(e.Sequence),
-					X:  float64(e.X) / s,
-					Y:  float64(e.Y) / s,
+					ID:       TouchID(e.Sequence),
+					X:        float64(e.X) / s,
+					Y:        float64(e.Y) / s,
+					Pressure: 1,
 				}
 			case touch.TypeEnd:
 				delete(touches, e.Sequence)
@@ -448,6 +450,14 @@ func (m *Monitor) Name() string {
 	return ""
 }
 
+func (m *Monitor) RefreshRate() int {
+	return 0
+}
+
+func (m *Monitor) DeviceScaleFactor() float64 {
+	return theUI.DeviceScaleFactor()
+}
+
 func (u *UserInterface) AppendMonitors(mons []*Monitor) []*Monitor {
 	return append(mons, theMonitor)
 }
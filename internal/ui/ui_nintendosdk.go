@@ -53,6 +53,10 @@ func (*graphicsDriverCreatorImpl) newPlayStation5() (graphicsdriver.Graphics, er
 	return nil, errors.New("ui: PlayStation 5 is not supported in this environment")
 }
 
+func (*graphicsDriverCreatorImpl) newSoftware() (graphicsdriver.Graphics, error) {
+	return nil, errors.New("ui: Software is not supported in this environment")
+}
+
 const deviceScaleFactor = 1
 
 func init() {
@@ -186,6 +190,14 @@ func (m *Monitor) Name() string {
 	return ""
 }
 
+func (m *Monitor) RefreshRate() int {
+	return 0
+}
+
+func (m *Monitor) DeviceScaleFactor() float64 {
+	return theUI.DeviceScaleFactor()
+}
+
 func (u *UserInterface) AppendMonitors(mons []*Monitor) []*Monitor {
 	return append(mons, theMonitor)
 }
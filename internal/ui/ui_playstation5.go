@@ -48,6 +48,10 @@ func (*graphicsDriverCreatorImpl) newPlayStation5() (graphicsdriver.Graphics, er
 	return playstation5.NewGraphics()
 }
 
+func (*graphicsDriverCreatorImpl) newSoftware() (graphicsdriver.Graphics, error) {
+	return nil, errors.New("ui: Software is not supported in this environment")
+}
+
 const (
 	// TODO: Get this value from the SDK.
 	screenWidth       = 3840
@@ -164,6 +168,14 @@ func (m *Monitor) Name() string {
 	return ""
 }
 
+func (m *Monitor) RefreshRate() int {
+	return 0
+}
+
+func (m *Monitor) DeviceScaleFactor() float64 {
+	return theUI.DeviceScaleFactor()
+}
+
 func (u *UserInterface) AppendMonitors(mons []*Monitor) []*Monitor {
 	return append(mons, theMonitor)
 }
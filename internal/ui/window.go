@@ -43,6 +43,8 @@ type Window interface {
 	IsClosingHandled() bool
 	SetMousePassthrough(enabled bool)
 	IsMousePassthrough() bool
+	SetDragRegions(regions []image.Rectangle)
+	DragRegions() []image.Rectangle
 }
 
 type nullWindow struct{}
@@ -128,3 +130,10 @@ func (*nullWindow) SetMousePassthrough(enabled bool) {
 func (*nullWindow) IsMousePassthrough() bool {
 	return false
 }
+
+func (*nullWindow) SetDragRegions(regions []image.Rectangle) {
+}
+
+func (*nullWindow) DragRegions() []image.Rectangle {
+	return nil
+}
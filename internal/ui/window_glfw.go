@@ -12,7 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-//go:build !android && !ios && !js && !nintendosdk && !playstation5
+//go:build !android && !ios && !js && !nintendosdk && !playstation5 && !ebitengineheadless
 
 package ui
 
@@ -505,3 +505,17 @@ func (w *glfwWindow) IsMousePassthrough() bool {
 	})
 	return v
 }
+
+func (w *glfwWindow) SetDragRegions(regions []image.Rectangle) {
+	if w.ui.isTerminated() {
+		return
+	}
+	w.ui.setDragRegions(append([]image.Rectangle(nil), regions...))
+}
+
+func (w *glfwWindow) DragRegions() []image.Rectangle {
+	if w.ui.isTerminated() {
+		return nil
+	}
+	return append([]image.Rectangle(nil), w.ui.getDragRegions()...)
+}
@@ -0,0 +1,147 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/atlas"
+)
+
+const linearBlendShaderSrc = `//kage:unit pixels
+
+package main
+
+func decode(c vec3) vec3 {
+	return mix(c/12.92, pow((c+0.055)/1.055, vec3(2.4)), step(vec3(0.04045), c))
+}
+
+func encode(c vec3) vec3 {
+	return mix(c*12.92, 1.055*pow(c, vec3(1.0/2.4))-0.055, step(vec3(0.0031308), c))
+}
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	src := imageSrc0UnsafeAt(srcPos)
+	dst := imageSrc1UnsafeAt(srcPos)
+	if src.a > 0 {
+		src.rgb = decode(src.rgb/src.a) * src.a
+	}
+	if dst.a > 0 {
+		dst.rgb = decode(dst.rgb/dst.a) * dst.a
+	}
+	c := src + dst*(1-src.a)
+	if c.a == 0 {
+		return vec4(0)
+	}
+	return vec4(encode(c.rgb/c.a)*c.a, c.a)
+}
+`
+
+// linearBlender composites images in the linear color space.
+//
+// As blending in the graphics drivers is done in the sRGB space, linearBlender renders the source and
+// copies the destination into offscreen images, and then blends them with a shader that decodes and encodes sRGB colors.
+type linearBlender struct {
+	src    *Image
+	dst    *Image
+	shader *Shader
+
+	m sync.Mutex
+}
+
+var theLinearBlender linearBlender
+
+// drawImage draws img on dst like DrawImage, but blends the colors with source-over in the linear color space.
+func (l *linearBlender) drawImage(dst *Image, img *Image, options *DrawImageOptions) {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	// Calculate the region to be affected.
+	b := img.Bounds()
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range [][2]float64{{0, 0}, {float64(b.Dx()), 0}, {0, float64(b.Dy())}, {float64(b.Dx()), float64(b.Dy())}} {
+		x, y := options.GeoM.Apply(p[0], p[1])
+		minX = math.Min(minX, x)
+		minY = math.Min(minY, y)
+		maxX = math.Max(maxX, x)
+		maxY = math.Max(maxY, y)
+	}
+	r := image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX)), int(math.Ceil(maxY))).Intersect(dst.Bounds())
+	if r.Empty() {
+		return
+	}
+
+	l.ensureImages(r.Dx(), r.Dy())
+	tmpR := image.Rect(0, 0, r.Dx(), r.Dy())
+
+	// Render the source image with the options except for blending.
+	src := l.src.SubImage(tmpR).(*Image)
+	src.Clear()
+	op := *options
+	op.GeoM.Translate(-float64(r.Min.X), -float64(r.Min.Y))
+	op.CompositeMode = CompositeModeCustom
+	op.Blend = BlendSourceOver
+	op.LinearBlending = false
+	src.DrawImage(img, &op)
+
+	// Copy the current destination region.
+	// A sub-image is rendered with its upper-left corner at the origin, so no translation is needed.
+	d := l.dst.SubImage(tmpR).(*Image)
+	copyOp := &DrawImageOptions{}
+	copyOp.Blend = BlendCopy
+	d.DrawImage(dst.SubImage(r).(*Image), copyOp)
+
+	shaderOp := &DrawRectShaderOptions{}
+	shaderOp.GeoM.Translate(float64(r.Min.X), float64(r.Min.Y))
+	shaderOp.Blend = BlendCopy
+	shaderOp.Images[0] = src
+	shaderOp.Images[1] = d
+	dst.DrawRectShader(r.Dx(), r.Dy(), l.ensureShader(), shaderOp)
+}
+
+func (l *linearBlender) ensureImages(width, height int) {
+	if l.src != nil {
+		s := l.src.Bounds().Size()
+		if s.X >= width && s.Y >= height {
+			return
+		}
+		if width < s.X {
+			width = s.X
+		}
+		if height < s.Y {
+			height = s.Y
+		}
+		l.src.Dispose()
+		l.dst.Dispose()
+	}
+	l.src = newImage(image.Rect(0, 0, width, height), atlas.ImageTypeUnmanaged)
+	l.dst = newImage(image.Rect(0, 0, width, height), atlas.ImageTypeUnmanaged)
+}
+
+func (l *linearBlender) ensureShader() *Shader {
+	if l.shader != nil {
+		return l.shader
+	}
+	s, err := NewShader([]byte(linearBlendShaderSrc))
+	if err != nil {
+		panic(fmt.Sprintf("ebiten: NewShader for the linear blending shader failed: %v", err))
+	}
+	l.shader = s
+	return s
+}
@@ -20,14 +20,16 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/internal/ui"
 )
 
-type position struct {
-	x int
-	y int
+type touch struct {
+	x        int
+	y        int
+	pressure float64
+	radius   float64
 }
 
 var (
 	keys    = map[ui.Key]struct{}{}
-	touches = map[ui.TouchID]position{}
+	touches = map[ui.TouchID]touch{}
 )
 
 var (
@@ -36,11 +38,13 @@ var (
 
 func updateInput(runes []rune) {
 	touchSlice = touchSlice[:0]
-	for id, position := range touches {
+	for id, t := range touches {
 		touchSlice = append(touchSlice, ui.TouchForInput{
-			ID: id,
-			X:  float64(position.x),
-			Y:  float64(position.y),
+			ID:       id,
+			X:        float64(t.x),
+			Y:        float64(t.y),
+			Pressure: t.pressure,
+			Radius:   t.radius,
 		})
 	}
 
@@ -119,10 +119,19 @@ var androidKeyToSDL = map[int]int{
 	keycodeButton16:     35,
 }
 
-func UpdateTouchesOnAndroid(action int, id int, x, y int) {
+func UpdateTouchesOnAndroid(action int, id int, x, y int, pressure, radius float64) {
 	switch action {
 	case 0x00, 0x05, 0x02: // ACTION_DOWN, ACTION_POINTER_DOWN, ACTION_MOVE
-		touches[ui.TouchID(id)] = position{x, y}
+		if pressure > 1 {
+			// The pressure can exceed 1 depending on the calibration of the device.
+			pressure = 1
+		}
+		touches[ui.TouchID(id)] = touch{
+			x:        x,
+			y:        y,
+			pressure: pressure,
+			radius:   radius,
+		}
 		updateInput(nil)
 	case 0x01, 0x06: // ACTION_UP, ACTION_POINTER_UP
 		delete(touches, ui.TouchID(id))
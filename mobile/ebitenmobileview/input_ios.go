@@ -44,11 +44,16 @@ func getIDFromPtr(ptr int64) int {
 	return id
 }
 
-func UpdateTouchesOnIOS(phase int, ptr int64, x, y int) {
+func UpdateTouchesOnIOS(phase int, ptr int64, x, y int, pressure, radius float64) {
 	switch phase {
 	case C.UITouchPhaseBegan, C.UITouchPhaseMoved, C.UITouchPhaseStationary:
 		id := getIDFromPtr(ptr)
-		touches[ui.TouchID(id)] = position{x, y}
+		touches[ui.TouchID(id)] = touch{
+			x:        x,
+			y:        y,
+			pressure: pressure,
+			radius:   radius,
+		}
 		updateInput(nil)
 	case C.UITouchPhaseEnded, C.UITouchPhaseCancelled:
 		id := getIDFromPtr(ptr)
@@ -15,6 +15,8 @@
 package ebiten
 
 import (
+	"image"
+
 	"github.com/hajimehoshi/ebiten/v2/internal/ui"
 )
 
@@ -27,6 +29,37 @@ func (m *MonitorType) Name() string {
 	return (*ui.Monitor)(m).Name()
 }
 
+// RefreshRate returns the monitor's refresh rate in Hz.
+//
+// RefreshRate returns 0 when the refresh rate is unknown.
+// On browsers and mobiles, RefreshRate always returns 0 so far.
+// Browsers don't expose the display's refresh rate, and the cadence of requestAnimationFrame is not reliable
+// e.g. when the tab is in background.
+//
+// On displays with a variable refresh rate (e.g. G-SYNC or FreeSync), RefreshRate returns the nominal
+// refresh rate of the current video mode, which is the maximum rate. The actual refresh rate might be lower.
+func (m *MonitorType) RefreshRate() int {
+	return (*ui.Monitor)(m).RefreshRate()
+}
+
+// Bounds returns the monitor's bounds in device-independent pixels.
+//
+// On desktops, the origin is the upper-left corner of the primary monitor.
+// As each monitor's bounds are scaled by its own device scale factor, monitors with different device scale factors
+// might overlap or have a gap in this coordinate.
+// On browsers, Bounds returns the screen's size at the origin.
+// On the other platforms, Bounds might return an empty rectangle.
+func (m *MonitorType) Bounds() image.Rectangle {
+	return (*ui.Monitor)(m).Bounds()
+}
+
+// DeviceScaleFactor returns the monitor's device scale factor.
+//
+// The value is the same as DeviceScaleFactor while the window is on the monitor.
+func (m *MonitorType) DeviceScaleFactor() float64 {
+	return (*ui.Monitor)(m).DeviceScaleFactor()
+}
+
 // Monitor returns the current monitor.
 func Monitor() *MonitorType {
 	m := ui.Get().Monitor()
@@ -37,6 +70,12 @@ func Monitor() *MonitorType {
 }
 
 // SetMonitor sets the monitor that the window should be on. This can be called before or after Run.
+//
+// The window is placed at the center of the monitor.
+// If the window is in fullscreen mode, the window becomes fullscreen on the monitor.
+// To make the window fullscreen on a specific monitor, call SetFullscreen(true) after SetMonitor.
+//
+// SetMonitor does nothing if the monitor is already disconnected.
 func SetMonitor(monitor *MonitorType) {
 	ui.Get().Window().SetMonitor((*ui.Monitor)(monitor))
 }
@@ -44,6 +83,10 @@ func SetMonitor(monitor *MonitorType) {
 // AppendMonitors returns the monitors reported by the system.
 // On desktop platforms, there will always be at least one monitor appended and the first monitor in the slice will be the primary monitor.
 // Any monitors added or removed will show up with subsequent calls to this function.
+//
+// The monitor list can change at runtime, e.g. when a monitor is connected or disconnected.
+// A *MonitorType value for the same monitor is kept as long as the monitor's state like its bounds is not changed,
+// so the values can be compared with == to detect changes.
 func AppendMonitors(monitors []*MonitorType) []*MonitorType {
 	// TODO: This is not an efficient operation. It would be best if we could directly pass monitors directly into `ui.AppendMonitors`.
 	for _, m := range ui.Get().AppendMonitors(nil) {
@@ -51,3 +94,17 @@ func AppendMonitors(monitors []*MonitorType) []*MonitorType {
 	}
 	return monitors
 }
+
+// MonitorRefreshRate returns the refresh rate in Hz of the current monitor.
+//
+// MonitorRefreshRate returns 0 when the refresh rate is unknown or there is no current monitor.
+// See (*MonitorType).RefreshRate for the limitations.
+//
+// MonitorRefreshRate is concurrent-safe.
+func MonitorRefreshRate() int {
+	m := Monitor()
+	if m == nil {
+		return 0
+	}
+	return m.RefreshRate()
+}
@@ -0,0 +1,159 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ebiten
+
+import (
+	"fmt"
+	"sync"
+)
+
+// PendingPixels represents pixels to be read by Image.ReadPixelsOnNextFrame.
+type PendingPixels struct {
+	pixels   []byte
+	callback func(pixels []byte)
+
+	// frame is the frame count when ReadPixelsOnNextFrame is called.
+	frame int64
+
+	// read is true when the pixels are read.
+	read bool
+
+	// ready is true when the pixels are read and passed to the callback.
+	ready bool
+
+	m sync.Mutex
+}
+
+// IsReady reports whether the pixels are read.
+//
+// IsReady is concurrent-safe.
+func (p *PendingPixels) IsReady() bool {
+	p.m.Lock()
+	defer p.m.Unlock()
+	return p.ready
+}
+
+// Pixels returns the read pixels if the pixels are ready. Otherwise, Pixels returns nil.
+//
+// The returned slice is the same as the slice given to ReadPixelsOnNextFrame.
+//
+// Pixels is concurrent-safe.
+func (p *PendingPixels) Pixels() []byte {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if !p.ready {
+		return nil
+	}
+	return p.pixels
+}
+
+func (p *PendingPixels) markRead() {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.read = true
+}
+
+// ReadPixelsOnNextFrame reads the image's pixels without waiting for GPU, and returns a handle to the result.
+// The result is available from the next frame.
+//
+// Unlike ReadPixels, ReadPixelsOnNextFrame doesn't flush the enqueued graphics commands nor wait for them.
+// Reading the pixels is enqueued as a graphics command, and is executed with the other commands at the end of the frame.
+// The pixels are the image's pixels at the time ReadPixelsOnNextFrame is called.
+// The modifications after ReadPixelsOnNextFrame are not included.
+//
+// The format of pixels is the same as ReadPixels.
+// If len(pixels) is not 4 * (bounds width) * (bounds height), ReadPixelsOnNextFrame panics.
+// pixels must not be accessed until the pixels are ready.
+//
+// When the pixels are ready, callback is called with pixels if callback is not nil.
+// callback is called from the same goroutine as Update or Draw, before Update or Draw is called in a following frame.
+// Instead of callback, PendingPixels.IsReady can be used to poll the result.
+//
+// Disposing the image after ReadPixelsOnNextFrame doesn't cancel reading. The pixels are still ready at a following frame.
+//
+// If the image is disposed, ReadPixelsOnNextFrame panics.
+func (i *Image) ReadPixelsOnNextFrame(pixels []byte, callback func(pixels []byte)) *PendingPixels {
+	i.copyCheck()
+
+	if i.isDisposed() {
+		panic("ebiten: ReadPixelsOnNextFrame cannot be called on a disposed image")
+	}
+
+	b := i.Bounds()
+	if got, want := len(pixels), 4*b.Dx()*b.Dy(); got != want {
+		panic(fmt.Sprintf("ebiten: len(pixels) must be %d but %d at ReadPixelsOnNextFrame", want, got))
+	}
+
+	p := &PendingPixels{
+		pixels:   pixels,
+		callback: callback,
+	}
+	thePendingPixelsQueue.add(p)
+	i.image.ReadPixelsAsync(pixels, i.adjustedBounds(), p.markRead)
+	return p
+}
+
+// pendingPixelsQueue is a queue of PendingPixels that are not resolved yet.
+type pendingPixelsQueue struct {
+	items []*PendingPixels
+	frame int64
+	m     sync.Mutex
+}
+
+var thePendingPixelsQueue pendingPixelsQueue
+
+func (q *pendingPixelsQueue) add(p *PendingPixels) {
+	q.m.Lock()
+	defer q.m.Unlock()
+	p.frame = q.frame
+	q.items = append(q.items, p)
+}
+
+// endFrame notifies that the current frame ends.
+func (q *pendingPixelsQueue) endFrame() {
+	q.m.Lock()
+	defer q.m.Unlock()
+	q.frame++
+}
+
+// resolve makes the pixels read in the previous frames ready, and calls their callbacks.
+func (q *pendingPixelsQueue) resolve() {
+	q.m.Lock()
+	var ps []*PendingPixels
+	var n int
+	for _, p := range q.items {
+		p.m.Lock()
+		if p.frame < q.frame && p.read {
+			p.ready = true
+			p.m.Unlock()
+			ps = append(ps, p)
+			continue
+		}
+		p.m.Unlock()
+		q.items[n] = p
+		n++
+	}
+	for i := n; i < len(q.items); i++ {
+		q.items[i] = nil
+	}
+	q.items = q.items[:n]
+	q.m.Unlock()
+
+	for _, p := range ps {
+		if p.callback != nil {
+			p.callback(p.pixels)
+		}
+	}
+}
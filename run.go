@@ -245,6 +245,27 @@ type RunGameOptions struct {
 	// ScreenTransparent indicates whether the window is transparent or not.
 	// ScreenTransparent is valid on desktops and browsers.
 	//
+	// When ScreenTransparent is true, the window's framebuffer has an alpha channel and is composited with the desktop
+	// (or the web page) per pixel. The screen image is cleared with the transparent color instead of black,
+	// and the pixels' alpha values drawn on the screen are used as they are. As well as other images,
+	// the screen's colors are premultiplied alpha, so a color's RGB values must not exceed its alpha value.
+	// The region outside of the game screen (e.g. letterboxes) is also transparent.
+	//
+	// The support depends on the platform:
+	//
+	//   - Windows: The window is composited by DWM.
+	//   - macOS: The window and its layer are not opaque.
+	//   - Linux and BSD (X11): A compositing window manager is required. Otherwise, the transparent pixels are black.
+	//     Wayland is not supported natively, and the window runs via XWayland.
+	//   - Browsers: The canvas and the body's background are transparent.
+	//   - Mobiles: ScreenTransparent is ignored.
+	//
+	// As the format of the framebuffer is determined when the window is created, ScreenTransparent cannot be changed
+	// after the game starts.
+	//
+	// In order to make a non-rectangular window like a desktop mascot, combine ScreenTransparent with
+	// SetWindowDecorated(false), and SetWindowMousePassthrough(true) if mouse events should go through the window.
+	//
 	// The default (zero) value is false, which means that the window is not transparent.
 	ScreenTransparent bool
 
@@ -315,6 +336,8 @@ func RunGameWithOptions(game Game, options *RunGameOptions) error {
 		atomic.StoreInt32(&screenTransparent, 0)
 	}
 	g := newGameForUI(game, op.ScreenTransparent)
+	// The screen is no longer available after the main loop ends.
+	defer theGameForUI.Store((*gameForUI)(nil))
 
 	if err := ui.Get().Run(g, op); err != nil {
 		if errors.Is(err, Termination) {
@@ -363,6 +386,7 @@ func CursorMode() CursorModeType {
 // CursorModeVisible sets the cursor to always be visible.
 // CursorModeHidden hides the system cursor when over the window.
 // CursorModeCaptured hides the system cursor and locks it to the window.
+// Use CursorDelta to get the cursor movement in CursorModeCaptured.
 //
 // CursorModeCaptured also works on browsers.
 // When the user exits the captured mode not by SetCursorMode but by the UI (e.g., pressing ESC),
@@ -463,6 +487,35 @@ func SetRunnableOnUnfocused(runnableOnUnfocused bool) {
 	ui.Get().SetRunnableOnUnfocused(runnableOnUnfocused)
 }
 
+// CaptureScreenAsImage returns a copy of the game screen's content that was rendered most recently.
+//
+// The returned image's size is the game screen's size returned by Layout, not the window's size in device pixels,
+// and is not affected by the device scale factor. The returned image doesn't include the letterboxes or
+// the result of FinalScreenDrawer.
+// The returned image's colors are premultiplied alpha as image.RGBA. If the screen is not transparent
+// (see RunGameOptions.ScreenTransparent), the returned image is opaque as the screen is shown on a black background.
+//
+// CaptureScreenAsImage is intended to be called from Update, where the screen keeps the result of the last Draw.
+// If CaptureScreenAsImage is called during Draw, the returned image includes only what is rendered in the current
+// Draw so far. The pixels are read after all the preceding rendering commands are executed.
+//
+// CaptureScreenAsImage returns nil before the first frame starts or after the game ends.
+//
+// To save the image as a PNG file, use image/png's Encode.
+//
+// CaptureScreenAsImage is concurrent-safe, but it can't be called before the main loop starts.
+func CaptureScreenAsImage() image.Image {
+	g, ok := theGameForUI.Load().(*gameForUI)
+	if !ok || g == nil {
+		return nil
+	}
+	img := g.captureOffscreen()
+	if img == nil {
+		return nil
+	}
+	return img
+}
+
 // DeviceScaleFactor returns a device scale factor value of the current monitor which the window belongs to.
 //
 // DeviceScaleFactor returns a meaningful value on high-DPI display environment,
@@ -489,6 +542,9 @@ func IsVsyncEnabled() bool {
 
 // SetVsyncEnabled sets a boolean value indicating whether
 // the game uses the display's vsync.
+//
+// SetVsyncEnabled can be called even while the game is running, and the graphics context is not recreated.
+// See also SetMaxFPS.
 func SetVsyncEnabled(enabled bool) {
 	if enabled {
 		ui.Get().SetFPSMode(ui.FPSModeVsyncOn)
@@ -497,6 +553,34 @@ func SetVsyncEnabled(enabled bool) {
 	}
 }
 
+// MaxFPS returns the current maximum FPS (frames per second).
+// If the maximum FPS is not specified, MaxFPS returns 0.
+//
+// MaxFPS is concurrent-safe.
+func MaxFPS() int {
+	return ui.Get().MaxFPS()
+}
+
+// SetMaxFPS sets the maximum FPS (frames per second), that limits how many times Draw is called in a second.
+// If fps is 0 or negative, FPS is not limited by software. The initial value is 0.
+//
+// SetMaxFPS limits FPS by sleeping regardless of the display's refresh rate.
+// This is useful e.g. to cap FPS with SetVsyncEnabled(false) on devices where vsync misbehaves.
+// If vsync is enabled, FPS is also limited by the display's refresh rate.
+// SetVsyncEnabled and SetMaxFPS can be called at any time, even while the game is running.
+//
+// Sleeping is less accurate than vsync, and the frame pacing might not be smooth.
+// With vsync disabled, a smaller maximum FPS consumes less power, and no limit consumes the most power.
+//
+// TPS is independent of FPS. See also SetTPS.
+//
+// SetMaxFPS doesn't work on mobiles so far.
+//
+// SetMaxFPS is concurrent-safe.
+func SetMaxFPS(fps int) {
+	ui.Get().SetMaxFPS(fps)
+}
+
 // FPSModeType is a type of FPS modes.
 //
 // Deprecated: as of v2.5. Use SetVsyncEnabled instead.
@@ -565,9 +649,13 @@ func ScheduleFrame() {
 
 // TPS returns the current maximum TPS.
 //
+// If TPS is set to SyncWithRefreshRate, TPS returns the refresh rate of the current monitor,
+// or DefaultTPS if the refresh rate is unknown, so that TPS can be used as a number of ticks in a second.
+// If TPS is set to SyncWithFPS, TPS returns SyncWithFPS.
+//
 // TPS is concurrent-safe.
 func TPS() int {
-	return clock.TPS()
+	return clock.EffectiveTPS()
 }
 
 // MaxTPS returns the current maximum TPS.
@@ -587,6 +675,21 @@ func ActualTPS() float64 {
 	return clock.ActualTPS()
 }
 
+// TickFraction returns the progress of the time from the last tick to the next tick in [0, 1).
+//
+// Update is called at a fixed timestep, that is 1/TPS seconds, and might be called multiple times
+// or not called at all in one frame.
+// To render the game smoothly regardless of the frame rate, Draw can interpolate the states
+// between the previous tick and the current tick with TickFraction, e.g. prev + (current-prev)*TickFraction().
+// Note that the interpolation delays the rendering by up to one tick.
+//
+// If TPS is SyncWithFPS, TickFraction always returns 0.
+//
+// TickFraction is concurrent-safe.
+func TickFraction() float64 {
+	return clock.TickFraction()
+}
+
 // CurrentTPS returns the current TPS (ticks per second),
 // that represents how many Update function is called in a second.
 //
@@ -598,6 +701,16 @@ func CurrentTPS() float64 {
 // SyncWithFPS is a special TPS value that means TPS syncs with FPS.
 const SyncWithFPS = clock.SyncWithFPS
 
+// SyncWithRefreshRate is a special TPS value that means TPS follows the refresh rate of the current monitor.
+//
+// Unlike SyncWithFPS, the game is updated based on the monitor's refresh rate even when vsync is disabled.
+// When the window moves to another monitor with a different refresh rate, TPS follows the new monitor.
+//
+// A non-integer refresh rate like 59.94 Hz is rounded to the nearest integer.
+// If the refresh rate is unknown e.g. on browsers and mobiles, DefaultTPS is used instead.
+// See (*MonitorType).RefreshRate for the limitations.
+const SyncWithRefreshRate = clock.SyncWithRefreshRate
+
 // UncappedTPS is a special TPS value that means TPS syncs with FPS.
 //
 // Deprecated: as of v2.2. Use SyncWithFPS instead.
@@ -608,7 +721,8 @@ const UncappedTPS = SyncWithFPS
 // The initial value is 60.
 //
 // If tps is SyncWithFPS, TPS is uncapped and the game is updated per frame.
-// If tps is negative but not SyncWithFPS, SetTPS panics.
+// If tps is SyncWithRefreshRate, TPS follows the refresh rate of the current monitor.
+// If tps is negative but not SyncWithFPS or SyncWithRefreshRate, SetTPS panics.
 //
 // SetTPS is concurrent-safe.
 func SetTPS(tps int) {
@@ -699,3 +813,15 @@ func toUIRunOptions(options *RunGameOptions) *ui.RunOptions {
 func DroppedFiles() fs.FS {
 	return theInputState.droppedFiles()
 }
+
+// DroppedFilesPosition returns the position where the files are dropped, in the same 'logical' coordinate as CursorPosition.
+//
+// DroppedFilesPosition is valid only when DroppedFiles returns a non-nil value.
+//
+// DroppedFilesPosition works on desktops and browsers.
+//
+// DroppedFilesPosition is concurrent-safe.
+func DroppedFilesPosition() (x, y int) {
+	dx, dy := theInputState.droppedFilesPosition()
+	return int(dx), int(dy)
+}
@@ -30,6 +30,30 @@ import (
 type Shader struct {
 	shader *ui.Shader
 	unit   shaderir.Unit
+
+	// src is the Kage source. src is nil for a built-in shader.
+	src []byte
+
+	// ir is the compiled program. ir is nil for a built-in shader.
+	ir *shaderir.Program
+
+	uniforms []ShaderUniform
+
+	// origin is the shader with the default entry point if this shader is created by WithFragmentEntry.
+	origin *Shader
+
+	// fragmentEntry is the name of the entry point if this shader is created by WithFragmentEntry.
+	fragmentEntry string
+
+	fragmentEntryShaders  map[string]*Shader
+	fragmentEntryShadersM sync.Mutex
+
+	// compilation is the background compilation if this shader is created by NewShaderAsync.
+	// The other members are available only after the compilation is done.
+	compilation *ShaderCompilation
+
+	// fallback is the shader used for drawing while the compilation is not done.
+	fallback *Shader
 }
 
 // NewShader compiles a shader program in the shading language Kage, and returns the result.
@@ -43,16 +67,356 @@ func NewShader(src []byte) (*Shader, error) {
 		return nil, err
 	}
 	return &Shader{
-		shader: ui.NewShader(ir),
-		unit:   ir.Unit,
+		shader:   ui.NewShader(ir),
+		unit:     ir.Unit,
+		src:      append([]byte(nil), src...),
+		ir:       ir,
+		uniforms: shaderUniformsFromProgram(ir),
+	}, nil
+}
+
+// NewShaderFromBinary creates a shader from the data encoded by Shader.MarshalBinary.
+//
+// NewShaderFromBinary doesn't compile the Kage source again, so this is faster than NewShader.
+//
+// If the data was encoded by a different version of Ebitengine, or the compiled program in the data is broken,
+// NewShaderFromBinary compiles the source in the data again.
+// If the data is broken and the source cannot be read, NewShaderFromBinary returns an error.
+func NewShaderFromBinary(data []byte) (*Shader, error) {
+	src, ir, err := graphics.UnmarshalShader(data)
+	if err != nil {
+		return nil, err
+	}
+	return &Shader{
+		shader:   ui.NewShader(ir),
+		unit:     ir.Unit,
+		src:      src,
+		ir:       ir,
+		uniforms: shaderUniformsFromProgram(ir),
 	}, nil
 }
 
+// MarshalBinary encodes the compiled shader program into bytes. MarshalBinary implements encoding.BinaryMarshaler.
+//
+// The result can be passed to NewShaderFromBinary to create the same shader without compiling the source again.
+// This is useful to warm up shaders at startup, e.g., by saving the results to files at the first launch
+// and loading them at the following launches.
+//
+// The format is versioned, and the compiled program is valid only for the same version of Ebitengine.
+// NewShaderFromBinary compiles the source again for data encoded by a different version.
+//
+// MarshalBinary returns an error for a built-in shader or a shader created by WithFragmentEntry.
+//
+// MarshalBinary is concurrent-safe.
+func (s *Shader) MarshalBinary() ([]byte, error) {
+	if err := s.waitCompilation(); err != nil {
+		return nil, err
+	}
+	if s.origin != nil {
+		return nil, fmt.Errorf("ebiten: MarshalBinary is not available for a shader created by WithFragmentEntry")
+	}
+	if s.ir == nil {
+		return nil, fmt.Errorf("ebiten: MarshalBinary is not available for a built-in shader")
+	}
+	return graphics.MarshalShader(s.src, s.ir)
+}
+
+// NewShaderAsyncOptions represents options for NewShaderAsync.
+type NewShaderAsyncOptions struct {
+	// Fallback is the shader used for drawing instead while the compilation is not done.
+	//
+	// If Fallback is nil, drawing with the shader blocks until the compilation is done.
+	//
+	// The uniform variables specified for drawing are passed to Fallback as they are.
+	// Uniform variables that Fallback doesn't declare are ignored.
+	Fallback *Shader
+}
+
+// ShaderCompilation represents a compilation of a shader program running in background.
+type ShaderCompilation struct {
+	shader *Shader
+	err    error
+
+	// done is closed when the compilation is done.
+	done chan struct{}
+}
+
+// NewShaderAsync starts compiling a shader program in the shading language Kage in background,
+// and returns a handle of the compilation immediately.
+//
+// This is useful to compile shaders during a loading screen without blocking the game,
+// and to avoid a stutter at the first draw call with a shader.
+// When the Kage compilation is done, the compilation by the graphics driver is also done at the next frame.
+//
+// The shader returned by ShaderCompilation.Shader can be used for drawing before the compilation is done.
+// In this case, the fallback shader is used if options.Fallback is specified. Otherwise, the draw call blocks.
+//
+// If the compilation fails, the error is available from ShaderCompilation.Err,
+// and drawing with the shader panics.
+func NewShaderAsync(src []byte, options *NewShaderAsyncOptions) *ShaderCompilation {
+	if options == nil {
+		options = &NewShaderAsyncOptions{}
+	}
+
+	c := &ShaderCompilation{
+		done: make(chan struct{}),
+	}
+	c.shader = &Shader{
+		compilation: c,
+		fallback:    options.Fallback,
+	}
+
+	src = append([]byte(nil), src...)
+	go func() {
+		defer close(c.done)
+		ir, err := graphics.CompileShader(src)
+		if err != nil {
+			c.err = err
+			return
+		}
+		s := c.shader
+		s.shader = ui.NewShader(ir)
+		s.unit = ir.Unit
+		s.src = src
+		s.ir = ir
+		s.uniforms = shaderUniformsFromProgram(ir)
+		s.shader.Preload()
+	}()
+	return c
+}
+
+// Shader returns the shader being compiled.
+//
+// The returned shader can be used for drawing even before the compilation is done.
+// See NewShaderAsync for the behavior in this case.
+// The other functions of the returned shader block until the compilation is done.
+//
+// Shader is concurrent-safe.
+func (c *ShaderCompilation) Shader() *Shader {
+	return c.shader
+}
+
+// IsReady reports whether the compilation is done, regardless of whether the compilation succeeded.
+//
+// IsReady doesn't block.
+//
+// IsReady is concurrent-safe.
+func (c *ShaderCompilation) IsReady() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Err returns the error of the compilation.
+//
+// Err returns nil if the compilation is not done yet or the compilation succeeded.
+//
+// Err is concurrent-safe.
+func (c *ShaderCompilation) Err() error {
+	if !c.IsReady() {
+		return nil
+	}
+	return c.err
+}
+
+// Wait blocks until the compilation is done, and returns the error of the compilation.
+//
+// Wait is concurrent-safe.
+func (c *ShaderCompilation) Wait() error {
+	<-c.done
+	return c.err
+}
+
+// waitCompilation blocks until the compilation is done if the shader is created by NewShaderAsync.
+// waitCompilation returns the error of the compilation.
+func (s *Shader) waitCompilation() error {
+	if s.compilation == nil {
+		return nil
+	}
+	return s.compilation.Wait()
+}
+
+// shaderForDrawing returns the shader actually used for drawing.
+//
+// If the shader is being compiled in background, shaderForDrawing returns the fallback shader if exists,
+// or blocks until the compilation is done.
+func (s *Shader) shaderForDrawing() *Shader {
+	if s.compilation == nil {
+		return s
+	}
+	if !s.compilation.IsReady() && s.fallback != nil {
+		return s.fallback.shaderForDrawing()
+	}
+	if err := s.compilation.Wait(); err != nil {
+		panic(fmt.Sprintf("ebiten: the given shader failed to compile: %v", err))
+	}
+	return s
+}
+
+// ShaderUniform represents a uniform variable declared in a shader.
+type ShaderUniform struct {
+	// Name is the name of the uniform variable.
+	Name string
+
+	// Type is the type of the uniform variable in the Kage notation, e.g. "float", "ivec2", "mat4", or "[4]vec4".
+	Type string
+
+	// ValueCount is the number of the numeric values for the uniform variable.
+	// For example, ValueCount is 1 for float, 16 for mat4, and 8 for [4]vec2.
+	// If the type is a vector, a matrix, or an array, a slice or an array of this length
+	// is specified to DrawTrianglesShaderOptions.Uniforms or DrawRectShaderOptions.Uniforms.
+	ValueCount int
+}
+
+func shaderUniformsFromProgram(ir *shaderir.Program) []ShaderUniform {
+	var uniforms []ShaderUniform
+	for i, name := range ir.UniformNames[graphics.PreservedUniformVariablesCount:] {
+		t := ir.Uniforms[graphics.PreservedUniformVariablesCount+i]
+		uniforms = append(uniforms, ShaderUniform{
+			Name:       name,
+			Type:       t.String(),
+			ValueCount: t.Uint32Count(),
+		})
+	}
+	return uniforms
+}
+
+// Uniforms returns the uniform variables declared in the shader, in the order of the declarations.
+//
+// This is useful to build a tool like a property inspector for a shader without parsing the source.
+//
+// For a built-in shader, Uniforms returns nil.
+//
+// Uniforms is concurrent-safe.
+func (s *Shader) Uniforms() []ShaderUniform {
+	_ = s.waitCompilation()
+	if len(s.uniforms) == 0 {
+		return nil
+	}
+	uniforms := make([]ShaderUniform, len(s.uniforms))
+	copy(uniforms, s.uniforms)
+	return uniforms
+}
+
+// WithFragmentEntry returns a shader that uses the function of the given name as the fragment shader entry point
+// instead of Fragment.
+//
+// This enables one Kage program to have multiple effects, e.g.:
+//
+//	func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+//		return imageSrc0At(srcPos)
+//	}
+//
+//	func Grayscale(dstPos vec4, srcPos vec2, color vec4) vec4 {
+//		c := imageSrc0At(srcPos)
+//		y := dot(c.rgb, vec3(0.299, 0.587, 0.114))
+//		return vec4(y, y, y, c.a)
+//	}
+//
+// The function must have the same signature as Fragment.
+// The other functions, including Fragment, are treated as regular functions.
+// As well as Fragment, the entry point function cannot be called from other functions.
+// Uniform variables are shared among the entry points, so the same Uniforms can be used for all of them.
+//
+// Each entry point is compiled into a separate shader program.
+// The result is cached, so calling WithFragmentEntry with the same name at every draw call is cheap.
+// WithFragmentEntry with "Fragment" returns the shader itself.
+//
+// WithFragmentEntry returns an error if compiling the program with the given entry point fails,
+// e.g., the function doesn't exist or the function's signature is not valid for a fragment shader.
+//
+// The returned shader has its own compiled program, and its lifetime is related to the original shader as follows:
+//
+//   - Deallocate affects only the shader it is called on. Deallocating the original shader doesn't affect the returned shaders,
+//     and deallocating a returned shader doesn't affect the original shader.
+//   - Disposing the original shader disposes all the returned shaders too. After that, WithFragmentEntry returns an error.
+//   - Disposing a returned shader doesn't affect the original shader. After that, WithFragmentEntry returns a new shader for the same name.
+//
+// WithFragmentEntry is concurrent-safe.
+func (s *Shader) WithFragmentEntry(name string) (*Shader, error) {
+	if s.origin != nil {
+		return s.origin.WithFragmentEntry(name)
+	}
+	if err := s.waitCompilation(); err != nil {
+		return nil, err
+	}
+
+	s.fragmentEntryShadersM.Lock()
+	defer s.fragmentEntryShadersM.Unlock()
+
+	if s.isDisposed() {
+		return nil, fmt.Errorf("ebiten: the shader is already disposed")
+	}
+	if name == "Fragment" {
+		return s, nil
+	}
+	if s.src == nil {
+		return nil, fmt.Errorf("ebiten: WithFragmentEntry is not available for a built-in shader")
+	}
+
+	if shader, ok := s.fragmentEntryShaders[name]; ok {
+		return shader, nil
+	}
+
+	ir, err := graphics.CompileShaderWithFragmentEntry(s.src, name)
+	if err != nil {
+		return nil, err
+	}
+	shader := &Shader{
+		shader:        ui.NewShader(ir),
+		unit:          ir.Unit,
+		src:           s.src,
+		uniforms:      shaderUniformsFromProgram(ir),
+		origin:        s,
+		fragmentEntry: name,
+	}
+	if s.fragmentEntryShaders == nil {
+		s.fragmentEntryShaders = map[string]*Shader{}
+	}
+	s.fragmentEntryShaders[name] = shader
+	return shader, nil
+}
+
 // Dispose disposes the shader program.
 // After disposing, the shader is no longer available.
 //
+// If the shader is created by WithFragmentEntry, Dispose removes the shader from the original shader's cache.
+// If the shader has shaders created by WithFragmentEntry, Dispose disposes them too.
+//
 // Deprecated: as of v2.7. Use Deallocate instead.
 func (s *Shader) Dispose() {
+	if err := s.waitCompilation(); err != nil {
+		return
+	}
+
+	if o := s.origin; o != nil {
+		o.fragmentEntryShadersM.Lock()
+		if o.fragmentEntryShaders[s.fragmentEntry] == s {
+			delete(o.fragmentEntryShaders, s.fragmentEntry)
+		}
+		o.fragmentEntryShadersM.Unlock()
+		s.dispose()
+		return
+	}
+
+	s.fragmentEntryShadersM.Lock()
+	shaders := s.fragmentEntryShaders
+	s.fragmentEntryShaders = nil
+	s.dispose()
+	s.fragmentEntryShadersM.Unlock()
+
+	for _, shader := range shaders {
+		shader.dispose()
+	}
+}
+
+func (s *Shader) dispose() {
+	if s.shader == nil {
+		return
+	}
 	s.shader.Deallocate()
 	s.shader = nil
 }
@@ -71,6 +435,9 @@ func (s *Shader) isDisposed() bool {
 //
 // If the shader is disposed, Deallocate does nothing.
 func (s *Shader) Deallocate() {
+	if err := s.waitCompilation(); err != nil {
+		return
+	}
 	if s.shader == nil {
 		return
 	}
@@ -19,6 +19,7 @@ import (
 	"image"
 	"image/color"
 	"math"
+	"reflect"
 	"testing"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -2394,3 +2395,673 @@ func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
 		}
 	}
 }
+
+func TestShaderWithFragmentEntry(t *testing.T) {
+	const w, h = 16, 16
+
+	s, err := ebiten.NewShader([]byte(`//kage:unit pixels
+
+package main
+
+var Alpha float
+
+func red() vec4 {
+	return vec4(1, 0, 0, 1) * Alpha
+}
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	return red()
+}
+
+func Blue(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	return vec4(0, 0, 1, 1) * Alpha
+}
+
+func Red(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	return red()
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		Entry string
+		Want  color.RGBA
+	}{
+		{Entry: "Fragment", Want: color.RGBA{R: 0xff, A: 0xff}},
+		{Entry: "Blue", Want: color.RGBA{B: 0xff, A: 0xff}},
+		{Entry: "Red", Want: color.RGBA{R: 0xff, A: 0xff}},
+	} {
+		es, err := s.WithFragmentEntry(tc.Entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if es2, err := s.WithFragmentEntry(tc.Entry); err != nil || es2 != es {
+			t.Errorf("WithFragmentEntry(%q) must return the cached shader", tc.Entry)
+		}
+
+		dst := ebiten.NewImage(w, h)
+		op := &ebiten.DrawRectShaderOptions{}
+		op.Uniforms = map[string]any{
+			"Alpha": 1,
+		}
+		dst.DrawRectShader(w, h, es, op)
+		if got := dst.At(0, 0).(color.RGBA); !sameColors(got, tc.Want, 2) {
+			t.Errorf("entry: %s, got: %v, want: %v", tc.Entry, got, tc.Want)
+		}
+	}
+
+	if _, err := s.WithFragmentEntry("Missing"); err == nil {
+		t.Errorf("WithFragmentEntry with a missing function must return an error")
+	}
+}
+
+func TestShaderWithFragmentEntryDispose(t *testing.T) {
+	const w, h = 16, 16
+
+	s, err := ebiten.NewShader([]byte(`//kage:unit pixels
+
+package main
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	return vec4(1, 0, 0, 1)
+}
+
+func Blue(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	return vec4(0, 0, 1, 1)
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	es, err := s.WithFragmentEntry("Blue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	es.Dispose()
+
+	// After the derived shader is disposed, a new shader is returned.
+	es2, err := s.WithFragmentEntry("Blue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if es2 == es {
+		t.Errorf("WithFragmentEntry must not return the disposed shader")
+	}
+	dst := ebiten.NewImage(w, h)
+	dst.DrawRectShader(w, h, es2, nil)
+	if got, want := dst.At(0, 0).(color.RGBA), (color.RGBA{B: 0xff, A: 0xff}); !sameColors(got, want, 2) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+
+	// After the original shader is disposed, WithFragmentEntry returns an error.
+	s.Dispose()
+	if _, err := s.WithFragmentEntry("Blue"); err == nil {
+		t.Errorf("WithFragmentEntry for a disposed shader must return an error")
+	}
+	if _, err := es2.WithFragmentEntry("Blue"); err == nil {
+		t.Errorf("WithFragmentEntry for a shader derived from a disposed shader must return an error")
+	}
+}
+
+func TestShaderWithFragmentEntryDisposeOriginal(t *testing.T) {
+	const w, h = 16, 16
+
+	s, err := ebiten.NewShader([]byte(`//kage:unit pixels
+
+package main
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	return vec4(1, 0, 0, 1)
+}
+
+func Blue(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	return vec4(0, 0, 1, 1)
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	es, err := s.WithFragmentEntry("Blue")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Deallocating the original shader doesn't affect the derived shader, and vice versa.
+	s.Deallocate()
+	dst := ebiten.NewImage(w, h)
+	dst.DrawRectShader(w, h, es, nil)
+	if got, want := dst.At(0, 0).(color.RGBA), (color.RGBA{B: 0xff, A: 0xff}); !sameColors(got, want, 2) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+	es.Deallocate()
+	dst.DrawRectShader(w, h, s, nil)
+	if got, want := dst.At(0, 0).(color.RGBA), (color.RGBA{R: 0xff, A: 0xff}); !sameColors(got, want, 2) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+
+	// Disposing the original shader disposes the derived shader too.
+	s.Dispose()
+	defer func() {
+		if recover() == nil {
+			t.Errorf("DrawRectShader with a shader derived from a disposed shader must panic but not")
+		}
+	}()
+	dst.DrawRectShader(w, h, es, nil)
+}
+
+func TestShaderUniforms(t *testing.T) {
+	s, err := ebiten.NewShader([]byte(`//kage:unit pixels
+
+package main
+
+var Time float
+var Cursor vec2
+var Lights [4]vec4
+var Mode int
+var Projection mat4
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	return vec4(0)
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := s.Uniforms()
+	want := []ebiten.ShaderUniform{
+		{Name: "Time", Type: "float", ValueCount: 1},
+		{Name: "Cursor", Type: "vec2", ValueCount: 2},
+		{Name: "Lights", Type: "[4]vec4", ValueCount: 16},
+		{Name: "Mode", Type: "int", ValueCount: 1},
+		{Name: "Projection", Type: "mat4", ValueCount: 16},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+}
+
+func TestShaderUniformStructs(t *testing.T) {
+	const w, h = 16, 16
+
+	s, err := ebiten.NewShader([]byte(`//kage:unit pixels
+
+package main
+
+var Lights [4]vec4
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	// Each light consists of a color (vec4) and an intensity and padding (vec4).
+	return Lights[2] * Lights[3].x
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type light struct {
+		Color     [4]float32
+		Intensity float32
+		_         [3]float32
+	}
+
+	dst := ebiten.NewImage(w, h)
+	op := &ebiten.DrawRectShaderOptions{}
+	op.Uniforms = map[string]any{
+		"Lights": []light{
+			{Color: [4]float32{1, 0, 0, 1}, Intensity: 1},
+			{Color: [4]float32{0, 1, 0, 1}, Intensity: 0.5},
+		},
+	}
+	dst.DrawRectShader(w, h, s, op)
+	if got, want := dst.At(0, 0).(color.RGBA), (color.RGBA{G: 0x80, A: 0x80}); !sameColors(got, want, 2) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+
+	// The structs don't fill the array.
+	func() {
+		defer func() {
+			if e := recover(); e == nil {
+				t.Errorf("DrawRectShader with a wrong number of structs must panic")
+			}
+		}()
+		op.Uniforms = map[string]any{
+			"Lights": []light{
+				{Color: [4]float32{1, 0, 0, 1}, Intensity: 1},
+			},
+		}
+		dst.DrawRectShader(w, h, s, op)
+	}()
+}
+
+func TestShaderBoolAndIntUniforms(t *testing.T) {
+	const w, h = 16, 16
+
+	s, err := ebiten.NewShader([]byte(`//kage:unit pixels
+
+package main
+
+var Flag bool
+var Flags [2]bool
+var Mode int
+var Scale float
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	r := 0.0
+	if Flag {
+		r = 1
+	}
+	g := 0.0
+	if Flags[1] {
+		g = 1
+	}
+	b := 0.0
+	// 16777217 (2^24 + 1) cannot be represented as a float32 exactly.
+	if Mode == 16777217 {
+		b = 1
+	}
+	return vec4(r, g, b, 1) * Scale
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Name     string
+		Uniforms map[string]any
+		Want     color.RGBA
+	}{
+		{
+			Name: "bool",
+			Uniforms: map[string]any{
+				"Flag":  true,
+				"Flags": []bool{false, true},
+				"Mode":  1<<24 + 1,
+				"Scale": 1,
+			},
+			Want: color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+		},
+		{
+			Name: "false",
+			Uniforms: map[string]any{
+				"Flag":  false,
+				"Flags": [2]bool{true, false},
+				"Mode":  1 << 24,
+				"Scale": 1.0,
+			},
+			Want: color.RGBA{A: 0xff},
+		},
+		{
+			Name: "numeric",
+			Uniforms: map[string]any{
+				"Flag":  1,
+				"Flags": []int{0, 2},
+				"Mode":  int64(1<<24 + 1),
+				"Scale": uint8(1),
+			},
+			Want: color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+		},
+		{
+			Name: "float for int",
+			Uniforms: map[string]any{
+				"Flag":  true,
+				"Flags": [2]bool{false, true},
+				"Mode":  16777217.75,
+				"Scale": float32(1),
+			},
+			Want: color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			dst := ebiten.NewImage(w, h)
+			defer dst.Deallocate()
+
+			op := &ebiten.DrawRectShaderOptions{}
+			op.Uniforms = tc.Uniforms
+			dst.DrawRectShader(w, h, s, op)
+			if got, want := dst.At(0, 0).(color.RGBA), tc.Want; !sameColors(got, want, 2) {
+				t.Errorf("got: %v, want: %v", got, want)
+			}
+		})
+	}
+}
+
+func TestShaderBoolAndFloatUniformsMismatch(t *testing.T) {
+	const w, h = 16, 16
+
+	s, err := ebiten.NewShader([]byte(`//kage:unit pixels
+
+package main
+
+var Flag bool
+var Flags [2]bool
+var Scale float
+var Color vec2
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	if Flag || Flags[1] {
+		return vec4(Color, 0, 1) * Scale
+	}
+	return vec4(0)
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Name     string
+		Uniforms map[string]any
+	}{
+		{
+			Name:     "bool for float",
+			Uniforms: map[string]any{"Scale": true},
+		},
+		{
+			Name:     "bools for vec2",
+			Uniforms: map[string]any{"Color": []bool{true, false}},
+		},
+		{
+			Name:     "float for bool",
+			Uniforms: map[string]any{"Flag": 1.0},
+		},
+		{
+			Name:     "floats for [2]bool",
+			Uniforms: map[string]any{"Flags": [2]float32{0, 1}},
+		},
+		{
+			Name: "struct with a float for [2]bool",
+			Uniforms: map[string]any{"Flags": []struct {
+				A bool
+				B float32
+			}{{true, 1}}},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			dst := ebiten.NewImage(w, h)
+			defer dst.Deallocate()
+
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("DrawRectShader must panic but not")
+				}
+			}()
+
+			op := &ebiten.DrawRectShaderOptions{}
+			op.Uniforms = tc.Uniforms
+			dst.DrawRectShader(w, h, s, op)
+		})
+	}
+}
+
+func TestShaderImageSrcAtLod(t *testing.T) {
+	const w, h = 16, 16
+
+	src := ebiten.NewImage(w, h)
+	defer src.Deallocate()
+	pix := make([]byte, 4*w*h)
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			idx := 4 * (i + j*w)
+			pix[idx] = byte(i * 16)
+			pix[idx+1] = byte(j * 16)
+			pix[idx+3] = 0xff
+		}
+	}
+	src.WritePixels(pix)
+
+	for _, unit := range []string{"texels", "pixels"} {
+		unit := unit
+		t.Run(fmt.Sprintf("unit %s", unit), func(t *testing.T) {
+			// The source textures have only the base level, so the results must be the same regardless of lod.
+			s, err := ebiten.NewShader([]byte(fmt.Sprintf(`//kage:unit %s
+
+package main
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	return imageSrc0AtLod(srcPos, 3)
+}
+`, unit)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.Deallocate()
+
+			dst := ebiten.NewImage(w, h)
+			defer dst.Deallocate()
+
+			op := &ebiten.DrawRectShaderOptions{}
+			op.Images[0] = src
+			dst.DrawRectShader(w, h, s, op)
+
+			for j := 0; j < h; j++ {
+				for i := 0; i < w; i++ {
+					got := dst.At(i, j).(color.RGBA)
+					want := src.At(i, j).(color.RGBA)
+					if !sameColors(got, want, 1) {
+						t.Errorf("dst.At(%d, %d): got: %v, want: %v", i, j, got, want)
+					}
+				}
+			}
+		})
+	}
+
+	if _, err := ebiten.NewShader([]byte(`//kage:unit pixels
+
+package main
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	return imageSrc0AtLod(srcPos, ivec2(1))
+}
+`)); err == nil {
+		t.Errorf("NewShader with a non-float lod must return an error but not")
+	}
+}
+
+func TestShaderMarshalBinary(t *testing.T) {
+	const w, h = 16, 16
+
+	s, err := ebiten.NewShader([]byte(`//kage:unit pixels
+
+package main
+
+var Color vec4
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	return Color
+}
+
+func Half(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	return Color / 2
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2, err := ebiten.NewShaderFromBinary(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := s2.Uniforms(), s.Uniforms(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Uniforms(): got: %v, want: %v", got, want)
+	}
+
+	dst := ebiten.NewImage(w, h)
+	op := &ebiten.DrawRectShaderOptions{}
+	op.Uniforms = map[string]any{
+		"Color": []float32{1, 0, 0, 1},
+	}
+	dst.DrawRectShader(w, h, s2, op)
+	if got, want := dst.At(0, 0).(color.RGBA), (color.RGBA{R: 0xff, A: 0xff}); !sameColors(got, want, 2) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+
+	// The source is kept, so WithFragmentEntry is available.
+	hs, err := s2.WithFragmentEntry("Half")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst.Clear()
+	dst.DrawRectShader(w, h, hs, op)
+	if got, want := dst.At(0, 0).(color.RGBA), (color.RGBA{R: 0x80, A: 0x80}); !sameColors(got, want, 2) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+	if _, err := hs.MarshalBinary(); err == nil {
+		t.Errorf("MarshalBinary for a shader created by WithFragmentEntry must return an error but not")
+	}
+
+	if _, err := ebiten.NewShaderFromBinary(data[:20]); err == nil {
+		t.Errorf("NewShaderFromBinary with truncated data must return an error but not")
+	}
+
+	// If the compiled program is broken, the source is compiled again.
+	s3, err := ebiten.NewShaderFromBinary(data[:len(data)-8])
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst.Clear()
+	dst.DrawRectShader(w, h, s3, op)
+	if got, want := dst.At(0, 0).(color.RGBA), (color.RGBA{R: 0xff, A: 0xff}); !sameColors(got, want, 2) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+	if _, err := ebiten.NewShaderFromBinary([]byte("foo")); err == nil {
+		t.Errorf("NewShaderFromBinary with invalid data must return an error but not")
+	}
+}
+
+func TestShaderAsync(t *testing.T) {
+	const w, h = 16, 16
+
+	fallback, err := ebiten.NewShader([]byte(`//kage:unit pixels
+
+package main
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	return vec4(0, 0, 1, 1)
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := ebiten.NewShaderAsync([]byte(`//kage:unit pixels
+
+package main
+
+var Color vec4
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	return Color
+}
+`), &ebiten.NewShaderAsyncOptions{
+		Fallback: fallback,
+	})
+
+	dst := ebiten.NewImage(w, h)
+	op := &ebiten.DrawRectShaderOptions{}
+	op.Uniforms = map[string]any{
+		"Color": []float32{1, 0, 0, 1},
+	}
+
+	// Whether the fallback is used or not depends on the timing.
+	dst.DrawRectShader(w, h, c.Shader(), op)
+	if got := dst.At(0, 0).(color.RGBA); got != (color.RGBA{R: 0xff, A: 0xff}) && got != (color.RGBA{B: 0xff, A: 0xff}) {
+		t.Errorf("got: %v, want: red or blue", got)
+	}
+
+	if err := c.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if !c.IsReady() {
+		t.Errorf("IsReady(): got: false, want: true")
+	}
+	if got, want := len(c.Shader().Uniforms()), 1; got != want {
+		t.Errorf("len(Uniforms()): got: %d, want: %d", got, want)
+	}
+
+	dst.Clear()
+	dst.DrawRectShader(w, h, c.Shader(), op)
+	if got, want := dst.At(0, 0).(color.RGBA), (color.RGBA{R: 0xff, A: 0xff}); got != want {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+
+	// Without a fallback, drawing blocks until the compilation is done.
+	c = ebiten.NewShaderAsync([]byte(`//kage:unit pixels
+
+package main
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	return vec4(0, 1, 0, 1)
+}
+`), nil)
+	dst.Clear()
+	dst.DrawRectShader(w, h, c.Shader(), nil)
+	if got, want := dst.At(0, 0).(color.RGBA), (color.RGBA{G: 0xff, A: 0xff}); got != want {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+
+	c = ebiten.NewShaderAsync([]byte(`package main
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	return foo
+}
+`), nil)
+	if err := c.Wait(); err == nil {
+		t.Errorf("Wait must return an error but not")
+	}
+	if err := c.Err(); err == nil {
+		t.Errorf("Err must return an error but not")
+	}
+}
+
+func TestShaderFlushDisposals(t *testing.T) {
+	const w, h = 16, 16
+
+	s, err := ebiten.NewShader([]byte(`//kage:unit pixels
+
+package main
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	return vec4(1, 0, 0, 1)
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Use the shader so that the shader is created in the graphics driver.
+	dst := ebiten.NewImage(w, h)
+	dst.DrawRectShader(w, h, s, nil)
+	if got, want := dst.At(0, 0).(color.RGBA), (color.RGBA{R: 0xff, A: 0xff}); got != want {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+
+	var d0 ebiten.DebugInfo
+	ebiten.ReadDebugInfo(&d0)
+
+	s.Deallocate()
+	ebiten.FlushDisposals()
+
+	var d1 ebiten.DebugInfo
+	ebiten.ReadDebugInfo(&d1)
+	// Other shaders might be released by finalizers at the same time.
+	if got, want := d1.ShaderCount, d0.ShaderCount-1; got > want {
+		t.Errorf("ShaderCount: got: %d, want: <= %d", got, want)
+	}
+	if got, want := d1.PendingShaderDisposalCount, 0; got != want {
+		t.Errorf("PendingShaderDisposalCount: got: %d, want: %d", got, want)
+	}
+}
@@ -0,0 +1,346 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"strings"
+
+	"github.com/go-text/typesetting/segmenter"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// CachedTextLine represents a line of a CachedText.
+type CachedTextLine struct {
+	// StartIndexInBytes is the start index in bytes of the line in the CachedText's text.
+	StartIndexInBytes int
+
+	// EndIndexInBytes is the end index in bytes of the line in the CachedText's text.
+	// The trailing whitespaces of the line and the newline character are not included.
+	EndIndexInBytes int
+
+	// X, Y, Width, and Height represent the line's rectangle
+	// in the same coordinates as Glyph's X and Y, i.e., the coordinates where the text is rendered by Draw without GeoM.
+	//
+	// For a horizontal face, the rectangle's height is the sum of the face's HAscent and HDescent.
+	// For a vertical face, the rectangle's width is the sum of the face's VAscent and VDescent.
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+}
+
+// CachedText is a text with a face and layout options, whose layout result is cached.
+//
+// Draw with the same text and the same face calculates the layout every time, though the shaping results are cached.
+// CachedText calculates the layout only once, and keeps the glyphs until the inputs are changed.
+// This is useful to draw a static text like a label in a HUD every frame.
+//
+// CachedText can also wrap the text so that each line fits in a maximum width (see SetMaxWidth).
+// The text is wrapped at the line breaking opportunities defined by the Unicode line breaking algorithm (UAX #14),
+// e.g., between words or between CJK characters. The newline character '\n' always breaks a line.
+// A word that doesn't fit in the maximum width by itself is put on its own line without being broken.
+// For a vertical face, the maximum width is the maximum length in the vertical direction.
+// This is useful for a text in a UI container like a scroll view, which is rewrapped only when the container is resized.
+//
+// CachedText keeps the references to the glyph images, so the glyph images are kept valid even after they are evicted from the glyph cache.
+//
+// CachedText cannot detect changes of the face's properties, e.g. GoTextFace.Size.
+// Call SetFace or Invalidate after changing them.
+//
+// The zero value of CachedText is an empty text without a face, and drawing it does nothing.
+//
+// CachedText's methods are not concurrent-safe.
+type CachedText struct {
+	text     string
+	face     Face
+	options  LayoutOptions
+	maxWidth float64
+
+	lines   []CachedTextLine
+	wrapped string
+	glyphs  []Glyph
+	width   float64
+	height  float64
+	valid   bool
+
+	runes           []rune
+	runeByteIndices []int
+
+	defaultOptions ebiten.DrawImageOptions
+}
+
+// NewCachedText creates a new CachedText.
+//
+// options can be nil. In this case, the default options are used.
+func NewCachedText(text string, face Face, options *LayoutOptions) *CachedText {
+	c := &CachedText{
+		text: text,
+		face: face,
+	}
+	if options != nil {
+		c.options = *options
+	}
+	return c
+}
+
+// Text returns the text.
+func (c *CachedText) Text() string {
+	return c.text
+}
+
+// SetText sets the text.
+// If the text is the same as the current one, the cache is kept.
+func (c *CachedText) SetText(text string) {
+	if c.text == text {
+		return
+	}
+	c.text = text
+	c.valid = false
+}
+
+// Face returns the face.
+func (c *CachedText) Face() Face {
+	return c.face
+}
+
+// SetFace sets the face.
+// SetFace always invalidates the cache even if the face is the same as the current one,
+// as the face's properties might be changed.
+func (c *CachedText) SetFace(face Face) {
+	c.face = face
+	c.valid = false
+}
+
+// LayoutOptions returns the layout options.
+func (c *CachedText) LayoutOptions() LayoutOptions {
+	return c.options
+}
+
+// SetLayoutOptions sets the layout options.
+// If the options are the same as the current ones, the cache is kept.
+func (c *CachedText) SetLayoutOptions(options *LayoutOptions) {
+	var o LayoutOptions
+	if options != nil {
+		o = *options
+	}
+	if c.options == o {
+		return
+	}
+	c.options = o
+	c.valid = false
+}
+
+// MaxWidth returns the maximum width.
+func (c *CachedText) MaxWidth() float64 {
+	return c.maxWidth
+}
+
+// SetMaxWidth sets the maximum width to wrap the text.
+// If the maximum width is the same as the current one, the cache is kept.
+//
+// If maxWidth is 0 or less, the text is not wrapped except for the newline characters.
+// The default (zero) value is 0.
+func (c *CachedText) SetMaxWidth(maxWidth float64) {
+	if c.maxWidth == maxWidth {
+		return
+	}
+	c.maxWidth = maxWidth
+	c.valid = false
+}
+
+// Invalidate discards the cache. The layout is calculated again at the next use.
+func (c *CachedText) Invalidate() {
+	c.valid = false
+}
+
+func (c *CachedText) ensureCache() {
+	if c.valid {
+		return
+	}
+
+	for i := range c.glyphs {
+		c.glyphs[i] = Glyph{}
+	}
+	c.glyphs = c.glyphs[:0]
+	c.lines = c.lines[:0]
+	c.wrapped = ""
+	c.width, c.height = 0, 0
+
+	if c.face != nil {
+		c.wrap()
+		c.layout()
+	}
+	c.valid = true
+}
+
+// wrap calculates the ranges of the wrapped lines.
+func (c *CachedText) wrap() {
+	var seg segmenter.Segmenter
+	var offset int
+	for {
+		paragraph, _, found := strings.Cut(c.text[offset:], "\n")
+		c.wrapParagraph(&seg, offset, paragraph)
+		if !found {
+			break
+		}
+		offset += len(paragraph) + 1
+	}
+}
+
+// wrapParagraph wraps a paragraph without newline characters, which starts at offset in bytes in the text.
+func (c *CachedText) wrapParagraph(seg *segmenter.Segmenter, offset int, paragraph string) {
+	if c.maxWidth <= 0 || paragraph == "" {
+		c.lines = append(c.lines, CachedTextLine{
+			StartIndexInBytes: offset,
+			EndIndexInBytes:   offset + len(paragraph),
+		})
+		return
+	}
+
+	c.runes = c.runes[:0]
+	c.runeByteIndices = c.runeByteIndices[:0]
+	for i, r := range paragraph {
+		c.runes = append(c.runes, r)
+		c.runeByteIndices = append(c.runeByteIndices, i)
+	}
+	c.runeByteIndices = append(c.runeByteIndices, len(paragraph))
+
+	start := 0
+	// end is the end of the current line candidate without the trailing whitespaces.
+	end := -1
+	seg.Init(c.runes)
+	iter := seg.LineIterator()
+	for iter.Next() {
+		l := iter.Line()
+		segStart := c.runeByteIndices[l.Offset]
+		segEnd := c.runeByteIndices[l.Offset+len(l.Text)]
+		segEndTrimmed := segStart + len(strings.TrimRight(paragraph[segStart:segEnd], " \t"))
+
+		if end >= 0 && c.face.advance(paragraph[start:segEndTrimmed]) > c.maxWidth {
+			c.lines = append(c.lines, CachedTextLine{
+				StartIndexInBytes: offset + start,
+				EndIndexInBytes:   offset + end,
+			})
+			start = segStart
+		}
+		end = segEndTrimmed
+	}
+	c.lines = append(c.lines, CachedTextLine{
+		StartIndexInBytes: offset + start,
+		EndIndexInBytes:   offset + end,
+	})
+}
+
+// layout calculates the glyphs and the rectangles of the wrapped lines.
+func (c *CachedText) layout() {
+	if c.maxWidth <= 0 {
+		// The lines cover the whole text without any modification.
+		c.wrapped = c.text
+	} else {
+		var b strings.Builder
+		for i, l := range c.lines {
+			if i > 0 {
+				b.WriteByte('\n')
+			}
+			b.WriteString(c.text[l.StartIndexInBytes:l.EndIndexInBytes])
+		}
+		c.wrapped = b.String()
+	}
+
+	m := c.face.Metrics()
+	horizontal := c.face.direction().isHorizontal()
+	var i int
+	forEachLine(c.wrapped, c.face, &c.options, func(line string, indexOffset int, originX, originY float64) {
+		l := &c.lines[i]
+		a := c.face.advance(line)
+		if horizontal {
+			l.X = originX
+			l.Y = originY - m.HAscent
+			l.Width = a
+			l.Height = m.HAscent + m.HDescent
+		} else {
+			l.X = originX
+			l.Y = originY
+			l.Width = m.VAscent + m.VDescent
+			l.Height = a
+		}
+
+		// Map the glyphs' indices in the wrapped text to the indices in the original text.
+		n := len(c.glyphs)
+		c.glyphs = c.face.appendGlyphsForLine(c.glyphs, line, indexOffset, originX, originY)
+		for j := n; j < len(c.glyphs); j++ {
+			g := &c.glyphs[j]
+			g.StartIndexInBytes += l.StartIndexInBytes - indexOffset
+			g.EndIndexInBytes += l.StartIndexInBytes - indexOffset
+		}
+		i++
+	})
+	// forEachLine skips an empty text. Keep the empty line's rectangle in this case.
+	if c.wrapped == "" && len(c.lines) > 0 {
+		if horizontal {
+			c.lines[0].Height = m.HAscent + m.HDescent
+		} else {
+			c.lines[0].Width = m.VAscent + m.VDescent
+		}
+	}
+
+	c.width, c.height = Measure(c.wrapped, c.face, c.options.LineSpacingInPixels)
+}
+
+// Lines returns the lines of the text.
+// If the maximum width is positive, the lines are the wrapped ones.
+//
+// The returned slice is valid until the cache is invalidated. The returned slice must not be modified.
+func (c *CachedText) Lines() []CachedTextLine {
+	c.ensureCache()
+	return c.lines
+}
+
+// Glyphs returns the cached glyphs.
+// The result is the same as AppendGlyphs with the same text, face, and layout options, when the text is not wrapped.
+// When the text is wrapped, the glyphs are the wrapped text's, and their StartIndexInBytes and EndIndexInBytes are the indices in the original text.
+//
+// The returned slice is valid until the cache is invalidated. The returned slice must not be modified.
+func (c *CachedText) Glyphs() []Glyph {
+	c.ensureCache()
+	return c.glyphs
+}
+
+// Size returns the size of the text.
+// The result is the same as Measure(c.Text(), c.Face(), c.LayoutOptions().LineSpacingInPixels), when the text is not wrapped.
+// When the text is wrapped, the result is the wrapped text's size, and the height is useful for a scroll container.
+func (c *CachedText) Size() (width, height float64) {
+	c.ensureCache()
+	return c.width, c.height
+}
+
+// Draw draws the text on the given destination image dst.
+//
+// The result is the same as Draw with the same text, face, and options, when the text is not wrapped.
+// options.GeoM is an additional geometry transformation after putting the rendering region along with the alignments.
+//
+// options can be nil. In this case, the default options are used.
+func (c *CachedText) Draw(dst *ebiten.Image, options *ebiten.DrawImageOptions) {
+	c.ensureCache()
+
+	if options == nil {
+		c.defaultOptions = ebiten.DrawImageOptions{}
+		options = &c.defaultOptions
+	}
+	geoM := options.GeoM
+	drawGlyphs(dst, c.glyphs, options)
+	options.GeoM = geoM
+}
@@ -0,0 +1,171 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image/color"
+
+	"github.com/go-text/typesetting/opentype/api"
+	"github.com/go-text/typesetting/opentype/loader"
+)
+
+// colrLayer is a layer of a color glyph in a COLR table.
+type colrLayer struct {
+	gid api.GID
+
+	// color is the layer's color from the CPAL table.
+	color color.NRGBA
+
+	// foreground reports whether the layer uses the text's foreground color instead of color.
+	foreground bool
+}
+
+// colrLayers is a set of color glyphs in a COLR table, indexed by the base glyph IDs.
+type colrLayers map[api.GID][]colrLayer
+
+const colrForegroundPaletteIndex = 0xffff
+
+// parseCOLRAndCPAL parses the COLR and CPAL tables of the font.
+// Only the version 0 records of COLR and the first palette of CPAL are used.
+//
+// parseCOLRAndCPAL returns nil if the font doesn't have COLR or CPAL tables.
+// A malformed COLR or CPAL table doesn't make the font invalid. parseCOLRAndCPAL returns nil for it too,
+// and the glyphs are rendered in monochrome.
+func parseCOLRAndCPAL(l *loader.Loader) colrLayers {
+	colrTag := loader.MustNewTag("COLR")
+	cpalTag := loader.MustNewTag("CPAL")
+	if !l.HasTable(colrTag) || !l.HasTable(cpalTag) {
+		return nil
+	}
+
+	colr, err := l.RawTable(colrTag)
+	if err != nil {
+		return nil
+	}
+	cpal, err := l.RawTable(cpalTag)
+	if err != nil {
+		return nil
+	}
+
+	palette, err := parseCPAL(cpal)
+	if err != nil {
+		return nil
+	}
+	layers, err := parseCOLR(colr, palette)
+	if err != nil {
+		return nil
+	}
+	return layers
+}
+
+// inRange reports whether size bytes from offset are in data.
+// offset is compared as uint64 so that a large offset doesn't overflow int on 32-bit platforms.
+func inRange(data []byte, offset uint32, size int) bool {
+	return uint64(offset)+uint64(size) <= uint64(len(data))
+}
+
+// parseCOLR parses the version 0 records of a COLR table.
+// The records are also available in a COLR version 1 table. The other version 1 data like paints are ignored.
+//
+// See https://learn.microsoft.com/en-us/typography/opentype/spec/colr.
+func parseCOLR(data []byte, palette []color.NRGBA) (colrLayers, error) {
+	if len(data) < 14 {
+		return nil, fmt.Errorf("text: COLR table is too short: %d", len(data))
+	}
+	numBaseGlyphRecords := int(binary.BigEndian.Uint16(data[2:4]))
+	numLayerRecords := int(binary.BigEndian.Uint16(data[12:14]))
+
+	if numBaseGlyphRecords == 0 {
+		return nil, nil
+	}
+	if !inRange(data, binary.BigEndian.Uint32(data[4:8]), 6*numBaseGlyphRecords) {
+		return nil, fmt.Errorf("text: COLR base glyph records are out of range")
+	}
+	if !inRange(data, binary.BigEndian.Uint32(data[8:12]), 4*numLayerRecords) {
+		return nil, fmt.Errorf("text: COLR layer records are out of range")
+	}
+	// The offsets are in range, and then they fit in int.
+	baseGlyphRecordsOffset := int(binary.BigEndian.Uint32(data[4:8]))
+	layerRecordsOffset := int(binary.BigEndian.Uint32(data[8:12]))
+
+	layers := colrLayers{}
+	for i := 0; i < numBaseGlyphRecords; i++ {
+		r := data[baseGlyphRecordsOffset+6*i:]
+		gid := api.GID(binary.BigEndian.Uint16(r[0:2]))
+		firstLayerIndex := int(binary.BigEndian.Uint16(r[2:4]))
+		numLayers := int(binary.BigEndian.Uint16(r[4:6]))
+		if numLayers == 0 {
+			continue
+		}
+		if firstLayerIndex+numLayers > numLayerRecords {
+			return nil, fmt.Errorf("text: COLR layers for glyph %d are out of range", gid)
+		}
+
+		ls := make([]colrLayer, numLayers)
+		for j := range ls {
+			r := data[layerRecordsOffset+4*(firstLayerIndex+j):]
+			ls[j].gid = api.GID(binary.BigEndian.Uint16(r[0:2]))
+			idx := int(binary.BigEndian.Uint16(r[2:4]))
+			// An out-of-range index is treated as the foreground color in the same way as 0xffff.
+			if idx == colrForegroundPaletteIndex || idx >= len(palette) {
+				ls[j].foreground = true
+				continue
+			}
+			ls[j].color = palette[idx]
+		}
+		layers[gid] = ls
+	}
+	return layers, nil
+}
+
+// parseCPAL parses the first palette of a CPAL table.
+//
+// See https://learn.microsoft.com/en-us/typography/opentype/spec/cpal.
+func parseCPAL(data []byte) ([]color.NRGBA, error) {
+	if len(data) < 12 {
+		return nil, fmt.Errorf("text: CPAL table is too short: %d", len(data))
+	}
+	numPaletteEntries := int(binary.BigEndian.Uint16(data[2:4]))
+	numPalettes := int(binary.BigEndian.Uint16(data[4:6]))
+	numColorRecords := int(binary.BigEndian.Uint16(data[6:8]))
+
+	if numPalettes == 0 {
+		return nil, nil
+	}
+	if len(data) < 14 {
+		return nil, fmt.Errorf("text: CPAL table is too short: %d", len(data))
+	}
+	firstColorIndex := int(binary.BigEndian.Uint16(data[12:14]))
+	if firstColorIndex+numPaletteEntries > numColorRecords || !inRange(data, binary.BigEndian.Uint32(data[8:12]), 4*numColorRecords) {
+		return nil, fmt.Errorf("text: CPAL color records are out of range")
+	}
+	// The offset is in range, and then it fits in int.
+	colorRecordsArrayOffset := int(binary.BigEndian.Uint32(data[8:12]))
+
+	palette := make([]color.NRGBA, numPaletteEntries)
+	for i := range palette {
+		r := data[colorRecordsArrayOffset+4*(firstColorIndex+i):]
+		// A color record is in the BGRA order.
+		palette[i] = color.NRGBA{
+			R: r[2],
+			G: r[1],
+			B: r[0],
+			A: r[3],
+		}
+	}
+	return palette, nil
+}
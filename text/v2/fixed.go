@@ -0,0 +1,50 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"golang.org/x/image/math/fixed"
+)
+
+// Fixed26_6ToFloat64 converts a 26.6 fixed-point number to a float64 value.
+//
+// The conversion is exact.
+// The text layout uses the same conversion, so the result is consistent with positions like Glyph.X and Glyph.Y.
+func Fixed26_6ToFloat64(x fixed.Int26_6) float64 {
+	return fixed26_6ToFloat64(x)
+}
+
+// Fixed26_6ToFloat32 converts a 26.6 fixed-point number to a float32 value.
+//
+// The conversion is exact unless the value is too big to represent as float32.
+func Fixed26_6ToFloat32(x fixed.Int26_6) float32 {
+	return fixed26_6ToFloat32(x)
+}
+
+// Float64ToFixed26_6 converts a float64 value to a 26.6 fixed-point number.
+//
+// The fractional part is rounded down to a multiple of 1/64, i.e., the result is never greater than x.
+// This is the same rounding as the text layout, e.g., an origin position given to Draw is converted in the same way.
+// Note that this is different from fixed.I or rounding to the nearest value.
+func Float64ToFixed26_6(x float64) fixed.Int26_6 {
+	return float64ToFixed26_6(x)
+}
+
+// Float32ToFixed26_6 converts a float32 value to a 26.6 fixed-point number.
+//
+// The fractional part is rounded down to a multiple of 1/64 in the same way as Float64ToFixed26_6.
+func Float32ToFixed26_6(x float32) fixed.Int26_6 {
+	return float32ToFixed26_6(x)
+}
@@ -16,7 +16,9 @@ package text
 
 import (
 	"math"
+	"runtime"
 	"sync"
+	"sync/atomic"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/internal/hook"
@@ -37,6 +39,82 @@ func init() {
 	})
 }
 
+// CacheStats represents statistics of the glyph image caches.
+type CacheStats struct {
+	// GlyphImageCount is the number of the glyph images in the caches.
+	GlyphImageCount int
+
+	// GlyphImageBytes is the approximate number of bytes of the glyph images in the caches,
+	// assuming 4 bytes per pixel.
+	// Note that the actual memory usage on GPU might be different since glyph images are packed into internal texture atlases.
+	GlyphImageBytes int64
+
+	// Hits is the number of lookups that found a cached glyph image.
+	Hits int64
+
+	// Misses is the number of lookups that didn't find a cached glyph image and created a new one.
+	Misses int64
+}
+
+var (
+	cacheStatsGlyphImageCount int64
+	cacheStatsGlyphImageBytes int64
+	cacheStatsHits            int64
+	cacheStatsMisses          int64
+)
+
+// ReadCacheStats writes the statistics of the glyph image caches for all the faces, e.g., StdFace and GoTextFace,
+// into a provided struct.
+//
+// Hits and Misses are accumulated since the program started. To calculate a hit rate in a period,
+// take the differences of two CacheStats values.
+//
+// The glyph images of a face that is garbage-collected are removed from the statistics eventually,
+// but not immediately.
+//
+// ReadCacheStats is concurrent-safe.
+func ReadCacheStats(s *CacheStats) {
+	s.GlyphImageCount = int(atomic.LoadInt64(&cacheStatsGlyphImageCount))
+	s.GlyphImageBytes = atomic.LoadInt64(&cacheStatsGlyphImageBytes)
+	s.Hits = atomic.LoadInt64(&cacheStatsHits)
+	s.Misses = atomic.LoadInt64(&cacheStatsMisses)
+}
+
+// glyphImageCacheUsage is the usage of a glyph image cache.
+//
+// glyphImageCacheUsage is allocated separately from a cache so that the usage can be subtracted from the global statistics
+// by a finalizer when the cache is garbage-collected.
+type glyphImageCacheUsage struct {
+	count int64
+	bytes int64
+}
+
+func newGlyphImageCacheUsage() *glyphImageCacheUsage {
+	u := &glyphImageCacheUsage{}
+	runtime.SetFinalizer(u, func(u *glyphImageCacheUsage) {
+		u.add(nil, -1)
+	})
+	return u
+}
+
+// add adds the usage of img to u. sign must be 1 or -1.
+// If img is nil, add adds the whole usage of u, i.e., u.add(nil, -1) removes u's usage from the global statistics.
+func (u *glyphImageCacheUsage) add(img *ebiten.Image, sign int64) {
+	var count, bytes int64
+	if img != nil {
+		b := img.Bounds()
+		count = sign
+		bytes = sign * int64(b.Dx()) * int64(b.Dy()) * 4
+		u.count += count
+		u.bytes += bytes
+	} else {
+		count = sign * u.count
+		bytes = sign * u.bytes
+	}
+	atomic.AddInt64(&cacheStatsGlyphImageCount, count)
+	atomic.AddInt64(&cacheStatsGlyphImageBytes, bytes)
+}
+
 type glyphImageCacheEntry struct {
 	image *ebiten.Image
 	atime int64
@@ -44,6 +122,7 @@ type glyphImageCacheEntry struct {
 
 type glyphImageCache[Key comparable] struct {
 	cache map[Key]*glyphImageCacheEntry
+	usage *glyphImageCacheUsage
 	m     sync.Mutex
 }
 
@@ -53,12 +132,15 @@ func (g *glyphImageCache[Key]) getOrCreate(face Face, key Key, create func() *eb
 
 	e, ok := g.cache[key]
 	if ok {
+		atomic.AddInt64(&cacheStatsHits, 1)
 		e.atime = now()
 		return e.image
 	}
+	atomic.AddInt64(&cacheStatsMisses, 1)
 
 	if g.cache == nil {
 		g.cache = map[Key]*glyphImageCacheEntry{}
+		g.usage = newGlyphImageCacheUsage()
 	}
 
 	img := create()
@@ -73,6 +155,9 @@ func (g *glyphImageCache[Key]) getOrCreate(face Face, key Key, create func() *eb
 		e.atime = infTime
 	}
 	g.cache[key] = e
+	if img != nil {
+		g.usage.add(img, 1)
+	}
 
 	// Clean up old entries.
 
@@ -88,6 +173,9 @@ func (g *glyphImageCache[Key]) getOrCreate(face Face, key Key, create func() *eb
 				continue
 			}
 			delete(g.cache, key)
+			if e.image != nil {
+				g.usage.add(e.image, -1)
+			}
 		}
 	}
 
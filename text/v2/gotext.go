@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"image"
 	"strings"
 
 	"github.com/go-text/typesetting/di"
@@ -29,7 +30,6 @@ import (
 	"golang.org/x/text/language"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
 var _ Face = (*GoTextFace)(nil)
@@ -37,6 +37,24 @@ var _ Face = (*GoTextFace)(nil)
 // GoTextFace is a Face implementation for go-text's font.Face (github.com/go-text/typesetting).
 // With a GoTextFace, shaping.HarfBuzzShaper is always used as a shaper internally.
 // GoTextFace includes the source and various options.
+//
+// If the font has COLR and CPAL tables, like some color emoji fonts, color glyphs are rendered with their colored layers.
+// Only the version 0 layers of COLR and the first palette of CPAL are used.
+// A layer with the foreground color is rendered in white, and DrawOptions.ColorScale scales all the layers' colors multiplicatively.
+// If a glyph has no layers, the glyph is rendered with its outline in the usual way.
+//
+// If the font has embedded color bitmaps in the sbix table or the CBDT/CBLC tables, like some color emoji fonts,
+// glyphs are rendered with the bitmaps instead of the outlines. Only PNG and JPEG bitmaps are supported.
+// A bitmap strike is selected by Size as pixels per em:
+// the smallest strike that is equal to or larger than Size is selected, and if there is no such strike, the largest strike is selected.
+// Then, the bitmap is scaled to Size. For example, with strikes for 20 and 64 pixels per em, a face with Size 32 uses the strike for 64 pixels
+// and scales it down by half, and a face with Size 96 uses the strike for 64 pixels and scales it up.
+//
+// The text is shaped with grapheme clusters (UAX #29) as units. An emoji ZWJ sequence or an emoji with a skin tone modifier
+// is rendered as one glyph when the font has the ligature for it.
+// The StartIndexInBytes and EndIndexInBytes of the glyphs for a cluster cover the whole cluster.
+//
+// Color layers and bitmaps are not used for vector paths (AppendVectorPath) or SDFFace.
 type GoTextFace struct {
 	// Source is the font face source.
 	Source *GoTextFaceSource
@@ -48,13 +66,31 @@ type GoTextFace struct {
 	// Size is the font size in pixels.
 	Size float64
 
-	// Language is a hiint for a language (BCP 47).
+	// Language is a hint for a language (BCP 47).
+	//
+	// Language is passed to the shaper so that locale-specific glyph substitutions apply,
+	// e.g. Serbian vs Russian Cyrillic italics, or regional variants of Han characters.
+	// Shaped results are cached per language, so changing Language doesn't reuse a result for another language.
+	// The default (zero) value is undetermined, and no locale-specific substitutions apply.
 	Language language.Tag
 
 	// Script is a hint for a script code hint of (ISO 15924).
 	// If this is empty, the script is guessed from the specified language.
 	Script language.Script
 
+	// BaselineShift is an offset of the baseline in pixels.
+	//
+	// BaselineShift is useful to align a fallback face's baseline optically with the primary face's baseline in a MultiFace,
+	// especially when the faces have different sizes or different designs.
+	// With a horizontal direction, a positive value moves glyphs downward.
+	// With a vertical direction, a positive value moves glyphs rightward.
+	//
+	// BaselineShift affects only the positions of rendered glyphs and vector paths.
+	// BaselineShift doesn't affect Metrics, GlyphBounds, or advances.
+	//
+	// The default (zero) value is 0.
+	BaselineShift float64
+
 	variations []font.Variation
 	features   []shaping.FontFeature
 
@@ -183,6 +219,38 @@ func MustParseTag(str string) Tag {
 	return t
 }
 
+// WithSize returns a new GoTextFace with the given size.
+// The other properties including variations and features are copied from g.
+//
+// The returned face shares the same GoTextFaceSource with g, so the font is not parsed again.
+// The shaper and the shaping plans in the source are shared regardless of sizes.
+// Glyph images are cached per size in the source, so faces with the same size, including ones created by WithSize, share the glyph images.
+//
+// WithSize is useful to recreate a face for a new size, e.g. when the window is resized.
+// Changing variations or features of the returned face doesn't affect g, and vice versa.
+func (g *GoTextFace) WithSize(size float64) *GoTextFace {
+	f := &GoTextFace{
+		Source:        g.Source,
+		Direction:     g.Direction,
+		Size:          size,
+		Language:      g.Language,
+		Script:        g.Script,
+		BaselineShift: g.BaselineShift,
+
+		variationsString: g.variationsString,
+		featuresString:   g.featuresString,
+	}
+	if len(g.variations) > 0 {
+		f.variations = make([]font.Variation, len(g.variations))
+		copy(f.variations, g.variations)
+	}
+	if len(g.features) > 0 {
+		f.features = make([]shaping.FontFeature, len(g.features))
+		copy(f.features, g.features)
+	}
+	return f
+}
+
 // Metrics implements Face.
 func (g *GoTextFace) Metrics() Metrics {
 	scale := g.Source.scale(g.Size)
@@ -202,6 +270,37 @@ func (g *GoTextFace) Metrics() Metrics {
 	return m
 }
 
+// GlyphBounds implements Face.
+func (g *GoTextFace) GlyphBounds(r rune) (bounds image.Rectangle, advance float64) {
+	if !g.hasGlyph(r) {
+		return image.Rectangle{}, 0
+	}
+
+	output, gs := g.Source.shape(string(r), g)
+
+	// One rune usually corresponds to one glyph, but this is not guaranteed.
+	var b fixed.Rectangle26_6
+	var origin fixed.Point26_6
+	for i, glyph := range gs {
+		gb := glyph.bounds.Add(origin)
+		if i == 0 {
+			b = gb
+		} else {
+			b = b.Union(gb)
+		}
+		origin = origin.Add(fixed.Point26_6{
+			X: glyph.shapingGlyph.XAdvance,
+			Y: -glyph.shapingGlyph.YAdvance,
+		})
+	}
+
+	a := fixed26_6ToFloat64(output.Advance)
+	if !g.direction().isHorizontal() {
+		a = -a
+	}
+	return fixed26_6RectangleToImageRectangle(b), a
+}
+
 func (g *GoTextFace) ensureVariationsString() string {
 	if g.variationsString != "" {
 		return g.variationsString
@@ -285,6 +384,37 @@ func (g *GoTextFace) advance(text string) float64 {
 	return -fixed26_6ToFloat64(output.Advance)
 }
 
+// appendAdvances implements Face.
+func (g *GoTextFace) appendAdvances(advances []float64, text string) []float64 {
+	_, gs := g.Source.shape(text, g)
+
+	// Accumulate the advances for each cluster, indexed by the cluster's start index in bytes.
+	clusterAdvances := make([]fixed.Int26_6, len(text))
+	clusterEnds := make([]int, len(text))
+	horizontal := g.direction().isHorizontal()
+	for _, glyph := range gs {
+		if horizontal {
+			clusterAdvances[glyph.startIndex] += glyph.shapingGlyph.XAdvance
+		} else {
+			clusterAdvances[glyph.startIndex] -= glyph.shapingGlyph.YAdvance
+		}
+		if clusterEnds[glyph.startIndex] < glyph.endIndex {
+			clusterEnds[glyph.startIndex] = glyph.endIndex
+		}
+	}
+
+	var a fixed.Int26_6
+	var end int
+	for i := range text {
+		if i >= end {
+			a += clusterAdvances[i]
+			end = clusterEnds[i]
+		}
+		advances = append(advances, fixed26_6ToFloat64(a))
+	}
+	return advances
+}
+
 // hasGlyph implements Face.
 func (g *GoTextFace) hasGlyph(r rune) bool {
 	_, ok := g.Source.f.Cmap.Lookup(r)
@@ -293,6 +423,7 @@ func (g *GoTextFace) hasGlyph(r rune) bool {
 
 // appendGlyphsForLine implements Face.
 func (g *GoTextFace) appendGlyphsForLine(glyphs []Glyph, line string, indexOffset int, originX, originY float64) []Glyph {
+	originX, originY = g.shiftBaseline(originX, originY)
 	origin := fixed.Point26_6{
 		X: float64ToFixed26_6(originX),
 		Y: float64ToFixed26_6(originY),
@@ -340,6 +471,12 @@ func (g *GoTextFace) glyphImage(glyph glyph, origin fixed.Point26_6) (*ebiten.Im
 		variations: g.ensureVariationsString(),
 	}
 	img := g.Source.getOrCreateGlyphImage(g, key, func() *ebiten.Image {
+		if glyph.bitmap != nil {
+			return bitmapToImage(glyph.bitmap, subpixelOffset, b)
+		}
+		if len(glyph.layers) > 0 {
+			return layersToImage(glyph.layers, subpixelOffset, b)
+		}
 		return segmentsToImage(glyph.scaledSegments, subpixelOffset, b)
 	})
 
@@ -348,8 +485,16 @@ func (g *GoTextFace) glyphImage(glyph glyph, origin fixed.Point26_6) (*ebiten.Im
 	return img, imgX, imgY
 }
 
+func (g *GoTextFace) shiftBaseline(originX, originY float64) (float64, float64) {
+	if g.direction().isHorizontal() {
+		return originX, originY + g.BaselineShift
+	}
+	return originX + g.BaselineShift, originY
+}
+
 // appendVectorPathForLine implements Face.
-func (g *GoTextFace) appendVectorPathForLine(path *vector.Path, line string, originX, originY float64) {
+func (g *GoTextFace) appendVectorPathForLine(path vectorPath, line string, originX, originY float64) {
+	originX, originY = g.shiftBaseline(originX, originY)
 	origin := fixed.Point26_6{
 		X: float64ToFixed26_6(originX),
 		Y: float64ToFixed26_6(originY),
@@ -369,6 +514,17 @@ func (g *GoTextFace) direction() Direction {
 	return g.Direction
 }
 
+// scripts implements Face.
+func (g *GoTextFace) scripts() map[glanguage.Script]struct{} {
+	return g.Source.scripts()
+}
+
+// hasScript implements Face.
+func (g *GoTextFace) hasScript(script glanguage.Script) bool {
+	_, ok := g.Source.scripts()[script]
+	return ok
+}
+
 // private implements Face.
 func (g *GoTextFace) private() {
 }
@@ -16,7 +16,9 @@ package text
 
 import (
 	"bytes"
+	"image/color"
 	"io"
+	"math"
 	"sync"
 
 	"github.com/go-text/typesetting/font"
@@ -46,6 +48,20 @@ type glyph struct {
 	endIndex       int
 	scaledSegments []api.Segment
 	bounds         fixed.Rectangle26_6
+
+	// layers is colored layers of a color glyph.
+	// If layers is empty, the glyph is rendered with scaledSegments.
+	layers []glyphLayer
+
+	// bitmap is an embedded color bitmap of a glyph like sbix or CBDT.
+	// If bitmap is not nil, the glyph is rendered with bitmap, and bounds is the bitmap's bounds.
+	bitmap *api.GlyphBitmap
+}
+
+// glyphLayer is a colored layer of a color glyph.
+type glyphLayer struct {
+	scaledSegments []api.Segment
+	color          color.Color
 }
 
 type goTextOutputCacheValue struct {
@@ -66,9 +82,24 @@ type GoTextFaceSource struct {
 	f        font.Face
 	metadata Metadata
 
+	// colrLayers is the color glyphs from the COLR and CPAL tables.
+	colrLayers colrLayers
+
 	outputCache     map[goTextOutputCacheKey]*goTextOutputCacheValue
 	glyphImageCache map[float64]*glyphImageCache[goTextGlyphImageCacheKey]
 
+	// sdfImageCache is a cache of signed distance field images for SDFFaces.
+	// The images don't depend on sizes, so the cache is shared by SDFFaces with different sizes.
+	sdfImageCache glyphImageCache[goTextGlyphImageCacheKey]
+
+	// shaper is shared by all the GoTextFaces using this source.
+	// The shaper caches a font object and shaping plans keyed by a script, a direction, a language, and features.
+	// As a size doesn't matter to them, GoTextFaces with different sizes can reuse them.
+	shaper shaping.HarfbuzzShaper
+
+	supportedScripts     map[language.Script]struct{}
+	supportedScriptsOnce sync.Once
+
 	addr *GoTextFaceSource
 
 	m sync.Mutex
@@ -110,7 +141,8 @@ func NewGoTextFaceSource(source io.Reader) (*GoTextFaceSource, error) {
 	}
 
 	s := &GoTextFaceSource{
-		f: &ofont.Face{Font: f},
+		f:          &ofont.Face{Font: f},
+		colrLayers: parseCOLRAndCPAL(l),
 	}
 	s.addr = s
 	s.metadata = metadataFromLoader(l)
@@ -137,7 +169,8 @@ func NewGoTextFaceSourcesFromCollection(source io.Reader) ([]*GoTextFaceSource,
 			return nil, err
 		}
 		s := &GoTextFaceSource{
-			f: &ofont.Face{Font: f},
+			f:          &ofont.Face{Font: f},
+			colrLayers: parseCOLRAndCPAL(l),
 		}
 		s.addr = s
 		s.metadata = metadataFromLoader(l)
@@ -164,6 +197,20 @@ func (g *GoTextFaceSource) UnsafeInternal() font.Face {
 	return g.f
 }
 
+func (g *GoTextFaceSource) scripts() map[language.Script]struct{} {
+	g.copyCheck()
+
+	g.supportedScriptsOnce.Do(func() {
+		c := scriptCoverage{}
+		for it := g.f.Cmap.Iter(); it.Next(); {
+			r, _ := it.Char()
+			c.add(r)
+		}
+		g.supportedScripts = c.supportedScripts()
+	})
+	return g.supportedScripts
+}
+
 func (g *GoTextFaceSource) shape(text string, face *GoTextFace) (shaping.Output, []glyph) {
 	g.copyCheck()
 
@@ -189,7 +236,7 @@ func (g *GoTextFaceSource) shape(text string, face *GoTextFace) (shaping.Output,
 		Script:       face.gScript(),
 		Language:     language.Language(face.Language.String()),
 	}
-	out := (&shaping.HarfbuzzShaper{}).Shape(input)
+	out := g.shaper.Shape(input)
 	if g.outputCache == nil {
 		g.outputCache = map[goTextOutputCacheKey]*goTextOutputCacheValue{}
 	}
@@ -200,10 +247,20 @@ func (g *GoTextFaceSource) shape(text string, face *GoTextFace) (shaping.Output,
 	}
 	indices = append(indices, len(text))
 
+	scale := float32(g.scale(fixed26_6ToFloat64(out.Size)))
+
+	// Pixels per em are used to select a bitmap strike.
+	ppem := math.Ceil(face.Size)
+	if ppem > math.MaxUint16 {
+		ppem = math.MaxUint16
+	}
+	g.f.XPpem, g.f.YPpem = uint16(ppem), uint16(ppem)
+
 	gs := make([]glyph, len(out.Glyphs))
 	for i, gl := range out.Glyphs {
 		gl := gl
 		var segs []api.Segment
+		var bitmap *api.GlyphBitmap
 		switch data := g.f.GlyphData(gl.GlyphID).(type) {
 		case api.GlyphOutline:
 			segs = data.Segments
@@ -213,27 +270,66 @@ func (g *GoTextFaceSource) shape(text string, face *GoTextFace) (shaping.Output,
 			if data.Outline != nil {
 				segs = data.Outline.Segments
 			}
+			if data.Format == api.PNG || data.Format == api.JPG {
+				bitmap = &data
+			}
 		}
 
-		scaledSegs := make([]api.Segment, len(segs))
-		scale := float32(g.scale(fixed26_6ToFloat64(out.Size)))
-		for i, seg := range segs {
-			scaledSegs[i] = seg
-			for j := range seg.Args {
-				scaledSegs[i].Args[j].X *= scale
-				scaledSegs[i].Args[j].Y *= scale
-				scaledSegs[i].Args[j].Y *= -1
+		scaledSegs := scaleSegments(segs, scale)
+		bounds := segmentsToBounds(scaledSegs)
+
+		if bitmap != nil {
+			// The extents are in font units, which are converted from the selected strike's pixels.
+			if ext, ok := g.f.GlyphExtents(gl.GlyphID); ok && ext.Width != 0 && ext.Height != 0 {
+				bounds = fixed.Rectangle26_6{
+					Min: fixed.Point26_6{
+						X: float32ToFixed26_6(ext.XBearing * scale),
+						Y: float32ToFixed26_6(-ext.YBearing * scale),
+					},
+					Max: fixed.Point26_6{
+						X: float32ToFixed26_6((ext.XBearing + ext.Width) * scale),
+						Y: float32ToFixed26_6(-(ext.YBearing + ext.Height) * scale),
+					},
+				}
+			} else {
+				bitmap = nil
 			}
 		}
 
+		var layers []glyphLayer
+		for _, l := range g.colrLayers[gl.GlyphID] {
+			if bitmap != nil {
+				break
+			}
+			outline, ok := g.f.GlyphData(l.gid).(api.GlyphOutline)
+			if !ok || len(outline.Segments) == 0 {
+				continue
+			}
+			var clr color.Color = l.color
+			if l.foreground {
+				// The foreground color is applied by DrawOptions.ColorScale.
+				clr = color.White
+			}
+			segs := scaleSegments(outline.Segments, scale)
+			layers = append(layers, glyphLayer{
+				scaledSegments: segs,
+				color:          clr,
+			})
+			bounds = bounds.Union(segmentsToBounds(segs))
+		}
+
 		gs[i] = glyph{
 			shapingGlyph:   &gl,
 			startIndex:     indices[gl.ClusterIndex],
 			endIndex:       indices[gl.ClusterIndex+gl.RuneCount],
 			scaledSegments: scaledSegs,
-			bounds:         segmentsToBounds(scaledSegs),
+			bounds:         bounds,
+			layers:         layers,
+			bitmap:         bitmap,
 		}
 	}
+	// Reset the pixels per em as the shaper might use them.
+	g.f.XPpem, g.f.YPpem = 0, 0
 	g.outputCache[key] = &goTextOutputCacheValue{
 		output: out,
 		glyphs: gs,
@@ -254,6 +350,19 @@ func (g *GoTextFaceSource) shape(text string, face *GoTextFace) (shaping.Output,
 	return out, gs
 }
 
+func scaleSegments(segs []api.Segment, scale float32) []api.Segment {
+	scaledSegs := make([]api.Segment, len(segs))
+	for i, seg := range segs {
+		scaledSegs[i] = seg
+		for j := range seg.Args {
+			scaledSegs[i].Args[j].X *= scale
+			scaledSegs[i].Args[j].Y *= scale
+			scaledSegs[i].Args[j].Y *= -1
+		}
+	}
+	return scaledSegs
+}
+
 func (g *GoTextFaceSource) scale(size float64) float64 {
 	return size / float64(g.f.Upem())
 }
@@ -267,3 +376,7 @@ func (g *GoTextFaceSource) getOrCreateGlyphImage(goTextFace *GoTextFace, key goT
 	}
 	return g.glyphImageCache[goTextFace.Size].getOrCreate(goTextFace, key, create)
 }
+
+func (g *GoTextFaceSource) getOrCreateSDFImage(sdfFace *SDFFace, key goTextGlyphImageCacheKey, create func() *ebiten.Image) *ebiten.Image {
+	return g.sdfImageCache.getOrCreate(sdfFace, key, create)
+}
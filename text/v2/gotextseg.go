@@ -15,16 +15,19 @@
 package text
 
 import (
+	"bytes"
 	"image"
 	"image/draw"
+	"image/jpeg"
+	"image/png"
 	"math"
 
 	"github.com/go-text/typesetting/opentype/api"
+	xdraw "golang.org/x/image/draw"
 	"golang.org/x/image/math/fixed"
 	gvector "golang.org/x/image/vector"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
 func segmentsToBounds(segs []api.Segment) fixed.Rectangle26_6 {
@@ -80,21 +83,87 @@ func segmentsToImage(segs []api.Segment, subpixelOffset fixed.Point26_6, glyphBo
 		return nil
 	}
 
+	w, h, ok := glyphImageSize(glyphBounds)
+	if !ok {
+		return nil
+	}
+
+	rast := segmentsToRasterizer(segs, subpixelOffset, glyphBounds, w, h)
+	rast.DrawOp = draw.Src
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	rast.Draw(dst, dst.Bounds(), image.Opaque, image.Point{})
+	return ebiten.NewImageFromImage(dst)
+}
+
+// layersToImage renders colored layers of a glyph to an image.
+// The layers are composited in order, i.e., the first layer is the bottom-most layer.
+func layersToImage(layers []glyphLayer, subpixelOffset fixed.Point26_6, glyphBounds fixed.Rectangle26_6) *ebiten.Image {
+	if len(layers) == 0 {
+		return nil
+	}
+
+	w, h, ok := glyphImageSize(glyphBounds)
+	if !ok {
+		return nil
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for _, l := range layers {
+		rast := segmentsToRasterizer(l.scaledSegments, subpixelOffset, glyphBounds, w, h)
+		rast.DrawOp = draw.Over
+		rast.Draw(dst, dst.Bounds(), image.NewUniform(l.color), image.Point{})
+	}
+	return ebiten.NewImageFromImage(dst)
+}
+
+// bitmapToImage renders an embedded bitmap of a glyph to an image.
+// The bitmap is scaled to fit with the glyph bounds.
+func bitmapToImage(bitmap *api.GlyphBitmap, subpixelOffset fixed.Point26_6, glyphBounds fixed.Rectangle26_6) *ebiten.Image {
+	var src image.Image
+	var err error
+	switch bitmap.Format {
+	case api.PNG:
+		src, err = png.Decode(bytes.NewReader(bitmap.Data))
+	case api.JPG:
+		src, err = jpeg.Decode(bytes.NewReader(bitmap.Data))
+	default:
+		return nil
+	}
+	if err != nil {
+		return nil
+	}
+
+	w, h, ok := glyphImageSize(glyphBounds)
+	if !ok {
+		return nil
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	r := image.Rect(
+		subpixelOffset.X.Round(),
+		subpixelOffset.Y.Round(),
+		(subpixelOffset.X + glyphBounds.Max.X - glyphBounds.Min.X).Round(),
+		(subpixelOffset.Y + glyphBounds.Max.Y - glyphBounds.Min.Y).Round())
+	xdraw.BiLinear.Scale(dst, r, src, src.Bounds(), xdraw.Over, nil)
+	return ebiten.NewImageFromImage(dst)
+}
+
+func glyphImageSize(glyphBounds fixed.Rectangle26_6) (int, int, bool) {
 	w, h := (glyphBounds.Max.X - glyphBounds.Min.X).Ceil(), (glyphBounds.Max.Y - glyphBounds.Min.Y).Ceil()
 	if w == 0 || h == 0 {
-		return nil
+		return 0, 0, false
 	}
 
 	// Add always 1 to the size.
 	// In theory, it is possible to determine whether +1 is necessary or not, but the calculation is pretty complicated.
-	w++
-	h++
+	return w + 1, h + 1, true
+}
 
+func segmentsToRasterizer(segs []api.Segment, subpixelOffset fixed.Point26_6, glyphBounds fixed.Rectangle26_6, w, h int) *gvector.Rasterizer {
 	biasX := fixed26_6ToFloat32(-glyphBounds.Min.X + subpixelOffset.X)
 	biasY := fixed26_6ToFloat32(-glyphBounds.Min.Y + subpixelOffset.Y)
 
 	rast := gvector.NewRasterizer(w, h)
-	rast.DrawOp = draw.Src
 	for _, seg := range segs {
 		switch seg.Op {
 		case api.SegmentOpMoveTo:
@@ -114,13 +183,10 @@ func segmentsToImage(segs []api.Segment, subpixelOffset fixed.Point26_6, glyphBo
 			)
 		}
 	}
-
-	dst := image.NewRGBA(image.Rect(0, 0, w, h))
-	rast.Draw(dst, dst.Bounds(), image.Opaque, image.Point{})
-	return ebiten.NewImageFromImage(dst)
+	return rast
 }
 
-func appendVectorPathFromSegments(path *vector.Path, segs []api.Segment, x, y float32) {
+func appendVectorPathFromSegments(path vectorPath, segs []api.Segment, x, y float32) {
 	for _, seg := range segs {
 		switch seg.Op {
 		case api.SegmentOpMoveTo:
@@ -0,0 +1,200 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-text/typesetting/segmenter"
+)
+
+// NextGraphemeBoundary returns the byte index of the next grapheme cluster boundary after pos in str.
+//
+// A grapheme cluster is a user-perceived character defined by UAX #29, e.g., a character with combining marks,
+// an emoji ZWJ sequence, or an emoji with a skin tone modifier.
+// NextGraphemeBoundary is useful to move a caret forward by one character in a text editor.
+//
+// If pos is in the middle of a cluster, NextGraphemeBoundary returns the end of the cluster.
+// If pos is len(str) or more, NextGraphemeBoundary returns len(str).
+// If pos is negative, NextGraphemeBoundary returns the end of the first cluster.
+//
+// NextGraphemeBoundary is concurrent-safe.
+func NextGraphemeBoundary(str string, pos int) int {
+	if pos >= len(str) {
+		return len(str)
+	}
+	next := len(str)
+	found := false
+	forEachGraphemeCluster(str, func(start, end int) {
+		if found || end <= pos {
+			return
+		}
+		next = end
+		found = true
+	})
+	return next
+}
+
+// PrevGraphemeBoundary returns the byte index of the previous grapheme cluster boundary before pos in str.
+//
+// See NextGraphemeBoundary for grapheme clusters.
+// PrevGraphemeBoundary is useful to move a caret backward by one character in a text editor.
+//
+// If pos is in the middle of a cluster, PrevGraphemeBoundary returns the start of the cluster.
+// If pos is 0 or less, PrevGraphemeBoundary returns 0.
+// If pos is more than len(str), PrevGraphemeBoundary returns the start of the last cluster.
+//
+// PrevGraphemeBoundary is concurrent-safe.
+func PrevGraphemeBoundary(str string, pos int) int {
+	if pos <= 0 {
+		return 0
+	}
+	var prev int
+	forEachGraphemeCluster(str, func(start, end int) {
+		if start < pos {
+			prev = start
+		}
+	})
+	return prev
+}
+
+// isASCII reports whether text consists of only ASCII characters.
+func isASCII(text string) bool {
+	for i := 0; i < len(text); i++ {
+		if text[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// mayExtendGraphemeCluster reports whether r might form a grapheme cluster with the adjacent characters.
+//
+// mayExtendGraphemeCluster is conservative. UAX #29 breaks between two characters for which this returns false.
+// The characters that can extend a cluster are CR, Extend, ZWJ, SpacingMark, Prepend, Hangul jamo and syllables,
+// and regional indicators.
+func mayExtendGraphemeCluster(r rune) bool {
+	switch {
+	case r == '\r':
+		return true
+	case r < 0x300:
+		return false
+	case 0x1100 <= r && r <= 0x11ff, 0xa960 <= r && r <= 0xa97f, 0xac00 <= r && r <= 0xd7ff:
+		// Hangul jamo and syllables
+		return true
+	case 0x1f1e6 <= r && r <= 0x1f1ff:
+		// Regional indicators
+		return true
+	case 0x1f3fb <= r && r <= 0x1f3ff:
+		// Emoji modifiers
+		return true
+	case r == 0x0e33 || r == 0x0eb3:
+		// THAI CHARACTER SARA AM and LAO VOWEL SIGN AM are SpacingMark.
+		return true
+	case r == 0x0d4e || r == 0x111c2 || r == 0x111c3 || r == 0x1193f || r == 0x11941 || r == 0x11a3a ||
+		(0x11a84 <= r && r <= 0x11a89) || r == 0x11d46 || r == 0x11f02:
+		// Prepend letters
+		return true
+	}
+	// Marks are Extend or SpacingMark. Format characters include ZWJ and the other Prepend characters.
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc, unicode.Cf, unicode.Other_Grapheme_Extend)
+}
+
+// isSingleRuneClusters reports whether every grapheme cluster in text consists of one rune.
+func isSingleRuneClusters(text string) bool {
+	for _, r := range text {
+		if mayExtendGraphemeCluster(r) {
+			return false
+		}
+	}
+	return true
+}
+
+type graphemeSegmenter struct {
+	runes   []rune
+	indices []int
+	seg     segmenter.Segmenter
+}
+
+// theGraphemeSegmenterPool is a pool of *graphemeSegmenter to reuse the buffers for segmentation.
+var theGraphemeSegmenterPool = sync.Pool{
+	New: func() any {
+		return &graphemeSegmenter{}
+	},
+}
+
+// forEachGraphemeCluster calls f for each grapheme cluster in text defined by UAX #29.
+// start and end are the byte indices of the cluster in text.
+func forEachGraphemeCluster(text string, f func(start, end int)) {
+	// In ASCII, only CR LF is a cluster with multiple characters.
+	if isASCII(text) {
+		for i := 0; i < len(text); i++ {
+			if text[i] == '\r' && i+1 < len(text) && text[i+1] == '\n' {
+				f(i, i+2)
+				i++
+				continue
+			}
+			f(i, i+1)
+		}
+		return
+	}
+
+	// Skip the segmentation when it is obvious that every cluster is one rune, e.g. CJK text.
+	if isSingleRuneClusters(text) {
+		for i := 0; i < len(text); {
+			_, l := utf8.DecodeRuneInString(text[i:])
+			f(i, i+l)
+			i += l
+		}
+		return
+	}
+
+	s := theGraphemeSegmenterPool.Get().(*graphemeSegmenter)
+	defer theGraphemeSegmenterPool.Put(s)
+
+	s.runes = s.runes[:0]
+	s.indices = s.indices[:0]
+	for i, r := range text {
+		s.runes = append(s.runes, r)
+		s.indices = append(s.indices, i)
+	}
+	s.indices = append(s.indices, len(text))
+
+	s.seg.Init(s.runes)
+	iter := s.seg.GraphemeIterator()
+	for iter.Next() {
+		g := iter.Grapheme()
+		f(s.indices[g.Offset], s.indices[g.Offset+len(g.Text)])
+	}
+}
+
+// isClusterJoiner reports whether r is a character that only joins or modifies the other characters in a grapheme cluster
+// and that fonts don't have to have a visible glyph for, e.g., ZERO WIDTH JOINER and variation selectors.
+func isClusterJoiner(r rune) bool {
+	switch {
+	case r == 0x200c || r == 0x200d:
+		// ZERO WIDTH NON-JOINER and ZERO WIDTH JOINER
+		return true
+	case 0xfe00 <= r && r <= 0xfe0f:
+		// Variation selectors
+		return true
+	case 0xe0100 <= r && r <= 0xe01ef:
+		// Variation selectors supplement
+		return true
+	}
+	return false
+}
@@ -15,7 +15,11 @@
 package text
 
 import (
+	"image"
+	"image/color"
+	"math"
 	"strings"
+	"sync"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/vector"
@@ -36,9 +40,124 @@ const (
 // DrawImageOptions.GeoM is an additional geometry transformation
 // after putting the rendering region along with the specified alignments.
 // DrawImageOptions.ColorScale scales the text color.
+// If DrawImageOptions.LinearBlending is true, the glyphs are blended in the linear color space,
+// which makes the thickness of the glyphs' edges consistent regardless of the colors.
 type DrawOptions struct {
 	ebiten.DrawImageOptions
 	LayoutOptions
+
+	// Shader is a shader to render each glyph.
+	// If Shader is nil, the glyphs are rendered as usual.
+	//
+	// The glyph image is specified as imageSrc0, and srcPos in Fragment is a position in the glyph image.
+	// The glyph image is a mask and its alpha value represents the coverage.
+	// For SDFFace, the glyph image is a signed distance field instead. See SDFFace for details.
+	//
+	// In addition to Uniforms, the following uniform variables are available if the shader declares them:
+	//
+	//   - GlyphIndex (int): the index of the glyph in the glyphs to render.
+	//   - GlyphCount (int): the number of the glyphs to render.
+	//   - GlyphPosition (float): the normalized position of the glyph, i.e., 0 for the first glyph and 1 for the last glyph.
+	//
+	// The glyphs without images, e.g., spaces, are not counted.
+	//
+	// DrawImageOptions.GeoM, DrawImageOptions.ColorScale, and DrawImageOptions.Blend are applied as well as
+	// DrawRectShader. DrawImageOptions.Filter is ignored.
+	Shader *ebiten.Shader
+
+	// Uniforms is a set of uniform variables for Shader.
+	// Uniforms is ignored if Shader is nil.
+	//
+	// The values for GlyphIndex, GlyphCount, and GlyphPosition in Uniforms are overwritten.
+	Uniforms map[string]any
+
+	// GlyphHook is a function called for each glyph before rendering, which is useful for per-glyph effects
+	// like wavy, rainbow, or jittering texts.
+	// If GlyphHook is nil, the glyphs are rendered as usual.
+	//
+	// glyph is the glyph to render, and index is the index of the glyph in the glyphs to render.
+	// The glyphs without images, e.g., spaces, are not counted, in the same way as GlyphIndex for Shader.
+	//
+	// The returned GeoM is applied to the glyph image before the glyph is put at (glyph.X, glyph.Y).
+	// Thus, the GeoM is in the glyph image's coordinates. For example, to rotate a glyph around its center,
+	// translate it by the negative half of glyph.Image's size, rotate it, and translate it back.
+	// The returned ColorScale is multiplied with DrawImageOptions.ColorScale.
+	// The zero values of GeoM and ColorScale mean no changes.
+	//
+	// GlyphHook is called exactly once for each glyph in each Draw call.
+	GlyphHook func(glyph Glyph, index int) (ebiten.GeoM, ebiten.ColorScale)
+
+	// ShadowColor is the color of the text's drop shadow.
+	// If ShadowColor is nil, no shadow is rendered.
+	//
+	// The shadow is rendered with the same glyphs and the same layout as the text, before the text is rendered.
+	// The glyphs' colors are multiplied by ShadowColor, so a grayscale glyph's shadow has exactly ShadowColor.
+	// DrawImageOptions.ColorScale's alpha is also applied to the shadow, but its other components are not.
+	// DrawImageOptions.Blend is applied to the shadow, and Shader is not applied to the shadow.
+	//
+	// The default (zero) value is nil.
+	ShadowColor color.Color
+
+	// ShadowOffsetX and ShadowOffsetY are the offset of the shadow from the text.
+	// The offset is in the text's coordinates, i.e., the offset is applied before DrawImageOptions.GeoM.
+	//
+	// The default (zero) values are 0.
+	ShadowOffsetX float64
+	ShadowOffsetY float64
+
+	// ShadowBlur is the radius of the shadow's blur in the text's coordinates.
+	// The blur is a simple box blur, and its cost increases with the number of glyphs.
+	// If ShadowBlur is 0 or less, the shadow is not blurred.
+	//
+	// The default (zero) value is 0.
+	ShadowBlur float64
+
+	// OutlineColor is the color of the text's outline.
+	// If OutlineColor is nil or OutlineWidth is 0 or less, no outline is rendered.
+	//
+	// The outline is rendered by dilating the glyph images, behind the text and in front of the shadow.
+	// As the outline is made from the rendered glyph images regardless of the face types,
+	// the outline is consistent among StdFace, GoTextFace, and the faces in a MultiFace.
+	// The glyphs' colors are multiplied by OutlineColor, so a grayscale glyph's outline has exactly OutlineColor.
+	// DrawImageOptions.ColorScale's alpha is also applied to the outline, but its other components are not.
+	// DrawImageOptions.Blend is applied to the outline, and Shader is not applied to the outline.
+	//
+	// The default (zero) value is nil.
+	OutlineColor color.Color
+
+	// OutlineWidth is the width of the outline in the text's coordinates, i.e., before DrawImageOptions.GeoM is applied.
+	// The cost to render an outline increases with the square of OutlineWidth.
+	//
+	// The default (zero) value is 0.
+	OutlineWidth float64
+
+	// ClipRect is a rectangle in the destination image's coordinates to clip the rendering.
+	// The text, the shadow, and the outline are rendered only inside ClipRect.
+	// The glyphs entirely outside ClipRect are skipped without being rendered.
+	//
+	// ClipRect doesn't allocate an offscreen image. This is useful to render a label in a scroll view.
+	//
+	// If ClipRect is empty, the text is not clipped.
+	//
+	// The default (zero) value is an empty rectangle.
+	ClipRect image.Rectangle
+
+	// ScaleByDeviceScaleFactor indicates whether the text is scaled by the current monitor's device scale factor.
+	//
+	// If ScaleByDeviceScaleFactor is true, the face's size and LineSpacingInPixels are interpreted in device-independent pixels,
+	// and they are multiplied by the device scale factor of the current monitor (see ebiten.Monitor) at every Draw.
+	// Thus, the text keeps the same physical size even when the window moves between monitors with different device scale factors.
+	// The position of the text specified by GeoM is not scaled.
+	//
+	// If the face is *GoTextFace or a MultiFace consisting of *GoTextFace values, the glyphs are rasterized at the scaled size,
+	// so the text is rendered crisply. For the other faces, the rendered glyphs are scaled, which might make the text blurry.
+	//
+	// ScaleByDeviceScaleFactor is meant to be used with a game whose Layout returns the outside size multiplied by the device scale factor,
+	// i.e., the screen is in device pixels. If Layout returns the outside size as it is, the screen is already scaled by the device scale factor,
+	// and ScaleByDeviceScaleFactor would scale the text twice.
+	//
+	// The default (zero) value is false.
+	ScaleByDeviceScaleFactor bool
 }
 
 // LayoutOptions represents options for layouting texts.
@@ -104,21 +223,423 @@ func Draw(dst *ebiten.Image, text string, face Face, options *DrawOptions) {
 		options = &DrawOptions{}
 	}
 
+	glyphs := theGlyphsPool.get()
+	defer func() {
+		theGlyphsPool.put(glyphs)
+	}()
+
+	layoutOptions := &options.LayoutOptions
+	if options.ScaleByDeviceScaleFactor {
+		f, o, scale := deviceScaledFace(face, &options.LayoutOptions)
+		face = f
+		layoutOptions = &o
+		if scale != 1 {
+			geoM := options.GeoM
+			options.GeoM.Reset()
+			options.GeoM.Scale(scale, scale)
+			options.GeoM.Concat(geoM)
+			defer func() {
+				options.GeoM = geoM
+			}()
+		}
+	}
+
+	clipped := !options.ClipRect.Empty()
+	if clipped {
+		dst = dst.SubImage(options.ClipRect).(*ebiten.Image)
+	}
+
+	glyphs = AppendGlyphs(glyphs, text, face, layoutOptions)
+	if options.GlyphHook != nil {
+		applyGlyphHook(glyphs, options.GlyphHook)
+	}
+	if options.ShadowColor != nil {
+		drawGlyphsShadow(dst, glyphs, options)
+	}
+	if options.OutlineColor != nil && options.OutlineWidth > 0 {
+		drawGlyphsOutline(dst, glyphs, options)
+	}
+	if options.Shader != nil {
+		drawGlyphsWithShader(dst, glyphs, &options.DrawImageOptions, options.Shader, options.Uniforms)
+		return
+	}
+	if clipped {
+		drawGlyphs(dst, cullGlyphs(glyphs, options.GeoM, dst.Bounds()), &options.DrawImageOptions)
+		return
+	}
+	drawGlyphs(dst, glyphs, &options.DrawImageOptions)
+}
+
+// cullGlyphs removes the glyphs that are rendered entirely outside of bounds with geoM.
+// cullGlyphs modifies the given slice and returns the result.
+func cullGlyphs(glyphs []Glyph, geoM ebiten.GeoM, bounds image.Rectangle) []Glyph {
+	n := 0
+	for _, g := range glyphs {
+		if g.Image == nil {
+			continue
+		}
+		if !glyphBounds(g, geoM).Overlaps(bounds) {
+			continue
+		}
+		glyphs[n] = g
+		n++
+	}
+	return glyphs[:n]
+}
+
+// deviceScaledFace returns a face and layout options scaled by the current device scale factor for DrawOptions.ScaleByDeviceScaleFactor.
+//
+// If the face cannot be scaled, deviceScaledFace returns the face and the options as they are,
+// and the returned scale is the device scale factor to scale the rendered glyphs.
+// Otherwise, the returned scale is 1.
+func deviceScaledFace(face Face, options *LayoutOptions) (Face, LayoutOptions, float64) {
+	o := *options
+	s := deviceScaleFactor()
+	if s == 1 {
+		return face, o, 1
+	}
+	if f, ok := scaleFace(face, s); ok {
+		o.LineSpacingInPixels *= s
+		return f, o, 1
+	}
+	return face, o, s
+}
+
+var (
+	deviceScaleFactorM     sync.Mutex
+	deviceScaleFactorTime  int64 = -1
+	deviceScaleFactorValue float64
+)
+
+// deviceScaleFactor returns the device scale factor of the current monitor.
+//
+// As ebiten.Monitor might wait for the main thread, the value is cached and updated at most once per tick.
+func deviceScaleFactor() float64 {
+	deviceScaleFactorM.Lock()
+	defer deviceScaleFactorM.Unlock()
+
+	if t := now(); deviceScaleFactorTime != t {
+		deviceScaleFactorValue = currentDeviceScaleFactor()
+		deviceScaleFactorTime = t
+	}
+	return deviceScaleFactorValue
+}
+
+func currentDeviceScaleFactor() float64 {
+	m := ebiten.Monitor()
+	if m == nil {
+		return 1
+	}
+	if s := m.DeviceScaleFactor(); s > 0 {
+		return s
+	}
+	return 1
+}
+
+// scaleFace returns a new face whose size is scaled by scale.
+// scaleFace returns false if the face cannot be scaled without scaling the glyph images.
+func scaleFace(face Face, scale float64) (Face, bool) {
+	switch f := face.(type) {
+	case *GoTextFace:
+		// Copying the face is cheaper than WithSize, and the variations and features are not modified.
+		f2 := *f
+		f2.Size *= scale
+		f2.BaselineShift *= scale
+		return &f2, true
+	case *lastResortFace:
+		sf, ok := scaleFace(f.Face, scale)
+		if !ok {
+			return nil, false
+		}
+		return &lastResortFace{Face: sf}, true
+	case MultiFace:
+		m := make(MultiFace, len(f))
+		for i, ff := range f {
+			sf, ok := scaleFace(ff, scale)
+			if !ok {
+				return nil, false
+			}
+			m[i] = sf
+		}
+		return m, true
+	}
+	return nil, false
+}
+
+// applyGlyphHook applies the results of the hook to the glyphs.
+func applyGlyphHook(glyphs []Glyph, hook func(glyph Glyph, index int) (ebiten.GeoM, ebiten.ColorScale)) {
+	var idx int
+	for i := range glyphs {
+		g := &glyphs[i]
+		if g.Image == nil {
+			continue
+		}
+		geoM, colorScale := hook(*g, idx)
+		idx++
+		g.geoM.Concat(geoM)
+		g.colorScale = colorScale
+	}
+}
+
+// drawGlyphs draws the glyphs on dst.
+// options.GeoM is applied after the glyphs are put at their positions. options.GeoM is modified during drawing.
+func drawGlyphs(dst *ebiten.Image, glyphs []Glyph, options *ebiten.DrawImageOptions) {
+	if options.LinearBlending {
+		drawGlyphsInLinearSpace(dst, glyphs, options)
+		return
+	}
+
 	geoM := options.GeoM
-	for _, g := range AppendGlyphs(nil, text, face, &options.LayoutOptions) {
-		op := &options.DrawImageOptions
-		op.GeoM.Reset()
+	colorScale := options.ColorScale
+	defer func() {
+		options.ColorScale = colorScale
+	}()
+	for _, g := range glyphs {
+		options.GeoM = g.geoM
+		options.GeoM.Translate(g.X, g.Y)
+		options.GeoM.Concat(geoM)
+		options.ColorScale = colorScale
+		options.ColorScale.ScaleWithColorScale(g.colorScale)
+		if g.sdf {
+			drawSDFGlyph(dst, g.Image, options)
+			continue
+		}
+		dst.DrawImage(g.Image, options)
+	}
+}
+
+var (
+	// linearBlendingBuffer is an offscreen image to render glyphs before blending them in the linear color space.
+	linearBlendingBuffer  *ebiten.Image
+	linearBlendingBufferM sync.Mutex
+)
+
+// prepareOffscreenBuffer returns a cleared region of the given size in the offscreen image *buffer.
+// If *buffer is nil or too small, *buffer is recreated.
+func prepareOffscreenBuffer(buffer **ebiten.Image, width, height int) *ebiten.Image {
+	if *buffer != nil {
+		if s := (*buffer).Bounds().Size(); s.X < width || s.Y < height {
+			(*buffer).Dispose()
+			*buffer = nil
+		}
+	}
+	if *buffer == nil {
+		*buffer = ebiten.NewImageWithOptions(image.Rect(0, 0, width, height), &ebiten.NewImageOptions{
+			Unmanaged: true,
+		})
+	}
+	buf := (*buffer).SubImage(image.Rect(0, 0, width, height)).(*ebiten.Image)
+	buf.Clear()
+	return buf
+}
+
+// glyphsBounds returns the bounds of the rendered glyphs with geoM in the destination.
+func glyphsBounds(glyphs []Glyph, geoM ebiten.GeoM) image.Rectangle {
+	var r image.Rectangle
+	for _, g := range glyphs {
+		if g.Image == nil {
+			continue
+		}
+		r = r.Union(glyphBounds(g, geoM))
+	}
+	return r
+}
+
+// glyphBounds returns the bounds of the rendered glyph with geoM in the destination.
+func glyphBounds(g Glyph, geoM ebiten.GeoM) image.Rectangle {
+	gm := g.geoM
+	gm.Translate(g.X, g.Y)
+	gm.Concat(geoM)
+	b := g.Image.Bounds()
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range [][2]float64{{0, 0}, {float64(b.Dx()), 0}, {0, float64(b.Dy())}, {float64(b.Dx()), float64(b.Dy())}} {
+		x, y := gm.Apply(p[0], p[1])
+		minX = math.Min(minX, x)
+		minY = math.Min(minY, y)
+		maxX = math.Max(maxX, x)
+		maxY = math.Max(maxY, y)
+	}
+	return image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX)), int(math.Ceil(maxY)))
+}
+
+// drawGlyphsInLinearSpace draws the glyphs on dst with blending them in the linear color space.
+//
+// Blending in the linear color space is expensive, so the glyphs are rendered on an offscreen image first,
+// and then the offscreen image is blended on dst at once.
+func drawGlyphsInLinearSpace(dst *ebiten.Image, glyphs []Glyph, options *ebiten.DrawImageOptions) {
+	linearBlendingBufferM.Lock()
+	defer linearBlendingBufferM.Unlock()
+
+	// Calculate the region to render the glyphs.
+	r := glyphsBounds(glyphs, options.GeoM).Intersect(dst.Bounds())
+	if r.Empty() {
+		return
+	}
+
+	buf := prepareOffscreenBuffer(&linearBlendingBuffer, r.Dx(), r.Dy())
+
+	op := *options
+	op.LinearBlending = false
+	op.CompositeMode = ebiten.CompositeModeCustom
+	op.Blend = ebiten.BlendSourceOver
+	op.GeoM.Translate(-float64(r.Min.X), -float64(r.Min.Y))
+	drawGlyphs(buf, glyphs, &op)
+
+	op2 := &ebiten.DrawImageOptions{}
+	op2.GeoM.Translate(float64(r.Min.X), float64(r.Min.Y))
+	op2.CompositeMode = options.CompositeMode
+	op2.Blend = options.Blend
+	op2.LinearBlending = true
+	dst.DrawImage(buf, op2)
+}
+
+// drawGlyphsWithShader draws the glyphs on dst with the given shader.
+func drawGlyphsWithShader(dst *ebiten.Image, glyphs []Glyph, options *ebiten.DrawImageOptions, shader *ebiten.Shader, uniforms map[string]any) {
+	var count int
+	for _, g := range glyphs {
+		if g.Image != nil {
+			count++
+		}
+	}
+
+	op := &ebiten.DrawRectShaderOptions{}
+	op.CompositeMode = options.CompositeMode
+	op.Blend = options.Blend
+	op.Uniforms = make(map[string]any, len(uniforms)+3)
+	for k, v := range uniforms {
+		op.Uniforms[k] = v
+	}
+	op.Uniforms["GlyphCount"] = count
+
+	var idx int
+	for _, g := range glyphs {
+		if g.Image == nil {
+			continue
+		}
+
+		var pos float64
+		if count > 1 {
+			pos = float64(idx) / float64(count-1)
+		}
+		op.Uniforms["GlyphIndex"] = idx
+		op.Uniforms["GlyphPosition"] = pos
+		idx++
+
+		op.GeoM = g.geoM
 		op.GeoM.Translate(g.X, g.Y)
-		op.GeoM.Concat(geoM)
-		dst.DrawImage(g.Image, op)
+		op.GeoM.Concat(options.GeoM)
+		op.ColorScale = options.ColorScale
+		op.ColorScale.ScaleWithColorScale(g.colorScale)
+		op.Images[0] = g.Image
+		b := g.Image.Bounds()
+		dst.DrawRectShader(b.Dx(), b.Dy(), shader, op)
 	}
 }
 
+// glyphsPool is a pool of glyph slices to avoid allocations at Draw.
+type glyphsPool struct {
+	pool [][]Glyph
+	m    sync.Mutex
+}
+
+var theGlyphsPool glyphsPool
+
+func (p *glyphsPool) get() []Glyph {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if len(p.pool) == 0 {
+		return nil
+	}
+	v := p.pool[len(p.pool)-1]
+	p.pool[len(p.pool)-1] = nil
+	p.pool = p.pool[:len(p.pool)-1]
+	return v
+}
+
+func (p *glyphsPool) put(v []Glyph) {
+	// Remove the references to the glyph images.
+	for i := range v {
+		v[i] = Glyph{}
+	}
+
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if len(p.pool) >= 16 {
+		return
+	}
+	p.pool = append(p.pool, v[:0])
+}
+
+// DrawBlock draws a given text in a given rectangle bounds on a given destination image dst.
+// face is the font for text rendering.
+//
+// The rendering region is put in bounds based on the alignments in DrawOptions.
+// For example, if the face's direction is left-to-right, and both PrimaryAlign and SecondaryAlign are AlignCenter,
+// the text is put at the center of bounds.
+// DrawOptions.GeoM is applied after the rendering region is put in bounds.
+//
+// The rendered text is clipped by bounds.
+// DrawBlock reports whether the rendering region overflows bounds.
+//
+// DrawBlock is concurrent-safe.
+func DrawBlock(dst *ebiten.Image, text string, face Face, bounds image.Rectangle, options *DrawOptions) (overflow bool) {
+	if options == nil {
+		options = &DrawOptions{}
+	}
+
+	var w, h float64
+	if options.ScaleByDeviceScaleFactor {
+		f, o, scale := deviceScaledFace(face, &options.LayoutOptions)
+		w, h = Measure(text, f, o.LineSpacingInPixels)
+		w *= scale
+		h *= scale
+	} else {
+		w, h = Measure(text, face, options.LineSpacingInPixels)
+	}
+	overflow = w > float64(bounds.Dx()) || h > float64(bounds.Dy())
+
+	var x, y float64
+	hAlign, vAlign := calcAligns(face.direction(), options.PrimaryAlign, options.SecondaryAlign)
+	switch hAlign {
+	case horizontalAlignLeft:
+		x = float64(bounds.Min.X)
+	case horizontalAlignCenter:
+		x = float64(bounds.Min.X+bounds.Max.X) / 2
+	case horizontalAlignRight:
+		x = float64(bounds.Max.X)
+	}
+	switch vAlign {
+	case verticalAlignTop:
+		y = float64(bounds.Min.Y)
+	case verticalAlignCenter:
+		y = float64(bounds.Min.Y+bounds.Max.Y) / 2
+	case verticalAlignBottom:
+		y = float64(bounds.Max.Y)
+	}
+
+	geoM := options.GeoM
+	options.GeoM.Reset()
+	options.GeoM.Translate(x, y)
+	options.GeoM.Concat(geoM)
+	Draw(dst.SubImage(bounds).(*ebiten.Image), text, face, options)
+	options.GeoM = geoM
+
+	return overflow
+}
+
 // AppendGlyphs appends glyphs to the given slice and returns a slice.
 //
 // AppendGlyphs is a low-level API, and you can use AppendGlyphs to have more control than Draw.
 // AppendGlyphs is also available to precache glyphs.
 //
+// AppendGlyphs works in a similar way to the built-in append function.
+// If glyphs has enough capacity, AppendGlyphs doesn't allocate a new slice.
+// By reusing the returned slice like glyphs = AppendGlyphs(glyphs[:0], ...) e.g. every frame,
+// AppendGlyphs doesn't allocate memory in a steady state as long as the glyph images are cached.
+//
 // For the details of options, see Draw function.
 //
 // AppendGlyphs is concurrent-safe.
@@ -130,6 +651,10 @@ func AppendGlyphs(glyphs []Glyph, text string, face Face, options *LayoutOptions
 //
 // AppendVectorPath works only when the face is *GoTextFace or a composite face using *GoTextFace so far.
 // For other types, AppendVectorPath does nothing.
+//
+// The glyphs' outlines overlap for holes like the counter of 'O', and might overlap each other.
+// Fill the path with the non-zero rule, e.g. vector.FillRuleNonZero at vector.DrawFilledPath, to render the glyphs correctly.
+// To choose the anti-aliasing quality for small texts, use vector.DrawFilledPathWithOptions with AntiAliasSamples.
 func AppendVectorPath(path *vector.Path, text string, face Face, options *LayoutOptions) {
 	forEachLine(text, face, options, func(line string, indexOffset int, originX, originY float64) {
 		face.appendVectorPathForLine(path, line, originX, originY)
@@ -158,7 +683,9 @@ func forEachLine(text string, face Face, options *LayoutOptions, f func(text str
 	}
 
 	// Calculate the advances for each line.
-	var advances []float64
+	// Use a buffer on the stack to avoid allocations in the usual cases.
+	var advancesBuf [16]float64
+	advances := advancesBuf[:0]
 	var longestAdvance float64
 	var lineCount int
 	for t := text; ; {
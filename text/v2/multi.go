@@ -15,9 +15,11 @@
 package text
 
 import (
+	"fmt"
+	"image"
 	"unicode/utf8"
 
-	"github.com/hajimehoshi/ebiten/v2/vector"
+	glanguage "github.com/go-text/typesetting/language"
 )
 
 var _ Face = (MultiFace)(nil)
@@ -25,40 +27,149 @@ var _ Face = (MultiFace)(nil)
 // MultiFace is a Face that consists of multiple Face objects.
 // The face in the first index is used in the highest priority, and the last the lowest priority.
 //
+// A face is selected for each grapheme cluster (UAX #29), not for each rune.
+// The first face that has the glyphs for all the runes in a cluster is used, ignoring joiners like ZERO WIDTH JOINER and variation selectors.
+// Thus, an emoji ZWJ sequence or an emoji with a skin tone modifier is never split into multiple faces.
+//
 // There is a known issue: if the writing directions of the faces don't agree, the rendering result might be messed up.
+// Use NewMultiFace to detect this misconfiguration.
+// Converting a slice to MultiFace directly, e.g. MultiFace{face0, face1}, is still available for backward compatibility,
+// but the directions are not checked.
 type MultiFace []Face
 
+// NewMultiFace creates a new MultiFace from the given faces.
+//
+// NewMultiFace returns an error when the writing directions of the faces don't agree.
+func NewMultiFace(faces ...Face) (MultiFace, error) {
+	for i, f := range faces {
+		if i == 0 {
+			continue
+		}
+		if f.direction() != faces[0].direction() {
+			return nil, fmt.Errorf("text: the direction of the face at %d doesn't agree with the direction of the first face at NewMultiFace", i)
+		}
+	}
+	m := make(MultiFace, len(faces))
+	copy(m, faces)
+	return m, nil
+}
+
+// NewMultiFaceWithLastResort creates a new MultiFace from the given faces and a last-resort face.
+//
+// The last-resort face is used for runes that none of faces has, e.g., to render a consistent placeholder
+// like a .notdef box (tofu) so that missing glyphs are visible.
+// Without a last-resort face, such runes are not rendered and don't advance.
+// The last-resort face is used in the lowest priority, and is treated as if it had glyphs for all the runes.
+// What is rendered for a missing rune depends on lastResort. For example, a GoTextFace renders the font's .notdef glyph.
+//
+// NewMultiFaceWithLastResort returns an error when the writing directions of the faces and lastResort don't agree.
+func NewMultiFaceWithLastResort(faces []Face, lastResort Face) (MultiFace, error) {
+	m, err := NewMultiFace(append(faces[:len(faces):len(faces)], &lastResortFace{Face: lastResort})...)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// lastResortFace is a Face that is treated as if it had glyphs for all the runes.
+type lastResortFace struct {
+	Face
+}
+
+// hasGlyph implements Face.
+func (l *lastResortFace) hasGlyph(r rune) bool {
+	return true
+}
+
 // Metrics implements Face.
+//
+// Metrics returns the maximum values of all the faces' metrics.
+// This is conservative and might overstate the line height when a rarely-used face is much bigger than the others.
+// Use MetricsForText to calculate metrics based on the faces actually used for a text.
 func (m MultiFace) Metrics() Metrics {
 	var mt Metrics
 	for _, f := range m {
-		mt1 := f.Metrics()
-		if mt1.Height > mt.Height {
-			mt.Height = mt1.Height
-		}
-		if mt1.HAscent > mt.HAscent {
-			mt.HAscent = mt1.HAscent
-		}
-		if mt1.HDescent > mt.HDescent {
-			mt.HDescent = mt1.HDescent
-		}
-		if mt1.Width > mt.Width {
-			mt.Width = mt1.Width
-		}
-		if mt1.VAscent > mt.VAscent {
-			mt.VAscent = mt1.VAscent
+		mt = maxMetrics(mt, f.Metrics())
+	}
+	return mt
+}
+
+// MetricsForText returns the maximum values of the metrics of the faces that are actually used to render the given text.
+//
+// If no faces are used for the text, e.g., the text is empty, MetricsForText returns the first face's metrics.
+func (m MultiFace) MetricsForText(text string) Metrics {
+	return m.metricsForChunks(m.splitText(text))
+}
+
+func (m MultiFace) metricsForChunks(chunks []textChunk) Metrics {
+	if len(m) == 0 {
+		return Metrics{}
+	}
+
+	var mt Metrics
+	var used bool
+	// The number of faces is usually small, so a slice is enough to record the used faces.
+	visited := make([]bool, len(m))
+	for _, c := range chunks {
+		if c.faceIndex == -1 {
+			continue
 		}
-		if mt1.VDescent > mt.VDescent {
-			mt.VDescent = mt1.VDescent
+		if visited[c.faceIndex] {
+			continue
 		}
+		visited[c.faceIndex] = true
+		mt = maxMetrics(mt, m[c.faceIndex].Metrics())
+		used = true
+	}
+	if !used {
+		return m[0].Metrics()
 	}
 	return mt
 }
 
+func maxMetrics(a, b Metrics) Metrics {
+	if b.Height > a.Height {
+		a.Height = b.Height
+	}
+	if b.HAscent > a.HAscent {
+		a.HAscent = b.HAscent
+	}
+	if b.HDescent > a.HDescent {
+		a.HDescent = b.HDescent
+	}
+	if b.Width > a.Width {
+		a.Width = b.Width
+	}
+	if b.VAscent > a.VAscent {
+		a.VAscent = b.VAscent
+	}
+	if b.VDescent > a.VDescent {
+		a.VDescent = b.VDescent
+	}
+	return a
+}
+
+// GlyphBounds implements Face.
+//
+// GlyphBounds delegates to the first face that has the glyph for r.
+func (m MultiFace) GlyphBounds(r rune) (bounds image.Rectangle, advance float64) {
+	for _, f := range m {
+		if !f.hasGlyph(r) {
+			continue
+		}
+		return f.GlyphBounds(r)
+	}
+	return image.Rectangle{}, 0
+}
+
 // advance implements Face.
 func (m MultiFace) advance(text string) float64 {
+	return m.advanceForChunks(text, m.splitText(text))
+}
+
+func (m MultiFace) advanceForChunks(text string, chunks []textChunk) float64 {
 	var a float64
-	for _, c := range m.splitText(text) {
+	for _, c := range chunks {
 		if c.faceIndex == -1 {
 			continue
 		}
@@ -68,6 +179,32 @@ func (m MultiFace) advance(text string) float64 {
 	return a
 }
 
+// appendAdvances implements Face.
+func (m MultiFace) appendAdvances(advances []float64, text string) []float64 {
+	return m.appendAdvancesForChunks(advances, text, m.splitText(text))
+}
+
+func (m MultiFace) appendAdvancesForChunks(advances []float64, text string, chunks []textChunk) []float64 {
+	var a float64
+	for _, c := range chunks {
+		t := text[c.textStartIndex:c.textEndIndex]
+		if c.faceIndex == -1 {
+			for range t {
+				advances = append(advances, a)
+			}
+			continue
+		}
+		f := m[c.faceIndex]
+		n := len(advances)
+		advances = f.appendAdvances(advances, t)
+		for i := n; i < len(advances); i++ {
+			advances[i] += a
+		}
+		a += f.advance(t)
+	}
+	return advances
+}
+
 // hasGlyph implements Face.
 func (m MultiFace) hasGlyph(r rune) bool {
 	for _, f := range m {
@@ -80,7 +217,11 @@ func (m MultiFace) hasGlyph(r rune) bool {
 
 // appendGlyphsForLine implements Face.
 func (m MultiFace) appendGlyphsForLine(glyphs []Glyph, line string, indexOffset int, originX, originY float64) []Glyph {
-	for _, c := range m.splitText(line) {
+	return m.appendGlyphsForChunks(glyphs, line, m.splitText(line), indexOffset, originX, originY)
+}
+
+func (m MultiFace) appendGlyphsForChunks(glyphs []Glyph, line string, chunks []textChunk, indexOffset int, originX, originY float64) []Glyph {
+	for _, c := range chunks {
 		if c.faceIndex == -1 {
 			continue
 		}
@@ -98,8 +239,12 @@ func (m MultiFace) appendGlyphsForLine(glyphs []Glyph, line string, indexOffset
 }
 
 // appendVectorPathForLine implements Face.
-func (m MultiFace) appendVectorPathForLine(path *vector.Path, line string, originX, originY float64) {
-	for _, c := range m.splitText(line) {
+func (m MultiFace) appendVectorPathForLine(path vectorPath, line string, originX, originY float64) {
+	m.appendVectorPathForChunks(path, line, m.splitText(line), originX, originY)
+}
+
+func (m MultiFace) appendVectorPathForChunks(path vectorPath, line string, chunks []textChunk, originX, originY float64) {
+	for _, c := range chunks {
 		if c.faceIndex == -1 {
 			continue
 		}
@@ -122,6 +267,27 @@ func (m MultiFace) direction() Direction {
 	return m[0].direction()
 }
 
+// scripts implements Face.
+func (m MultiFace) scripts() map[glanguage.Script]struct{} {
+	ss := map[glanguage.Script]struct{}{}
+	for _, f := range m {
+		for s := range f.scripts() {
+			ss[s] = struct{}{}
+		}
+	}
+	return ss
+}
+
+// hasScript implements Face.
+func (m MultiFace) hasScript(script glanguage.Script) bool {
+	for _, f := range m {
+		if f.hasScript(script) {
+			return true
+		}
+	}
+	return false
+}
+
 // private implements Face.
 func (m MultiFace) private() {
 }
@@ -132,36 +298,77 @@ type textChunk struct {
 	faceIndex      int
 }
 
+// splitText splits text into chunks for the faces.
+//
+// A grapheme cluster, e.g., an emoji ZWJ sequence or an emoji with a skin tone modifier, is never split into multiple faces,
+// so that the face can shape the cluster as one glyph.
 func (m MultiFace) splitText(text string) []textChunk {
 	var chunks []textChunk
 
-	for ri, r := range text {
-		// -1 indicates the default face index. -1 is used when no face is found for the glyph.
-		fi := -1
+	forEachGraphemeCluster(text, func(start, end int) {
+		chunks = appendTextChunk(chunks, end-start, m.faceIndexForCluster(text[start:end]))
+	})
 
-		_, l := utf8.DecodeRuneInString(text[ri:])
-		for i, f := range m {
-			if !f.hasGlyph(r) {
+	return chunks
+}
+
+// faceIndexForCluster returns the index of the first face that has the glyphs for all the runes in the grapheme cluster.
+// Joiners like ZERO WIDTH JOINER are not taken into account.
+// If there is no such face, faceIndexForCluster returns the index of the first face that has the glyph for the first rune.
+// faceIndexForCluster returns -1 when no face is found.
+func (m MultiFace) faceIndexForCluster(cluster string) int {
+	r, l := utf8.DecodeRuneInString(cluster)
+	if l == len(cluster) {
+		return m.faceIndexForRune(r)
+	}
+
+	for i, f := range m {
+		// A last-resort face is used only when the other faces don't have the first rune.
+		if _, ok := f.(*lastResortFace); ok {
+			continue
+		}
+		hasAll := true
+		for _, r := range cluster {
+			if isClusterJoiner(r) {
 				continue
 			}
-			fi = i
-			break
+			if !f.hasGlyph(r) {
+				hasAll = false
+				break
+			}
+		}
+		if hasAll {
+			return i
 		}
+	}
+	return m.faceIndexForRune(r)
+}
 
-		var s int
-		if len(chunks) > 0 {
-			if chunks[len(chunks)-1].faceIndex == fi {
-				chunks[len(chunks)-1].textEndIndex += l
-				continue
-			}
-			s = chunks[len(chunks)-1].textEndIndex
+// faceIndexForRune returns the index of the first face that has the glyph for r.
+// faceIndexForRune returns -1 when no face is found.
+func (m MultiFace) faceIndexForRune(r rune) int {
+	for i, f := range m {
+		if f.hasGlyph(r) {
+			return i
 		}
-		chunks = append(chunks, textChunk{
-			textStartIndex: s,
-			textEndIndex:   s + l,
-			faceIndex:      fi,
-		})
 	}
+	return -1
+}
 
-	return chunks
+// appendTextChunk appends a rune with the given byte length rendered with the face index fi to chunks.
+// -1 indicates the default face index. -1 is used when no face is found for the glyph.
+func appendTextChunk(chunks []textChunk, l int, fi int) []textChunk {
+	var s int
+	if len(chunks) > 0 {
+		if chunks[len(chunks)-1].faceIndex == fi {
+			chunks[len(chunks)-1].textEndIndex += l
+			return chunks
+		}
+		s = chunks[len(chunks)-1].textEndIndex
+	}
+	return append(chunks, textChunk{
+		textStartIndex: s,
+		textEndIndex:   s + l,
+		faceIndex:      fi,
+	})
 }
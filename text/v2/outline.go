@@ -0,0 +1,82 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"image"
+	"math"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+var (
+	// outlineBuffer is an offscreen image to accumulate dilated glyphs for an outline.
+	outlineBuffer  *ebiten.Image
+	outlineBufferM sync.Mutex
+)
+
+// drawGlyphsOutline draws the outline of the glyphs on dst.
+func drawGlyphsOutline(dst *ebiten.Image, glyphs []Glyph, options *DrawOptions) {
+	outlineBufferM.Lock()
+	defer outlineBufferM.Unlock()
+
+	// Calculate the region to render the outline.
+	w := options.OutlineWidth
+	var r image.Rectangle
+	for _, p := range [][2]float64{{-w, -w}, {w, -w}, {-w, w}, {w, w}} {
+		var g ebiten.GeoM
+		g.Translate(p[0], p[1])
+		g.Concat(options.GeoM)
+		r = r.Union(glyphsBounds(glyphs, g))
+	}
+	r = r.Intersect(dst.Bounds())
+	if r.Empty() {
+		return
+	}
+
+	buf := prepareOffscreenBuffer(&outlineBuffer, r.Dx(), r.Dy())
+
+	// Dilate the glyphs by rendering them at the positions on concentric circles.
+	// The circles are at most 1 pixel apart, and the positions on each circle are also at most 1 pixel apart,
+	// so that thin parts of glyphs don't make holes in the outline.
+	op := &ebiten.DrawImageOptions{}
+	op.Filter = options.Filter
+	ringCount := int(math.Ceil(w))
+	for i := 1; i <= ringCount; i++ {
+		radius := w * float64(i) / float64(ringCount)
+		n := int(math.Ceil(2 * math.Pi * radius))
+		if n < 8 {
+			n = 8
+		}
+		for j := 0; j < n; j++ {
+			theta := 2 * math.Pi * float64(j) / float64(n)
+			op.GeoM.Reset()
+			op.GeoM.Translate(radius*math.Cos(theta), radius*math.Sin(theta))
+			op.GeoM.Concat(options.GeoM)
+			op.GeoM.Translate(-float64(r.Min.X), -float64(r.Min.Y))
+			drawGlyphs(buf, glyphs, op)
+		}
+	}
+
+	op2 := &ebiten.DrawImageOptions{}
+	op2.GeoM.Translate(float64(r.Min.X), float64(r.Min.Y))
+	op2.ColorScale.ScaleWithColor(options.OutlineColor)
+	op2.ColorScale.ScaleAlpha(options.ColorScale.A())
+	op2.CompositeMode = options.CompositeMode
+	op2.Blend = options.Blend
+	op2.LinearBlending = options.LinearBlending
+	dst.DrawImage(buf, op2)
+}
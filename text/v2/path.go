@@ -0,0 +1,127 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// PathOverflow represents how to treat glyphs that don't fit in the path at DrawOnPath.
+type PathOverflow int
+
+const (
+	// PathOverflowDrop drops glyphs out of the path.
+	PathOverflowDrop PathOverflow = iota
+
+	// PathOverflowWrap wraps glyphs out of the path around to the path's start.
+	// This is useful for a closed path like a circle.
+	PathOverflowWrap
+)
+
+// DrawOnPathOptions represents options for the DrawOnPath function.
+//
+// DrawOnPathOptions embeds ebiten.DrawImageOptions.
+// DrawImageOptions.GeoM is an additional geometry transformation
+// after putting glyphs along the path.
+// DrawImageOptions.ColorScale scales the text color.
+type DrawOnPathOptions struct {
+	ebiten.DrawImageOptions
+
+	// Offset is the distance along the path where the text starts.
+	Offset float64
+
+	// Overflow specifies how to treat glyphs that don't fit in the path.
+	// The default value is PathOverflowDrop.
+	Overflow PathOverflow
+}
+
+// DrawOnPath draws a given text along a given path on a given destination image dst.
+// face is the font for text rendering.
+//
+// Each glyph is put so that the center of the glyph on the baseline is at the position of the path,
+// and is rotated to the path's tangent there.
+// The positions are determined by the distance along the path, not the straight-line distance.
+//
+// The text is rendered as one line. The '\n' newline character doesn't break the line.
+//
+// DrawOnPath works only with a horizontal-direction face.
+// For a vertical-direction face, DrawOnPath does nothing.
+//
+// DrawOnPath is concurrent-safe.
+func DrawOnPath(dst *ebiten.Image, text string, face Face, path *vector.Path, options *DrawOnPathOptions) {
+	if options == nil {
+		options = &DrawOnPathOptions{}
+	}
+
+	if !face.direction().isHorizontal() {
+		return
+	}
+
+	length := float64(path.Length())
+	if length == 0 {
+		return
+	}
+
+	glyphs := theGlyphsPool.get()
+	defer func() {
+		theGlyphsPool.put(glyphs)
+	}()
+
+	geoM := options.GeoM
+	glyphs = face.appendGlyphsForLine(glyphs, text, 0, 0, 0)
+	for _, g := range glyphs {
+		w, h := g.Image.Bounds().Dx(), g.Image.Bounds().Dy()
+		if w == 0 || h == 0 {
+			continue
+		}
+
+		// The glyph's center on the baseline is the pivot.
+		sw, _ := g.geoM.Apply(float64(w), 0)
+		cx := g.X + sw/2
+		pos := options.Offset + cx
+		if pos < 0 || pos > length {
+			switch options.Overflow {
+			case PathOverflowDrop:
+				continue
+			case PathOverflowWrap:
+				pos = math.Mod(pos, length)
+				if pos < 0 {
+					pos += length
+				}
+			}
+		}
+
+		x, y, angle, ok := path.PointAtLength(float32(pos))
+		if !ok {
+			continue
+		}
+
+		op := &options.DrawImageOptions
+		op.GeoM = g.geoM
+		op.GeoM.Translate(g.X-cx, g.Y)
+		op.GeoM.Rotate(float64(angle))
+		op.GeoM.Translate(float64(x), float64(y))
+		op.GeoM.Concat(geoM)
+		if g.sdf {
+			drawSDFGlyph(dst, g.Image, op)
+			continue
+		}
+		dst.DrawImage(g.Image, op)
+	}
+	options.GeoM = geoM
+}
@@ -0,0 +1,102 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"sort"
+	"sync"
+
+	glanguage "github.com/go-text/typesetting/language"
+	"golang.org/x/text/language"
+)
+
+// scriptCoverageThreshold is the number of runes that a face must cover to support a script.
+// A face covering only a few stray runes of a script, e.g. a Latin font including some CJK symbols, doesn't support the script.
+// If a script has less than twice the threshold runes, the half of the script's runes is used as the threshold instead.
+const scriptCoverageThreshold = 32
+
+var (
+	scriptRuneCounts     map[glanguage.Script]int
+	scriptRuneCountsOnce sync.Once
+)
+
+func scriptRuneCount(script glanguage.Script) int {
+	scriptRuneCountsOnce.Do(func() {
+		scriptRuneCounts = map[glanguage.Script]int{}
+		for _, r := range glanguage.ScriptRanges {
+			scriptRuneCounts[r.Script] += int(r.End-r.Start) + 1
+		}
+	})
+	return scriptRuneCounts[script]
+}
+
+// isNeutralScript reports whether the script doesn't belong to a specific writing system.
+// Runes in a neutral script, like spaces and punctuations, are rendered with the surrounding script's face.
+func isNeutralScript(script glanguage.Script) bool {
+	switch script {
+	case glanguage.Common, glanguage.Inherited, glanguage.Unknown:
+		return true
+	}
+	return false
+}
+
+// scriptCoverage counts runes covered by a face for each script.
+type scriptCoverage map[glanguage.Script]int
+
+func (s scriptCoverage) add(r rune) {
+	script := glanguage.LookupScript(r)
+	if isNeutralScript(script) {
+		return
+	}
+	s[script]++
+}
+
+func (s scriptCoverage) supportedScripts() map[glanguage.Script]struct{} {
+	scripts := map[glanguage.Script]struct{}{}
+	for script, c := range s {
+		threshold := scriptCoverageThreshold
+		if n := scriptRuneCount(script); n < threshold*2 {
+			threshold = (n + 1) / 2
+		}
+		if c < threshold {
+			continue
+		}
+		scripts[script] = struct{}{}
+	}
+	return scripts
+}
+
+// SupportedScripts returns the Unicode scripts that the face supports.
+//
+// A face supports a script when the face has glyphs for a sufficient number of the script's characters.
+// Scripts for characters common to multiple writing systems, like spaces or digits, are not included.
+//
+// The result is sorted by the script codes.
+//
+// SupportedScripts is concurrent-safe.
+func SupportedScripts(face Face) []language.Script {
+	var scripts []language.Script
+	for s := range face.scripts() {
+		script, err := language.ParseScript(s.String())
+		if err != nil {
+			continue
+		}
+		scripts = append(scripts, script)
+	}
+	sort.Slice(scripts, func(i, j int) bool {
+		return scripts[i].String() < scripts[j].String()
+	})
+	return scripts
+}
@@ -0,0 +1,156 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"image"
+	"unicode/utf8"
+
+	glanguage "github.com/go-text/typesetting/language"
+)
+
+var _ Face = (ScriptMultiFace)(nil)
+
+// ScriptMultiFace is a Face that consists of multiple Face objects like MultiFace,
+// but chooses a face based on Unicode scripts rather than each rune.
+//
+// ScriptMultiFace groups runes into runs of the same script.
+// For each run, the first face supporting the script is used. See SupportedScripts for the definition of the support.
+// This prevents a face that happens to have a glyph of a different script from being chosen,
+// e.g. a Latin face including some CJK glyphs, and gives more consistent rendering results.
+//
+// Runes for characters common to multiple scripts, like spaces or punctuations, belong to the surrounding run.
+// If the chosen face doesn't have a glyph, or no face supports the script, a face is chosen for each rune in the same way as MultiFace.
+//
+// There is a known issue: if the writing directions of the faces don't agree, the rendering result might be messed up.
+type ScriptMultiFace []Face
+
+// Metrics implements Face.
+//
+// Metrics returns the maximum values of all the faces' metrics.
+func (s ScriptMultiFace) Metrics() Metrics {
+	return MultiFace(s).Metrics()
+}
+
+// MetricsForText returns the maximum values of the metrics of the faces that are actually used to render the given text.
+//
+// If no faces are used for the text, e.g., the text is empty, MetricsForText returns the first face's metrics.
+func (s ScriptMultiFace) MetricsForText(text string) Metrics {
+	return MultiFace(s).metricsForChunks(s.splitText(text))
+}
+
+// GlyphBounds implements Face.
+func (s ScriptMultiFace) GlyphBounds(r rune) (bounds image.Rectangle, advance float64) {
+	fi := s.faceIndexForScript(glanguage.LookupScript(r), r)
+	if fi == -1 {
+		return image.Rectangle{}, 0
+	}
+	return s[fi].GlyphBounds(r)
+}
+
+// advance implements Face.
+func (s ScriptMultiFace) advance(text string) float64 {
+	return MultiFace(s).advanceForChunks(text, s.splitText(text))
+}
+
+// appendAdvances implements Face.
+func (s ScriptMultiFace) appendAdvances(advances []float64, text string) []float64 {
+	return MultiFace(s).appendAdvancesForChunks(advances, text, s.splitText(text))
+}
+
+// hasGlyph implements Face.
+func (s ScriptMultiFace) hasGlyph(r rune) bool {
+	return MultiFace(s).hasGlyph(r)
+}
+
+// appendGlyphsForLine implements Face.
+func (s ScriptMultiFace) appendGlyphsForLine(glyphs []Glyph, line string, indexOffset int, originX, originY float64) []Glyph {
+	return MultiFace(s).appendGlyphsForChunks(glyphs, line, s.splitText(line), indexOffset, originX, originY)
+}
+
+// appendVectorPathForLine implements Face.
+func (s ScriptMultiFace) appendVectorPathForLine(path vectorPath, line string, originX, originY float64) {
+	MultiFace(s).appendVectorPathForChunks(path, line, s.splitText(line), originX, originY)
+}
+
+// direction implements Face.
+func (s ScriptMultiFace) direction() Direction {
+	return MultiFace(s).direction()
+}
+
+// scripts implements Face.
+func (s ScriptMultiFace) scripts() map[glanguage.Script]struct{} {
+	return MultiFace(s).scripts()
+}
+
+// hasScript implements Face.
+func (s ScriptMultiFace) hasScript(script glanguage.Script) bool {
+	return MultiFace(s).hasScript(script)
+}
+
+// private implements Face.
+func (s ScriptMultiFace) private() {
+}
+
+// faceIndexForScript returns the index of the face to render r in a run of the given script.
+// faceIndexForScript returns -1 when no face is found.
+func (s ScriptMultiFace) faceIndexForScript(script glanguage.Script, r rune) int {
+	if !isNeutralScript(script) {
+		for i, f := range s {
+			if !f.hasScript(script) {
+				continue
+			}
+			if f.hasGlyph(r) {
+				return i
+			}
+			break
+		}
+	}
+	return MultiFace(s).faceIndexForRune(r)
+}
+
+func (s ScriptMultiFace) splitText(text string) []textChunk {
+	// Determine the script of each run first.
+	// A rune in a neutral script belongs to the previous run, or the next run if there is no previous run.
+	runeScripts := make([]glanguage.Script, 0, len(text))
+	current := glanguage.Common
+	var leadingNeutrals int
+	for _, r := range text {
+		script := glanguage.LookupScript(r)
+		if isNeutralScript(script) {
+			if isNeutralScript(current) {
+				leadingNeutrals++
+			}
+			runeScripts = append(runeScripts, current)
+			continue
+		}
+		if isNeutralScript(current) {
+			for i := 0; i < leadingNeutrals; i++ {
+				runeScripts[i] = script
+			}
+		}
+		current = script
+		runeScripts = append(runeScripts, script)
+	}
+
+	var chunks []textChunk
+	var i int
+	for ri, r := range text {
+		_, l := utf8.DecodeRuneInString(text[ri:])
+		chunks = appendTextChunk(chunks, l, s.faceIndexForScript(runeScripts[i], r))
+		i++
+	}
+	return chunks
+}
@@ -0,0 +1,411 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"math"
+	"sync"
+
+	glanguage "github.com/go-text/typesetting/language"
+	"github.com/go-text/typesetting/opentype/api"
+	"golang.org/x/image/math/fixed"
+	gvector "golang.org/x/image/vector"
+	"golang.org/x/text/language"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	// sdfBaseSize is the font size in pixels to rasterize a glyph into a signed distance field.
+	sdfBaseSize = 64
+
+	// sdfSpread is the maximum distance in pixels at sdfBaseSize that a signed distance field can represent.
+	// This is also the padding around a glyph in a signed distance field image.
+	sdfSpread = 8
+)
+
+const sdfShaderSrc = `//kage:unit pixels
+
+package main
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	// Sample the distance with a bilinear filter.
+	p := srcPos - 1/2.0
+	p0 := floor(p) + 1/2.0
+	rate := fract(p)
+	d0 := imageSrc0At(p0).a
+	d1 := imageSrc0At(p0 + vec2(1, 0)).a
+	d2 := imageSrc0At(p0 + vec2(0, 1)).a
+	d3 := imageSrc0At(p0 + vec2(1, 1)).a
+	d := mix(mix(d0, d1, rate.x), mix(d2, d3, rate.x), rate.y)
+
+	// The edge is at 0.5. Smooth the edge over about one destination pixel.
+	w := max(fwidth(d), 1/256.0) / 2
+	return color * smoothstep(0.5-w, 0.5+w, d)
+}
+`
+
+var (
+	sdfShader     *ebiten.Shader
+	sdfShaderOnce sync.Once
+)
+
+func ensureSDFShader() *ebiten.Shader {
+	sdfShaderOnce.Do(func() {
+		s, err := ebiten.NewShader([]byte(sdfShaderSrc))
+		if err != nil {
+			panic(fmt.Sprintf("text: compiling the SDF shader failed: %v", err))
+		}
+		sdfShader = s
+	})
+	return sdfShader
+}
+
+// drawSDFGlyph draws a glyph image of a signed distance field with the given options.
+func drawSDFGlyph(dst *ebiten.Image, img *ebiten.Image, options *ebiten.DrawImageOptions) {
+	op := &ebiten.DrawRectShaderOptions{}
+	op.GeoM = options.GeoM
+	op.ColorScale = options.ColorScale
+	op.CompositeMode = options.CompositeMode
+	op.Blend = options.Blend
+	op.Images[0] = img
+	b := img.Bounds()
+	dst.DrawRectShader(b.Dx(), b.Dy(), ensureSDFShader(), op)
+}
+
+var _ Face = (*SDFFace)(nil)
+
+// SDFFaceOptions represents options for NewSDFFace.
+type SDFFaceOptions struct {
+	// Size is the font size in pixels.
+	Size float64
+
+	// Direction is the rendering direction.
+	// The default (zero) value is left-to-right horizontal.
+	Direction Direction
+
+	// Language is a hint for a language (BCP 47).
+	// See GoTextFace.Language for more details.
+	Language language.Tag
+
+	// Script is a hint for a script code hint of (ISO 15924).
+	// If this is empty, the script is guessed from the specified language.
+	Script language.Script
+}
+
+// SDFFace is a Face that renders glyphs with signed distance fields (SDF).
+//
+// A glyph is rasterized only once into a signed distance field regardless of the size,
+// and the field is shared by all the SDFFaces using the same source.
+// At rendering, a shader reconstructs the glyph's sharp edges from the field.
+// Then, the glyphs are kept crisp even when the text is scaled up by DrawOptions.GeoM, e.g., for a zoomable map.
+//
+// SDFFace is compatible with DrawOptions. DrawOptions.ColorScale and DrawOptions.Blend are applied as well as Draw with other faces.
+// DrawOptions.Filter is ignored as the shader always samples the field smoothly.
+//
+// The glyph images that AppendGlyphs returns for an SDFFace are signed distance fields,
+// where the alpha value 0.5 represents the glyph's edge, and the image is bigger than the glyph size.
+// Drawing them with DrawImage doesn't render the glyphs correctly. Use Draw instead.
+//
+// The quality of very thin strokes or sharp corners might be worse than GoTextFace.
+// Also, as a glyph is not aligned to the pixel grid, a small text might look blurrier than GoTextFace.
+type SDFFace struct {
+	face GoTextFace
+}
+
+// NewSDFFace creates a new SDFFace with the given source and options.
+func NewSDFFace(source *GoTextFaceSource, options *SDFFaceOptions) *SDFFace {
+	if options == nil {
+		options = &SDFFaceOptions{}
+	}
+	return &SDFFace{
+		face: GoTextFace{
+			Source:    source,
+			Direction: options.Direction,
+			Size:      options.Size,
+			Language:  options.Language,
+			Script:    options.Script,
+		},
+	}
+}
+
+// Metrics implements Face.
+func (s *SDFFace) Metrics() Metrics {
+	return s.face.Metrics()
+}
+
+// GlyphBounds implements Face.
+func (s *SDFFace) GlyphBounds(r rune) (bounds image.Rectangle, advance float64) {
+	return s.face.GlyphBounds(r)
+}
+
+// advance implements Face.
+func (s *SDFFace) advance(text string) float64 {
+	return s.face.advance(text)
+}
+
+// appendAdvances implements Face.
+func (s *SDFFace) appendAdvances(advances []float64, text string) []float64 {
+	return s.face.appendAdvances(advances, text)
+}
+
+// hasGlyph implements Face.
+func (s *SDFFace) hasGlyph(r rune) bool {
+	return s.face.hasGlyph(r)
+}
+
+// appendGlyphsForLine implements Face.
+func (s *SDFFace) appendGlyphsForLine(glyphs []Glyph, line string, indexOffset int, originX, originY float64) []Glyph {
+	if s.face.Size <= 0 {
+		return glyphs
+	}
+
+	scale := s.face.Size / sdfBaseSize
+	x, y := originX, originY
+	_, gs := s.face.Source.shape(line, &s.face)
+	for _, glyph := range gs {
+		if img, imgX, imgY := s.glyphImage(glyph); img != nil {
+			var geoM ebiten.GeoM
+			geoM.Scale(scale, scale)
+			glyphs = append(glyphs, Glyph{
+				StartIndexInBytes: indexOffset + glyph.startIndex,
+				EndIndexInBytes:   indexOffset + glyph.endIndex,
+				GID:               uint32(glyph.shapingGlyph.GlyphID),
+				Image:             img,
+				X:                 x + float64(imgX)*scale,
+				Y:                 y + float64(imgY)*scale,
+				geoM:              geoM,
+				sdf:               true,
+			})
+		}
+		x += fixed26_6ToFloat64(glyph.shapingGlyph.XAdvance)
+		y -= fixed26_6ToFloat64(glyph.shapingGlyph.YAdvance)
+	}
+	return glyphs
+}
+
+// glyphImage returns the signed distance field image for the glyph and the image's position at sdfBaseSize.
+func (s *SDFFace) glyphImage(glyph glyph) (*ebiten.Image, int, int) {
+	// The scaled segments are for the face's size. Rescale them to sdfBaseSize.
+	segs := make([]api.Segment, len(glyph.scaledSegments))
+	scale := float32(sdfBaseSize / s.face.Size)
+	for i, seg := range glyph.scaledSegments {
+		segs[i] = seg
+		for j := range seg.Args {
+			segs[i].Args[j].X *= scale
+			segs[i].Args[j].Y *= scale
+		}
+	}
+
+	b := segmentsToBounds(segs)
+	key := goTextGlyphImageCacheKey{
+		gid:        glyph.shapingGlyph.GlyphID,
+		variations: s.face.ensureVariationsString(),
+	}
+	img := s.face.Source.getOrCreateSDFImage(s, key, func() *ebiten.Image {
+		return segmentsToSDFImage(segs, b)
+	})
+	return img, b.Min.X.Floor() - sdfSpread, b.Min.Y.Floor() - sdfSpread
+}
+
+// appendVectorPathForLine implements Face.
+func (s *SDFFace) appendVectorPathForLine(path vectorPath, line string, originX, originY float64) {
+	s.face.appendVectorPathForLine(path, line, originX, originY)
+}
+
+// direction implements Face.
+func (s *SDFFace) direction() Direction {
+	return s.face.direction()
+}
+
+// scripts implements Face.
+func (s *SDFFace) scripts() map[glanguage.Script]struct{} {
+	return s.face.scripts()
+}
+
+// hasScript implements Face.
+func (s *SDFFace) hasScript(script glanguage.Script) bool {
+	return s.face.hasScript(script)
+}
+
+// private implements Face.
+func (s *SDFFace) private() {
+}
+
+// segmentsToSDFImage rasterizes the segments and converts the result into a signed distance field image.
+// The alpha value represents the signed distance: 0.5 is on the edge, and a bigger value is more inside.
+func segmentsToSDFImage(segs []api.Segment, glyphBounds fixed.Rectangle26_6) *ebiten.Image {
+	if len(segs) == 0 {
+		return nil
+	}
+
+	minX, minY := glyphBounds.Min.X.Floor(), glyphBounds.Min.Y.Floor()
+	maxX, maxY := glyphBounds.Max.X.Ceil(), glyphBounds.Max.Y.Ceil()
+	if minX == maxX || minY == maxY {
+		return nil
+	}
+
+	w, h := maxX-minX+2*sdfSpread, maxY-minY+2*sdfSpread
+	biasX := float32(-minX + sdfSpread)
+	biasY := float32(-minY + sdfSpread)
+
+	rast := gvector.NewRasterizer(w, h)
+	rast.DrawOp = draw.Src
+	for _, seg := range segs {
+		switch seg.Op {
+		case api.SegmentOpMoveTo:
+			rast.MoveTo(seg.Args[0].X+biasX, seg.Args[0].Y+biasY)
+		case api.SegmentOpLineTo:
+			rast.LineTo(seg.Args[0].X+biasX, seg.Args[0].Y+biasY)
+		case api.SegmentOpQuadTo:
+			rast.QuadTo(
+				seg.Args[0].X+biasX, seg.Args[0].Y+biasY,
+				seg.Args[1].X+biasX, seg.Args[1].Y+biasY,
+			)
+		case api.SegmentOpCubeTo:
+			rast.CubeTo(
+				seg.Args[0].X+biasX, seg.Args[0].Y+biasY,
+				seg.Args[1].X+biasX, seg.Args[1].Y+biasY,
+				seg.Args[2].X+biasX, seg.Args[2].Y+biasY,
+			)
+		}
+	}
+	coverage := image.NewAlpha(image.Rect(0, 0, w, h))
+	rast.Draw(coverage, coverage.Bounds(), image.Opaque, image.Point{})
+
+	// distToInside is the distance to the nearest inside pixel, and distToOutside is the distance to the nearest outside pixel.
+	distToInside := make([]float64, w*h)
+	distToOutside := make([]float64, w*h)
+	for i, a := range coverage.Pix {
+		if a >= 0x80 {
+			distToOutside[i] = math.Inf(1)
+		} else {
+			distToInside[i] = math.Inf(1)
+		}
+	}
+	distanceTransform(distToInside, w, h)
+	distanceTransform(distToOutside, w, h)
+
+	pix := make([]byte, 4*w*h)
+	for i, a := range coverage.Pix {
+		var d float64
+		if a != 0 && a != 0xff {
+			// A pixel on the edge. The coverage is a better approximation of the distance.
+			d = float64(a)/0xff - 0.5
+		} else if a >= 0x80 {
+			// The edge is at the middle between the pixel centers.
+			d = math.Sqrt(distToOutside[i]) - 0.5
+		} else {
+			d = -math.Sqrt(distToInside[i]) + 0.5
+		}
+		v := 0.5 + d/(2*sdfSpread)
+		v = math.Min(math.Max(v, 0), 1)
+		c := byte(math.Round(v * 0xff))
+		// The image is premultiplied-alpha, so all the values must be the same.
+		pix[4*i] = c
+		pix[4*i+1] = c
+		pix[4*i+2] = c
+		pix[4*i+3] = c
+	}
+
+	img := ebiten.NewImage(w, h)
+	img.WritePixels(pix)
+	return img
+}
+
+// distanceTransform converts the values into the squared Euclidean distances to the nearest zero-valued pixels in place.
+// The values must be 0 or +Inf.
+//
+// This is based on Felzenszwalb and Huttenlocher's algorithm, "Distance Transforms of Sampled Functions".
+func distanceTransform(values []float64, width, height int) {
+	n := width
+	if n < height {
+		n = height
+	}
+	f := make([]float64, n)
+	d := make([]float64, n)
+	v := make([]int, n)
+	z := make([]float64, n+1)
+
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			f[y] = values[y*width+x]
+		}
+		distanceTransform1D(d, f[:height], v, z)
+		for y := 0; y < height; y++ {
+			values[y*width+x] = d[y]
+		}
+	}
+	for y := 0; y < height; y++ {
+		copy(f, values[y*width:(y+1)*width])
+		distanceTransform1D(values[y*width:(y+1)*width], f[:width], v, z)
+	}
+}
+
+// distanceTransform1D calculates the one-dimensional squared distance transform of f into d.
+// v and z are buffers whose lengths must be at least len(f) and len(f)+1 respectively.
+func distanceTransform1D(d []float64, f []float64, v []int, z []float64) {
+	n := len(f)
+
+	// Find the first finite value. If there is no such value, all the distances are infinite.
+	var k int
+	first := -1
+	for q := 0; q < n; q++ {
+		if !math.IsInf(f[q], 1) {
+			first = q
+			break
+		}
+	}
+	if first == -1 {
+		for q := 0; q < n; q++ {
+			d[q] = math.Inf(1)
+		}
+		return
+	}
+
+	v[0] = first
+	z[0] = math.Inf(-1)
+	z[1] = math.Inf(1)
+	for q := first + 1; q < n; q++ {
+		if math.IsInf(f[q], 1) {
+			continue
+		}
+		var s float64
+		for {
+			r := v[k]
+			s = ((f[q] + float64(q*q)) - (f[r] + float64(r*r))) / float64(2*q-2*r)
+			if s > z[k] {
+				break
+			}
+			k--
+		}
+		k++
+		v[k] = q
+		z[k] = s
+		z[k+1] = math.Inf(1)
+	}
+
+	k = 0
+	for q := 0; q < n; q++ {
+		for z[k+1] < float64(q) {
+			k++
+		}
+		r := v[k]
+		d[q] = float64((q-r)*(q-r)) + f[r]
+	}
+}
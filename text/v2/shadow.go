@@ -0,0 +1,108 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"image"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// shadowBlurSampleCount is the number of samples in each axis to blur a shadow.
+// As 255 is divisible by 5, a fully opaque region is kept fully opaque after blurring.
+const shadowBlurSampleCount = 5
+
+var (
+	// shadowBuffers are offscreen images to blur a shadow horizontally and then vertically.
+	shadowBuffers  [2]*ebiten.Image
+	shadowBuffersM sync.Mutex
+)
+
+// drawGlyphsShadow draws the shadow of the glyphs on dst.
+func drawGlyphsShadow(dst *ebiten.Image, glyphs []Glyph, options *DrawOptions) {
+	var geoM ebiten.GeoM
+	geoM.Translate(options.ShadowOffsetX, options.ShadowOffsetY)
+	geoM.Concat(options.GeoM)
+
+	var colorScale ebiten.ColorScale
+	colorScale.ScaleWithColor(options.ShadowColor)
+	colorScale.ScaleAlpha(options.ColorScale.A())
+
+	if options.ShadowBlur <= 0 {
+		op := options.DrawImageOptions
+		op.GeoM = geoM
+		op.ColorScale = colorScale
+		drawGlyphs(dst, glyphs, &op)
+		return
+	}
+
+	shadowBuffersM.Lock()
+	defer shadowBuffersM.Unlock()
+
+	// Calculate the region to render the blurred shadow.
+	blur := options.ShadowBlur
+	var r image.Rectangle
+	for _, p := range [][2]float64{{-blur, -blur}, {blur, -blur}, {-blur, blur}, {blur, blur}} {
+		var g ebiten.GeoM
+		g.Translate(p[0], p[1])
+		g.Concat(geoM)
+		r = r.Union(glyphsBounds(glyphs, g))
+	}
+	r = r.Intersect(dst.Bounds())
+	if r.Empty() {
+		return
+	}
+
+	bufs := [2]*ebiten.Image{
+		prepareOffscreenBuffer(&shadowBuffers[0], r.Dx(), r.Dy()),
+		prepareOffscreenBuffer(&shadowBuffers[1], r.Dx(), r.Dy()),
+	}
+
+	// Accumulate the samples additively, which results in a box blur.
+	// The blur is separable, so blur the shadow horizontally first, and then vertically.
+	// The offsets are in the text's coordinates, and the vertical offsets are transformed by GeoM's linear part.
+	op := &ebiten.DrawImageOptions{}
+	op.Blend = ebiten.BlendLighter
+	op.Filter = options.Filter
+	op.ColorScale.ScaleAlpha(1.0 / shadowBlurSampleCount)
+	for i := 0; i < shadowBlurSampleCount; i++ {
+		d := blur * (2*float64(i)/(shadowBlurSampleCount-1) - 1)
+		op.GeoM.Reset()
+		op.GeoM.Translate(d, 0)
+		op.GeoM.Concat(geoM)
+		op.GeoM.Translate(-float64(r.Min.X), -float64(r.Min.Y))
+		drawGlyphs(bufs[0], glyphs, op)
+	}
+	op.Filter = ebiten.FilterLinear
+	for i := 0; i < shadowBlurSampleCount; i++ {
+		d := blur * (2*float64(i)/(shadowBlurSampleCount-1) - 1)
+		g := geoM
+		g.SetElement(0, 2, 0)
+		g.SetElement(1, 2, 0)
+		dx, dy := g.Apply(0, d)
+		op.GeoM.Reset()
+		op.GeoM.Translate(dx, dy)
+		bufs[1].DrawImage(bufs[0], op)
+	}
+
+	op2 := &ebiten.DrawImageOptions{}
+	op2.GeoM.Translate(float64(r.Min.X), float64(r.Min.Y))
+	op2.ColorScale = colorScale
+	op2.CompositeMode = options.CompositeMode
+	op2.Blend = options.Blend
+	op2.LinearBlending = options.LinearBlending
+	dst.DrawImage(bufs[1], op2)
+}
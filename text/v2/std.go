@@ -15,14 +15,19 @@
 package text
 
 import (
+	"fmt"
 	"image"
+	"image/color"
+	"image/draw"
+	"sync"
+	"sync/atomic"
 	"unicode/utf8"
 
+	glanguage "github.com/go-text/typesetting/language"
 	"golang.org/x/image/font"
 	"golang.org/x/image/math/fixed"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
 var _ Face = (*StdFace)(nil)
@@ -37,11 +42,21 @@ type stdFaceGlyphImageCacheKey struct {
 // StdFace is a Face implementation for a semi-standard font.Face (golang.org/x/image/font).
 // StdFace is useful to transit from existing codebase with text v1, or to use some bitmap fonts defined as font.Face.
 // StdFace must not be copied by value.
+//
+// If the font.Face's glyph mask images have colors, i.e., their color models are not alpha or gray models,
+// the glyphs are rendered with their own colors. This is useful for pre-colored bitmap fonts like color emojis.
+// In this case, DrawOptions.ColorScale scales the glyphs' colors multiplicatively.
 type StdFace struct {
 	f *faceWithCache
 
 	glyphImageCache glyphImageCache[stdFaceGlyphImageCacheKey]
 
+	// subpixelGranularity is accessed atomically.
+	subpixelGranularity int32
+
+	supportedScripts     map[glanguage.Script]struct{}
+	supportedScriptsOnce sync.Once
+
 	addr *StdFace
 }
 
@@ -74,6 +89,50 @@ func (s *StdFace) Metrics() Metrics {
 	}
 }
 
+// GlyphBounds implements Face.
+func (s *StdFace) GlyphBounds(r rune) (bounds image.Rectangle, advance float64) {
+	s.copyCheck()
+
+	b, a, ok := s.f.GlyphBounds(r)
+	if !ok {
+		return image.Rectangle{}, 0
+	}
+	return fixed26_6RectangleToImageRectangle(b), fixed26_6ToFloat64(a)
+}
+
+// SetSubpixelGranularity sets the number of subpixel positions per pixel in the horizontal direction.
+//
+// StdFace caches a glyph image for each subpixel position, so a bigger granularity makes glyphs' positions more accurate,
+// but requires more cache entries e.g. for animated scrolling texts.
+// For example, 4 means a glyph is rendered at 1/4 pixel precision, and 1 means a glyph is rendered at integer pixels.
+//
+// granularity must be 0, 1, 2, 4, 8, 16, 32, or 64. Otherwise, SetSubpixelGranularity panics.
+// 0 means the default granularity, which is decided based on the face's size:
+// 8 for less than 20 pixels, 4 for less than 40 pixels, 2 for less than 80 pixels, and 1 for others.
+// Smaller glyphs need more precise positions to look good.
+//
+// SetSubpixelGranularity is concurrent-safe.
+func (s *StdFace) SetSubpixelGranularity(granularity int) {
+	s.copyCheck()
+
+	switch granularity {
+	case 0, 1, 2, 4, 8, 16, 32, 64:
+	default:
+		panic(fmt.Sprintf("text: invalid subpixel granularity: %d", granularity))
+	}
+	atomic.StoreInt32(&s.subpixelGranularity, int32(granularity))
+}
+
+// SubpixelGranularity returns the number of subpixel positions per pixel in the horizontal direction.
+// If the granularity is not set by SetSubpixelGranularity, SubpixelGranularity returns the default granularity.
+//
+// SubpixelGranularity is concurrent-safe.
+func (s *StdFace) SubpixelGranularity() int {
+	s.copyCheck()
+
+	return glyphVariationCount(s)
+}
+
 // UnsafeInternal returns its internal font.Face.
 //
 // This is unsafe since this might make internal cache states out of sync.
@@ -87,6 +146,23 @@ func (s *StdFace) advance(text string) float64 {
 	return fixed26_6ToFloat64(font.MeasureString(s.f, text))
 }
 
+// appendAdvances implements Face.
+func (s *StdFace) appendAdvances(advances []float64, text string) []float64 {
+	// This should be consistent with font.MeasureString.
+	var a fixed.Int26_6
+	prevR := rune(-1)
+	for _, r := range text {
+		if prevR >= 0 {
+			a += s.f.Kern(prevR, r)
+		}
+		ra, _ := s.f.GlyphAdvance(r)
+		a += ra
+		advances = append(advances, fixed26_6ToFloat64(a))
+		prevR = r
+	}
+	return advances
+}
+
 // hasGlyph implements Face.
 func (s *StdFace) hasGlyph(r rune) bool {
 	_, ok := s.f.GlyphAdvance(r)
@@ -162,27 +238,70 @@ func (s *StdFace) glyphImageImpl(r rune, subpixelOffset fixed.Point26_6, glyphBo
 
 	rgba := image.NewRGBA(image.Rect(0, 0, w, h))
 
-	d := font.Drawer{
-		Dst:  rgba,
-		Src:  image.White,
-		Face: s.f,
-		Dot: fixed.Point26_6{
-			X: -glyphBounds.Min.X + subpixelOffset.X,
-			Y: -glyphBounds.Min.Y + subpixelOffset.Y,
-		},
+	dot := fixed.Point26_6{
+		X: -glyphBounds.Min.X + subpixelOffset.X,
+		Y: -glyphBounds.Min.Y + subpixelOffset.Y,
+	}
+	if dr, mask, maskp, _, ok := s.f.Glyph(dot, r); ok {
+		if isColoredGlyphMask(mask) {
+			// Keep the glyph's own colors. ColorScale is applied multiplicatively at rendering.
+			draw.Draw(rgba, dr, mask, maskp, draw.Over)
+		} else {
+			draw.DrawMask(rgba, dr, image.White, image.Point{}, mask, maskp, draw.Over)
+		}
 	}
-	d.DrawString(string(r))
 
+	// rgba holds premultiplied-alpha colors as draw.Draw and draw.DrawMask work in premultiplied alpha,
+	// so the default alpha mode (ebiten.AlphaModeAuto) uploads the pixels as they are.
+	// The anti-aliased edges are blended in premultiplied alpha without halos,
+	// though they might look thin or thick depending on the background as blending is done in the sRGB space.
 	return ebiten.NewImageFromImage(rgba)
 }
 
+// isColoredGlyphMask reports whether the given glyph mask image has its own colors, like a color emoji.
+func isColoredGlyphMask(mask image.Image) bool {
+	switch mask.ColorModel() {
+	case color.AlphaModel, color.Alpha16Model, color.GrayModel, color.Gray16Model:
+		return false
+	}
+	return true
+}
+
 // direction implelements Face.
 func (s *StdFace) direction() Direction {
 	return DirectionLeftToRight
 }
 
 // appendVectorPathForLine implements Face.
-func (s *StdFace) appendVectorPathForLine(path *vector.Path, line string, originX, originY float64) {
+func (s *StdFace) appendVectorPathForLine(path vectorPath, line string, originX, originY float64) {
+}
+
+// scripts implements Face.
+func (s *StdFace) scripts() map[glanguage.Script]struct{} {
+	s.copyCheck()
+
+	s.supportedScriptsOnce.Do(func() {
+		// font.Face doesn't have a way to iterate its glyphs. Probe all the runes belonging to specific scripts.
+		c := scriptCoverage{}
+		for _, sr := range glanguage.ScriptRanges {
+			if isNeutralScript(sr.Script) {
+				continue
+			}
+			for r := sr.Start; r <= sr.End; r++ {
+				if s.f.hasGlyphWithoutCache(r) {
+					c.add(r)
+				}
+			}
+		}
+		s.supportedScripts = c.supportedScripts()
+	})
+	return s.supportedScripts
+}
+
+// hasScript implements Face.
+func (s *StdFace) hasScript(script glanguage.Script) bool {
+	_, ok := s.scripts()[script]
+	return ok
 }
 
 // Metrics implelements Face.
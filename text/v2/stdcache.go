@@ -86,6 +86,16 @@ func (f *faceWithCache) GlyphBounds(r rune) (bounds fixed.Rectangle26_6, advance
 	return
 }
 
+// hasGlyphWithoutCache reports whether the face has a glyph for r without caching the result.
+// This is useful to probe a lot of runes.
+func (f *faceWithCache) hasGlyphWithoutCache(r rune) bool {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	_, ok := f.f.GlyphAdvance(r)
+	return ok
+}
+
 func (f *faceWithCache) GlyphAdvance(r rune) (advance fixed.Int26_6, ok bool) {
 	f.m.Lock()
 	defer f.m.Unlock()
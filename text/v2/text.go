@@ -18,13 +18,15 @@
 package text
 
 import (
+	"image"
 	"math"
 	"strings"
+	"sync/atomic"
 
+	glanguage "github.com/go-text/typesetting/language"
 	"golang.org/x/image/math/fixed"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
 // Face is an interface representing a font face. The implementations are only faces in this package, like GoTextFace and StdFace.
@@ -32,19 +34,40 @@ type Face interface {
 	// Metrics returns the metrics for this Face.
 	Metrics() Metrics
 
+	// GlyphBounds returns the bounds of the glyph for the given rune and the glyph's advance without rendering it.
+	//
+	// The bounds are relative to the origin (dot) position, in pixels.
+	// The Y axis is downward, so the Y values above the baseline are negative for a horizontal face.
+	//
+	// If the face doesn't have a glyph for r, GlyphBounds returns zero values.
+	GlyphBounds(r rune) (bounds image.Rectangle, advance float64)
+
 	advance(text string) float64
+	appendAdvances(advances []float64, text string) []float64
 
 	hasGlyph(r rune) bool
 
 	appendGlyphsForLine(glyphs []Glyph, line string, indexOffset int, originX, originY float64) []Glyph
-	appendVectorPathForLine(path *vector.Path, line string, originX, originY float64)
+	appendVectorPathForLine(path vectorPath, line string, originX, originY float64)
 
 	direction() Direction
 
+	scripts() map[glanguage.Script]struct{}
+	hasScript(script glanguage.Script) bool
+
 	// private is an unexported function preventing being implemented by other packages.
 	private()
 }
 
+// vectorPath is a destination to append a vector path. *vector.Path implements vectorPath.
+type vectorPath interface {
+	MoveTo(x, y float32)
+	LineTo(x, y float32)
+	QuadTo(x1, y1, x2, y2 float32)
+	CubicTo(x1, y1, x2, y2, x3, y3 float32)
+	Close()
+}
+
 // Metrics holds the metrics for a Face.
 // A visual depiction is at https://developer.apple.com/library/mac/documentation/TextFonts/Conceptual/CocoaTextArchitecture/Art/glyph_metrics_2x.png
 type Metrics struct {
@@ -91,7 +114,17 @@ func float64ToFixed26_6(x float64) fixed.Int26_6 {
 	return fixed.Int26_6(i)<<6 + fixed.Int26_6(frac*(1<<6))
 }
 
+func fixed26_6RectangleToImageRectangle(r fixed.Rectangle26_6) image.Rectangle {
+	return image.Rect(r.Min.X.Floor(), r.Min.Y.Floor(), r.Max.X.Ceil(), r.Max.Y.Ceil())
+}
+
 func glyphVariationCount(face Face) int {
+	if s, ok := face.(*StdFace); ok {
+		if g := atomic.LoadInt32(&s.subpixelGranularity); g > 0 {
+			return int(g)
+		}
+	}
+
 	var s float64
 	if m := face.Metrics(); face.direction().isHorizontal() {
 		s = m.HAscent + m.HDescent
@@ -129,7 +162,9 @@ type Glyph struct {
 	GID uint32
 
 	// Image is a rasterized glyph image.
-	// Image is a grayscale image i.e. RGBA values are the same.
+	// Image is a grayscale image i.e. RGBA values are the same,
+	// unless the face is a StdFace with colored glyphs or a GoTextFace with color glyphs.
+	// If the face is an SDFFace, Image is a signed distance field. See SDFFace for more details.
 	// Image should be used as a render source and should not be modified.
 	Image *ebiten.Image
 
@@ -142,10 +177,22 @@ type Glyph struct {
 	// The position is determined in a sequence of characters given at AppendGlyphs.
 	// The position's origin is the first character's origin position.
 	Y float64
+
+	// geoM is a geometry transformation applied to Image before translating it by (X, Y).
+	geoM ebiten.GeoM
+
+	// colorScale is a color scale applied to Image in addition to the color scale for the text.
+	colorScale ebiten.ColorScale
+
+	// sdf reports whether Image is a signed distance field to render with the SDF shader.
+	sdf bool
 }
 
 // Advance returns the advanced distance from the origin position when rendering the given text with the given face.
 //
+// Advance includes the advances of all the glyphs including leading and trailing whitespaces.
+// Whitespaces are never trimmed, so Advance of a text with a trailing space is where a caret after the space should be.
+//
 // Advance doesn't treat multiple lines.
 //
 // Advance is concurrent-safe.
@@ -153,6 +200,22 @@ func Advance(text string, face Face) float64 {
 	return face.advance(text)
 }
 
+// Advances returns the cumulative advances after each rune of the given text with the given face.
+//
+// The i-th value is the advanced distance from the origin position after the cluster that the i-th rune belongs to.
+// This is where Draw would put the next glyph, except for the kerning with the next glyph.
+// If multiple runes form one cluster, e.g., a ligature, all of them have the same value.
+// The last value is the same as Advance(text, face).
+//
+// Advances calculates all the values at once, which is more efficient than calling Advance for each prefix of the text.
+//
+// Advances doesn't treat multiple lines.
+//
+// Advances is concurrent-safe.
+func Advances(text string, face Face) []float64 {
+	return face.appendAdvances(nil, text)
+}
+
 // Direction represents a direction of text rendering.
 // Direction indicates both the primary direction, in which a text in one line is rendered,
 // and the secondary direction, in which multiple lines are rendered.
@@ -190,6 +253,13 @@ func (d Direction) isHorizontal() bool {
 // With a horizontal direction face, the width is the longest line's advance, and the height is the total of line heights.
 // With a vertical direction face, the width and the height are calculated in an opposite manner.
 //
+// The advance of each line is the same as Advance, so leading and trailing whitespaces are included.
+// Measure never trims whitespaces.
+// A text field can use Measure or Advance of the text before the caret to position the caret, even after a trailing space.
+// On the other hand, this means that a line ending with whitespaces is wider than its visible glyphs.
+// Note that only '\n' is treated as a line break. Other control characters like '\r' and '\t' are measured
+// as ordinary glyphs with the face's advances, which are often the advances of the .notdef glyph.
+//
 // Measure is concurrent-safe.
 func Measure(text string, face Face, lineSpacingInPixels float64) (width, height float64) {
 	if text == "" {
@@ -239,6 +309,14 @@ func Measure(text string, face Face, lineSpacingInPixels float64) (width, height
 // (`(*ebiten.Image).WritePixels` and `(*ebiten.Image).DrawImage`) and can never be merged as one draw call.
 // CacheGlyphs creates necessary glyphs without rendering them so that these operations are likely merged into one draw call regardless of the size of the text.
 //
+// CacheGlyphs is also useful to pre-warm glyphs before they appear.
+// Creating a glyph image for the first time requires rasterization, which might cause stutters
+// when many new glyphs appear at once, e.g., when a dialog with many CJK characters is opened.
+// Calling CacheGlyphs with the text in advance, e.g., during a loading screen, makes such a reveal smooth.
+// CacheGlyphs works with any faces including StdFace, GoTextFace, and MultiFace.
+// Note that glyphs that are not used for a while might be evicted when the cache is full,
+// so pre-warming should be done shortly before the glyphs are used. ReadCacheStats is useful to confirm the effect.
+//
 // CacheGlyphs is concurrent-safe.
 func CacheGlyphs(text string, face Face) {
 	var x, y float64
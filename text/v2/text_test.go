@@ -15,19 +15,31 @@
 package text_test
 
 import (
+	"bytes"
+	"encoding/binary"
 	"image"
 	"image/color"
+	"image/png"
+	"math"
+	"reflect"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"testing"
+	"unicode/utf8"
 
+	"github.com/go-text/typesetting/segmenter"
 	"github.com/hajimehoshi/bitmapfont/v3"
 	"golang.org/x/image/font"
 	"golang.org/x/image/math/fixed"
+	"golang.org/x/text/language"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/examples/resources/fonts"
 	t "github.com/hajimehoshi/ebiten/v2/internal/testing"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
 func TestMain(m *testing.M) {
@@ -240,3 +252,1840 @@ func TestUnhashableFace(t *testing.T) {
 		}
 	}
 }
+
+func TestGlyphBounds(t *testing.T) {
+	f := text.NewStdFace(&testStdFace{})
+	b, a := f.GlyphBounds('a')
+	if want := image.Rect(0, 0, testStdFaceSize, testStdFaceSize); b != want {
+		t.Errorf("bounds: got: %v, want: %v", b, want)
+	}
+	if want := float64(testStdFaceSize); a != want {
+		t.Errorf("advance: got: %v, want: %v", a, want)
+	}
+
+	// MultiFace should delegate to the first face that has the glyph.
+	bf := text.NewStdFace(bitmapfont.Face)
+	mf := text.MultiFace{bf, f}
+	gotB, gotA := mf.GlyphBounds('a')
+	wantB, wantA := bf.GlyphBounds('a')
+	if gotB != wantB {
+		t.Errorf("MultiFace bounds: got: %v, want: %v", gotB, wantB)
+	}
+	if gotA != wantA {
+		t.Errorf("MultiFace advance: got: %v, want: %v", gotA, wantA)
+	}
+}
+
+func TestGoTextFaceGlyphBounds(t *testing.T) {
+	src, err := text.NewGoTextFaceSource(bytes.NewReader(fonts.MPlus1pRegular_ttf))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, dir := range []text.Direction{
+		text.DirectionLeftToRight,
+		text.DirectionTopToBottomAndRightToLeft,
+	} {
+		f := &text.GoTextFace{
+			Source:    src,
+			Direction: dir,
+			Size:      32,
+		}
+		for _, r := range []rune{'a', 'g', 'あ'} {
+			b, a := f.GlyphBounds(r)
+			if b.Empty() {
+				t.Errorf("direction: %d, rune: %q: bounds must not be empty: %v", dir, r, b)
+			}
+			// The advance is the same as the advance of the one-rune text, and positive even in the vertical direction.
+			if want := text.Advance(string(r), f); a != want {
+				t.Errorf("direction: %d, rune: %q: advance: got: %v, want: %v", dir, r, a, want)
+			}
+			if a <= 0 {
+				t.Errorf("direction: %d, rune: %q: advance must be positive: %v", dir, r, a)
+			}
+			if dir == text.DirectionLeftToRight {
+				// A horizontal glyph is above the baseline and starts around the origin.
+				if b.Min.Y >= 0 {
+					t.Errorf("direction: %d, rune: %q: bounds must be above the baseline: %v", dir, r, b)
+				}
+				if b.Min.X < -1 || float64(b.Max.X) > a+1 {
+					t.Errorf("direction: %d, rune: %q: bounds must be in the advance %v: %v", dir, r, a, b)
+				}
+			}
+
+			// The bounds match the rendered glyph image, which might have an extra pixel for sub-pixel positions.
+			gs := text.AppendGlyphs(nil, string(r), f, nil)
+			if len(gs) != 1 {
+				t.Fatalf("direction: %d, rune: %q: len(glyphs): got: %d, want: 1", dir, r, len(gs))
+			}
+			ib := gs[0].Image.Bounds()
+			if dx := ib.Dx() - b.Dx(); dx < 0 || dx > 1 {
+				t.Errorf("direction: %d, rune: %q: width: got: %d, want: %d", dir, r, b.Dx(), ib.Dx())
+			}
+			if dy := ib.Dy() - b.Dy(); dy < 0 || dy > 1 {
+				t.Errorf("direction: %d, rune: %q: height: got: %d, want: %d", dir, r, b.Dy(), ib.Dy())
+			}
+		}
+
+		// A glyph that doesn't exist has no bounds.
+		if b, a := f.GlyphBounds('\U0010FFFF'); !b.Empty() || a != 0 {
+			t.Errorf("direction: %d: bounds: %v, advance: %v, want empty bounds and 0 advance", dir, b, a)
+		}
+	}
+
+	// 'g' has a descender.
+	f := &text.GoTextFace{
+		Source: src,
+		Size:   32,
+	}
+	if b, _ := f.GlyphBounds('g'); b.Max.Y <= 0 {
+		t.Errorf("bounds of 'g' must be below the baseline: %v", b)
+	}
+}
+
+func TestDrawOnPath(t *testing.T) {
+	f := text.NewStdFace(&testStdFace{})
+
+	var path vector.Path
+	path.MoveTo(0, 0)
+	path.LineTo(testStdFaceSize*4, 0)
+
+	dst := ebiten.NewImage(testStdFaceSize*4, testStdFaceSize*2)
+	op := &text.DrawOnPathOptions{}
+	op.Offset = testStdFaceSize
+	text.DrawOnPath(dst, "b", f, &path, op)
+
+	for j := 0; j < testStdFaceSize*2; j++ {
+		for i := 0; i < testStdFaceSize*4; i++ {
+			got := dst.At(i, j)
+			var want color.RGBA
+			if testStdFaceSize <= i && i < testStdFaceSize*2 && j < testStdFaceSize {
+				want = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+			}
+			if got != want {
+				t.Errorf("At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+
+	// Glyphs out of the path are dropped by default.
+	dst.Clear()
+	op.Offset = testStdFaceSize * 4
+	text.DrawOnPath(dst, "b", f, &path, op)
+	for j := 0; j < testStdFaceSize*2; j++ {
+		for i := 0; i < testStdFaceSize*4; i++ {
+			if got, want := dst.At(i, j), (color.RGBA{}); got != want {
+				t.Errorf("At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestNewMultiFace(t *testing.T) {
+	src, err := text.NewGoTextFaceSource(bytes.NewReader(fonts.MPlus1pRegular_ttf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := &text.GoTextFace{
+		Source: src,
+		Size:   16,
+	}
+	v := &text.GoTextFace{
+		Source:    src,
+		Direction: text.DirectionTopToBottomAndRightToLeft,
+		Size:      16,
+	}
+	s := text.NewStdFace(bitmapfont.Face)
+
+	if _, err := text.NewMultiFace(h, s); err != nil {
+		t.Errorf("NewMultiFace(h, s) must succeed but failed: %v", err)
+	}
+	if _, err := text.NewMultiFace(v, v); err != nil {
+		t.Errorf("NewMultiFace(v, v) must succeed but failed: %v", err)
+	}
+	if _, err := text.NewMultiFace(h, v); err == nil {
+		t.Errorf("NewMultiFace(h, v) must fail but not")
+	}
+	if _, err := text.NewMultiFace(s, v); err == nil {
+		t.Errorf("NewMultiFace(s, v) must fail but not")
+	}
+}
+
+func TestReadCacheStats(t *testing.T) {
+	f := text.NewStdFace(&testStdFace{})
+
+	var s0 text.CacheStats
+	text.ReadCacheStats(&s0)
+
+	// The first rendering creates a glyph image, and the second rendering reuses it.
+	text.AppendGlyphs(nil, "a", f, nil)
+	var s1 text.CacheStats
+	text.ReadCacheStats(&s1)
+	text.AppendGlyphs(nil, "a", f, nil)
+	var s2 text.CacheStats
+	text.ReadCacheStats(&s2)
+
+	if got, want := s1.Misses-s0.Misses, int64(1); got != want {
+		t.Errorf("misses: got: %d, want: %d", got, want)
+	}
+	if got, want := s1.GlyphImageCount-s0.GlyphImageCount, 1; got != want {
+		t.Errorf("glyph image count: got: %d, want: %d", got, want)
+	}
+	if got := s1.GlyphImageBytes - s0.GlyphImageBytes; got < testStdFaceSize*testStdFaceSize*4 {
+		t.Errorf("glyph image bytes: got: %d, want: >= %d", got, testStdFaceSize*testStdFaceSize*4)
+	}
+	if got, want := s2.Hits-s1.Hits, int64(1); got != want {
+		t.Errorf("hits: got: %d, want: %d", got, want)
+	}
+	if got, want := s2.Misses-s1.Misses, int64(0); got != want {
+		t.Errorf("misses: got: %d, want: %d", got, want)
+	}
+}
+
+func TestCacheGlyphsPrewarm(t *testing.T) {
+	src, err := text.NewGoTextFaceSource(bytes.NewReader(fonts.MPlus1pRegular_ttf))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const str = "あいうえお\n漢字とかな"
+	for _, f := range []text.Face{
+		text.NewStdFace(bitmapfont.Face),
+		&text.GoTextFace{
+			Source: src,
+			Size:   16,
+		},
+	} {
+		text.CacheGlyphs(str, f)
+
+		var s0 text.CacheStats
+		text.ReadCacheStats(&s0)
+
+		// Glyphs at any subpixel positions should already be cached.
+		op := &text.LayoutOptions{}
+		text.AppendGlyphs(nil, str, f, op)
+		op.PrimaryAlign = text.AlignCenter
+		text.AppendGlyphs(nil, str, f, op)
+		op.PrimaryAlign = text.AlignEnd
+		text.AppendGlyphs(nil, str, f, op)
+
+		var s1 text.CacheStats
+		text.ReadCacheStats(&s1)
+		if got, want := s1.Misses-s0.Misses, int64(0); got != want {
+			t.Errorf("misses (%T): got: %d, want: %d", f, got, want)
+		}
+		if s1.Hits == s0.Hits {
+			t.Errorf("hits (%T): got: %d, want: > %d", f, s1.Hits, s0.Hits)
+		}
+	}
+}
+
+func TestNewMultiFaceWithLastResort(t *testing.T) {
+	src, err := text.NewGoTextFaceSource(bytes.NewReader(fonts.MPlus1pRegular_ttf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := &text.GoTextFace{
+		Source: src,
+		Size:   16,
+	}
+	v := &text.GoTextFace{
+		Source:    src,
+		Direction: text.DirectionTopToBottomAndRightToLeft,
+		Size:      16,
+	}
+
+	// U+E000 is in the private use area, and the font doesn't have a glyph for it.
+	const str = "a\ue000b"
+
+	m, err := text.NewMultiFace(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(text.AppendGlyphs(nil, str, m, nil)), 2; got != want {
+		t.Errorf("len(AppendGlyphs(nil, %q, m, nil)): got: %d, want: %d", str, got, want)
+	}
+
+	lm, err := text.NewMultiFaceWithLastResort([]text.Face{h}, h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	glyphs := text.AppendGlyphs(nil, str, lm, nil)
+	if got, want := len(glyphs), 3; got != want {
+		t.Fatalf("len(AppendGlyphs(nil, %q, lm, nil)): got: %d, want: %d", str, got, want)
+	}
+	if got, want := glyphs[1].StartIndexInBytes, 1; got != want {
+		t.Errorf("glyphs[1].StartIndexInBytes: got: %d, want: %d", got, want)
+	}
+	if glyphs[1].Image == nil {
+		t.Errorf("glyphs[1].Image must not be nil")
+	}
+	if got, notWant := text.Advance(str, lm), text.Advance(str, m); got <= notWant {
+		t.Errorf("Advance(%q, lm): got: %f, want: > %f", str, got, notWant)
+	}
+
+	if _, err := text.NewMultiFaceWithLastResort([]text.Face{h}, v); err == nil {
+		t.Errorf("NewMultiFaceWithLastResort([]text.Face{h}, v) must fail but not")
+	}
+}
+
+type coloredStdFace struct {
+	testStdFace
+}
+
+func (c *coloredStdFace) Glyph(dot fixed.Point26_6, r rune) (dr image.Rectangle, mask image.Image, maskp image.Point, advance fixed.Int26_6, ok bool) {
+	dr = image.Rect(0, 0, testStdFaceSize, testStdFaceSize)
+	img := image.NewRGBA(dr)
+	for j := dr.Min.Y; j < dr.Max.Y; j++ {
+		for i := dr.Min.X; i < dr.Max.X; i++ {
+			img.SetRGBA(i, j, color.RGBA{R: 0xff, G: 0x80, A: 0xff})
+		}
+	}
+	mask = img
+	advance = fixed.I(testStdFaceSize)
+	ok = true
+	return
+}
+
+func TestColoredStdFace(t *testing.T) {
+	f := text.NewStdFace(&coloredStdFace{})
+	dst := ebiten.NewImage(testStdFaceSize*2, testStdFaceSize*2)
+	op := &text.DrawOptions{}
+	op.ColorScale.Scale(1, 0.5, 1, 1)
+	text.Draw(dst, "a", f, op)
+
+	for j := 0; j < testStdFaceSize*2; j++ {
+		for i := 0; i < testStdFaceSize*2; i++ {
+			got := dst.At(i, j)
+			var want color.RGBA
+			if i < testStdFaceSize && j < testStdFaceSize {
+				want = color.RGBA{R: 0xff, G: 0x40, A: 0xff}
+			}
+			if got != want {
+				t.Errorf("At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func BenchmarkDrawGoTextFacesWithVariousSizes(b *testing.B) {
+	src, err := text.NewGoTextFaceSource(bytes.NewReader(fonts.MPlus1pRegular_ttf))
+	if err != nil {
+		b.Fatal(err)
+	}
+	var faces []*text.GoTextFace
+	for i := 0; i < 20; i++ {
+		faces = append(faces, &text.GoTextFace{
+			Source: src,
+			Size:   float64(8 + i*2),
+		})
+	}
+	dst := ebiten.NewImage(640, 480)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Use a different text for each iteration to avoid hitting the shaping output cache.
+		str := "The quick brown fox jumps over the lazy dog. " + strconv.Itoa(i)
+		for _, f := range faces {
+			text.Draw(dst, str, f, nil)
+		}
+	}
+}
+
+func TestDrawWithGlyphHook(t *testing.T) {
+	f := text.NewStdFace(&coloredStdFace{})
+	dst := ebiten.NewImage(testStdFaceSize*3, testStdFaceSize*3)
+
+	var indices []int
+	op := &text.DrawOptions{}
+	op.ColorScale.Scale(1, 0.5, 1, 1)
+	op.GlyphHook = func(glyph text.Glyph, index int) (ebiten.GeoM, ebiten.ColorScale) {
+		indices = append(indices, index)
+		var geoM ebiten.GeoM
+		var colorScale ebiten.ColorScale
+		if index == 1 {
+			geoM.Translate(0, testStdFaceSize)
+			colorScale.Scale(0.5, 0, 1, 1)
+		}
+		return geoM, colorScale
+	}
+	text.Draw(dst, "aa", f, op)
+
+	if got, want := indices, []int{0, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("indices: got: %v, want: %v", got, want)
+	}
+
+	var cs ebiten.ColorScale
+	cs.Scale(1, 0.5, 1, 1)
+	if got, want := op.ColorScale, cs; got != want {
+		t.Errorf("op.ColorScale: got: %v, want: %v", got, want)
+	}
+
+	for j := 0; j < testStdFaceSize*3; j++ {
+		for i := 0; i < testStdFaceSize*3; i++ {
+			got := dst.At(i, j)
+			var want color.RGBA
+			switch {
+			case i < testStdFaceSize && j < testStdFaceSize:
+				want = color.RGBA{R: 0xff, G: 0x40, A: 0xff}
+			case testStdFaceSize <= i && i < testStdFaceSize*2 && testStdFaceSize <= j && j < testStdFaceSize*2:
+				want = color.RGBA{R: 0x80, A: 0xff}
+			}
+			if got != want {
+				t.Errorf("At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestDrawWithShadow(t *testing.T) {
+	f := text.NewStdFace(&testStdFace{})
+	dst := ebiten.NewImage(testStdFaceSize*3, testStdFaceSize*3)
+
+	op := &text.DrawOptions{}
+	op.ShadowColor = color.RGBA{B: 0xff, A: 0xff}
+	op.ShadowOffsetX = testStdFaceSize / 2
+	op.ShadowOffsetY = testStdFaceSize
+	text.Draw(dst, "b", f, op)
+
+	for j := 0; j < testStdFaceSize*3; j++ {
+		for i := 0; i < testStdFaceSize*3; i++ {
+			got := dst.At(i, j)
+			var want color.RGBA
+			switch {
+			// The text is rendered over the shadow.
+			case i < testStdFaceSize && j < testStdFaceSize:
+				want = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+			case testStdFaceSize/2 <= i && i < testStdFaceSize*3/2 && testStdFaceSize <= j && j < testStdFaceSize*2:
+				want = color.RGBA{B: 0xff, A: 0xff}
+			}
+			if got != want {
+				t.Errorf("At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+
+	// With a blur, the shadow spreads out of the glyph's region.
+	dst.Clear()
+	op.ShadowOffsetX = 0
+	op.ShadowOffsetY = testStdFaceSize * 3 / 2
+	op.ShadowBlur = 2
+	text.Draw(dst, "b", f, op)
+	if got := dst.At(testStdFaceSize+1, testStdFaceSize*2).(color.RGBA); got.A == 0 || got.A == 0xff || got.B != got.A {
+		t.Errorf("At(%d, %d): got: %v, want: a partially transparent blue", testStdFaceSize+1, testStdFaceSize*2, got)
+	}
+	if got, want := dst.At(testStdFaceSize/2, testStdFaceSize*2).(color.RGBA), (color.RGBA{B: 0xff, A: 0xff}); got != want {
+		t.Errorf("At(%d, %d): got: %v, want: %v", testStdFaceSize/2, testStdFaceSize*2, got, want)
+	}
+}
+
+func TestDrawWithOutline(t *testing.T) {
+	f := text.NewStdFace(&testStdFace{})
+	dst := ebiten.NewImage(testStdFaceSize*3, testStdFaceSize*3)
+
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(testStdFaceSize, testStdFaceSize)
+	op.OutlineColor = color.RGBA{R: 0xff, A: 0xff}
+	op.OutlineWidth = 2
+	text.Draw(dst, "b", f, op)
+
+	for _, tc := range []struct {
+		X    int
+		Y    int
+		Want color.RGBA
+	}{
+		// The text is rendered over the outline.
+		{testStdFaceSize, testStdFaceSize, color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}},
+		{testStdFaceSize*2 - 1, testStdFaceSize*2 - 1, color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}},
+
+		// The outline surrounds the text.
+		{testStdFaceSize - 1, testStdFaceSize + 3, color.RGBA{R: 0xff, A: 0xff}},
+		{testStdFaceSize - 2, testStdFaceSize + 3, color.RGBA{R: 0xff, A: 0xff}},
+		{testStdFaceSize * 2, testStdFaceSize + 3, color.RGBA{R: 0xff, A: 0xff}},
+		{testStdFaceSize + 3, testStdFaceSize - 2, color.RGBA{R: 0xff, A: 0xff}},
+		{testStdFaceSize + 3, testStdFaceSize*2 + 1, color.RGBA{R: 0xff, A: 0xff}},
+
+		// The outline doesn't reach farther than its width.
+		{testStdFaceSize - 3, testStdFaceSize + 3, color.RGBA{}},
+		{testStdFaceSize + 3, testStdFaceSize*2 + 2, color.RGBA{}},
+	} {
+		if got := dst.At(tc.X, tc.Y); got != tc.Want {
+			t.Errorf("At(%d, %d): got: %v, want: %v", tc.X, tc.Y, got, tc.Want)
+		}
+	}
+}
+
+func TestDrawWithClipRect(t *testing.T) {
+	f := text.NewStdFace(&coloredStdFace{})
+	dst := ebiten.NewImage(testStdFaceSize*4, testStdFaceSize*2)
+
+	op := &text.DrawOptions{}
+	op.ClipRect = image.Rect(testStdFaceSize/2, 0, testStdFaceSize*3/2, testStdFaceSize)
+	text.Draw(dst, "aaa", f, op)
+
+	for j := 0; j < testStdFaceSize*2; j++ {
+		for i := 0; i < testStdFaceSize*4; i++ {
+			got := dst.At(i, j)
+			var want color.RGBA
+			if image.Pt(i, j).In(op.ClipRect) {
+				want = color.RGBA{R: 0xff, G: 0x80, A: 0xff}
+			}
+			if got != want {
+				t.Errorf("At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestDrawScaleByDeviceScaleFactor(t *testing.T) {
+	scale := 1
+	if m := ebiten.Monitor(); m != nil && m.DeviceScaleFactor() > 0 {
+		if s := m.DeviceScaleFactor(); s != float64(int(s)) {
+			t.Skipf("the device scale factor is not an integer: %v", s)
+		}
+		scale = int(m.DeviceScaleFactor())
+	}
+
+	f := text.NewStdFace(&coloredStdFace{})
+	size := testStdFaceSize * scale
+	dst := ebiten.NewImage(size*3, size*3)
+
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(testStdFaceSize, 0)
+	op.ScaleByDeviceScaleFactor = true
+	text.Draw(dst, "a", f, op)
+
+	var geoM ebiten.GeoM
+	geoM.Translate(testStdFaceSize, 0)
+	if got, want := op.GeoM, geoM; got != want {
+		t.Errorf("op.GeoM: got: %v, want: %v", got, want)
+	}
+
+	// The position by GeoM is not scaled, but the glyph is scaled.
+	for j := 0; j < size*3; j++ {
+		for i := 0; i < size*3; i++ {
+			got := dst.At(i, j)
+			var want color.RGBA
+			if testStdFaceSize <= i && i < testStdFaceSize+size && j < size {
+				want = color.RGBA{R: 0xff, G: 0x80, A: 0xff}
+			}
+			if got != want {
+				t.Errorf("At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestGoTextFaceWithSize(t *testing.T) {
+	src, err := text.NewGoTextFaceSource(bytes.NewReader(fonts.MPlus1pRegular_ttf))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f0 := &text.GoTextFace{
+		Source:    src,
+		Direction: text.DirectionRightToLeft,
+		Size:      16,
+	}
+	f0.SetFeature(text.MustParseTag("liga"), 0)
+
+	f1 := f0.WithSize(32)
+	if f1.Source != f0.Source {
+		t.Errorf("WithSize must share the source")
+	}
+	if got, want := f1.Size, 32.0; got != want {
+		t.Errorf("f1.Size: got: %v, want: %v", got, want)
+	}
+	if got, want := f1.Direction, f0.Direction; got != want {
+		t.Errorf("f1.Direction: got: %v, want: %v", got, want)
+	}
+	if got, want := f1.Metrics().HAscent, f0.Metrics().HAscent*2; got != want {
+		t.Errorf("f1.Metrics().HAscent: got: %v, want: %v", got, want)
+	}
+	if got, want := text.Advance("Hello", f1), text.Advance("Hello", f0)*2; math.Abs(got-want) > 1 {
+		t.Errorf("Advance: got: %v, want: %v", got, want)
+	}
+
+	// Changing the features of the derived face must not affect the original face.
+	f1.SetFeature(text.MustParseTag("liga"), 1)
+	f2 := f0.WithSize(32)
+	if got, want := text.Advance("ffi", f2), text.Advance("ffi", f0)*2; math.Abs(got-want) > 1 {
+		t.Errorf("Advance with the original features: got: %v, want: %v", got, want)
+	}
+}
+
+func TestFixed26_6Conversions(t *testing.T) {
+	testCases := []struct {
+		Float float64
+		Fixed fixed.Int26_6
+	}{
+		{0, 0},
+		{1, 64},
+		{1.5, 96},
+		{-1.5, -96},
+		{1.0 / 64, 1},
+		{-1.0 / 64, -1},
+		// The fractional part is rounded down.
+		{1.0 / 128, 0},
+		{-1.0 / 128, -1},
+		{2.99, 191},
+	}
+	for _, tc := range testCases {
+		if got, want := text.Float64ToFixed26_6(tc.Float), tc.Fixed; got != want {
+			t.Errorf("Float64ToFixed26_6(%v): got: %v, want: %v", tc.Float, got, want)
+		}
+		if got, want := text.Float32ToFixed26_6(float32(tc.Float)), tc.Fixed; got != want {
+			t.Errorf("Float32ToFixed26_6(%v): got: %v, want: %v", tc.Float, got, want)
+		}
+	}
+
+	for _, x := range []fixed.Int26_6{0, 1, -1, 64, -64, 100, -100, 1 << 20, -(1 << 20)} {
+		if got, want := text.Fixed26_6ToFloat64(x), float64(x)/64; got != want {
+			t.Errorf("Fixed26_6ToFloat64(%v): got: %v, want: %v", x, got, want)
+		}
+		if got, want := text.Fixed26_6ToFloat32(x), float32(x)/64; got != want {
+			t.Errorf("Fixed26_6ToFloat32(%v): got: %v, want: %v", x, got, want)
+		}
+		if got, want := text.Float64ToFixed26_6(text.Fixed26_6ToFloat64(x)), x; got != want {
+			t.Errorf("round trip of %v: got: %v, want: %v", x, got, want)
+		}
+	}
+}
+
+func TestGoTextFaceBaselineShift(t *testing.T) {
+	src, err := text.NewGoTextFaceSource(bytes.NewReader(fonts.MPlus1pRegular_ttf))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const shift = 5
+	for _, dir := range []text.Direction{
+		text.DirectionLeftToRight,
+		text.DirectionTopToBottomAndRightToLeft,
+	} {
+		f := &text.GoTextFace{
+			Source:    src,
+			Direction: dir,
+			Size:      16,
+		}
+		gs0 := text.AppendGlyphs(nil, "Hello, 世界", f, nil)
+		f.BaselineShift = shift
+		gs1 := text.AppendGlyphs(nil, "Hello, 世界", f, nil)
+		if len(gs0) != len(gs1) {
+			t.Fatalf("len(glyphs): got: %d, want: %d", len(gs1), len(gs0))
+		}
+		for i := range gs0 {
+			dx, dy := gs1[i].X-gs0[i].X, gs1[i].Y-gs0[i].Y
+			wantDX, wantDY := 0.0, float64(shift)
+			if dir != text.DirectionLeftToRight {
+				wantDX, wantDY = shift, 0
+			}
+			if dx != wantDX || dy != wantDY {
+				t.Errorf("direction: %d, glyph %d: offset: got: (%v, %v), want: (%v, %v)", dir, i, dx, dy, wantDX, wantDY)
+			}
+		}
+	}
+}
+
+// runeSetStdFace is a font.Face that has glyphs only for the given runes.
+type runeSetStdFace struct {
+	testStdFace
+	runes   string
+	advance int
+}
+
+func (f *runeSetStdFace) GlyphAdvance(r rune) (advance fixed.Int26_6, ok bool) {
+	if !strings.ContainsRune(f.runes, r) {
+		return 0, false
+	}
+	return fixed.I(f.advance), true
+}
+
+func (f *runeSetStdFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	return 0
+}
+
+func TestMultiFaceGraphemeClusters(t *testing.T) {
+	const (
+		family   = "\U0001F468\u200d\U0001F469\u200d\U0001F467"
+		thumbsUp = "\U0001F44D\U0001F3FD"
+	)
+
+	// The primary face has the joiner and the skin tone modifier, but not the emoji.
+	primary := text.NewStdFace(&runeSetStdFace{
+		runes:   "x\u200d\U0001F3FD",
+		advance: 10,
+	})
+	emoji := text.NewStdFace(&runeSetStdFace{
+		runes:   "\U0001F468\U0001F469\U0001F467\U0001F44D\U0001F3FD",
+		advance: 6,
+	})
+	m, err := text.NewMultiFace(primary, emoji)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The whole clusters must be rendered with the emoji face.
+	for _, str := range []string{family, thumbsUp} {
+		if got, want := text.Advance(str, m), text.Advance(str, emoji); got != want {
+			t.Errorf("Advance(%q): got: %v, want: %v", str, got, want)
+		}
+		if got, want := text.Advance("x"+str+"x", m), text.Advance(str, emoji)+text.Advance("xx", primary); got != want {
+			t.Errorf("Advance(%q): got: %v, want: %v", "x"+str+"x", got, want)
+		}
+	}
+
+	// A standalone modifier is rendered with the primary face.
+	if got, want := text.Advance("\U0001F3FD", m), text.Advance("\U0001F3FD", primary); got != want {
+		t.Errorf("Advance(%q): got: %v, want: %v", "\U0001F3FD", got, want)
+	}
+}
+
+func TestGoTextFaceGraphemeClusterIndices(t *testing.T) {
+	src, err := text.NewGoTextFaceSource(bytes.NewReader(fonts.MPlus1pRegular_ttf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &text.GoTextFace{
+		Source: src,
+		Size:   16,
+	}
+
+	// Even if the font doesn't have the glyphs for the emoji, the glyphs' indices cover the whole clusters.
+	for _, cluster := range []string{
+		"e\u0301",
+		"\U0001F468\u200d\U0001F469\u200d\U0001F467",
+		"\U0001F44D\U0001F3FD",
+	} {
+		for _, g := range text.AppendGlyphs(nil, cluster+"a", f, nil) {
+			start, end := 0, len(cluster)
+			if g.StartIndexInBytes >= len(cluster) {
+				start, end = len(cluster), len(cluster)+1
+			}
+			if g.StartIndexInBytes != start || g.EndIndexInBytes != end {
+				t.Errorf("%q: indices: got: [%d, %d), want: [%d, %d)", cluster, g.StartIndexInBytes, g.EndIndexInBytes, start, end)
+			}
+		}
+	}
+}
+
+// graphemeBoundaries returns the grapheme cluster boundaries in str by the segmenter.
+func graphemeBoundaries(str string) []int {
+	var runes []rune
+	var indices []int
+	for i, r := range str {
+		runes = append(runes, r)
+		indices = append(indices, i)
+	}
+	indices = append(indices, len(str))
+
+	var seg segmenter.Segmenter
+	seg.Init(runes)
+	iter := seg.GraphemeIterator()
+	boundaries := []int{0}
+	for iter.Next() {
+		g := iter.Grapheme()
+		boundaries = append(boundaries, indices[g.Offset+len(g.Text)])
+	}
+	return boundaries
+}
+
+func TestGraphemeBoundariesWithoutSegmentation(t *testing.T) {
+	// Segmentation is skipped for text without characters that might extend clusters.
+	// Check that the result is the same as the segmenter for every character in the planes with assigned characters.
+	for r := rune(0); r <= 0xeffff; r++ {
+		if 0xd800 <= r && r <= 0xdfff {
+			continue
+		}
+		// Skip the planes 4-13, which are unassigned.
+		if 0x40000 <= r && r < 0xe0000 {
+			continue
+		}
+		str := "\u3042" + string(r) + string(r) + "\u3042"
+
+		want := graphemeBoundaries(str)
+		got := []int{0}
+		for got[len(got)-1] < len(str) {
+			got = append(got, text.NextGraphemeBoundary(str, got[len(got)-1]))
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("U+%04X: got: %v, want: %v", r, got, want)
+		}
+	}
+}
+
+func TestGraphemeBoundaryAllocs(t *testing.T) {
+	for _, str := range []string{
+		"Hello, World!",
+		"\u3053\u3093\u306b\u3061\u306f\u3001\u4e16\u754c",
+		"Caf\u00e9 cr\u00e8me br\u00fbl\u00e9e",
+	} {
+		if got := testing.AllocsPerRun(100, func() {
+			text.NextGraphemeBoundary(str, 1)
+			text.PrevGraphemeBoundary(str, len(str))
+		}); got > 0 {
+			t.Errorf("%q: allocations: got: %v, want: 0", str, got)
+		}
+	}
+}
+
+func TestGraphemeBoundaries(t *testing.T) {
+	// "e" + COMBINING ACUTE ACCENT, the family emoji, the thumbs up emoji with a skin tone modifier, and CR LF.
+	const str = "ae\u0301\U0001F468\u200d\U0001F469\u200d\U0001F467\U0001F44D\U0001F3FD\r\nb"
+	boundaries := []int{0, 1, 4, 22, 30, 32, 33}
+
+	for i := 0; i < len(boundaries)-1; i++ {
+		if got, want := text.NextGraphemeBoundary(str, boundaries[i]), boundaries[i+1]; got != want {
+			t.Errorf("NextGraphemeBoundary(str, %d): got: %d, want: %d", boundaries[i], got, want)
+		}
+		if got, want := text.PrevGraphemeBoundary(str, boundaries[i+1]), boundaries[i]; got != want {
+			t.Errorf("PrevGraphemeBoundary(str, %d): got: %d, want: %d", boundaries[i+1], got, want)
+		}
+	}
+
+	// In the middle of a cluster.
+	if got, want := text.NextGraphemeBoundary(str, 5), 22; got != want {
+		t.Errorf("NextGraphemeBoundary(str, 5): got: %d, want: %d", got, want)
+	}
+	if got, want := text.PrevGraphemeBoundary(str, 5), 4; got != want {
+		t.Errorf("PrevGraphemeBoundary(str, 5): got: %d, want: %d", got, want)
+	}
+
+	// Out of range.
+	if got, want := text.NextGraphemeBoundary(str, len(str)), len(str); got != want {
+		t.Errorf("NextGraphemeBoundary(str, len(str)): got: %d, want: %d", got, want)
+	}
+	if got, want := text.PrevGraphemeBoundary(str, 0), 0; got != want {
+		t.Errorf("PrevGraphemeBoundary(str, 0): got: %d, want: %d", got, want)
+	}
+	if got, want := text.NextGraphemeBoundary("", 0), 0; got != want {
+		t.Errorf("NextGraphemeBoundary(\"\", 0): got: %d, want: %d", got, want)
+	}
+}
+
+func TestMultiFaceMetricsForText(t *testing.T) {
+	src, err := text.NewGoTextFaceSource(bytes.NewReader(fonts.MPlus1pRegular_ttf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	small := text.NewStdFace(bitmapfont.Face)
+	big := &text.GoTextFace{
+		Source: src,
+		Size:   32,
+	}
+	mf := text.MultiFace{small, big}
+
+	if got, want := mf.MetricsForText("Hello"), small.Metrics(); got != want {
+		t.Errorf("MetricsForText(%q): got: %v, want: %v", "Hello", got, want)
+	}
+	if got, want := mf.MetricsForText(""), small.Metrics(); got != want {
+		t.Errorf("MetricsForText(%q): got: %v, want: %v", "", got, want)
+	}
+	if got, want := mf.MetricsForText("Hello"), mf.Metrics(); got == want {
+		t.Errorf("MetricsForText(%q) must be different from Metrics() but not: %v", "Hello", got)
+	}
+}
+
+func TestDrawBlock(t *testing.T) {
+	f := text.NewStdFace(&testStdFace{})
+	dst := ebiten.NewImage(testStdFaceSize*4, testStdFaceSize*4)
+
+	bounds := image.Rect(testStdFaceSize, testStdFaceSize, testStdFaceSize*3, testStdFaceSize*3)
+	op := &text.DrawOptions{}
+	op.PrimaryAlign = text.AlignCenter
+	op.SecondaryAlign = text.AlignEnd
+	if text.DrawBlock(dst, "b", f, bounds, op) {
+		t.Errorf("DrawBlock must not report overflow")
+	}
+
+	for j := 0; j < testStdFaceSize*4; j++ {
+		for i := 0; i < testStdFaceSize*4; i++ {
+			got := dst.At(i, j)
+			var want color.RGBA
+			if testStdFaceSize*3/2 <= i && i < testStdFaceSize*5/2 && testStdFaceSize*2 <= j && j < testStdFaceSize*3 {
+				want = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+			}
+			if got != want {
+				t.Errorf("At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+
+	// A too small bounds clips the text.
+	dst.Clear()
+	bounds = image.Rect(0, 0, testStdFaceSize/2, testStdFaceSize/2)
+	if !text.DrawBlock(dst, "b", f, bounds, nil) {
+		t.Errorf("DrawBlock must report overflow")
+	}
+	for j := 0; j < testStdFaceSize*4; j++ {
+		for i := 0; i < testStdFaceSize*4; i++ {
+			got := dst.At(i, j)
+			var want color.RGBA
+			if image.Pt(i, j).In(bounds) {
+				want = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+			}
+			if got != want {
+				t.Errorf("At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestAdvances(t *testing.T) {
+	f := text.NewStdFace(&testStdFace{})
+	// With testStdFace, 'b' has a negative kerning.
+	got := text.Advances("aab", f)
+	want := []float64{testStdFaceSize, testStdFaceSize * 2, testStdFaceSize * 2}
+	if len(got) != len(want) {
+		t.Fatalf("len(Advances): got: %d, want: %d", len(got), len(want))
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("Advances[%d]: got: %v, want: %v", i, got[i], want[i])
+		}
+	}
+
+	src, err := text.NewGoTextFaceSource(bytes.NewReader(fonts.MPlus1pRegular_ttf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	faces := []text.Face{
+		text.NewStdFace(bitmapfont.Face),
+		&text.GoTextFace{
+			Source: src,
+			Size:   16,
+		},
+		&text.GoTextFace{
+			Source:    src,
+			Direction: text.DirectionTopToBottomAndRightToLeft,
+			Size:      16,
+		},
+		text.MultiFace{&text.GoTextFace{
+			Source: src,
+			Size:   16,
+		}, text.NewStdFace(bitmapfont.Face)},
+	}
+	const str = "Hello, 世界"
+	for _, f := range faces {
+		as := text.Advances(str, f)
+		if got, want := len(as), utf8.RuneCountInString(str); got != want {
+			t.Errorf("len(Advances): got: %d, want: %d", got, want)
+			continue
+		}
+		for i := 1; i < len(as); i++ {
+			if as[i] < as[i-1] {
+				t.Errorf("Advances must not decrease: Advances[%d]: %v, Advances[%d]: %v", i-1, as[i-1], i, as[i])
+			}
+		}
+		if got, want := as[len(as)-1], text.Advance(str, f); got != want {
+			t.Errorf("Advances[%d]: got: %v, want: %v", len(as)-1, got, want)
+		}
+	}
+}
+
+func TestMeasureTrailingWhitespaces(t *testing.T) {
+	src, err := text.NewGoTextFaceSource(bytes.NewReader(fonts.MPlus1pRegular_ttf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	faces := []text.Face{
+		text.NewStdFace(bitmapfont.Face),
+		&text.GoTextFace{
+			Source: src,
+			Size:   16,
+		},
+		text.MultiFace{&text.GoTextFace{
+			Source: src,
+			Size:   16,
+		}, text.NewStdFace(bitmapfont.Face)},
+	}
+	for _, f := range faces {
+		space := text.Advance(" ", f)
+		if space <= 0 {
+			t.Errorf("Advance(\" \", %T): got: %v, want: positive", f, space)
+			continue
+		}
+		w0, _ := text.Measure("Hello", f, 0)
+		for i, str := range []string{"Hello ", " Hello", "Hello\nHello "} {
+			w1, _ := text.Measure(str, f, 0)
+			if got, want := w1, w0+space; got != want {
+				t.Errorf("%d: Measure(%q, %T): got: %v, want: %v", i, str, f, got, want)
+			}
+		}
+	}
+}
+
+func TestStdFaceSubpixelGranularity(t *testing.T) {
+	f := text.NewStdFace(bitmapfont.Face)
+	// bitmapfont.Face is small enough to use the finest default granularity.
+	if got, want := f.SubpixelGranularity(), 8; got != want {
+		t.Errorf("SubpixelGranularity(): got: %d, want: %d", got, want)
+	}
+
+	f.SetSubpixelGranularity(2)
+	if got, want := f.SubpixelGranularity(), 2; got != want {
+		t.Errorf("SubpixelGranularity(): got: %d, want: %d", got, want)
+	}
+
+	f.SetSubpixelGranularity(0)
+	if got, want := f.SubpixelGranularity(), 8; got != want {
+		t.Errorf("SubpixelGranularity(): got: %d, want: %d", got, want)
+	}
+
+	defer func() {
+		if e := recover(); e == nil {
+			t.Errorf("SetSubpixelGranularity(3) must panic but not")
+		}
+	}()
+	f.SetSubpixelGranularity(3)
+}
+
+// strayStdFace has glyphs for Latin alphabets and only one stray Han character.
+type strayStdFace struct {
+	testStdFace
+}
+
+func (s *strayStdFace) has(r rune) bool {
+	return 'A' <= r && r <= 'Z' || 'a' <= r && r <= 'z' || r == '世'
+}
+
+func (s *strayStdFace) Glyph(dot fixed.Point26_6, r rune) (dr image.Rectangle, mask image.Image, maskp image.Point, advance fixed.Int26_6, ok bool) {
+	if !s.has(r) {
+		return
+	}
+	return s.testStdFace.Glyph(dot, r)
+}
+
+func (s *strayStdFace) GlyphBounds(r rune) (bounds fixed.Rectangle26_6, advance fixed.Int26_6, ok bool) {
+	if !s.has(r) {
+		return
+	}
+	return s.testStdFace.GlyphBounds(r)
+}
+
+func (s *strayStdFace) GlyphAdvance(r rune) (advance fixed.Int26_6, ok bool) {
+	if !s.has(r) {
+		return
+	}
+	return s.testStdFace.GlyphAdvance(r)
+}
+
+func TestScriptMultiFace(t *testing.T) {
+	stray := text.NewStdFace(&strayStdFace{})
+	bf := text.NewStdFace(bitmapfont.Face)
+
+	if got, want := text.SupportedScripts(stray), []language.Script{language.MustParseScript("Latn")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SupportedScripts(stray): got: %v, want: %v", got, want)
+	}
+
+	const str = "a世"
+
+	// MultiFace uses the stray Han glyph.
+	if got, want := text.Advance(str, text.MultiFace{stray, bf}), text.Advance(str, stray); got != want {
+		t.Errorf("MultiFace: got: %v, want: %v", got, want)
+	}
+
+	// ScriptMultiFace uses the face that supports Han.
+	if got, want := text.Advance(str, text.ScriptMultiFace{stray, bf}), text.Advance("a", stray)+text.Advance("世", bf); got != want {
+		t.Errorf("ScriptMultiFace: got: %v, want: %v", got, want)
+	}
+
+	// A nested MultiFace supports the scripts of its faces.
+	if got, want := text.Advance(str, text.ScriptMultiFace{stray, text.MultiFace{bf}}), text.Advance("a", stray)+text.Advance("世", bf); got != want {
+		t.Errorf("ScriptMultiFace with a nested MultiFace: got: %v, want: %v", got, want)
+	}
+}
+
+func TestAppendGlyphsAllocs(t *testing.T) {
+	f := text.NewStdFace(bitmapfont.Face)
+
+	// Prepare the texts in advance not to count allocations for string conversions.
+	var strs []string
+	for i := 0; i < 60; i++ {
+		strs = append(strs, "Frame: "+strconv.Itoa(i))
+	}
+	for _, str := range strs {
+		text.CacheGlyphs(str, f)
+	}
+
+	glyphs := text.AppendGlyphs(nil, strs[0], f, nil)
+	var i int
+	if got := testing.AllocsPerRun(100, func() {
+		glyphs = text.AppendGlyphs(glyphs[:0], strs[i%len(strs)], f, nil)
+		i++
+	}); got > 0 {
+		t.Errorf("AppendGlyphs allocations: got: %v, want: 0", got)
+	}
+}
+
+func TestDrawAllocs(t *testing.T) {
+	f := text.NewStdFace(bitmapfont.Face)
+	dst := ebiten.NewImage(640, 480)
+
+	var strs []string
+	for i := 0; i < 60; i++ {
+		strs = append(strs, "Frame: "+strconv.Itoa(i))
+	}
+	for _, str := range strs {
+		text.CacheGlyphs(str, f)
+	}
+
+	op := &text.DrawOptions{}
+	text.Draw(dst, strs[0], f, op)
+	var i int
+	if got := testing.AllocsPerRun(100, func() {
+		text.Draw(dst, strs[i%len(strs)], f, op)
+		i++
+	}); got > 0 {
+		t.Errorf("Draw allocations: got: %v, want: 0", got)
+	}
+}
+
+func BenchmarkAppendGlyphsFrameCounter(b *testing.B) {
+	f := text.NewStdFace(bitmapfont.Face)
+
+	// Prepare the texts in advance not to count allocations for string conversions.
+	var strs []string
+	for i := 0; i < 60; i++ {
+		strs = append(strs, "Frame: "+strconv.Itoa(i))
+	}
+	for _, str := range strs {
+		text.CacheGlyphs(str, f)
+	}
+
+	var glyphs []text.Glyph
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		glyphs = text.AppendGlyphs(glyphs[:0], strs[i%len(strs)], f, nil)
+	}
+}
+
+func BenchmarkDrawFrameCounter(b *testing.B) {
+	f := text.NewStdFace(bitmapfont.Face)
+	dst := ebiten.NewImage(640, 480)
+
+	var strs []string
+	for i := 0; i < 60; i++ {
+		strs = append(strs, "Frame: "+strconv.Itoa(i))
+	}
+	for _, str := range strs {
+		text.CacheGlyphs(str, f)
+	}
+
+	op := &text.DrawOptions{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		text.Draw(dst, strs[i%len(strs)], f, op)
+	}
+}
+
+func TestTransformFace(t *testing.T) {
+	var geoM ebiten.GeoM
+	geoM.Scale(2, 2)
+	f := text.TransformFace(text.NewStdFace(&testStdFace{}), geoM)
+
+	if got, want := text.Advance("a", f), float64(testStdFaceSize*2); got != want {
+		t.Errorf("Advance: got: %v, want: %v", got, want)
+	}
+	if got, want := f.Metrics().HDescent, float64(testStdFaceSize*2); got != want {
+		t.Errorf("Metrics().HDescent: got: %v, want: %v", got, want)
+	}
+	if got, want := text.Advance("a", text.MultiFace{f}), float64(testStdFaceSize*2); got != want {
+		t.Errorf("Advance with MultiFace: got: %v, want: %v", got, want)
+	}
+
+	dst := ebiten.NewImage(testStdFaceSize*4, testStdFaceSize*4)
+	text.Draw(dst, "b", f, nil)
+	for j := 0; j < testStdFaceSize*4; j++ {
+		for i := 0; i < testStdFaceSize*4; i++ {
+			// The edges might be blended due to the linear filter.
+			inside := 1 <= i && i < testStdFaceSize*2-1 && 1 <= j && j < testStdFaceSize*2-1
+			outside := i >= testStdFaceSize*2+2 || j >= testStdFaceSize*2+2
+			if !inside && !outside {
+				continue
+			}
+			got := dst.At(i, j)
+			var want color.RGBA
+			if inside {
+				want = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+			}
+			if got != want {
+				t.Errorf("At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestTransformFaceRotate(t *testing.T) {
+	var geoM ebiten.GeoM
+	geoM.Rotate(math.Pi / 2)
+	f := text.TransformFace(text.NewStdFace(&testStdFace{}), geoM)
+
+	// A rotation doesn't change the advances.
+	if got, want := text.Advance("aa", f), float64(testStdFaceSize*2); math.Abs(got-want) > 1e-9 {
+		t.Errorf("Advance: got: %v, want: %v", got, want)
+	}
+	b, a := f.GlyphBounds('a')
+	if want := float64(testStdFaceSize); math.Abs(a-want) > 1e-9 {
+		t.Errorf("GlyphBounds advance: got: %v, want: %v", a, want)
+	}
+	// The bounds might be expanded by 1 due to the error of the rotation.
+	if got, want := b.Dx(), testStdFaceSize; got < want || got > want+1 {
+		t.Errorf("GlyphBounds width: got: %v, want: %v", got, want)
+	}
+	if got, want := b.Dy(), testStdFaceSize; got < want || got > want+1 {
+		t.Errorf("GlyphBounds height: got: %v, want: %v", got, want)
+	}
+
+	// The line is rotated around its origin, so the glyphs are arranged downward.
+	const size = testStdFaceSize * 4
+	dst := ebiten.NewImage(size, size)
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(size/2, 0)
+	text.Draw(dst, "aa", f, op)
+	for j := 0; j < size; j++ {
+		for i := 0; i < size; i++ {
+			// The edges might be blended due to the linear filter.
+			inside := size/2-testStdFaceSize+1 <= i && i < size/2-1 && 1 <= j && j < testStdFaceSize*2-1
+			outside := i < size/2-testStdFaceSize-2 || i >= size/2+2 || j >= testStdFaceSize*2+2
+			if !inside && !outside {
+				continue
+			}
+			got := dst.At(i, j)
+			var want color.RGBA
+			if inside {
+				want = color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0x80}
+			}
+			if got != want {
+				t.Errorf("At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestSDFFace(t *testing.T) {
+	src, err := text.NewGoTextFaceSource(bytes.NewReader(fonts.MPlus1pRegular_ttf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const size = 32
+	f := text.NewSDFFace(src, &text.SDFFaceOptions{
+		Size: size,
+	})
+	g := &text.GoTextFace{
+		Source: src,
+		Size:   size,
+	}
+
+	const str = "Ebitengine"
+	if got, want := text.Advance(str, f), text.Advance(str, g); got != want {
+		t.Errorf("Advance: got: %v, want: %v", got, want)
+	}
+	if got, want := f.Metrics(), g.Metrics(); got != want {
+		t.Errorf("Metrics(): got: %v, want: %v", got, want)
+	}
+
+	b, _ := g.GlyphBounds('I')
+	for _, scale := range []float64{1, 4} {
+		dst := ebiten.NewImage(size*8, size*8)
+		op := &text.DrawOptions{}
+		op.GeoM.Scale(scale, scale)
+		text.Draw(dst, "I", f, op)
+
+		// The center of the glyph must be filled.
+		cx := int(float64(b.Min.X+b.Max.X) / 2 * scale)
+		cy := int((g.Metrics().HAscent + float64(b.Min.Y+b.Max.Y)/2) * scale)
+		if _, _, _, a := dst.At(cx, cy).RGBA(); a < 0xc000 {
+			t.Errorf("scale: %v, At(%d, %d).A: got: %v, want: >= 0xc000", scale, cx, cy, a)
+		}
+
+		// A point far from the glyph must be empty.
+		x, y := size*8-1, size*8-1
+		if got, want := dst.At(x, y), (color.RGBA{}); got != want {
+			t.Errorf("scale: %v, At(%d, %d): got: %v, want: %v", scale, x, y, got, want)
+		}
+	}
+}
+
+func TestCachedText(t *testing.T) {
+	f := text.NewStdFace(&testStdFace{})
+	c := text.NewCachedText("ab\nb", f, &text.LayoutOptions{
+		LineSpacingInPixels: testStdFaceSize,
+	})
+
+	if got, want := c.Glyphs(), text.AppendGlyphs(nil, "ab\nb", f, &text.LayoutOptions{LineSpacingInPixels: testStdFaceSize}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Glyphs(): got: %v, want: %v", got, want)
+	}
+	w, h := c.Size()
+	if wantW, wantH := text.Measure("ab\nb", f, testStdFaceSize); w != wantW || h != wantH {
+		t.Errorf("Size(): got: (%v, %v), want: (%v, %v)", w, h, wantW, wantH)
+	}
+
+	// Without a maximum width, the lines are split only by the newline characters.
+	if got, want := len(c.Lines()), 2; got != want {
+		t.Errorf("len(Lines()): got: %d, want: %d", got, want)
+	}
+
+	c.SetText("b")
+	if got, want := len(c.Glyphs()), 1; got != want {
+		t.Errorf("len(Glyphs()) after SetText: got: %d, want: %d", got, want)
+	}
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(1, 2)
+	dst0 := ebiten.NewImage(testStdFaceSize*2, testStdFaceSize*2)
+	c.Draw(dst0, op)
+	if got, want := op.GeoM.Element(0, 2), 1.0; got != want {
+		t.Errorf("op.GeoM must be kept: got: %v, want: %v", got, want)
+	}
+
+	dst1 := ebiten.NewImage(testStdFaceSize*2, testStdFaceSize*2)
+	op1 := &text.DrawOptions{}
+	op1.GeoM.Translate(1, 2)
+	text.Draw(dst1, "b", f, op1)
+
+	for j := 0; j < testStdFaceSize*2; j++ {
+		for i := 0; i < testStdFaceSize*2; i++ {
+			if got, want := dst0.At(i, j), dst1.At(i, j); got != want {
+				t.Errorf("At(%d, %d): got: %v, want: %v", i, j, got, want)
+			}
+		}
+	}
+
+	var zero text.CachedText
+	zero.Draw(dst0, nil)
+	if got := len(zero.Glyphs()); got != 0 {
+		t.Errorf("len(Glyphs()) for the zero value: got: %d, want: 0", got)
+	}
+}
+
+func TestCachedTextWrap(t *testing.T) {
+	f := text.NewStdFace(&testStdFace{})
+	m := f.Metrics()
+	op := &text.LayoutOptions{
+		LineSpacingInPixels: testStdFaceSize * 2,
+	}
+	l := text.NewCachedText("aa cc aa\nac", f, op)
+	l.SetMaxWidth(testStdFaceSize * 5)
+
+	type line struct {
+		Start int
+		End   int
+		Width float64
+	}
+	var got []line
+	for _, ln := range l.Lines() {
+		got = append(got, line{
+			Start: ln.StartIndexInBytes,
+			End:   ln.EndIndexInBytes,
+			Width: ln.Width,
+		})
+	}
+	// The trailing whitespace at the wrapping position is not included.
+	want := []line{
+		{0, 5, testStdFaceSize * 5},
+		{6, 8, testStdFaceSize * 2},
+		{9, 11, testStdFaceSize * 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Lines(): got: %v, want: %v", got, want)
+	}
+	for i, ln := range l.Lines() {
+		if got, want := ln.Y, float64(i*testStdFaceSize*2); got != want {
+			t.Errorf("Lines()[%d].Y: got: %v, want: %v", i, got, want)
+		}
+		if got, want := ln.Height, m.HAscent+m.HDescent; got != want {
+			t.Errorf("Lines()[%d].Height: got: %v, want: %v", i, got, want)
+		}
+	}
+
+	// The glyphs are the same as the wrapped text's glyphs.
+	// The wrapped text has the same indices as the original text, as the whitespace at the wrapping position is replaced with '\n'.
+	const wrapped = "aa cc\naa\nac"
+	if got, want := l.Glyphs(), text.AppendGlyphs(nil, wrapped, f, op); !reflect.DeepEqual(got, want) {
+		t.Errorf("Glyphs(): got: %v, want: %v", got, want)
+	}
+	w, h := l.Size()
+	if wantW, wantH := text.Measure(wrapped, f, testStdFaceSize*2); w != wantW || h != wantH {
+		t.Errorf("Size(): got: (%v, %v), want: (%v, %v)", w, h, wantW, wantH)
+	}
+
+	// The glyphs' indices are in the original text even when multiple whitespaces are removed at the wrapping position.
+	l.SetText("aa   c")
+	l.SetMaxWidth(testStdFaceSize * 3)
+	if got, want := len(l.Lines()), 2; got != want {
+		t.Fatalf("len(Lines()): got: %d, want: %d", got, want)
+	}
+	if got, want := l.Glyphs()[2].StartIndexInBytes, 5; got != want {
+		t.Errorf("Glyphs()[2].StartIndexInBytes: got: %d, want: %d", got, want)
+	}
+
+	// A word that doesn't fit is not broken.
+	l.SetText("aa cc aa\nac")
+	l.SetMaxWidth(testStdFaceSize)
+	if got, want := len(l.Lines()), 4; got != want {
+		t.Errorf("len(Lines()) after SetMaxWidth: got: %d, want: %d", got, want)
+	}
+
+	// Without a maximum width, only the newline characters break lines.
+	l.SetMaxWidth(0)
+	if got, want := len(l.Lines()), 2; got != want {
+		t.Errorf("len(Lines()) without a maximum width: got: %d, want: %d", got, want)
+	}
+
+	var zero text.CachedText
+	zero.SetMaxWidth(testStdFaceSize)
+	zero.Draw(ebiten.NewImage(1, 1), nil)
+	if got := len(zero.Lines()); got != 0 {
+		t.Errorf("len(Lines()) for the zero value: got: %d, want: 0", got)
+	}
+}
+
+func TestDrawWithShader(t *testing.T) {
+	s, err := ebiten.NewShader([]byte(`//kage:unit pixels
+
+package main
+
+var GlyphPosition float
+var Green float
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	return vec4(GlyphPosition, Green, 0, 1) * imageSrc0At(srcPos).a
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var face unhashableStdFace
+	f := text.NewStdFace(&face)
+	dst := ebiten.NewImage(unhashableStdFaceSize*3, unhashableStdFaceSize)
+	op := &text.DrawOptions{}
+	op.Shader = s
+	op.Uniforms = map[string]any{
+		"Green": 1,
+	}
+	text.Draw(dst, "abc", f, op)
+
+	for i, r := range []uint8{0, 0x80, 0xff} {
+		x := unhashableStdFaceSize*i + unhashableStdFaceSize/2
+		got := dst.At(x, unhashableStdFaceSize/2).(color.RGBA)
+		want := color.RGBA{R: r, G: 0xff, A: 0xff}
+		// Allow a small error for the middle value.
+		if diff := int(got.R) - int(want.R); diff < -1 || diff > 1 || got.G != want.G || got.B != want.B || got.A != want.A {
+			t.Errorf("At(%d, %d): got: %v, want: %v", x, unhashableStdFaceSize/2, got, want)
+		}
+	}
+}
+
+// addFontTables returns a new OpenType font data with the given tables added.
+func addFontTables(data []byte, tables map[string][]byte) []byte {
+	type record struct {
+		tag  string
+		data []byte
+	}
+
+	numTables := int(binary.BigEndian.Uint16(data[4:6]))
+	var records []record
+	for i := 0; i < numTables; i++ {
+		r := data[12+16*i:]
+		offset := binary.BigEndian.Uint32(r[8:12])
+		length := binary.BigEndian.Uint32(r[12:16])
+		records = append(records, record{
+			tag:  string(r[0:4]),
+			data: data[offset : offset+length],
+		})
+	}
+	for tag, data := range tables {
+		records = append(records, record{
+			tag:  tag,
+			data: data,
+		})
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].tag < records[j].tag
+	})
+
+	header := make([]byte, 12+16*len(records))
+	copy(header[0:4], data[0:4])
+	binary.BigEndian.PutUint16(header[4:6], uint16(len(records)))
+	// searchRange, entrySelector, and rangeShift are not used by the loader.
+
+	body := []byte{}
+	for i, r := range records {
+		h := header[12+16*i:]
+		copy(h[0:4], r.tag)
+		binary.BigEndian.PutUint32(h[8:12], uint32(len(header)+len(body)))
+		binary.BigEndian.PutUint32(h[12:16], uint32(len(r.data)))
+		body = append(body, r.data...)
+		for len(body)%4 != 0 {
+			body = append(body, 0)
+		}
+	}
+	return append(header, body...)
+}
+
+func TestGoTextFaceColorGlyphs(t *testing.T) {
+	src, err := text.NewGoTextFaceSource(bytes.NewReader(fonts.MPlus1pRegular_ttf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gidA, ok := src.UnsafeInternal().NominalGlyph('a')
+	if !ok {
+		t.Fatal("glyph for 'a' is not found")
+	}
+	gidB, ok := src.UnsafeInternal().NominalGlyph('b')
+	if !ok {
+		t.Fatal("glyph for 'b' is not found")
+	}
+
+	// 'a' has a red layer, and 'b' has a foreground-colored layer.
+	colr := []byte{
+		0, 0, // version
+		0, 2, // numBaseGlyphRecords
+		0, 0, 0, 14, // baseGlyphRecordsOffset
+		0, 0, 0, 26, // layerRecordsOffset
+		0, 2, // numLayerRecords
+	}
+	colr = append(colr, byte(gidA>>8), byte(gidA))
+	colr = append(colr, 0, 0, 0, 1) // firstLayerIndex, numLayers
+	colr = append(colr, byte(gidB>>8), byte(gidB))
+	colr = append(colr, 0, 1, 0, 1) // firstLayerIndex, numLayers
+	colr = append(colr, byte(gidA>>8), byte(gidA))
+	colr = append(colr, 0, 0) // paletteIndex
+	colr = append(colr, byte(gidB>>8), byte(gidB))
+	colr = append(colr, 0xff, 0xff) // paletteIndex (foreground)
+
+	cpal := []byte{
+		0, 0, // version
+		0, 1, // numPaletteEntries
+		0, 1, // numPalettes
+		0, 1, // numColorRecords
+		0, 0, 0, 14, // colorRecordsArrayOffset
+		0, 0, // colorRecordIndices[0]
+		0, 0, 0xff, 0xff, // BGRA
+	}
+
+	data := addFontTables(fonts.MPlus1pRegular_ttf, map[string][]byte{
+		"COLR": colr,
+		"CPAL": cpal,
+	})
+	colorSrc, err := text.NewGoTextFaceSource(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &text.GoTextFace{
+		Source: colorSrc,
+		Size:   32,
+	}
+
+	diff := func(x, y byte) int {
+		if x > y {
+			return int(x - y)
+		}
+		return int(y - x)
+	}
+	for _, tc := range []struct {
+		Text  string
+		Color color.RGBA
+	}{
+		{
+			Text:  "a",
+			Color: color.RGBA{R: 0xff, A: 0xff},
+		},
+		{
+			Text:  "b",
+			Color: color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+		},
+	} {
+		dst := ebiten.NewImage(64, 64)
+		text.Draw(dst, tc.Text, f, nil)
+
+		var opaque bool
+		for j := 0; j < 64; j++ {
+			for i := 0; i < 64; i++ {
+				got := dst.At(i, j).(color.RGBA)
+				if got.A == 0 {
+					continue
+				}
+				// The color must be the layer's color with an alpha value for anti-aliasing.
+				want := color.RGBA{
+					R: byte(int(tc.Color.R) * int(got.A) / 0xff),
+					G: byte(int(tc.Color.G) * int(got.A) / 0xff),
+					B: byte(int(tc.Color.B) * int(got.A) / 0xff),
+					A: got.A,
+				}
+				if diff(got.R, want.R) > 1 || diff(got.G, want.G) > 1 || diff(got.B, want.B) > 1 {
+					t.Errorf("%q: At(%d, %d): got: %v, want: %v", tc.Text, i, j, got, want)
+				}
+				if got.A == 0xff {
+					opaque = true
+				}
+			}
+		}
+		if !opaque {
+			t.Errorf("%q: no opaque pixels", tc.Text)
+		}
+	}
+}
+
+func TestGoTextFaceMalformedColorTables(t *testing.T) {
+	src, err := text.NewGoTextFaceSource(bytes.NewReader(fonts.MPlus1pRegular_ttf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gid, ok := src.UnsafeInternal().NominalGlyph('a')
+	if !ok {
+		t.Fatal("glyph for 'a' is not found")
+	}
+
+	colr := func(baseGlyphRecordsOffset, layerRecordsOffset uint32) []byte {
+		data := []byte{
+			0, 0, // version
+			0, 1, // numBaseGlyphRecords
+			0, 0, 0, 0, // baseGlyphRecordsOffset
+			0, 0, 0, 0, // layerRecordsOffset
+			0, 1, // numLayerRecords
+		}
+		binary.BigEndian.PutUint32(data[4:8], baseGlyphRecordsOffset)
+		binary.BigEndian.PutUint32(data[8:12], layerRecordsOffset)
+		data = append(data, byte(gid>>8), byte(gid))
+		data = append(data, 0, 0, 0, 1) // firstLayerIndex, numLayers
+		data = append(data, byte(gid>>8), byte(gid))
+		data = append(data, 0, 0) // paletteIndex
+		return data
+	}
+	cpal := func(colorRecordsArrayOffset uint32) []byte {
+		data := []byte{
+			0, 0, // version
+			0, 1, // numPaletteEntries
+			0, 1, // numPalettes
+			0, 1, // numColorRecords
+			0, 0, 0, 0, // colorRecordsArrayOffset
+			0, 0, // colorRecordIndices[0]
+			0, 0, 0xff, 0xff, // BGRA
+		}
+		binary.BigEndian.PutUint32(data[8:12], colorRecordsArrayOffset)
+		return data
+	}
+
+	for _, tc := range []struct {
+		Name string
+		COLR []byte
+		CPAL []byte
+	}{
+		{
+			Name: "too short COLR",
+			COLR: colr(14, 20)[:10],
+			CPAL: cpal(14),
+		},
+		{
+			Name: "base glyph records out of range",
+			COLR: colr(0xfffffff0, 20),
+			CPAL: cpal(14),
+		},
+		{
+			Name: "layer records out of range",
+			COLR: colr(14, 0xffffffff),
+			CPAL: cpal(14),
+		},
+		{
+			Name: "color records out of range",
+			COLR: colr(14, 20),
+			CPAL: cpal(0x80000000),
+		},
+		{
+			Name: "too short CPAL",
+			COLR: colr(14, 20),
+			CPAL: cpal(14)[:8],
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			data := addFontTables(fonts.MPlus1pRegular_ttf, map[string][]byte{
+				"COLR": tc.COLR,
+				"CPAL": tc.CPAL,
+			})
+
+			s, err := text.NewGoTextFaceSource(bytes.NewReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ss, err := text.NewGoTextFaceSourcesFromCollection(bytes.NewReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := len(ss), 1; got != want {
+				t.Fatalf("len(sources): got: %d, want: %d", got, want)
+			}
+
+			for _, s := range []*text.GoTextFaceSource{s, ss[0]} {
+				// The color layers are dropped, and the glyph is rendered in monochrome.
+				dst := ebiten.NewImage(64, 64)
+				text.Draw(dst, "a", &text.GoTextFace{
+					Source: s,
+					Size:   32,
+				}, nil)
+
+				var opaque bool
+				for j := 0; j < 64; j++ {
+					for i := 0; i < 64; i++ {
+						got := dst.At(i, j).(color.RGBA)
+						if got.R != got.A || got.G != got.A || got.B != got.A {
+							t.Fatalf("At(%d, %d): got: %v, want: a gray color", i, j, got)
+						}
+						if got.A == 0xff {
+							opaque = true
+						}
+					}
+				}
+				if !opaque {
+					t.Errorf("no opaque pixels")
+				}
+			}
+		})
+	}
+}
+
+// fontTable returns the table with the given tag in the OpenType font data.
+func fontTable(data []byte, tag string) []byte {
+	numTables := int(binary.BigEndian.Uint16(data[4:6]))
+	for i := 0; i < numTables; i++ {
+		r := data[12+16*i:]
+		if string(r[0:4]) != tag {
+			continue
+		}
+		offset := binary.BigEndian.Uint32(r[8:12])
+		length := binary.BigEndian.Uint32(r[12:16])
+		return data[offset : offset+length]
+	}
+	return nil
+}
+
+func TestGoTextFaceBitmapGlyphs(t *testing.T) {
+	src, err := text.NewGoTextFaceSource(bytes.NewReader(fonts.MPlus1pRegular_ttf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gid, ok := src.UnsafeInternal().NominalGlyph('a')
+	if !ok {
+		t.Fatal("glyph for 'a' is not found")
+	}
+	numGlyphs := int(binary.BigEndian.Uint16(fontTable(fonts.MPlus1pRegular_ttf, "maxp")[4:6]))
+
+	// Create an sbix table with two strikes with different colors.
+	strikes := []struct {
+		ppem  int
+		color color.RGBA
+	}{
+		{
+			ppem:  16,
+			color: color.RGBA{R: 0xff, A: 0xff},
+		},
+		{
+			ppem:  64,
+			color: color.RGBA{G: 0xff, A: 0xff},
+		},
+	}
+	var strikeData [][]byte
+	for _, s := range strikes {
+		img := image.NewRGBA(image.Rect(0, 0, s.ppem/2, s.ppem/2))
+		for j := 0; j < img.Bounds().Dy(); j++ {
+			for i := 0; i < img.Bounds().Dx(); i++ {
+				img.SetRGBA(i, j, s.color)
+			}
+		}
+		var pngData bytes.Buffer
+		if err := png.Encode(&pngData, img); err != nil {
+			t.Fatal(err)
+		}
+
+		glyphData := []byte{
+			0, 0, // originOffsetX
+			0, 0, // originOffsetY
+			'p', 'n', 'g', ' ', // graphicType
+		}
+		glyphData = append(glyphData, pngData.Bytes()...)
+
+		headerSize := 4 + 4*(numGlyphs+1)
+		data := make([]byte, headerSize)
+		binary.BigEndian.PutUint16(data[0:2], uint16(s.ppem))
+		binary.BigEndian.PutUint16(data[2:4], 72)
+		for i := 0; i <= numGlyphs; i++ {
+			offset := headerSize
+			if i > int(gid) {
+				offset += len(glyphData)
+			}
+			binary.BigEndian.PutUint32(data[4+4*i:], uint32(offset))
+		}
+		data = append(data, glyphData...)
+		strikeData = append(strikeData, data)
+	}
+
+	sbix := make([]byte, 8+4*len(strikeData))
+	binary.BigEndian.PutUint16(sbix[0:2], 1) // version
+	binary.BigEndian.PutUint16(sbix[2:4], 1) // flags
+	binary.BigEndian.PutUint32(sbix[4:8], uint32(len(strikeData)))
+	for i, d := range strikeData {
+		binary.BigEndian.PutUint32(sbix[8+4*i:], uint32(len(sbix)))
+		sbix = append(sbix, d...)
+	}
+
+	data := addFontTables(fonts.MPlus1pRegular_ttf, map[string][]byte{
+		"sbix": sbix,
+	})
+	bitmapSrc, err := text.NewGoTextFaceSource(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		Size  float64
+		Color color.RGBA
+	}{
+		{
+			Size:  12,
+			Color: strikes[0].color,
+		},
+		{
+			Size:  16,
+			Color: strikes[0].color,
+		},
+		{
+			// The larger strike is selected when the size is between the strikes.
+			Size:  32,
+			Color: strikes[1].color,
+		},
+		{
+			// The largest strike is selected when the size is larger than all the strikes.
+			Size:  96,
+			Color: strikes[1].color,
+		},
+	} {
+		f := &text.GoTextFace{
+			Source: bitmapSrc,
+			Size:   tc.Size,
+		}
+		dst := ebiten.NewImage(128, 128)
+		text.Draw(dst, "a", f, nil)
+
+		var count int
+		for j := 0; j < 128; j++ {
+			for i := 0; i < 128; i++ {
+				got := dst.At(i, j).(color.RGBA)
+				if got.A != 0xff {
+					continue
+				}
+				if got != tc.Color {
+					t.Errorf("size: %v: At(%d, %d): got: %v, want: %v", tc.Size, i, j, got, tc.Color)
+				}
+				count++
+			}
+		}
+		// The bitmap is a square of the half of the size.
+		if want := int(tc.Size/2) * int(tc.Size/2); count < want/2 {
+			t.Errorf("size: %v: the number of opaque pixels: got: %d, want: around %d", tc.Size, count, want)
+		}
+	}
+}
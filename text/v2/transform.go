@@ -0,0 +1,272 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"image"
+	"math"
+
+	glanguage "github.com/go-text/typesetting/language"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+var _ Face = (*transformFace)(nil)
+
+type transformFace struct {
+	base Face
+	geoM ebiten.GeoM
+
+	glyphImageCache glyphImageCache[*ebiten.Image]
+}
+
+// TransformFace returns a Face that renders the base face with the given geometry transformation.
+//
+// The transformation is applied to each line around the line's origin position.
+// For example, this is useful to make a face with a non-uniform scale, or a skewed face like an oblique style.
+//
+// The advances and the metrics are also transformed.
+// An advance in the primary direction is scaled by the length of the transformed unit vector in the primary direction,
+// so a rotation doesn't change the advances.
+// The glyph positions in a line are transformed around the line's origin, so a rotation rotates each line around its origin,
+// while the lines are still laid out in the base face's direction.
+//
+// The glyph images are rendered by transforming the base face's glyph images.
+// The glyph positions are rounded to integers.
+// A big scale might make glyphs blurry. Use a face with a bigger size instead in this case.
+// If the base face is an SDFFace, the transformation is applied at rendering and the glyphs are kept crisp.
+//
+// The returned face works in a MultiFace as well as other faces.
+//
+// The returned face's methods are concurrent-safe.
+func TransformFace(base Face, geoM ebiten.GeoM) Face {
+	return &transformFace{
+		base: base,
+		geoM: geoM,
+	}
+}
+
+// apply applies the transformation to the position (x, y) around the origin (originX, originY).
+func (t *transformFace) apply(x, y float64, originX, originY float64) (float64, float64) {
+	x, y = t.geoM.Apply(x-originX, y-originY)
+	return x + originX, y + originY
+}
+
+// applyLinear applies the transformation's linear part without a translation.
+func (t *transformFace) applyLinear(x, y float64) (float64, float64) {
+	x, y = t.geoM.Apply(x, y)
+	return x - t.geoM.Element(0, 2), y - t.geoM.Element(1, 2)
+}
+
+func (t *transformFace) scaleX() float64 {
+	return math.Hypot(t.geoM.Element(0, 0), t.geoM.Element(1, 0))
+}
+
+func (t *transformFace) scaleY() float64 {
+	return math.Hypot(t.geoM.Element(0, 1), t.geoM.Element(1, 1))
+}
+
+// primaryScale returns the scale in the primary direction for advances.
+func (t *transformFace) primaryScale() float64 {
+	if t.base.direction().isHorizontal() {
+		return t.scaleX()
+	}
+	return t.scaleY()
+}
+
+// Metrics implements Face.
+func (t *transformFace) Metrics() Metrics {
+	m := t.base.Metrics()
+	sx, sy := t.scaleX(), t.scaleY()
+	return Metrics{
+		Height:   m.Height * sy,
+		HAscent:  m.HAscent * sy,
+		HDescent: m.HDescent * sy,
+		Width:    m.Width * sx,
+		VAscent:  m.VAscent * sx,
+		VDescent: m.VDescent * sx,
+	}
+}
+
+// GlyphBounds implements Face.
+func (t *transformFace) GlyphBounds(r rune) (bounds image.Rectangle, advance float64) {
+	b, a := t.base.GlyphBounds(r)
+	if b.Empty() {
+		return image.Rectangle{}, a * t.primaryScale()
+	}
+	minX, minY, maxX, maxY := t.transformedBounds(float64(b.Min.X), float64(b.Min.Y), float64(b.Max.X), float64(b.Max.Y))
+	return image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX)), int(math.Ceil(maxY))), a * t.primaryScale()
+}
+
+// transformedBounds returns the bounding box of the transformed rectangle (minX, minY)-(maxX, maxY) without a translation.
+func (t *transformFace) transformedBounds(minX, minY, maxX, maxY float64) (float64, float64, float64, float64) {
+	x0, y0 := t.applyLinear(minX, minY)
+	x1, y1 := t.applyLinear(maxX, minY)
+	x2, y2 := t.applyLinear(minX, maxY)
+	x3, y3 := t.applyLinear(maxX, maxY)
+	return math.Min(math.Min(x0, x1), math.Min(x2, x3)),
+		math.Min(math.Min(y0, y1), math.Min(y2, y3)),
+		math.Max(math.Max(x0, x1), math.Max(x2, x3)),
+		math.Max(math.Max(y0, y1), math.Max(y2, y3))
+}
+
+// advance implements Face.
+func (t *transformFace) advance(text string) float64 {
+	return t.base.advance(text) * t.primaryScale()
+}
+
+// appendAdvances implements Face.
+func (t *transformFace) appendAdvances(advances []float64, text string) []float64 {
+	n := len(advances)
+	advances = t.base.appendAdvances(advances, text)
+	s := t.primaryScale()
+	for i := n; i < len(advances); i++ {
+		advances[i] *= s
+	}
+	return advances
+}
+
+// hasGlyph implements Face.
+func (t *transformFace) hasGlyph(r rune) bool {
+	return t.base.hasGlyph(r)
+}
+
+// appendGlyphsForLine implements Face.
+func (t *transformFace) appendGlyphsForLine(glyphs []Glyph, line string, indexOffset int, originX, originY float64) []Glyph {
+	n := len(glyphs)
+	glyphs = t.base.appendGlyphsForLine(glyphs, line, indexOffset, originX, originY)
+
+	// Replace the glyphs with the transformed ones. A glyph might be removed when its transformed image is empty.
+	j := n
+	for i := n; i < len(glyphs); i++ {
+		g := glyphs[i]
+		if g.sdf {
+			// A signed distance field can be transformed at rendering without losing the quality.
+			linear := t.geoM
+			linear.SetElement(0, 2, 0)
+			linear.SetElement(1, 2, 0)
+			g.geoM.Concat(linear)
+			g.X, g.Y = t.apply(g.X, g.Y, originX, originY)
+			glyphs[j] = g
+			j++
+			continue
+		}
+		src := g.Image
+		img := t.glyphImageCache.getOrCreate(t, src, func() *ebiten.Image {
+			return t.transformGlyphImage(src)
+		})
+		if img == nil {
+			continue
+		}
+		w, h := src.Bounds().Dx(), src.Bounds().Dy()
+		minX, minY, _, _ := t.transformedBounds(0, 0, float64(w), float64(h))
+		x, y := t.apply(g.X, g.Y, originX, originY)
+		g.Image = img
+		g.X = math.Round(x + minX)
+		g.Y = math.Round(y + minY)
+		glyphs[j] = g
+		j++
+	}
+	for i := j; i < len(glyphs); i++ {
+		glyphs[i] = Glyph{}
+	}
+	return glyphs[:j]
+}
+
+func (t *transformFace) transformGlyphImage(src *ebiten.Image) *ebiten.Image {
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	minX, minY, maxX, maxY := t.transformedBounds(0, 0, float64(w), float64(h))
+	dw, dh := int(math.Ceil(maxX-minX)), int(math.Ceil(maxY-minY))
+	if dw == 0 || dh == 0 {
+		return nil
+	}
+
+	img := ebiten.NewImage(dw, dh)
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM = t.geoM
+	op.GeoM.SetElement(0, 2, 0)
+	op.GeoM.SetElement(1, 2, 0)
+	op.GeoM.Translate(-minX, -minY)
+	op.Filter = ebiten.FilterLinear
+	img.DrawImage(src, op)
+	return img
+}
+
+// appendVectorPathForLine implements Face.
+func (t *transformFace) appendVectorPathForLine(path vectorPath, line string, originX, originY float64) {
+	t.base.appendVectorPathForLine(&transformedVectorPath{
+		path:    path,
+		face:    t,
+		originX: originX,
+		originY: originY,
+	}, line, originX, originY)
+}
+
+// direction implements Face.
+func (t *transformFace) direction() Direction {
+	return t.base.direction()
+}
+
+// scripts implements Face.
+func (t *transformFace) scripts() map[glanguage.Script]struct{} {
+	return t.base.scripts()
+}
+
+// hasScript implements Face.
+func (t *transformFace) hasScript(script glanguage.Script) bool {
+	return t.base.hasScript(script)
+}
+
+// private implements Face.
+func (t *transformFace) private() {
+}
+
+// transformedVectorPath is a vectorPath that transforms the positions around the origin and appends them to another path.
+type transformedVectorPath struct {
+	path    vectorPath
+	face    *transformFace
+	originX float64
+	originY float64
+}
+
+func (t *transformedVectorPath) apply(x, y float32) (float32, float32) {
+	tx, ty := t.face.apply(float64(x), float64(y), t.originX, t.originY)
+	return float32(tx), float32(ty)
+}
+
+func (t *transformedVectorPath) MoveTo(x, y float32) {
+	t.path.MoveTo(t.apply(x, y))
+}
+
+func (t *transformedVectorPath) LineTo(x, y float32) {
+	t.path.LineTo(t.apply(x, y))
+}
+
+func (t *transformedVectorPath) QuadTo(x1, y1, x2, y2 float32) {
+	x1, y1 = t.apply(x1, y1)
+	x2, y2 = t.apply(x2, y2)
+	t.path.QuadTo(x1, y1, x2, y2)
+}
+
+func (t *transformedVectorPath) CubicTo(x1, y1, x2, y2, x3, y3 float32) {
+	x1, y1 = t.apply(x1, y1)
+	x2, y2 = t.apply(x2, y2)
+	x3, y3 = t.apply(x3, y3)
+	t.path.CubicTo(x1, y1, x2, y2, x3, y3)
+}
+
+func (t *transformedVectorPath) Close() {
+	t.path.Close()
+}
@@ -0,0 +1,371 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vector
+
+import (
+	"math"
+	"sort"
+)
+
+// Union returns a new path filling the region filled by p or other.
+//
+// The regions of p and other are determined by the non-zero rule, and every subpath is treated as closed,
+// in the same way as filling.
+// Self-intersecting subpaths and holes are supported.
+//
+// The returned path consists of closed subpaths, where outer contours and holes have the opposite directions.
+// The returned path can be filled with either FillRuleNonZero or FillRuleEvenOdd.
+// As curves are approximated with line segments when they are added, the returned path consists of line segments.
+//
+// p and other are not modified.
+func (p *Path) Union(other *Path) *Path {
+	return booleanOp(p, other, func(inP, inOther bool) bool {
+		return inP || inOther
+	})
+}
+
+// Intersect returns a new path filling the region filled by both p and other.
+//
+// See Union for the details of the regions and the returned path.
+func (p *Path) Intersect(other *Path) *Path {
+	return booleanOp(p, other, func(inP, inOther bool) bool {
+		return inP && inOther
+	})
+}
+
+// Subtract returns a new path filling the region filled by p but not by other.
+//
+// See Union for the details of the regions and the returned path.
+func (p *Path) Subtract(other *Path) *Path {
+	return booleanOp(p, other, func(inP, inOther bool) bool {
+		return inP && !inOther
+	})
+}
+
+// booleanGridSize is the grid size that the vertices are snapped to.
+// Snapping makes intersection points calculated from different pairs of edges identical.
+const booleanGridSize = 1.0 / 1024
+
+type bpoint struct {
+	x float64
+	y float64
+}
+
+func snapPoint(x, y float64) bpoint {
+	return bpoint{
+		x: math.Round(x/booleanGridSize) * booleanGridSize,
+		y: math.Round(y/booleanGridSize) * booleanGridSize,
+	}
+}
+
+type bedge struct {
+	p0 bpoint
+	p1 bpoint
+
+	// owner is 0 for the first path and 1 for the second path.
+	owner int
+}
+
+// key returns a key that is the same for the edges with the same end points regardless of their directions.
+func (e *bedge) key() [2]bpoint {
+	if e.p0.x < e.p1.x || (e.p0.x == e.p1.x && e.p0.y < e.p1.y) {
+		return [2]bpoint{e.p0, e.p1}
+	}
+	return [2]bpoint{e.p1, e.p0}
+}
+
+// appendPolygonEdges appends the edges of the path's subpaths as closed polygons.
+func appendPolygonEdges(edges []bedge, path *Path, owner int) []bedge {
+	if path == nil {
+		return edges
+	}
+	for _, s := range path.subpaths {
+		if len(s.points) < 2 {
+			continue
+		}
+		for i := range s.points {
+			p0 := s.points[i]
+			p1 := s.points[(i+1)%len(s.points)]
+			// A closed subpath's last point is the same as the first point.
+			if p0 == p1 {
+				continue
+			}
+			edges = append(edges, bedge{
+				p0:    bpoint{x: float64(p0.x), y: float64(p0.y)},
+				p1:    bpoint{x: float64(p1.x), y: float64(p1.y)},
+				owner: owner,
+			})
+		}
+	}
+	return edges
+}
+
+type splitPoint struct {
+	t  float64
+	pt bpoint
+}
+
+// splitEdges splits the edges at their intersections so that the edges don't cross each other except at their end points.
+// The end points are snapped to the grid.
+func splitEdges(edges []bedge) []bedge {
+	splits := make([][]splitPoint, len(edges))
+	for i := range edges {
+		a := &edges[i]
+		for j := i + 1; j < len(edges); j++ {
+			b := &edges[j]
+			addIntersections(a, b, &splits[i], &splits[j])
+		}
+	}
+
+	var result []bedge
+	for i := range edges {
+		e := &edges[i]
+		ss := splits[i]
+		sort.Slice(ss, func(a, b int) bool {
+			return ss[a].t < ss[b].t
+		})
+		prev := snapPoint(e.p0.x, e.p0.y)
+		for _, s := range ss {
+			pt := snapPoint(s.pt.x, s.pt.y)
+			if pt == prev {
+				continue
+			}
+			result = append(result, bedge{p0: prev, p1: pt, owner: e.owner})
+			prev = pt
+		}
+		if last := snapPoint(e.p1.x, e.p1.y); last != prev {
+			result = append(result, bedge{p0: prev, p1: last, owner: e.owner})
+		}
+	}
+	return result
+}
+
+// addIntersections adds the intersection points of the edges a and b to the split points of each edge.
+func addIntersections(a, b *bedge, splitsA, splitsB *[]splitPoint) {
+	const eps = 1e-9
+
+	rx, ry := a.p1.x-a.p0.x, a.p1.y-a.p0.y
+	sx, sy := b.p1.x-b.p0.x, b.p1.y-b.p0.y
+	qpx, qpy := b.p0.x-a.p0.x, b.p0.y-a.p0.y
+
+	d := rx*sy - ry*sx
+	if math.Abs(d) <= eps*math.Hypot(rx, ry)*math.Hypot(sx, sy) {
+		// The edges are parallel. Split the edges at the other's end points if they are collinear.
+		if math.Abs(qpx*ry-qpy*rx) > eps*math.Max(1, rx*rx+ry*ry) {
+			return
+		}
+		addPointOnEdge(a, b.p0, splitsA)
+		addPointOnEdge(a, b.p1, splitsA)
+		addPointOnEdge(b, a.p0, splitsB)
+		addPointOnEdge(b, a.p1, splitsB)
+		return
+	}
+
+	t := (qpx*sy - qpy*sx) / d
+	u := (qpx*ry - qpy*rx) / d
+	if t < -eps || t > 1+eps || u < -eps || u > 1+eps {
+		return
+	}
+
+	// Use the exact end points when the intersection is at an end point, so that T-junctions are connected.
+	var pt bpoint
+	switch {
+	case u <= eps:
+		pt = b.p0
+	case u >= 1-eps:
+		pt = b.p1
+	case t <= eps:
+		pt = a.p0
+	case t >= 1-eps:
+		pt = a.p1
+	default:
+		pt = bpoint{x: a.p0.x + t*rx, y: a.p0.y + t*ry}
+	}
+	if t > eps && t < 1-eps {
+		*splitsA = append(*splitsA, splitPoint{t: t, pt: pt})
+	}
+	if u > eps && u < 1-eps {
+		*splitsB = append(*splitsB, splitPoint{t: u, pt: pt})
+	}
+}
+
+// addPointOnEdge adds pt to the split points of e if pt is strictly inside of e.
+func addPointOnEdge(e *bedge, pt bpoint, splits *[]splitPoint) {
+	const eps = 1e-9
+
+	rx, ry := e.p1.x-e.p0.x, e.p1.y-e.p0.y
+	l := rx*rx + ry*ry
+	if l == 0 {
+		return
+	}
+	t := ((pt.x-e.p0.x)*rx + (pt.y-e.p0.y)*ry) / l
+	if t <= eps || t >= 1-eps {
+		return
+	}
+	*splits = append(*splits, splitPoint{t: t, pt: pt})
+}
+
+// windingContribution returns the contribution of the edge e to the winding number at (x, y),
+// counted with a ray from (x, y) to the positive X direction.
+//
+// If swapXY is true, X and Y are swapped, i.e. the ray is to the positive Y direction.
+func windingContribution(e *bedge, x, y float64, swapXY bool) int {
+	x0, y0, x1, y1 := e.p0.x, e.p0.y, e.p1.x, e.p1.y
+	if swapXY {
+		x0, y0, x1, y1 = y0, x0, y1, x1
+		x, y = y, x
+	}
+	if y0 <= y && y < y1 {
+		if x0+(y-y0)/(y1-y0)*(x1-x0) > x {
+			return 1
+		}
+		return 0
+	}
+	if y1 <= y && y < y0 {
+		if x0+(y-y0)/(y1-y0)*(x1-x0) > x {
+			return -1
+		}
+		return 0
+	}
+	return 0
+}
+
+// edgeDirection returns the winding number change of the edge when a point crosses the edge
+// from the positive side to the negative side of the ray axis.
+func edgeDirection(e *bedge, swapXY bool) int {
+	y0, y1 := e.p0.y, e.p1.y
+	if swapXY {
+		y0, y1 = e.p0.x, e.p1.x
+	}
+	if y0 < y1 {
+		return 1
+	}
+	return -1
+}
+
+func booleanOp(a, b *Path, op func(inA, inB bool) bool) *Path {
+	var edges []bedge
+	edges = appendPolygonEdges(edges, a, 0)
+	edges = appendPolygonEdges(edges, b, 1)
+	edges = splitEdges(edges)
+
+	// Group the edges with the same end points. Such edges overlap each other.
+	groups := map[[2]bpoint][]int{}
+	var keys [][2]bpoint
+	for i := range edges {
+		k := edges[i].key()
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], i)
+	}
+
+	// Classify each group of the edges.
+	// A group is on the boundary of the result when the insideness of the result differs between the two sides.
+	var boundary []bedge
+	for _, k := range keys {
+		p0, p1 := k[0], k[1]
+		mx, my := (p0.x+p1.x)/2, (p0.y+p1.y)/2
+
+		// Cast a ray to the direction that is not parallel to the edge.
+		swapXY := math.Abs(p1.x-p0.x) > math.Abs(p1.y-p0.y)
+
+		// Count the winding numbers at the positive side of the edge, excluding the edges in the group.
+		var windings [2]int
+		for i := range edges {
+			e := &edges[i]
+			if e.key() == k {
+				continue
+			}
+			windings[e.owner] += windingContribution(e, mx, my, swapXY)
+		}
+
+		// The winding numbers at the negative side include the edges in the group.
+		negWindings := windings
+		for _, i := range groups[k] {
+			e := &edges[i]
+			negWindings[e.owner] += edgeDirection(e, swapXY)
+		}
+
+		inPos := op(windings[0] != 0, windings[1] != 0)
+		inNeg := op(negWindings[0] != 0, negWindings[1] != 0)
+		if inPos == inNeg {
+			continue
+		}
+
+		// Orient the edge so that the inside is on the left side (in the coordinate system where Y is up).
+		dx, dy := 1.0, 0.0
+		if swapXY {
+			dx, dy = 0, 1
+		}
+		if inNeg {
+			dx, dy = -dx, -dy
+		}
+		if (p1.x-p0.x)*dy-(p1.y-p0.y)*dx > 0 {
+			boundary = append(boundary, bedge{p0: p0, p1: p1})
+		} else {
+			boundary = append(boundary, bedge{p0: p1, p1: p0})
+		}
+	}
+
+	return chainEdges(boundary)
+}
+
+// chainEdges connects the directed edges into closed subpaths.
+func chainEdges(edges []bedge) *Path {
+	outgoings := map[bpoint][]int{}
+	for i := range edges {
+		outgoings[edges[i].p0] = append(outgoings[edges[i].p0], i)
+	}
+	used := make([]bool, len(edges))
+
+	var path Path
+	for i := range edges {
+		if used[i] {
+			continue
+		}
+		used[i] = true
+		start := edges[i].p0
+		s := &subpath{
+			points: []point{
+				{x: float32(start.x), y: float32(start.y)},
+			},
+		}
+		current := edges[i].p1
+		for current != start {
+			s.points = append(s.points, point{x: float32(current.x), y: float32(current.y)})
+			next := -1
+			for _, j := range outgoings[current] {
+				if !used[j] {
+					next = j
+					break
+				}
+			}
+			// A dead end can happen only due to numerical errors. Close the subpath there.
+			if next == -1 {
+				break
+			}
+			used[next] = true
+			current = edges[next].p1
+		}
+		if len(s.points) < 3 {
+			continue
+		}
+		s.points = append(s.points, s.points[0])
+		s.closed = true
+		path.subpaths = append(path.subpaths, s)
+	}
+	return &path
+}
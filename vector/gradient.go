@@ -0,0 +1,221 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vector
+
+import (
+	"fmt"
+	"image/color"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// MaxGradientStops is the maximum number of the stops of a gradient.
+const MaxGradientStops = 16
+
+// The gradient position is calculated from srcPos, which is the same as the path's position.
+var gradientShaderSrc = fmt.Sprintf(`//kage:unit pixels
+
+package main
+
+const maxStops = %d
+
+var Start vec2
+var End vec2
+var Radius float
+var Radial int
+var Repeat int
+var StopCount int
+var Offsets [maxStops]float
+var Colors [maxStops]vec4
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	var t float
+	if Radial != 0 {
+		t = length(srcPos-Start) / Radius
+	} else {
+		d := End - Start
+		t = dot(srcPos-Start, d) / dot(d, d)
+	}
+	if Repeat != 0 {
+		t = fract(t)
+	} else {
+		t = clamp(t, 0, 1)
+	}
+
+	c := Colors[0]
+	for i := 1; i < maxStops; i++ {
+		if i >= StopCount {
+			break
+		}
+		o0 := Offsets[i-1]
+		o1 := Offsets[i]
+		if t >= o0 {
+			c = mix(Colors[i-1], Colors[i], clamp((t-o0)/max(o1-o0, 1.0/65536), 0, 1))
+		}
+	}
+	return c * color
+}
+`, MaxGradientStops)
+
+var (
+	gradientShader     *ebiten.Shader
+	gradientShaderOnce sync.Once
+)
+
+func ensureGradientShader() *ebiten.Shader {
+	gradientShaderOnce.Do(func() {
+		s, err := ebiten.NewShader([]byte(gradientShaderSrc))
+		if err != nil {
+			panic(fmt.Sprintf("vector: compiling the gradient shader failed: %v", err))
+		}
+		gradientShader = s
+	})
+	return gradientShader
+}
+
+// GradientStop represents a color at a position of a gradient.
+type GradientStop struct {
+	// Offset is the position of the stop in [0, 1].
+	// 0 is the start of the gradient and 1 is the end of the gradient.
+	Offset float32
+
+	// Color is the color of the stop.
+	Color color.Color
+}
+
+// GradientSpreadMode represents how a gradient is rendered outside of [0, 1].
+type GradientSpreadMode int
+
+const (
+	// GradientSpreadModeClamp extends the colors of the first and the last stops.
+	GradientSpreadModeClamp GradientSpreadMode = iota
+
+	// GradientSpreadModeRepeat repeats the gradient.
+	GradientSpreadModeRepeat
+)
+
+// GradientOptions represents options for DrawFilledPathLinearGradient and DrawFilledPathRadialGradient.
+type GradientOptions struct {
+	// SpreadMode is the way in which how the gradient is rendered outside of [0, 1].
+	//
+	// The default (zero) value is GradientSpreadModeClamp.
+	SpreadMode GradientSpreadMode
+
+	// FillRule is the rule whether an overlapped region is rendered or not.
+	//
+	// The default (zero) value is FillRuleNonZero.
+	FillRule FillRule
+
+	// AntiAlias indicates whether the rendering uses anti-alias or not.
+	//
+	// The default (zero) value is false.
+	AntiAlias bool
+}
+
+// DrawFilledPathLinearGradient fills the specified path with a linear gradient.
+// The gradient starts at (x0, y0) and ends at (x1, y1), in the same coordinate system as the path.
+// The color is constant along each line perpendicular to the gradient vector.
+//
+// stops must be sorted by their offsets. An offset less than the previous stop's offset is treated as the previous one,
+// which makes a hard color transition.
+// The colors are interpolated in the premultiplied-alpha space.
+// If stops is empty, nothing is rendered.
+// If stops has more than MaxGradientStops stops, DrawFilledPathLinearGradient panics.
+//
+// If (x0, y0) and (x1, y1) are the same, the path is filled with the last stop's color.
+//
+// options can be nil. In this case, the default options are used.
+func DrawFilledPathLinearGradient(dst *ebiten.Image, path *Path, x0, y0, x1, y1 float32, stops []GradientStop, options *GradientOptions) {
+	op := &ebiten.DrawTrianglesShaderOptions{}
+	op.Uniforms = map[string]any{
+		"Start": []float32{x0, y0},
+		"End":   []float32{x1, y1},
+	}
+	drawFilledPathGradient(dst, path, stops, options, op, x0 == x1 && y0 == y1)
+}
+
+// DrawFilledPathRadialGradient fills the specified path with a radial gradient.
+// The gradient starts at the center (cx, cy) and ends at the circle of the radius, in the same coordinate system as the path.
+//
+// See DrawFilledPathLinearGradient for the details of stops.
+//
+// If radius is 0 or less, the path is filled with the last stop's color.
+//
+// options can be nil. In this case, the default options are used.
+func DrawFilledPathRadialGradient(dst *ebiten.Image, path *Path, cx, cy, radius float32, stops []GradientStop, options *GradientOptions) {
+	op := &ebiten.DrawTrianglesShaderOptions{}
+	op.Uniforms = map[string]any{
+		"Start":  []float32{cx, cy},
+		"Radius": radius,
+		"Radial": 1,
+	}
+	drawFilledPathGradient(dst, path, stops, options, op, radius <= 0)
+}
+
+func drawFilledPathGradient(dst *ebiten.Image, path *Path, stops []GradientStop, options *GradientOptions, op *ebiten.DrawTrianglesShaderOptions, degenerate bool) {
+	if len(stops) > MaxGradientStops {
+		panic(fmt.Sprintf("vector: the number of gradient stops must be %d or less but %d", MaxGradientStops, len(stops)))
+	}
+	if len(stops) == 0 {
+		return
+	}
+	if options == nil {
+		options = &GradientOptions{}
+	}
+
+	if degenerate {
+		DrawFilledPath(dst, path, stops[len(stops)-1].Color, options.AntiAlias, options.FillRule)
+		return
+	}
+
+	offsets := make([]float32, MaxGradientStops)
+	colors := make([]float32, 4*MaxGradientStops)
+	for i, s := range stops {
+		o := s.Offset
+		if o < 0 {
+			o = 0
+		}
+		if o > 1 {
+			o = 1
+		}
+		if i > 0 && o < offsets[i-1] {
+			o = offsets[i-1]
+		}
+		offsets[i] = o
+
+		r, g, b, a := s.Color.RGBA()
+		colors[4*i] = float32(r) / 0xffff
+		colors[4*i+1] = float32(g) / 0xffff
+		colors[4*i+2] = float32(b) / 0xffff
+		colors[4*i+3] = float32(a) / 0xffff
+	}
+	op.Uniforms["StopCount"] = len(stops)
+	op.Uniforms["Offsets"] = offsets
+	op.Uniforms["Colors"] = colors
+	if options.SpreadMode == GradientSpreadModeRepeat {
+		op.Uniforms["Repeat"] = 1
+	}
+	op.FillRule = options.FillRule.ebitenFillRule()
+	op.AntiAlias = options.AntiAlias
+
+	vs, is := path.AppendVerticesAndIndicesForFilling(nil, nil)
+	for i := range vs {
+		// The shader calculates the gradient position from srcPos.
+		vs[i].SrcX = vs[i].DstX
+		vs[i].SrcY = vs[i].DstY
+	}
+	dst.DrawTrianglesShader(vs, is, ensureGradientShader(), op)
+}
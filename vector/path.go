@@ -379,6 +379,64 @@ func (p *Path) Arc(x, y, radius, startAngle, endAngle float32, dir Direction) {
 	p.CubicTo(cx0, cy0, cx1, cy1, x1, y1)
 }
 
+// Ellipse adds an elliptical arc to the path.
+// (x, y) is the center of the ellipse.
+// radiusX and radiusY are the radii of the ellipse's axes, and rotation is the rotation of the ellipse in radians.
+// startAngle and endAngle are the angles on the ellipse before the rotation is applied, in radians.
+//
+// If the angle from startAngle to endAngle in the given direction is 2π or more, Ellipse adds the whole ellipse.
+//
+// As well as Arc, Ellipse adds a line from the current position to the start of the arc.
+func (p *Path) Ellipse(x, y, radiusX, radiusY, rotation, startAngle, endAngle float32, dir Direction) {
+	// Adjust the angles.
+	var da float64
+	if dir == Clockwise {
+		for startAngle > endAngle {
+			endAngle += 2 * math.Pi
+		}
+		da = float64(endAngle - startAngle)
+	} else {
+		for startAngle < endAngle {
+			startAngle += 2 * math.Pi
+		}
+		da = float64(endAngle - startAngle)
+	}
+	if da >= 2*math.Pi {
+		da = 2 * math.Pi
+	} else if da <= -2*math.Pi {
+		da = -2 * math.Pi
+	}
+
+	sinr, cosr := math.Sincos(float64(rotation))
+	pos := func(ux, uy float64) (float32, float32) {
+		ux *= float64(radiusX)
+		uy *= float64(radiusY)
+		return x + float32(ux*cosr-uy*sinr), y + float32(ux*sinr+uy*cosr)
+	}
+
+	// Split the arc into curves whose angles are π/2 at most, so that each curve is well approximated.
+	n := int(math.Ceil(math.Abs(da) / (math.Pi / 2)))
+	if n == 0 {
+		n = 1
+	}
+	delta := da / float64(n)
+	// See https://docs.microsoft.com/en-us/xamarin/xamarin-forms/user-interface/graphics/skiasharp/curves/beziers.
+	l := math.Tan(delta/4) * 4 / 3
+
+	a0 := float64(startAngle)
+	sin0, cos0 := math.Sincos(a0)
+	p.LineTo(pos(cos0, sin0))
+	for i := 0; i < n; i++ {
+		a1 := float64(startAngle) + delta*float64(i+1)
+		sin1, cos1 := math.Sincos(a1)
+		cx0, cy0 := pos(cos0-l*sin0, sin0+l*cos0)
+		cx1, cy1 := pos(cos1+l*sin1, sin1-l*cos1)
+		x1, y1 := pos(cos1, sin1)
+		p.CubicTo(cx0, cy0, cx1, cy1, x1, y1)
+		sin0, cos0 = sin1, cos1
+	}
+}
+
 // Close adds a new line from the last position of the current subpath to the first position of the current subpath,
 // and marks the current subpath closed.
 // Following operations for this path will start with a new subpath.
@@ -390,6 +448,119 @@ func (p *Path) Close() {
 	subpath.close()
 }
 
+// Length returns the total length of the path.
+//
+// Curves are measured along the line segments approximating them.
+// The gaps between subpaths are not counted.
+func (p *Path) Length() float32 {
+	var l float32
+	for _, s := range p.subpaths {
+		for i := 0; i < len(s.points)-1; i++ {
+			l += distance(s.points[i], s.points[i+1])
+		}
+	}
+	return l
+}
+
+// PointAtLength returns the position and the tangent angle at the given distance along the path from its start.
+//
+// The distance is measured along the path, in the same way as Length.
+// The angle is in radians, and is measured from the positive X axis toward the positive Y axis.
+//
+// If length is out of the range [0, Length()], or the path doesn't have any segments, ok is false.
+func (p *Path) PointAtLength(length float32) (x, y, angle float32, ok bool) {
+	if length < 0 {
+		return 0, 0, 0, false
+	}
+
+	var l float32
+	var last point
+	var lastAngle float32
+	var found bool
+	for _, s := range p.subpaths {
+		for i := 0; i < len(s.points)-1; i++ {
+			p0, p1 := s.points[i], s.points[i+1]
+			d := distance(p0, p1)
+			if d == 0 {
+				continue
+			}
+			a := float32(math.Atan2(float64(p1.y-p0.y), float64(p1.x-p0.x)))
+			if length <= l+d {
+				t := (length - l) / d
+				return p0.x + (p1.x-p0.x)*t, p0.y + (p1.y-p0.y)*t, a, true
+			}
+			l += d
+			last = p1
+			lastAngle = a
+			found = true
+		}
+	}
+
+	// Allow a small error at the end of the path.
+	if found && length-l < 1e-3 {
+		return last.x, last.y, lastAngle, true
+	}
+	return 0, 0, 0, false
+}
+
+// Bounds returns the bounding box of the path.
+//
+// The bounds are calculated from the curves themselves, not their control points.
+// As curves are approximated with line segments when they are added, the bounds might have a small error.
+//
+// If the path doesn't have any points, Bounds returns zeros.
+func (p *Path) Bounds() (minX, minY, maxX, maxY float32) {
+	minX = float32(math.Inf(1))
+	minY = float32(math.Inf(1))
+	maxX = float32(math.Inf(-1))
+	maxY = float32(math.Inf(-1))
+	var found bool
+	for _, s := range p.subpaths {
+		for _, pt := range s.points {
+			if minX > pt.x {
+				minX = pt.x
+			}
+			if minY > pt.y {
+				minY = pt.y
+			}
+			if maxX < pt.x {
+				maxX = pt.x
+			}
+			if maxY < pt.y {
+				maxY = pt.y
+			}
+			found = true
+		}
+	}
+	if !found {
+		return 0, 0, 0, 0
+	}
+	return
+}
+
+// Transform applies geoM to all the points of the path.
+//
+// As curves are approximated with line segments when they are added, Transform transforms the line segments.
+// An affine transformation maps a curve to the same curve of the transformed control points,
+// so the result is the same as adding the transformed curves, except for the approximation's precision.
+// If the path is scaled up a lot, the segments might become visible.
+// In this case, add the curves with the transformed coordinates instead.
+func (p *Path) Transform(geoM ebiten.GeoM) {
+	for _, s := range p.subpaths {
+		for i, pt := range s.points {
+			x, y := geoM.Apply(float64(pt.x), float64(pt.y))
+			s.points[i] = point{
+				x: float32(x),
+				y: float32(y),
+			}
+		}
+	}
+}
+
+func distance(p0, p1 point) float32 {
+	return float32(math.Hypot(float64(p1.x-p0.x), float64(p1.y-p0.y)))
+}
+
 // AppendVerticesAndIndicesForFilling appends vertices and indices to fill this path and returns them.
 // AppendVerticesAndIndicesForFilling works in a similar way to the built-in append function.
 // If the arguments are nils, AppendVerticesAndIndicesForFilling returns new slices.
@@ -398,6 +569,8 @@ func (p *Path) Close() {
 //
 // The returned values are intended to be passed to DrawTriangles or DrawTrianglesShader with the fill rule NonZero or EvenOdd
 // in order to render a complex polygon like a concave polygon, a polygon with holes, or a self-intersecting polygon.
+// The returned values don't depend on a fill rule. Specify the fill rule at DrawTriangles or DrawTrianglesShader,
+// or use DrawFilledPath with FillRule.
 //
 // The returned vertices and indices should be rendered with a solid (non-transparent) color with the default Blend (source-over).
 // Otherwise, there is no guarantee about the rendering result.
@@ -471,6 +644,36 @@ type StrokeOptions struct {
 	//
 	// The default (zero) value is 0.
 	MiterLimit float32
+
+	// Dash is the dash pattern of the stroke.
+	// The values are the lengths of dashes and gaps in pixels alternately, starting with a dash.
+	// If the number of the values is odd, the values are repeated to make the number even, e.g. [5] is the same as [5, 5].
+	// For details, see https://developer.mozilla.org/en-US/docs/Web/SVG/Attribute/stroke-dasharray.
+	//
+	// A zero-length dash is rendered as a dot with LineCapRound or LineCapSquare, and is not rendered with LineCapButt.
+	// If Dash has a negative value or all the values are zero, the stroke is solid.
+	//
+	// The default (zero) value is nil, which means a solid stroke.
+	Dash []float32
+
+	// DashPhase is the offset of the dash pattern in pixels at the start of each subpath.
+	// For details, see https://developer.mozilla.org/en-US/docs/Web/SVG/Attribute/stroke-dashoffset.
+	//
+	// The default (zero) value is 0.
+	DashPhase float32
+
+	// WidthFunc is a function to specify a variable stroke width in pixels.
+	// t is the distance along each subpath from its start, normalized into [0, 1].
+	// If a dash pattern is specified, t is normalized for each dash.
+	//
+	// The width is sampled at each point of the segments approximating the path,
+	// and changes linearly between the points. The line joins and the line caps use the width at their positions.
+	// For a closed subpath, WidthFunc(0) and WidthFunc(1) should be the same to connect the stroke smoothly.
+	//
+	// If WidthFunc is not nil, Width is ignored.
+	//
+	// The default (zero) value is nil, which means the constant Width is used.
+	WidthFunc func(t float32) float32
 }
 
 // AppendVerticesAndIndicesForStroke appends vertices and indices to render a stroke of this path and returns them.
@@ -486,11 +689,22 @@ func (p *Path) AppendVerticesAndIndicesForStroke(vertices []ebiten.Vertex, indic
 		return vertices, indices
 	}
 
-	for _, subpath := range p.subpaths {
+	subpaths := p.subpaths
+	if pattern := dashPattern(op.Dash); pattern != nil {
+		subpaths = nil
+		for _, s := range p.subpaths {
+			subpaths = s.appendDashes(subpaths, pattern, op.DashPhase, op.LineCap != LineCapButt)
+		}
+	}
+
+	for _, subpath := range subpaths {
 		if subpath.pointCount() < 2 {
 			continue
 		}
 
+		widths := subpath.strokeWidths(op)
+
+		// rects are quadrangles for the segments. If the width is variable, they are not rectangles but trapezoids.
 		var rects [][4]point
 		for i := 0; i < subpath.pointCount()-1; i++ {
 			pt := subpath.points[i]
@@ -499,8 +713,10 @@ func (p *Path) AppendVerticesAndIndicesForStroke(vertices []ebiten.Vertex, indic
 			dx := nextPt.x - pt.x
 			dy := nextPt.y - pt.y
 			dist := float32(math.Sqrt(float64(dx*dx + dy*dy)))
-			extX := (dy) * op.Width / 2 / dist
-			extY := (-dx) * op.Width / 2 / dist
+			extX := (dy) * widths[i] / 2 / dist
+			extY := (-dx) * widths[i] / 2 / dist
+			nextExtX := (dy) * widths[i+1] / 2 / dist
+			nextExtY := (-dx) * widths[i+1] / 2 / dist
 
 			rects = append(rects, [4]point{
 				{
@@ -508,16 +724,16 @@ func (p *Path) AppendVerticesAndIndicesForStroke(vertices []ebiten.Vertex, indic
 					y: pt.y + extY,
 				},
 				{
-					x: nextPt.x + extX,
-					y: nextPt.y + extY,
+					x: nextPt.x + nextExtX,
+					y: nextPt.y + nextExtY,
 				},
 				{
 					x: pt.x - extX,
 					y: pt.y - extY,
 				},
 				{
-					x: nextPt.x - extX,
-					y: nextPt.y - extY,
+					x: nextPt.x - nextExtX,
+					y: nextPt.y - nextExtY,
 				},
 			})
 		}
@@ -605,9 +821,9 @@ func (p *Path) AppendVerticesAndIndicesForStroke(vertices []ebiten.Vertex, indic
 				var arc Path
 				arc.MoveTo(c.x, c.y)
 				if da < math.Pi {
-					arc.Arc(c.x, c.y, op.Width/2, a0, a1, Clockwise)
+					arc.Arc(c.x, c.y, widths[i+1]/2, a0, a1, Clockwise)
 				} else {
-					arc.Arc(c.x, c.y, op.Width/2, a0+math.Pi, a1+math.Pi, CounterClockwise)
+					arc.Arc(c.x, c.y, widths[i+1]/2, a0+math.Pi, a1+math.Pi, CounterClockwise)
 				}
 				vertices, indices = arc.AppendVerticesAndIndicesForFilling(vertices, indices)
 			}
@@ -636,7 +852,7 @@ func (p *Path) AppendVerticesAndIndicesForStroke(vertices []ebiten.Vertex, indic
 				a := float32(math.Atan2(float64(startR[0].y-startR[2].y), float64(startR[0].x-startR[2].x)))
 				var arc Path
 				arc.MoveTo(startR[0].x, startR[0].y)
-				arc.Arc(c.x, c.y, op.Width/2, a, a+math.Pi, CounterClockwise)
+				arc.Arc(c.x, c.y, widths[0]/2, a, a+math.Pi, CounterClockwise)
 				vertices, indices = arc.AppendVerticesAndIndicesForFilling(vertices, indices)
 			}
 			{
@@ -647,7 +863,7 @@ func (p *Path) AppendVerticesAndIndicesForStroke(vertices []ebiten.Vertex, indic
 				a := float32(math.Atan2(float64(endR[1].y-endR[3].y), float64(endR[1].x-endR[3].x)))
 				var arc Path
 				arc.MoveTo(endR[1].x, endR[1].y)
-				arc.Arc(c.x, c.y, op.Width/2, a, a+math.Pi, Clockwise)
+				arc.Arc(c.x, c.y, widths[len(widths)-1]/2, a, a+math.Pi, Clockwise)
 				vertices, indices = arc.AppendVerticesAndIndicesForFilling(vertices, indices)
 			}
 
@@ -656,7 +872,7 @@ func (p *Path) AppendVerticesAndIndicesForStroke(vertices []ebiten.Vertex, indic
 			{
 				a := math.Atan2(float64(startR[0].y-startR[1].y), float64(startR[0].x-startR[1].x))
 				s, c := math.Sincos(a)
-				dx, dy := float32(c)*op.Width/2, float32(s)*op.Width/2
+				dx, dy := float32(c)*widths[0]/2, float32(s)*widths[0]/2
 
 				var quad Path
 				quad.MoveTo(startR[0].x, startR[0].y)
@@ -668,7 +884,7 @@ func (p *Path) AppendVerticesAndIndicesForStroke(vertices []ebiten.Vertex, indic
 			{
 				a := math.Atan2(float64(endR[1].y-endR[0].y), float64(endR[1].x-endR[0].x))
 				s, c := math.Sincos(a)
-				dx, dy := float32(c)*op.Width/2, float32(s)*op.Width/2
+				dx, dy := float32(c)*widths[len(widths)-1]/2, float32(s)*widths[len(widths)-1]/2
 
 				var quad Path
 				quad.MoveTo(endR[1].x, endR[1].y)
@@ -682,3 +898,176 @@ func (p *Path) AppendVerticesAndIndicesForStroke(vertices []ebiten.Vertex, indic
 
 	return vertices, indices
 }
+
+// strokeWidths returns the stroke widths at the subpath's points.
+func (s *subpath) strokeWidths(op *StrokeOptions) []float32 {
+	widths := make([]float32, s.pointCount())
+	if op.WidthFunc == nil {
+		for i := range widths {
+			widths[i] = op.Width
+		}
+		return widths
+	}
+
+	var total float32
+	for i := 0; i < s.pointCount()-1; i++ {
+		total += distance(s.points[i], s.points[i+1])
+	}
+
+	var l float32
+	for i := range widths {
+		if i > 0 {
+			l += distance(s.points[i-1], s.points[i])
+		}
+		var t float32
+		if total > 0 {
+			t = l / total
+		}
+		if w := op.WidthFunc(t); w > 0 {
+			widths[i] = w
+		}
+	}
+	return widths
+}
+
+// dashPattern returns the normalized dash pattern, whose length is even.
+// dashPattern returns nil if the stroke should be solid.
+func dashPattern(dash []float32) []float32 {
+	if len(dash) == 0 {
+		return nil
+	}
+	var sum float32
+	for _, d := range dash {
+		if d < 0 {
+			return nil
+		}
+		sum += d
+	}
+	if sum == 0 {
+		return nil
+	}
+	if len(dash)%2 == 1 {
+		pattern := make([]float32, 0, len(dash)*2)
+		pattern = append(pattern, dash...)
+		pattern = append(pattern, dash...)
+		return pattern
+	}
+	return dash
+}
+
+// appendDashes splits the subpath into dashes with the given pattern and appends them to dashes.
+//
+// If keepZeroLength is true, a zero-length dash is appended as a very short subpath so that line caps are rendered as a dot.
+// If the subpath is closed and both the first and the last dashes touch the start point, they are joined as one dash.
+func (s *subpath) appendDashes(dashes []*subpath, pattern []float32, phase float32, keepZeroLength bool) []*subpath {
+	if s.pointCount() < 2 {
+		return dashes
+	}
+
+	var total float32
+	for _, d := range pattern {
+		total += d
+	}
+
+	// Find the initial position in the pattern.
+	phase = float32(math.Mod(float64(phase), float64(total)))
+	if phase < 0 {
+		phase += total
+	}
+	var idx int
+	for phase > 0 && phase >= pattern[idx] {
+		phase -= pattern[idx]
+		idx = (idx + 1) % len(pattern)
+	}
+	remaining := pattern[idx] - phase
+
+	startIndex := len(dashes)
+	// A zero-length dash at the start is not joined even if the subpath is closed.
+	startsWithDash := idx%2 == 0 && remaining > 0
+	var current *subpath
+
+	finish := func(dir point) {
+		if len(current.points) == 1 {
+			if !keepZeroLength {
+				current = nil
+				return
+			}
+			// Add a very short segment to determine the direction of the line caps.
+			const eps = 1e-3
+			pt := current.points[0]
+			current.points = append(current.points, point{x: pt.x + dir.x*eps, y: pt.y + dir.y*eps})
+		}
+		dashes = append(dashes, current)
+		current = nil
+	}
+
+	for i := 0; i < s.pointCount()-1; i++ {
+		p0, p1 := s.points[i], s.points[i+1]
+		d := distance(p0, p1)
+		if d == 0 {
+			continue
+		}
+		dir := point{x: (p1.x - p0.x) / d, y: (p1.y - p0.y) / d}
+		pos := func(t float32) point {
+			return point{x: p0.x + dir.x*t, y: p0.y + dir.y*t}
+		}
+
+		var t float32
+		for {
+			on := idx%2 == 0
+			step := d - t
+			if remaining < step {
+				step = remaining
+			}
+			// Start a new dash unless the dash would start at the end of the segment.
+			// A zero-length dash is an exception.
+			if on && current == nil && (step > 0 || remaining == 0) {
+				current = &subpath{
+					points: []point{pos(t)},
+				}
+			}
+			t += step
+			remaining -= step
+			if on && step > 0 {
+				current.appendPoint(pos(t))
+			}
+			if remaining > 0 {
+				break
+			}
+			if on {
+				finish(dir)
+			}
+			idx = (idx + 1) % len(pattern)
+			remaining = pattern[idx]
+		}
+	}
+
+	if current == nil {
+		return dashes
+	}
+
+	if s.closed && startsWithDash {
+		// The whole subpath is one dash.
+		if startIndex == len(dashes) {
+			current.closed = true
+			return append(dashes, current)
+		}
+		// Join the last dash and the first dash.
+		first := dashes[startIndex]
+		for _, pt := range first.points[1:] {
+			current.appendPoint(pt)
+		}
+		dashes[startIndex] = current
+		return dashes
+	}
+
+	lp := s.points[len(s.points)-1]
+	slp := s.points[len(s.points)-2]
+	d := distance(slp, lp)
+	var dir point
+	if d > 0 {
+		dir = point{x: (lp.x - slp.x) / d, y: (lp.y - slp.y) / d}
+	}
+	finish(dir)
+	return dashes
+}
@@ -0,0 +1,257 @@
+// Copyright 2023 The Ebitengine Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vector_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+func TestPathPointAtLength(t *testing.T) {
+	var p vector.Path
+	p.MoveTo(0, 0)
+	p.LineTo(10, 0)
+	p.LineTo(10, 10)
+
+	if got, want := p.Length(), float32(20); got != want {
+		t.Errorf("Length(): got: %v, want: %v", got, want)
+	}
+
+	testCases := []struct {
+		Length float32
+		X      float32
+		Y      float32
+		Angle  float32
+		OK     bool
+	}{
+		{Length: -1, OK: false},
+		{Length: 0, X: 0, Y: 0, Angle: 0, OK: true},
+		{Length: 5, X: 5, Y: 0, Angle: 0, OK: true},
+		{Length: 15, X: 10, Y: 5, Angle: math.Pi / 2, OK: true},
+		{Length: 20, X: 10, Y: 10, Angle: math.Pi / 2, OK: true},
+		{Length: 21, OK: false},
+	}
+	for _, tc := range testCases {
+		x, y, a, ok := p.PointAtLength(tc.Length)
+		if ok != tc.OK {
+			t.Errorf("PointAtLength(%v): ok: got: %v, want: %v", tc.Length, ok, tc.OK)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if x != tc.X || y != tc.Y || a != tc.Angle {
+			t.Errorf("PointAtLength(%v): got: (%v, %v, %v), want: (%v, %v, %v)", tc.Length, x, y, a, tc.X, tc.Y, tc.Angle)
+		}
+	}
+}
+
+func TestPathEllipse(t *testing.T) {
+	const (
+		cx = 100
+		cy = 50
+		rx = 80
+		ry = 40
+	)
+
+	for _, dir := range []vector.Direction{vector.Clockwise, vector.CounterClockwise} {
+		var p vector.Path
+		if dir == vector.Clockwise {
+			p.Ellipse(cx, cy, rx, ry, 0, 0, 2*math.Pi, dir)
+		} else {
+			p.Ellipse(cx, cy, rx, ry, 0, 2*math.Pi, 0, dir)
+		}
+
+		// Ramanujan's approximation of the perimeter of an ellipse.
+		want := math.Pi * (3*(rx+ry) - math.Sqrt((3*rx+ry)*(rx+3*ry)))
+		if got := float64(p.Length()); math.Abs(got-want) > want*0.01 {
+			t.Errorf("dir: %d, Length(): got: %v, want: %v", dir, got, want)
+		}
+
+		for l := float32(0); l < p.Length(); l += 10 {
+			x, y, _, ok := p.PointAtLength(l)
+			if !ok {
+				t.Errorf("dir: %d, PointAtLength(%v) must succeed but not", dir, l)
+				continue
+			}
+			dx, dy := float64(x-cx)/rx, float64(y-cy)/ry
+			if got := dx*dx + dy*dy; math.Abs(got-1) > 0.05 {
+				t.Errorf("dir: %d, PointAtLength(%v): (%v, %v) is not on the ellipse", dir, l, x, y)
+			}
+		}
+	}
+
+	// A rotated quarter ellipse.
+	var p vector.Path
+	p.Ellipse(0, 0, 20, 10, math.Pi/2, 0, math.Pi/2, vector.Clockwise)
+	x, y, _, ok := p.PointAtLength(0)
+	if !ok || math.Abs(float64(x)) > 1e-3 || math.Abs(float64(y-20)) > 1e-3 {
+		t.Errorf("PointAtLength(0): got: (%v, %v, %v), want: (0, 20, true)", x, y, ok)
+	}
+	x, y, _, ok = p.PointAtLength(p.Length())
+	if !ok || math.Abs(float64(x+10)) > 1e-3 || math.Abs(float64(y)) > 1e-3 {
+		t.Errorf("PointAtLength(Length()): got: (%v, %v, %v), want: (-10, 0, true)", x, y, ok)
+	}
+}
+
+func TestPathDash(t *testing.T) {
+	var p vector.Path
+	p.MoveTo(0, 0)
+	p.LineTo(100, 0)
+
+	// Use a solid line and a dot as references to count the dashes.
+	solid, _ := p.AppendVerticesAndIndicesForStroke(nil, nil, &vector.StrokeOptions{
+		Width: 2,
+	})
+	dot, _ := p.AppendVerticesAndIndicesForStroke(nil, nil, &vector.StrokeOptions{
+		Width:   2,
+		LineCap: vector.LineCapRound,
+		Dash:    []float32{0, 1000},
+	})
+
+	testCases := []struct {
+		Name      string
+		Dash      []float32
+		DashPhase float32
+		LineCap   vector.LineCap
+		Count     int
+	}{
+		{Name: "no dash", Dash: nil, Count: 1},
+		{Name: "regular", Dash: []float32{10, 10}, Count: 5},
+		{Name: "odd", Dash: []float32{10}, Count: 5},
+		{Name: "phase", Dash: []float32{10, 10}, DashPhase: 5, Count: 6},
+		{Name: "negative phase", Dash: []float32{10, 10}, DashPhase: -5, Count: 5},
+		{Name: "longer than path", Dash: []float32{200, 10}, Count: 1},
+		{Name: "zero-length butt", Dash: []float32{0, 10}, Count: 0},
+		{Name: "zero-length round", Dash: []float32{0, 10}, LineCap: vector.LineCapRound, Count: 11},
+		{Name: "negative", Dash: []float32{10, -10}, Count: 1},
+		{Name: "all zero", Dash: []float32{0, 0}, Count: 1},
+	}
+	for _, tc := range testCases {
+		vs, _ := p.AppendVerticesAndIndicesForStroke(nil, nil, &vector.StrokeOptions{
+			Width:     2,
+			LineCap:   tc.LineCap,
+			Dash:      tc.Dash,
+			DashPhase: tc.DashPhase,
+		})
+		got := len(vs) / len(solid)
+		if tc.LineCap != vector.LineCapButt {
+			got = len(vs) / len(dot)
+		}
+		if got != tc.Count {
+			t.Errorf("%s: the number of dashes: got: %d, want: %d", tc.Name, got, tc.Count)
+		}
+	}
+}
+
+func TestPathDashClosed(t *testing.T) {
+	var p vector.Path
+	p.MoveTo(0, 0)
+	p.LineTo(40, 0)
+	p.LineTo(40, 40)
+	p.LineTo(0, 40)
+	p.Close()
+
+	// The dashes are [0, 15), [30, 45), [60, 75), [90, 105), [120, 135), and [150, 160),
+	// where the last dash is joined to the first dash around the start point.
+	vs, _ := p.AppendVerticesAndIndicesForStroke(nil, nil, &vector.StrokeOptions{
+		Width: 2,
+		Dash:  []float32{15, 15},
+	})
+	// The dashes crossing a corner, [30, 45) and the joined one, have two segments and one join.
+	// Each segment has a rectangle of 4 vertices, and each join has 3 vertices as the miter limit is exceeded.
+	if got, want := len(vs), (2+1+1+1+2)*4+2*3; got != want {
+		t.Errorf("len(vertices): got: %d, want: %d", got, want)
+	}
+}
+
+func TestPathBounds(t *testing.T) {
+	var p vector.Path
+	if x0, y0, x1, y1 := p.Bounds(); x0 != 0 || y0 != 0 || x1 != 0 || y1 != 0 {
+		t.Errorf("Bounds() for an empty path: got: (%v, %v, %v, %v), want: (0, 0, 0, 0)", x0, y0, x1, y1)
+	}
+
+	// The control point (50, 100) is outside of the curve. The curve's peak is at (50, 50).
+	p.MoveTo(0, 0)
+	p.QuadTo(50, 100, 100, 0)
+	x0, y0, x1, y1 := p.Bounds()
+	if x0 != 0 || y0 != 0 || x1 != 100 {
+		t.Errorf("Bounds(): got: (%v, %v, %v, %v), want: (0, 0, 100, ~50)", x0, y0, x1, y1)
+	}
+	if math.Abs(float64(y1-50)) > 0.5 {
+		t.Errorf("Bounds(): maxY: got: %v, want: ~50", y1)
+	}
+
+	p.MoveTo(-10, 20)
+	if x0, _, _, _ := p.Bounds(); x0 != -10 {
+		t.Errorf("Bounds(): minX: got: %v, want: -10", x0)
+	}
+}
+
+func TestPathTransform(t *testing.T) {
+	var p vector.Path
+	p.MoveTo(0, 0)
+	p.LineTo(10, 0)
+	p.QuadTo(20, 10, 10, 20)
+	p.Close()
+	x0, y0, x1, y1 := p.Bounds()
+
+	var geoM ebiten.GeoM
+	geoM.Scale(2, 3)
+	geoM.Translate(5, -5)
+	p.Transform(geoM)
+
+	gotX0, gotY0, gotX1, gotY1 := p.Bounds()
+	wantX0, wantY0 := geoM.Apply(float64(x0), float64(y0))
+	wantX1, wantY1 := geoM.Apply(float64(x1), float64(y1))
+	if math.Abs(float64(gotX0)-wantX0) > 1e-3 || math.Abs(float64(gotY0)-wantY0) > 1e-3 || math.Abs(float64(gotX1)-wantX1) > 1e-3 || math.Abs(float64(gotY1)-wantY1) > 1e-3 {
+		t.Errorf("Bounds() after Transform: got: (%v, %v, %v, %v), want: (%v, %v, %v, %v)", gotX0, gotY0, gotX1, gotY1, wantX0, wantY0, wantX1, wantY1)
+	}
+}
+
+func TestPathStrokeWidthFunc(t *testing.T) {
+	var p vector.Path
+	p.MoveTo(0, 0)
+	p.LineTo(100, 0)
+
+	vs, _ := p.AppendVerticesAndIndicesForStroke(nil, nil, &vector.StrokeOptions{
+		Width: 100,
+		WidthFunc: func(t float32) float32 {
+			return 2 + 8*t
+		},
+	})
+	if got, want := len(vs), 4; got != want {
+		t.Fatalf("len(vertices): got: %d, want: %d", got, want)
+	}
+	for _, v := range vs {
+		var want float32 = 1
+		if v.DstX == 100 {
+			want = 5
+		}
+		if got := abs(v.DstY); got != want {
+			t.Errorf("vertex (%v, %v): |DstY|: got: %v, want: %v", v.DstX, v.DstY, got, want)
+		}
+	}
+}
+
+func abs(x float32) float32 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
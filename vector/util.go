@@ -15,9 +15,11 @@
 package vector
 
 import (
+	"fmt"
 	"image"
 	"image/color"
 	"math"
+	"sync"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
@@ -37,7 +39,7 @@ func init() {
 	whiteImage.WritePixels(pix)
 }
 
-func drawVerticesForUtil(dst *ebiten.Image, vs []ebiten.Vertex, is []uint16, clr color.Color, antialias bool) {
+func drawVerticesForUtil(dst *ebiten.Image, vs []ebiten.Vertex, is []uint16, clr color.Color, antialias bool, fillRule ebiten.FillRule) {
 	r, g, b, a := clr.RGBA()
 	for i := range vs {
 		vs[i].SrcX = 1
@@ -51,10 +53,12 @@ func drawVerticesForUtil(dst *ebiten.Image, vs []ebiten.Vertex, is []uint16, clr
 	op := &ebiten.DrawTrianglesOptions{}
 	op.ColorScaleMode = ebiten.ColorScaleModePremultipliedAlpha
 	op.AntiAlias = antialias
+	op.FillRule = fillRule
 	dst.DrawTriangles(vs, is, whiteSubImage, op)
 }
 
 // StrokeLine strokes a line (x0, y0)-(x1, y1) with the specified width and color.
+// To specify the line caps, use StrokeLineWithOptions.
 //
 // clr has be to be a solid (non-transparent) color.
 func StrokeLine(dst *ebiten.Image, x0, y0, x1, y1 float32, strokeWidth float32, clr color.Color, antialias bool) {
@@ -65,7 +69,43 @@ func StrokeLine(dst *ebiten.Image, x0, y0, x1, y1 float32, strokeWidth float32,
 	strokeOp.Width = strokeWidth
 	vs, is := path.AppendVerticesAndIndicesForStroke(nil, nil, strokeOp)
 
-	drawVerticesForUtil(dst, vs, is, clr, antialias)
+	drawVerticesForUtil(dst, vs, is, clr, antialias, ebiten.FillAll)
+}
+
+// StrokeLineWithOptions strokes a line (x0, y0)-(x1, y1) with the specified color and the stroke options.
+// The line's ends are rendered based on options.LineCap.
+//
+// If options is nil, nothing is rendered.
+//
+// clr has be to be a solid (non-transparent) color.
+func StrokeLineWithOptions(dst *ebiten.Image, x0, y0, x1, y1 float32, clr color.Color, antialias bool, options *StrokeOptions) {
+	StrokePolyline(dst, []float32{x0, y0, x1, y1}, clr, antialias, options)
+}
+
+// StrokePolyline strokes connected line segments with the specified color and the stroke options.
+// points is the coordinates of the vertices of the polyline in the order of x0, y0, x1, y1, and so on.
+// The polyline's ends are rendered based on options.LineCap, and the vertices are rendered based on options.LineJoin.
+//
+// If options is nil or points has less than 2 vertices, nothing is rendered.
+// If the length of points is odd, StrokePolyline panics.
+//
+// clr has be to be a solid (non-transparent) color.
+func StrokePolyline(dst *ebiten.Image, points []float32, clr color.Color, antialias bool, options *StrokeOptions) {
+	if len(points)%2 != 0 {
+		panic(fmt.Sprintf("vector: len(points) at StrokePolyline must be even but %d", len(points)))
+	}
+	if options == nil || len(points) < 4 {
+		return
+	}
+
+	var path Path
+	path.MoveTo(points[0], points[1])
+	for i := 2; i < len(points); i += 2 {
+		path.LineTo(points[i], points[i+1])
+	}
+	vs, is := path.AppendVerticesAndIndicesForStroke(nil, nil, options)
+
+	drawVerticesForUtil(dst, vs, is, clr, antialias, ebiten.FillAll)
 }
 
 // DrawFilledRect fills a rectangle with the specified width and color.
@@ -77,7 +117,7 @@ func DrawFilledRect(dst *ebiten.Image, x, y, width, height float32, clr color.Co
 	path.LineTo(x+width, y)
 	vs, is := path.AppendVerticesAndIndicesForFilling(nil, nil)
 
-	drawVerticesForUtil(dst, vs, is, clr, antialias)
+	drawVerticesForUtil(dst, vs, is, clr, antialias, ebiten.FillAll)
 }
 
 // StrokeRect strokes a rectangle with the specified width and color.
@@ -96,7 +136,7 @@ func StrokeRect(dst *ebiten.Image, x, y, width, height float32, strokeWidth floa
 	strokeOp.MiterLimit = 10
 	vs, is := path.AppendVerticesAndIndicesForStroke(nil, nil, strokeOp)
 
-	drawVerticesForUtil(dst, vs, is, clr, antialias)
+	drawVerticesForUtil(dst, vs, is, clr, antialias, ebiten.FillAll)
 }
 
 // DrawFilledCircle fills a circle with the specified center position (cx, cy), the radius (r), width and color.
@@ -105,7 +145,7 @@ func DrawFilledCircle(dst *ebiten.Image, cx, cy, r float32, clr color.Color, ant
 	path.Arc(cx, cy, r, 0, 2*math.Pi, Clockwise)
 	vs, is := path.AppendVerticesAndIndicesForFilling(nil, nil)
 
-	drawVerticesForUtil(dst, vs, is, clr, antialias)
+	drawVerticesForUtil(dst, vs, is, clr, antialias, ebiten.FillAll)
 }
 
 // StrokeCircle strokes a circle with the specified center position (cx, cy), the radius (r), width and color.
@@ -120,5 +160,255 @@ func StrokeCircle(dst *ebiten.Image, cx, cy, r float32, strokeWidth float32, clr
 	strokeOp.Width = strokeWidth
 	vs, is := path.AppendVerticesAndIndicesForStroke(nil, nil, strokeOp)
 
-	drawVerticesForUtil(dst, vs, is, clr, antialias)
+	drawVerticesForUtil(dst, vs, is, clr, antialias, ebiten.FillAll)
+}
+
+// RoundedRect returns a new closed path of a rectangle with rounded corners.
+// (x, y) is the upper-left position of the rectangle, and radius is the radius of all the corners.
+//
+// See RoundedRectWithRadii for the details.
+func RoundedRect(x, y, width, height, radius float32) *Path {
+	return RoundedRectWithRadii(x, y, width, height, radius, radius, radius, radius)
+}
+
+// RoundedRectWithRadii returns a new closed path of a rectangle with rounded corners of the specified radii.
+// (x, y) is the upper-left position of the rectangle.
+//
+// A negative radius is treated as 0, which makes a sharp corner.
+// If the sum of the radii at a side exceeds the length of the side, all the radii are scaled down proportionally
+// so that the corners don't overlap each other.
+//
+// If width or height is 0 or less, RoundedRectWithRadii returns an empty path.
+//
+// The returned path can be filled with DrawFilledPath or stroked with AppendVerticesAndIndicesForStroke.
+func RoundedRectWithRadii(x, y, width, height, topLeftRadius, topRightRadius, bottomRightRadius, bottomLeftRadius float32) *Path {
+	var path Path
+	if width <= 0 || height <= 0 {
+		return &path
+	}
+
+	tl := max32(topLeftRadius, 0)
+	tr := max32(topRightRadius, 0)
+	br := max32(bottomRightRadius, 0)
+	bl := max32(bottomLeftRadius, 0)
+
+	scale := float32(1)
+	for _, s := range [][2]float32{
+		{width, tl + tr},
+		{width, bl + br},
+		{height, tl + bl},
+		{height, tr + br},
+	} {
+		if s[1] > 0 && s[0]/s[1] < scale {
+			scale = s[0] / s[1]
+		}
+	}
+	tl *= scale
+	tr *= scale
+	br *= scale
+	bl *= scale
+
+	path.MoveTo(x+tl, y)
+	path.LineTo(x+width-tr, y)
+	if tr > 0 {
+		path.Arc(x+width-tr, y+tr, tr, -math.Pi/2, 0, Clockwise)
+	}
+	path.LineTo(x+width, y+height-br)
+	if br > 0 {
+		path.Arc(x+width-br, y+height-br, br, 0, math.Pi/2, Clockwise)
+	}
+	path.LineTo(x+bl, y+height)
+	if bl > 0 {
+		path.Arc(x+bl, y+height-bl, bl, math.Pi/2, math.Pi, Clockwise)
+	}
+	path.LineTo(x, y+tl)
+	if tl > 0 {
+		path.Arc(x+tl, y+tl, tl, math.Pi, 3*math.Pi/2, Clockwise)
+	}
+	path.Close()
+	return &path
+}
+
+func max32(a, b float32) float32 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// FillRule is the rule whether an overlapped region is rendered or not at DrawFilledPath.
+type FillRule int
+
+const (
+	// FillRuleNonZero means that triangles are rendered based on the non-zero rule.
+	// If and only if the number of overlaps is not 0, the region is rendered.
+	// This is the rule for TrueType and OpenType glyphs, so this works for paths from the text package.
+	FillRuleNonZero FillRule = iota
+
+	// FillRuleEvenOdd means that triangles are rendered based on the even-odd rule.
+	// If and only if the number of overlaps is odd, the region is rendered.
+	FillRuleEvenOdd
+)
+
+func (f FillRule) ebitenFillRule() ebiten.FillRule {
+	switch f {
+	case FillRuleNonZero:
+		return ebiten.NonZero
+	case FillRuleEvenOdd:
+		return ebiten.EvenOdd
+	default:
+		panic(fmt.Sprintf("vector: invalid fill rule: %d", f))
+	}
+}
+
+// DrawFilledPath fills the specified path with the specified color and the fill rule.
+//
+// A region overlapped by subpaths, like a hole of a glyph 'O' or a self-intersecting region, is rendered based on fillRule.
+//
+// To choose the quality of anti-aliasing, use DrawFilledPathWithOptions.
+func DrawFilledPath(dst *ebiten.Image, path *Path, clr color.Color, antialias bool, fillRule FillRule) {
+	vs, is := path.AppendVerticesAndIndicesForFilling(nil, nil)
+	drawVerticesForUtil(dst, vs, is, clr, antialias, fillRule.ebitenFillRule())
+}
+
+// DrawFilledPathOptions represents options for DrawFilledPathWithOptions.
+type DrawFilledPathOptions struct {
+	// FillRule is the rule whether an overlapped region is rendered or not.
+	//
+	// The default (zero) value is FillRuleNonZero.
+	FillRule FillRule
+
+	// AntiAliasSamples is the number of samples per pixel in each axis for anti-aliasing.
+	// A bigger value makes smoother edges, and a smaller value makes sharper edges.
+	//
+	// If AntiAliasSamples is 1 or less, the path is rendered without anti-aliasing.
+	// If AntiAliasSamples is 2, the result is the same as DrawFilledPath with antialias true.
+	// If AntiAliasSamples is more than 2, the path is rendered on an offscreen image whose size is AntiAliasSamples times as big as
+	// the path's bounds in each axis, and then the offscreen image is scaled down to dst.
+	// A big path is rendered in tiles so that the offscreen image doesn't exceed the maximum image size.
+	// AntiAliasSamples must be a power of two, i.e., 1, 2, 4, 8, or 16. Otherwise, DrawFilledPathWithOptions panics.
+	//
+	// The cost of rendering, especially the fill rate and the memory for the offscreen image, increases with the square of AntiAliasSamples.
+	// 4 is enough for most cases like small texts from text.AppendVectorPath.
+	//
+	// The default (zero) value is 0.
+	AntiAliasSamples int
+}
+
+// maxAntiAliasSamples is the maximum value for DrawFilledPathOptions.AntiAliasSamples.
+const maxAntiAliasSamples = 16
+
+// antiAliasBufferSize is the maximum size of antiAliasBuffers[0] in each axis.
+// This must be a multiple of maxAntiAliasSamples and must not exceed the maximum image size of any graphics library.
+const antiAliasBufferSize = 2048
+
+var (
+	// antiAliasBuffers are offscreen images to render a path with multiple samples.
+	// antiAliasBuffers[0] is for rendering a tile with the samples, and antiAliasBuffers[1] is half as big as it in each axis.
+	// The samples are averaged by scaling the image down by half repeatedly between the two images.
+	antiAliasBuffers [2]*ebiten.Image
+	antiAliasBufferM sync.Mutex
+)
+
+// DrawFilledPathWithOptions fills the specified path with the specified color and the options.
+//
+// options can be nil. In this case, the default options are used.
+func DrawFilledPathWithOptions(dst *ebiten.Image, path *Path, clr color.Color, options *DrawFilledPathOptions) {
+	if options == nil {
+		options = &DrawFilledPathOptions{}
+	}
+
+	n := options.AntiAliasSamples
+	if n < 1 {
+		n = 1
+	}
+	if n > maxAntiAliasSamples || n&(n-1) != 0 {
+		panic(fmt.Sprintf("vector: AntiAliasSamples must be a power of two up to %d but %d", maxAntiAliasSamples, options.AntiAliasSamples))
+	}
+
+	rule := options.FillRule.ebitenFillRule()
+	vs, is := path.AppendVerticesAndIndicesForFilling(nil, nil)
+	if n <= 2 {
+		drawVerticesForUtil(dst, vs, is, clr, n == 2, rule)
+		return
+	}
+
+	minX, minY, maxX, maxY := path.Bounds()
+	r := image.Rect(int(math.Floor(float64(minX))), int(math.Floor(float64(minY))), int(math.Ceil(float64(maxX))), int(math.Ceil(float64(maxY))))
+	r = r.Intersect(dst.Bounds())
+	if r.Empty() {
+		return
+	}
+
+	antiAliasBufferM.Lock()
+	defer antiAliasBufferM.Unlock()
+
+	// Render the path in tiles, so that the offscreen image doesn't exceed the maximum image size
+	// and its memory doesn't increase with the path's size.
+	tileSize := antiAliasBufferSize / n
+	w, h := r.Dx(), r.Dy()
+	if w > tileSize {
+		w = tileSize
+	}
+	if h > tileSize {
+		h = tileSize
+	}
+	for i := range antiAliasBuffers {
+		bw, bh := w*n>>i, h*n>>i
+		if antiAliasBuffers[i] != nil {
+			if s := antiAliasBuffers[i].Bounds().Size(); s.X < bw || s.Y < bh {
+				antiAliasBuffers[i].Dispose()
+				antiAliasBuffers[i] = nil
+			}
+		}
+		if antiAliasBuffers[i] == nil {
+			antiAliasBuffers[i] = ebiten.NewImageWithOptions(image.Rect(0, 0, bw, bh), &ebiten.NewImageOptions{
+				Unmanaged: true,
+			})
+		}
+	}
+
+	tileVs := make([]ebiten.Vertex, len(vs))
+	for y := r.Min.Y; y < r.Max.Y; y += tileSize {
+		for x := r.Min.X; x < r.Max.X; x += tileSize {
+			tile := image.Rect(x, y, x+tileSize, y+tileSize).Intersect(r)
+			drawAntiAliasedTile(dst, tile, vs, tileVs, is, clr, n, rule)
+		}
+	}
+}
+
+// drawAntiAliasedTile renders the region tile of the path with n samples per pixel in each axis.
+// vs are the vertices in dst's coordinates, and tileVs is a buffer to transform them.
+func drawAntiAliasedTile(dst *ebiten.Image, tile image.Rectangle, vs, tileVs []ebiten.Vertex, is []uint16, clr color.Color, n int, rule ebiten.FillRule) {
+	w, h := tile.Dx()*n, tile.Dy()*n
+	src := antiAliasBuffers[0].SubImage(image.Rect(0, 0, w, h)).(*ebiten.Image)
+	src.Clear()
+
+	copy(tileVs, vs)
+	for i := range tileVs {
+		tileVs[i].DstX = (tileVs[i].DstX - float32(tile.Min.X)) * float32(n)
+		tileVs[i].DstY = (tileVs[i].DstY - float32(tile.Min.Y)) * float32(n)
+	}
+	drawVerticesForUtil(src, tileVs, is, clr, false, rule)
+
+	// Scale the samples down by half repeatedly. Scaling down by exactly half with the linear filter averages 2x2 pixels.
+	// Mipmaps are not used, as they would be regenerated for the whole buffer for each tile.
+	op := &ebiten.DrawImageOptions{}
+	op.Filter = ebiten.FilterLinear
+	op.Mipmap = ebiten.MipmapModeDisabled
+	for i := 1; ; i++ {
+		w /= 2
+		h /= 2
+		op.GeoM.Reset()
+		op.GeoM.Scale(0.5, 0.5)
+		if w == tile.Dx() {
+			op.GeoM.Translate(float64(tile.Min.X), float64(tile.Min.Y))
+			dst.DrawImage(src, op)
+			return
+		}
+		next := antiAliasBuffers[i%2].SubImage(image.Rect(0, 0, w, h)).(*ebiten.Image)
+		next.Clear()
+		next.DrawImage(src, op)
+		src = next
+	}
 }
@@ -16,6 +16,7 @@ package vector_test
 
 import (
 	"image/color"
+	"math"
 	"testing"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -35,3 +36,358 @@ func TestLine0(t *testing.T) {
 		t.Errorf("got: %v, want: %v", got, want)
 	}
 }
+
+func TestDrawFilledPath(t *testing.T) {
+	// Two squares in the same direction. The inner square is a hole with the even-odd rule.
+	var p vector.Path
+	p.MoveTo(0, 0)
+	p.LineTo(16, 0)
+	p.LineTo(16, 16)
+	p.LineTo(0, 16)
+	p.Close()
+	p.MoveTo(4, 4)
+	p.LineTo(12, 4)
+	p.LineTo(12, 12)
+	p.LineTo(4, 12)
+	p.Close()
+
+	for _, fillRule := range []vector.FillRule{vector.FillRuleNonZero, vector.FillRuleEvenOdd} {
+		dst := ebiten.NewImage(16, 16)
+		vector.DrawFilledPath(dst, &p, color.White, false, fillRule)
+
+		if got, want := dst.At(2, 2), (color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}); got != want {
+			t.Errorf("fill rule: %d, At(2, 2): got: %v, want: %v", fillRule, got, want)
+		}
+		want := color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+		if fillRule == vector.FillRuleEvenOdd {
+			want = color.RGBA{}
+		}
+		if got := dst.At(8, 8); got != want {
+			t.Errorf("fill rule: %d, At(8, 8): got: %v, want: %v", fillRule, got, want)
+		}
+	}
+}
+
+func TestDrawFilledPathWithOptionsAntiAliasSamples(t *testing.T) {
+	// A square whose left edge covers 3/4 of the pixels.
+	var p vector.Path
+	p.MoveTo(0.25, 0)
+	p.LineTo(4, 0)
+	p.LineTo(4, 4)
+	p.LineTo(0.25, 4)
+	p.Close()
+
+	for _, samples := range []int{4, 8, 16} {
+		dst := ebiten.NewImage(8, 8)
+		op := &vector.DrawFilledPathOptions{}
+		op.AntiAliasSamples = samples
+		vector.DrawFilledPathWithOptions(dst, &p, color.White, op)
+
+		got := dst.At(0, 2).(color.RGBA)
+		if diff := int(got.A) - 0xbf; diff < -2 || diff > 2 || got.R != got.A {
+			t.Errorf("samples: %d, At(0, 2): got: %v, want: (0xbf, 0xbf, 0xbf, 0xbf)", samples, got)
+		}
+		if got, want := dst.At(2, 2), (color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}); got != want {
+			t.Errorf("samples: %d, At(2, 2): got: %v, want: %v", samples, got, want)
+		}
+		if got, want := dst.At(5, 2), (color.RGBA{}); got != want {
+			t.Errorf("samples: %d, At(5, 2): got: %v, want: %v", samples, got, want)
+		}
+	}
+
+	// Without anti-aliasing, a pixel is fully rendered or not rendered.
+	dst := ebiten.NewImage(8, 8)
+	vector.DrawFilledPathWithOptions(dst, &p, color.White, nil)
+	if got := dst.At(0, 2).(color.RGBA); got.A != 0 && got.A != 0xff {
+		t.Errorf("At(0, 2) without anti-aliasing: got: %v, want: an opaque or transparent color", got)
+	}
+}
+
+func TestDrawFilledPathWithOptionsAntiAliasSamplesBigPath(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode")
+	}
+
+	// The path is bigger than the tile size, which is the maximum size of the offscreen image (2048) divided by the samples.
+	const size = 136
+
+	var p vector.Path
+	p.MoveTo(0.5, 0.5)
+	p.LineTo(size-0.5, 0.5)
+	p.LineTo(size-0.5, size-0.5)
+	p.LineTo(0.5, size-0.5)
+	p.Close()
+
+	dst := ebiten.NewImage(size, size)
+	op := &vector.DrawFilledPathOptions{}
+	op.AntiAliasSamples = 16
+	vector.DrawFilledPathWithOptions(dst, &p, color.White, op)
+
+	pix := make([]byte, 4*size*size)
+	dst.ReadPixels(pix)
+	for j := 0; j < size; j++ {
+		for i := 0; i < size; i++ {
+			a := pix[4*(j*size+i)+3]
+			want := byte(0xff)
+			edgeX := i == 0 || i == size-1
+			edgeY := j == 0 || j == size-1
+			switch {
+			case edgeX && edgeY:
+				want = 0x40
+			case edgeX || edgeY:
+				want = 0x80
+			}
+			if diff := int(a) - int(want); diff < -2 || diff > 2 {
+				t.Fatalf("alpha at (%d, %d): got: %#x, want: %#x", i, j, a, want)
+			}
+		}
+	}
+}
+
+func TestDrawFilledPathWithOptionsInvalidAntiAliasSamples(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("DrawFilledPathWithOptions with AntiAliasSamples 3 must panic but not")
+		}
+	}()
+	var p vector.Path
+	p.MoveTo(0, 0)
+	p.LineTo(1, 0)
+	p.LineTo(1, 1)
+	p.Close()
+	vector.DrawFilledPathWithOptions(ebiten.NewImage(1, 1), &p, color.White, &vector.DrawFilledPathOptions{
+		AntiAliasSamples: 3,
+	})
+}
+
+func TestPathBooleanOperations(t *testing.T) {
+	square := func(x0, y0, x1, y1 float32) *vector.Path {
+		var p vector.Path
+		p.MoveTo(x0, y0)
+		p.LineTo(x1, y0)
+		p.LineTo(x1, y1)
+		p.LineTo(x0, y1)
+		p.Close()
+		return &p
+	}
+
+	a := square(0, 0, 8, 8)
+	b := square(4, 4, 12, 12)
+	// A square with a hole and a self-intersecting bow tie.
+	c := square(0, 0, 16, 16)
+	var d vector.Path
+	d.MoveTo(4, 4)
+	d.LineTo(12, 12)
+	d.LineTo(12, 4)
+	d.LineTo(4, 12)
+	d.Close()
+
+	white := color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	testCases := []struct {
+		Name    string
+		Path    *vector.Path
+		Filled  [][2]int
+		Cleared [][2]int
+	}{
+		{
+			Name:    "union",
+			Path:    a.Union(b),
+			Filled:  [][2]int{{2, 2}, {6, 6}, {10, 10}},
+			Cleared: [][2]int{{10, 2}, {2, 10}, {14, 14}},
+		},
+		{
+			Name:    "intersect",
+			Path:    a.Intersect(b),
+			Filled:  [][2]int{{5, 5}, {6, 6}},
+			Cleared: [][2]int{{2, 2}, {10, 10}},
+		},
+		{
+			Name:    "subtract",
+			Path:    a.Subtract(b),
+			Filled:  [][2]int{{2, 2}, {6, 2}, {2, 6}},
+			Cleared: [][2]int{{6, 6}, {10, 10}},
+		},
+		{
+			Name: "subtract a self-intersecting path",
+			Path: c.Subtract(&d),
+			// The bow tie has triangles at the left and the right of its crossing point (8, 8).
+			Filled:  [][2]int{{2, 2}, {8, 5}, {8, 10}, {14, 14}},
+			Cleared: [][2]int{{5, 8}, {10, 8}},
+		},
+	}
+	for _, tc := range testCases {
+		for _, fillRule := range []vector.FillRule{vector.FillRuleNonZero, vector.FillRuleEvenOdd} {
+			dst := ebiten.NewImage(16, 16)
+			vector.DrawFilledPath(dst, tc.Path, color.White, false, fillRule)
+			for _, pt := range tc.Filled {
+				if got, want := dst.At(pt[0], pt[1]), white; got != want {
+					t.Errorf("%s: fill rule: %d, At(%d, %d): got: %v, want: %v", tc.Name, fillRule, pt[0], pt[1], got, want)
+				}
+			}
+			for _, pt := range tc.Cleared {
+				if got, want := dst.At(pt[0], pt[1]), (color.RGBA{}); got != want {
+					t.Errorf("%s: fill rule: %d, At(%d, %d): got: %v, want: %v", tc.Name, fillRule, pt[0], pt[1], got, want)
+				}
+			}
+		}
+	}
+
+	// The operands are not modified.
+	if x0, y0, x1, y1 := a.Bounds(); x0 != 0 || y0 != 0 || x1 != 8 || y1 != 8 {
+		t.Errorf("Bounds(): got: (%v, %v, %v, %v), want: (0, 0, 8, 8)", x0, y0, x1, y1)
+	}
+}
+
+func TestRoundedRect(t *testing.T) {
+	white := color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+
+	p := vector.RoundedRect(0, 0, 16, 16, 6)
+	if x0, y0, x1, y1 := p.Bounds(); x0 != 0 || y0 != 0 || math.Abs(float64(x1-16)) > 1e-3 || math.Abs(float64(y1-16)) > 1e-3 {
+		t.Errorf("Bounds(): got: (%v, %v, %v, %v), want: (0, 0, 16, 16)", x0, y0, x1, y1)
+	}
+	dst := ebiten.NewImage(16, 16)
+	vector.DrawFilledPath(dst, p, color.White, false, vector.FillRuleNonZero)
+	for _, pt := range [][2]int{{0, 0}, {15, 0}, {15, 15}, {0, 15}} {
+		if got, want := dst.At(pt[0], pt[1]), (color.RGBA{}); got != want {
+			t.Errorf("At(%d, %d): got: %v, want: %v", pt[0], pt[1], got, want)
+		}
+	}
+	for _, pt := range [][2]int{{8, 0}, {0, 8}, {8, 8}, {15, 8}, {8, 15}} {
+		if got, want := dst.At(pt[0], pt[1]), white; got != want {
+			t.Errorf("At(%d, %d): got: %v, want: %v", pt[0], pt[1], got, want)
+		}
+	}
+
+	// Only the bottom-right corner is rounded. Too large a radius is scaled down to fit the rectangle.
+	p = vector.RoundedRectWithRadii(0, 0, 16, 16, 0, 0, 100, 0)
+	dst.Clear()
+	vector.DrawFilledPath(dst, p, color.White, false, vector.FillRuleNonZero)
+	for _, pt := range [][2]int{{0, 0}, {15, 0}, {0, 15}, {4, 4}} {
+		if got, want := dst.At(pt[0], pt[1]), white; got != want {
+			t.Errorf("At(%d, %d): got: %v, want: %v", pt[0], pt[1], got, want)
+		}
+	}
+	if got, want := dst.At(14, 14), (color.RGBA{}); got != want {
+		t.Errorf("At(14, 14): got: %v, want: %v", got, want)
+	}
+
+	if p := vector.RoundedRect(0, 0, 0, 16, 4); p.Length() != 0 {
+		t.Errorf("Length() for an empty rectangle: got: %v, want: 0", p.Length())
+	}
+}
+
+func TestStrokeLineWithOptions(t *testing.T) {
+	white := color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	for _, lineCap := range []vector.LineCap{vector.LineCapButt, vector.LineCapRound, vector.LineCapSquare} {
+		dst := ebiten.NewImage(16, 16)
+		op := &vector.StrokeOptions{}
+		op.Width = 4
+		op.LineCap = lineCap
+		vector.StrokeLineWithOptions(dst, 4, 8, 12, 8, color.White, false, op)
+
+		if got, want := dst.At(8, 8), white; got != want {
+			t.Errorf("line cap: %d, At(8, 8): got: %v, want: %v", lineCap, got, want)
+		}
+		// The cap extends the line by the half of the width.
+		want := white
+		if lineCap == vector.LineCapButt {
+			want = color.RGBA{}
+		}
+		if got := dst.At(2, 8); got != want {
+			t.Errorf("line cap: %d, At(2, 8): got: %v, want: %v", lineCap, got, want)
+		}
+	}
+}
+
+func TestStrokePolyline(t *testing.T) {
+	white := color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	for _, lineJoin := range []vector.LineJoin{vector.LineJoinMiter, vector.LineJoinBevel} {
+		dst := ebiten.NewImage(16, 16)
+		op := &vector.StrokeOptions{}
+		op.Width = 4
+		op.LineJoin = lineJoin
+		op.MiterLimit = 10
+		vector.StrokePolyline(dst, []float32{4, 4, 12, 4, 12, 12}, color.White, false, op)
+
+		for _, pt := range [][2]int{{8, 4}, {12, 8}} {
+			if got, want := dst.At(pt[0], pt[1]), white; got != want {
+				t.Errorf("line join: %d, At(%d, %d): got: %v, want: %v", lineJoin, pt[0], pt[1], got, want)
+			}
+		}
+		// The outer corner is rendered only with the miter join.
+		want := white
+		if lineJoin == vector.LineJoinBevel {
+			want = color.RGBA{}
+		}
+		if got := dst.At(13, 2); got != want {
+			t.Errorf("line join: %d, At(13, 2): got: %v, want: %v", lineJoin, got, want)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("StrokePolyline with an odd number of coordinates must panic")
+		}
+	}()
+	vector.StrokePolyline(ebiten.NewImage(16, 16), []float32{0, 0, 1}, color.White, false, &vector.StrokeOptions{Width: 1})
+}
+
+func TestDrawFilledPathGradient(t *testing.T) {
+	abs := func(x int) int {
+		if x < 0 {
+			return -x
+		}
+		return x
+	}
+	near := func(c0 color.Color, c1 color.RGBA) bool {
+		c := color.RGBAModel.Convert(c0).(color.RGBA)
+		return abs(int(c.R)-int(c1.R)) <= 16 && abs(int(c.G)-int(c1.G)) <= 16 && abs(int(c.B)-int(c1.B)) <= 16 && abs(int(c.A)-int(c1.A)) <= 16
+	}
+
+	var p vector.Path
+	p.MoveTo(0, 0)
+	p.LineTo(16, 0)
+	p.LineTo(16, 16)
+	p.LineTo(0, 16)
+	p.Close()
+
+	red := color.RGBA{R: 0xff, A: 0xff}
+	blue := color.RGBA{B: 0xff, A: 0xff}
+	stops := []vector.GradientStop{
+		{Offset: 0, Color: red},
+		{Offset: 1, Color: blue},
+	}
+
+	dst := ebiten.NewImage(16, 16)
+	vector.DrawFilledPathLinearGradient(dst, &p, 0, 0, 16, 0, stops, nil)
+	if got, want := dst.At(0, 8), red; !near(got, want) {
+		t.Errorf("linear: At(0, 8): got: %v, want: %v", got, want)
+	}
+	if got, want := dst.At(15, 8), blue; !near(got, want) {
+		t.Errorf("linear: At(15, 8): got: %v, want: %v", got, want)
+	}
+	if got, want := dst.At(8, 0), (color.RGBA{R: 0x78, B: 0x87, A: 0xff}); !near(got, want) {
+		t.Errorf("linear: At(8, 0): got: %v, want: %v", got, want)
+	}
+
+	// The gradient is repeated every 8 pixels.
+	dst.Clear()
+	vector.DrawFilledPathLinearGradient(dst, &p, 0, 0, 8, 0, stops, &vector.GradientOptions{
+		SpreadMode: vector.GradientSpreadModeRepeat,
+	})
+	for x := 0; x < 8; x++ {
+		if got, want := dst.At(x+8, 8), dst.At(x, 8); got != want {
+			t.Errorf("repeat: At(%d, 8): got: %v, want: %v", x+8, got, want)
+		}
+	}
+
+	// The area outside of the circle has the last stop's color with the clamp mode.
+	dst.Clear()
+	vector.DrawFilledPathRadialGradient(dst, &p, 8.5, 8.5, 4, stops, nil)
+	if got, want := dst.At(8, 8), red; !near(got, want) {
+		t.Errorf("radial: At(8, 8): got: %v, want: %v", got, want)
+	}
+	if got, want := dst.At(0, 0), blue; !near(got, want) {
+		t.Errorf("radial: At(0, 0): got: %v, want: %v", got, want)
+	}
+}
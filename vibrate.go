@@ -70,7 +70,11 @@ type VibrateGamepadOptions struct {
 
 // VibrateGamepad vibrates the specified gamepad with the specified options.
 //
-// VibrateGamepad works only on browsers and Nintendo Switch so far.
+// VibrateGamepad works on browsers, Windows (XInput gamepads), Xbox, and Nintendo Switch so far.
+// On the other environments, VibrateGamepad does nothing.
+// Use IsGamepadVibrationAvailable to check whether a gamepad can vibrate.
+//
+// If both StrongMagnitude and WeakMagnitude are 0, the current vibration stops on Windows and Xbox.
 //
 // VibrateGamepad is concurrent-safe.
 func VibrateGamepad(gamepadID GamepadID, options *VibrateGamepadOptions) {
@@ -80,3 +84,16 @@ func VibrateGamepad(gamepadID GamepadID, options *VibrateGamepadOptions) {
 	}
 	g.Vibrate(options.Duration, options.StrongMagnitude, options.WeakMagnitude)
 }
+
+// IsGamepadVibrationAvailable reports whether the specified gamepad can vibrate by VibrateGamepad.
+//
+// IsGamepadVibrationAvailable returns false if the gamepad is not connected.
+//
+// IsGamepadVibrationAvailable is concurrent-safe.
+func IsGamepadVibrationAvailable(gamepadID GamepadID) bool {
+	g := gamepad.Get(gamepadID)
+	if g == nil {
+		return false
+	}
+	return g.IsVibrationAvailable()
+}
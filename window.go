@@ -53,6 +53,8 @@ func IsWindowDecorated() bool {
 //
 // The window is decorated by default.
 //
+// To move an undecorated window with a custom-drawn title bar, use SetWindowDragRegions.
+//
 // SetWindowDecorated works only on desktops.
 // SetWindowDecorated does nothing if the platform is not a desktop.
 //
@@ -325,6 +327,9 @@ func IsWindowClosingHandled() bool {
 // Even if this is set true, some platforms might requrie a window to be undecorated
 // in order to make the mouse cursor passthrough the window.
 //
+// When mouse passthrough is enabled, the whole window ignores mouse events, including its opaque pixels.
+// This is useful for an overlay with a transparent window (see RunGameOptions.ScreenTransparent).
+//
 // SetWindowMousePassthrough works only on desktops.
 // SetWindowMousePassthrough does nothing if the platform is not a desktop.
 //
@@ -341,3 +346,34 @@ func SetWindowMousePassthrough(enabled bool) {
 func IsWindowMousePassthrough() bool {
 	return ui.Get().Window().IsMousePassthrough()
 }
+
+// SetWindowDragRegions sets the regions of the screen that work as the window's title bar on desktops.
+//
+// SetWindowDragRegions is useful to make a custom-drawn title bar with an undecorated window (see SetWindowDecorated).
+// When the left mouse button is pressed on one of the regions and the cursor is moved, the window moves along with the cursor.
+// When one of the regions is double-clicked, the window is maximized, or restored if the window is already maximized.
+// The window is not maximized when the window is not resizable (see SetWindowResizingMode).
+//
+// The regions are in the same coordinate as CursorPosition, i.e. the game screen's coordinate.
+// The mouse events on the regions are still reported to the game as usual.
+//
+// The window is not moved in fullscreen mode, while the window is maximized, or while the cursor is captured.
+//
+// SetWindowDragRegions replaces the existing regions. Passing nil or an empty slice removes all the regions.
+//
+// SetWindowDragRegions works only on desktops.
+// SetWindowDragRegions does nothing if the platform is not a desktop.
+//
+// SetWindowDragRegions is concurrent-safe.
+func SetWindowDragRegions(regions []image.Rectangle) {
+	ui.Get().Window().SetDragRegions(regions)
+}
+
+// WindowDragRegions returns the regions of the screen that work as the window's title bar.
+//
+// WindowDragRegions always returns nil if the platform is not a desktop.
+//
+// WindowDragRegions is concurrent-safe.
+func WindowDragRegions() []image.Rectangle {
+	return ui.Get().Window().DragRegions()
+}